@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"log/slog"
+	"maps"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,18 +12,27 @@ import (
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
-	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/couchcryptid/storm-data-api/internal/admin"
 	"github.com/couchcryptid/storm-data-api/internal/config"
+	"github.com/couchcryptid/storm-data-api/internal/csvapi"
 	"github.com/couchcryptid/storm-data-api/internal/database"
+	"github.com/couchcryptid/storm-data-api/internal/geojsonapi"
 	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/grpcapi"
+	"github.com/couchcryptid/storm-data-api/internal/httpserver"
 	"github.com/couchcryptid/storm-data-api/internal/kafka"
+	"github.com/couchcryptid/storm-data-api/internal/model"
 	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/couchcryptid/storm-data-api/internal/pb"
+	"github.com/couchcryptid/storm-data-api/internal/protoapi"
 	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/couchcryptid/storm-data-api/internal/streamapi"
+	"github.com/couchcryptid/storm-data-api/internal/tileapi"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -32,7 +43,7 @@ func main() {
 	}
 
 	logger := observability.NewLogger(cfg)
-	metrics := observability.NewMetrics()
+	metrics := observability.NewMetrics(cfg.MetricsNamespace)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
@@ -43,7 +54,12 @@ func main() {
 		os.Exit(1) //nolint:gocritic // startup exits before meaningful defers
 	}
 
-	pool, err := database.NewPool(ctx, cfg.DatabaseURL)
+	pool, err := database.NewPool(ctx, cfg.DatabaseURL,
+		database.WithMaxConns(int32(cfg.DBMaxConns)),
+		database.WithMinConns(int32(cfg.DBMinConns)),
+		database.WithMaxConnIdleTime(cfg.DBMaxConnIdleTime),
+		database.WithMaxConnLifetime(cfg.DBMaxConnLifetime),
+	)
 	if err != nil {
 		logger.Error("connect to database", "error", err)
 		os.Exit(1)
@@ -51,7 +67,48 @@ func main() {
 	defer pool.Close()
 
 	s := store.New(pool, metrics)
-	readiness := database.NewPoolReadiness(pool)
+	if cfg.DatabaseReplicaURL != "" {
+		replica, err := database.NewPool(ctx, cfg.DatabaseReplicaURL,
+			database.WithMaxConns(int32(cfg.DBMaxConns)),
+			database.WithMinConns(int32(cfg.DBMinConns)),
+			database.WithMaxConnIdleTime(cfg.DBMaxConnIdleTime),
+			database.WithMaxConnLifetime(cfg.DBMaxConnLifetime),
+		)
+		if err != nil {
+			// Reads fall back to the primary anyway; serve from it alone.
+			logger.Warn("connect to read replica, serving reads from primary", "error", err)
+		} else {
+			defer replica.Close()
+			s.SetReplica(replica)
+		}
+	}
+	if err := s.SetConflictTarget(cfg.ConflictColumns); err != nil {
+		logger.Error("configure insert conflict target", "error", err)
+		os.Exit(1)
+	}
+	s.SetQueryTimeout(cfg.QueryStatementTimeout)
+	eventTypeLabels := maps.Clone(model.DefaultEventTypeLabels)
+	maps.Copy(eventTypeLabels, cfg.EventTypeLabels)
+	s.SetEventTypeLabels(eventTypeLabels)
+	relabelled, err := s.RelabelEventTypes(ctx)
+	if err != nil {
+		logger.Error("relabel event types", "error", err)
+		os.Exit(1)
+	}
+	if relabelled > 0 {
+		logger.Info("relabelled stored event types", "reports", relabelled)
+	}
+	s.SetSkipBadRows(cfg.DBSkipBadRows, logger)
+	if cfg.QueryCacheTTL > 0 {
+		s.EnableCache(cfg.QueryCacheTTL, cfg.QueryCacheMaxSize)
+	}
+	if err := s.LoadSeverityThresholds(ctx); err != nil {
+		logger.Error("load severity thresholds", "error", err)
+		os.Exit(1)
+	}
+	// Reports not-ready as soon as shutdown begins, so the load balancer
+	// stops routing here while in-flight requests drain.
+	readiness := httpserver.NewReadiness(database.NewPoolReadiness(pool))
 
 	// DB pool stats collector
 	go func() {
@@ -76,6 +133,9 @@ func main() {
 		cfg.BatchSize, cfg.BatchFlushInterval,
 		s, metrics, logger,
 	)
+	if cfg.BatchPartialInsert {
+		consumer.EnablePartialInsert()
+	}
 	defer func() {
 		if err := consumer.Close(); err != nil {
 			logger.Error("kafka consumer close", "error", err)
@@ -87,51 +147,146 @@ func main() {
 		}
 	}()
 
-	// GraphQL server with three layers of query protection:
-	//  1. Complexity limit (600): caps total field cost to prevent wide/expensive queries
-	//  2. Depth limit (7): caps nesting depth to prevent deeply recursive queries
+	// GraphQL server with layers of query protection:
+	//  1. Complexity limit (QUERY_MAX_COMPLEXITY, 600): caps total field cost to prevent
+	//     wide/expensive queries; report lists cost per requested page item
+	//  2. Depth limit (QUERY_MAX_DEPTH, 7): caps nesting depth to prevent deeply recursive queries
 	//  3. Concurrency limit (2): caps parallel queries to prevent pgx pool exhaustion
-	//     (4 pool connections − 1 reserved for Kafka − 1 buffer = 2 for GraphQL)
+	//     (4 pool connections − 1 reserved for Kafka − 1 for streaming = 2 for GraphQL).
+	//     Streaming routes hold a connection for their whole run, so they get their
+	//     own limit (STREAM_MAX_CONCURRENT, 1) and a hard cap (STREAM_MAX_DURATION)
+	//     instead of competing with /query for its slots
+	//  4. Query budget (optional): caps store queries and DB time per request
+	//  5. Response size (optional): rejects responses larger than the configured bytes
+	resolver := &graph.Resolver{
+		Store:              s,
+		DefaultWindow:      cfg.QueryDefaultWindow,
+		MaxTimeRange:       cfg.QueryMaxTimeRange,
+		TimeRounding:       cfg.QueryTimeRounding,
+		AllowFutureReports: cfg.AllowFutureReports,
+		GeoConflictMode:    graph.GeoConflictMode(cfg.GeoConflictMode),
+		SeverityWeights: model.SeverityWeights{
+			Hail:    cfg.SeverityWeightHail,
+			Wind:    cfg.SeverityWeightWind,
+			Tornado: cfg.SeverityWeightTornado,
+		}.WithThresholds(s.SeverityThresholds()),
+		Metrics: metrics,
+		Logger:  logger,
+	}
 	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
-		Resolvers:  &graph.Resolver{Store: s},
+		Resolvers:  resolver,
 		Complexity: graph.NewComplexityRoot(),
 	}))
-	srv.Use(extension.FixedComplexityLimit(600))
-	srv.Use(graph.DepthLimit{MaxDepth: 7})
+	srv.Use(&graph.ComplexityLimit{MaxComplexity: cfg.QueryMaxComplexity})
+	srv.Use(graph.DepthLimit{MaxDepth: cfg.QueryMaxDepth})
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+	if cfg.QueryMaxResponseBytes > 0 {
+		srv.Use(graph.ResponseSizeLimit{MaxBytes: cfg.QueryMaxResponseBytes})
+	}
 
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
+	r.Use(observability.LoggingMiddleware(logger))
 	r.Use(middleware.Recoverer)
 	r.Use(cors.AllowAll().Handler)
 	r.Use(observability.MetricsMiddleware(metrics))
-	r.Use(graph.ConcurrencyLimit(2)) // see comment above for pool math
-	r.Handle("/", playground.Handler("Storm Data API", "/query"))
-	r.Handle("/query", srv)
-	r.Get("/healthz", observability.LivenessHandler())
-	r.Get("/readyz", observability.ReadinessHandler(readiness))
-	r.Handle("/metrics", promhttp.Handler())
+	r.Use(graph.RequestTimeout(cfg.QueryTimeoutMax))
+	r.Use(graph.QueryBudgetLimit(cfg.QueryBudgetQueries, cfg.QueryBudgetDBTime))
+	r.Use(graph.PlaceLookupCache())
+	r.Use(graph.CountyLookupLoader(s))
+	r.Group(func(r chi.Router) {
+		r.Use(graph.ConcurrencyLimit(cfg.StreamMaxConcurrent))
+		r.Use(graph.MaxDuration(cfg.StreamMaxDuration))
+		r.Get("/export.csv", csvapi.ExportHandler(s, resolver, csvapi.WithMaxRows(cfg.ReportsMaxRows)))
+		r.Post("/stream/county-groups", streamapi.CountyGroupsHandler(s, resolver))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(graph.ConcurrencyLimit(2)) // see comment above for pool math
+		r.Use(admin.IdentifyKey(cfg.AdminAPIKey)) // authorizes GraphQL mutations
+		r.Handle("/", playground.Handler("Storm Data API", "/query"))
+		r.Handle("/query", srv)
+		r.Post("/reports", protoapi.ReportsHandler(s, resolver,
+			protoapi.WithEmptyStatus(cfg.ReportsEmptyStatus),
+			protoapi.WithMaxRows(cfg.ReportsMaxRows),
+		))
+		r.Post("/reports.csv", csvapi.ReportsHandler(s, resolver, csvapi.WithMaxRows(cfg.ReportsMaxRows)))
+		r.Post("/reports.geojson", geojsonapi.ReportsHandler(s, resolver, geojsonapi.WithMaxRows(cfg.ReportsMaxRows)))
+		r.Get("/tiles/{z}/{x}/{y}.mvt", tileapi.TileHandler(s, resolver,
+			tileapi.WithClusterMaxZoom(cfg.TileClusterMaxZoom),
+			tileapi.WithClusterGrid(cfg.TileClusterGrid),
+		))
+		r.Get("/healthz", observability.LivenessHandler())
+		r.Get("/readyz", observability.ReadinessHandler(readiness))
+		r.Handle("/metrics", observability.MetricsHandler())
+
+		// Operator endpoints, only mounted when an admin key is configured.
+		if cfg.AdminAPIKey != "" {
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(admin.RequireKey(cfg.AdminAPIKey))
+				r.Post("/cache/flush", admin.FlushCacheHandler(s))
+				r.Post("/plan-hash", admin.PlanHashHandler(s, resolver))
+				if cfg.AdminExplainEnabled {
+					r.Post("/explain", admin.ExplainHandler(s, resolver))
+				}
+				r.Patch("/reports/{id}", admin.PatchReportHandler(s))
+			})
+		}
+	})
+
+	// gRPC query service, only started when a port is configured.
+	if cfg.GRPCPort != "" {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			logger.Error("grpc listen", "error", err)
+			os.Exit(1)
+		}
+		grpcServer := grpc.NewServer()
+		pb.RegisterStormReportServiceServer(grpcServer, grpcapi.NewServer(s, resolver))
+		go func() {
+			<-ctx.Done()
+			grpcServer.GracefulStop()
+		}()
+		go func() {
+			logger.Info("grpc server started", "port", cfg.GRPCPort)
+			if err := grpcServer.Serve(lis); err != nil {
+				logger.Error("grpc server error", "error", err)
+			}
+		}()
+	}
+
+	// http.TimeoutHandler buffers the whole response, so streaming routes
+	// bypass it. They extend their own write deadline as rows are flushed,
+	// stop at STREAM_MAX_DURATION, and stop when the client disconnects.
+	root := http.NewServeMux()
+	root.Handle("/stream/", r)
+	root.Handle("/export.csv", r)
+	root.Handle("/", http.TimeoutHandler(r, 25*time.Second, `{"errors":[{"message":"request timeout"}]}`))
 
 	server := &http.Server{
 		Addr:              ":" + cfg.Port,
-		Handler:           http.TimeoutHandler(r, 25*time.Second, `{"errors":[{"message":"request timeout"}]}`),
+		Handler:           root,
 		ReadTimeout:       10 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
 		WriteTimeout:      30 * time.Second,
 		IdleTimeout:       120 * time.Second,
 	}
 
+	lis, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		logger.Error("http listen", "error", err)
+		os.Exit(1)
+	}
 	go func() {
 		<-ctx.Done()
-		logger.Info("shutting down")
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
-		defer shutdownCancel()
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			logger.Error("server shutdown", "error", err)
-		}
+		logger.Info("shutting down, draining in-flight requests", "delay", cfg.ShutdownDelay, "grace_period", cfg.ShutdownTimeout)
 	}()
 
 	logger.Info("server started", "port", cfg.Port)
-	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+	if err := httpserver.RunServer(ctx, server, lis,
+		httpserver.WithGracePeriod(cfg.ShutdownTimeout),
+		httpserver.WithPreShutdownDelay(cfg.ShutdownDelay),
+		httpserver.WithReadiness(readiness),
+	); err != nil {
 		logger.Error("server error", "error", err)
 		os.Exit(1)
 	}
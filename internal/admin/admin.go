@@ -0,0 +1,172 @@
+// Package admin provides operator-only HTTP endpoints gated by a shared API key.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+// HeaderKey is the request header carrying the admin API key.
+const HeaderKey = "X-Admin-Key"
+
+// CacheFlusher clears cached query results.
+type CacheFlusher interface {
+	FlushCache() int
+}
+
+// PlanHasher hashes the structure of the query plan for a filter.
+type PlanHasher interface {
+	PlanHash(ctx context.Context, filter *model.StormReportFilter) (string, error)
+}
+
+// Explainer returns the page query for a filter and its plan. Implemented by
+// store.Store.
+type Explainer interface {
+	ExplainStormReports(ctx context.Context, filter *model.StormReportFilter) (*store.QueryPlan, error)
+}
+
+// ReportPatcher applies an admin correction to a stored report. Implemented
+// by store.Store.
+type ReportPatcher interface {
+	PatchStormReport(ctx context.Context, id string, patch *model.ReportPatch) (time.Time, error)
+}
+
+// RequireKey rejects requests whose X-Admin-Key header does not match key.
+// An empty key rejects every request, so admin endpoints fail closed when
+// ADMIN_API_KEY is unset.
+func RequireKey(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !keyMatches(r, key) {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type authorizedKey struct{}
+
+// IdentifyKey records whether the request's X-Admin-Key header matches key,
+// for admin operations served outside /admin such as the GraphQL
+// patchStormReport mutation. Unlike RequireKey it never rejects a request;
+// the operation checks Authorized instead. An empty key authorizes nobody.
+func IdentifyKey(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if keyMatches(r, key) {
+				r = r.WithContext(context.WithValue(r.Context(), authorizedKey{}, true))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Authorized reports whether IdentifyKey accepted the request's admin key.
+func Authorized(ctx context.Context) bool {
+	ok, _ := ctx.Value(authorizedKey{}).(bool)
+	return ok
+}
+
+func keyMatches(r *http.Request, key string) bool {
+	got := r.Header.Get(HeaderKey)
+	return key != "" && subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1
+}
+
+// FlushCacheHandler clears the query caches and reports how many entries were evicted.
+func FlushCacheHandler(f CacheFlusher) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]int{"evicted": f.FlushCache()})
+	}
+}
+
+// PlanHashHandler returns a hash of the query plan for the JSON filter in the
+// body, for spotting when the planner changes strategy for a filter shape.
+func PlanHashHandler(h PlanHasher, p apifilter.Preparer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := apifilter.FromBody(w, r, p)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		hash, err := h.PlanHash(r.Context(), filter)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "explain failed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"hash": hash})
+	}
+}
+
+// ExplainHandler returns the page query SQL, its args, and the Postgres plan
+// for the JSON filter in the body, without running the query.
+func ExplainHandler(e Explainer, p apifilter.Preparer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := apifilter.FromBody(w, r, p)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		plan, err := e.ExplainStormReports(r.Context(), filter)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "explain failed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"sql":  plan.SQL,
+			"args": plan.Args,
+			"plan": json.RawMessage(plan.Plan),
+		})
+	}
+}
+
+// PatchReportHandler applies the JSON ReportPatch in the body to the report
+// named by the {id} route parameter. Only the fields present are written. A
+// stale expectedUpdatedAt gets 409 with the row's current updatedAt, and so does
+// a report held locked by another edit for longer than the lock timeout.
+func PatchReportHandler(p ReportPatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		var patch model.ReportPatch
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, apifilter.MaxBodyBytes)).Decode(&patch); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid patch: " + err.Error()})
+			return
+		}
+		if err := patch.Validate(); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		updatedAt, err := p.PatchStormReport(r.Context(), id, &patch)
+		var lockErr *store.LockTimeoutError
+		switch {
+		case errors.Is(err, store.ErrEmptyPatch):
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, store.ErrReportNotFound):
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, store.ErrVersionConflict):
+			writeJSON(w, http.StatusConflict, map[string]any{"error": err.Error(), "updatedAt": updatedAt})
+		case errors.As(err, &lockErr):
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "patch failed"})
+		default:
+			writeJSON(w, http.StatusOK, map[string]any{"id": id, "updatedAt": updatedAt})
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
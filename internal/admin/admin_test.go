@@ -0,0 +1,254 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/cache"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheFlusher struct {
+	c *cache.Cache[int]
+}
+
+func (f cacheFlusher) FlushCache() int { return f.c.Flush() }
+
+func newFlushHandler(key string) (http.Handler, *cache.Cache[int]) {
+	c := cache.New[int](time.Minute, 10)
+	return RequireKey(key)(FlushCacheHandler(cacheFlusher{c: c})), c
+}
+
+func TestRequireKey_Missing(t *testing.T) {
+	h, c := newFlushHandler("secret")
+	c.Set("a", 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cache/flush", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, 1, c.Len(), "cache must not be flushed without a valid key")
+}
+
+func TestRequireKey_Wrong(t *testing.T) {
+	h, _ := newFlushHandler("secret")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cache/flush", nil)
+	req.Header.Set(HeaderKey, "nope")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequireKey_EmptyKeyFailsClosed(t *testing.T) {
+	h, _ := newFlushHandler("")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cache/flush", nil)
+	req.Header.Set(HeaderKey, "")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestIdentifyKey(t *testing.T) {
+	for _, tc := range []struct {
+		name, key, header string
+		want              bool
+	}{
+		{"match", "secret", "secret", true},
+		{"wrong", "secret", "nope", false},
+		{"missing", "secret", "", false},
+		{"no key configured", "", "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got, called bool
+			h := IdentifyKey(tc.key)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				called = true
+				got = Authorized(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tc.header != "" {
+				req.Header.Set(HeaderKey, tc.header)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.True(t, called, "never rejects")
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFlushCacheHandler_EvictsEntries(t *testing.T) {
+	h, c := newFlushHandler("secret")
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cache/flush", nil)
+	req.Header.Set(HeaderKey, "secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]int
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, 2, body["evicted"])
+	assert.Equal(t, 0, c.Len())
+}
+
+// fakePlanner "plans" a filter from its shape: which filters are set, never
+// their values, the way the database plans a parameterized query.
+type fakePlanner struct{}
+
+func (fakePlanner) PlanHash(_ context.Context, f *model.StormReportFilter) (string, error) {
+	if len(f.States) > 0 {
+		return "bitmap-scan-idx_state", nil
+	}
+	return "index-scan-idx_event_time", nil
+}
+
+type preparer func(*model.StormReportFilter) error
+
+func (p preparer) PrepareFilter(f *model.StormReportFilter) error { return p(f) }
+
+func planHash(t *testing.T, body string) (int, map[string]string) {
+	t.Helper()
+	h := RequireKey("secret")(PlanHashHandler(fakePlanner{}, preparer(func(f *model.StormReportFilter) error {
+		if f.TimeRange.From.IsZero() {
+			return errors.New("timeRange is required")
+		}
+		return nil
+	})))
+	req := httptest.NewRequest(http.MethodPost, "/admin/plan-hash", strings.NewReader(body))
+	req.Header.Set(HeaderKey, "secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var out map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	return rec.Code, out
+}
+
+func TestPlanHashHandler_SameShapeSameHash(t *testing.T) {
+	const tr = `"timeRange":{"from":"2024-04-26T00:00:00Z","to":"2024-04-27T00:00:00Z"}`
+
+	code, tx := planHash(t, `{`+tr+`,"states":["TX"]}`)
+	require.Equal(t, http.StatusOK, code)
+	_, ok := planHash(t, `{`+tr+`,"states":["OK","KS"]}`)
+	assert.Equal(t, tx["hash"], ok["hash"], "same shape, different values")
+
+	_, all := planHash(t, `{`+tr+`}`)
+	assert.NotEqual(t, tx["hash"], all["hash"], "different shape")
+}
+
+func TestPlanHashHandler_InvalidFilter(t *testing.T) {
+	code, out := planHash(t, `{"states":["TX"]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "timeRange is required", out["error"])
+
+	code, _ = planHash(t, `not json`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// fakePatcher records the patch it was given and returns a canned result.
+type fakePatcher struct {
+	got       *model.ReportPatch
+	updatedAt time.Time
+	err       error
+}
+
+func (f *fakePatcher) PatchStormReport(_ context.Context, _ string, patch *model.ReportPatch) (time.Time, error) {
+	f.got = patch
+	return f.updatedAt, f.err
+}
+
+func patchReport(p ReportPatcher, body string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Patch("/admin/reports/{id}", PatchReportHandler(p))
+	req := httptest.NewRequest(http.MethodPatch, "/admin/reports/r-1", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPatchReportHandler_OK(t *testing.T) {
+	now := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+	p := &fakePatcher{updatedAt: now}
+
+	rec := patchReport(p, `{"expectedUpdatedAt":"2024-04-27T00:00:00Z","magnitude":1.75}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, p.got)
+	require.NotNil(t, p.got.Magnitude)
+	assert.InDelta(t, 1.75, *p.got.Magnitude, 1e-9)
+	assert.Nil(t, p.got.Comments, "absent fields stay nil")
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "r-1", body["id"])
+	assert.Equal(t, "2024-04-27T12:00:00Z", body["updatedAt"])
+}
+
+func TestPatchReportHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"malformed", `{`, nil, http.StatusBadRequest},
+		{"missing version", `{"magnitude":1}`, nil, http.StatusBadRequest},
+		{"empty patch", `{"expectedUpdatedAt":"2024-04-27T00:00:00Z"}`, store.ErrEmptyPatch, http.StatusBadRequest},
+		{"not found", `{"expectedUpdatedAt":"2024-04-27T00:00:00Z","magnitude":1}`, store.ErrReportNotFound, http.StatusNotFound},
+		{"conflict", `{"expectedUpdatedAt":"2024-04-27T00:00:00Z","magnitude":1}`, store.ErrVersionConflict, http.StatusConflict},
+		{"locked", `{"expectedUpdatedAt":"2024-04-27T00:00:00Z","magnitude":1}`, &store.LockTimeoutError{ID: "r-1", Timeout: time.Second}, http.StatusConflict},
+		{"store failure", `{"expectedUpdatedAt":"2024-04-27T00:00:00Z","magnitude":1}`, errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := patchReport(&fakePatcher{err: tt.err}, tt.body)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.NotContains(t, rec.Body.String(), "boom", "store errors are not leaked")
+		})
+	}
+}
+
+type fakeExplainer struct{}
+
+func (fakeExplainer) ExplainStormReports(_ context.Context, f *model.StormReportFilter) (*store.QueryPlan, error) {
+	return &store.QueryPlan{
+		SQL:  "EXPLAIN (FORMAT JSON, ANALYZE false) SELECT 1 WHERE location_state = ANY($1)",
+		Args: []any{f.States},
+		Plan: `[{"Plan": {"Node Type": "Result"}}]`,
+	}, nil
+}
+
+func TestExplainHandler(t *testing.T) {
+	h := RequireKey("secret")(ExplainHandler(fakeExplainer{}, preparer(func(*model.StormReportFilter) error { return nil })))
+	req := httptest.NewRequest(http.MethodPost, "/admin/explain", strings.NewReader(`{"states":["TX"]}`))
+	req.Header.Set(HeaderKey, "secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var out struct {
+		SQL  string            `json:"sql"`
+		Args [][]string        `json:"args"`
+		Plan []json.RawMessage `json:"plan"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	assert.True(t, strings.HasPrefix(out.SQL, "EXPLAIN "))
+	assert.Equal(t, [][]string{{"TX"}}, out.Args)
+	assert.Len(t, out.Plan, 1, "plan is embedded as JSON, not a string")
+}
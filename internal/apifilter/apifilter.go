@@ -0,0 +1,77 @@
+// Package apifilter reads the StormReportFilter sent to the HTTP APIs, so
+// every surface applies the same body limit, validation, and error responses.
+package apifilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// MaxBodyBytes caps a JSON request body.
+const MaxBodyBytes = 64 << 10
+
+// Preparer validates a filter and applies defaults and query policy.
+// Implemented by graph.Resolver so every API enforces the same limits.
+type Preparer interface {
+	PrepareFilter(filter *model.StormReportFilter) error
+}
+
+// DecodeBody reads the JSON filter in r's body, capped at MaxBodyBytes.
+func DecodeBody(w http.ResponseWriter, r *http.Request) (*model.StormReportFilter, error) {
+	var filter model.StormReportFilter
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodyBytes)).Decode(&filter); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return &filter, nil
+}
+
+// DecodeQuery reads the URL-encoded JSON filter in r's filter query
+// parameter. A missing parameter is an empty filter.
+func DecodeQuery(r *http.Request) (*model.StormReportFilter, error) {
+	var filter model.StormReportFilter
+	raw := r.URL.Query().Get("filter")
+	if raw == "" {
+		return &filter, nil
+	}
+	if len(raw) > MaxBodyBytes {
+		return nil, fmt.Errorf("invalid filter: longer than %d bytes", MaxBodyBytes)
+	}
+	if err := json.Unmarshal([]byte(raw), &filter); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return &filter, nil
+}
+
+// FromBody decodes the body filter and prepares it with p. Errors are meant
+// for the client, as a 400.
+func FromBody(w http.ResponseWriter, r *http.Request, p Preparer) (*model.StormReportFilter, error) {
+	filter, err := DecodeBody(w, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.PrepareFilter(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// FromQuery decodes the query parameter filter and prepares it with p. Errors
+// are meant for the client, as a 400.
+func FromQuery(r *http.Request, p Preparer) (*model.StormReportFilter, error) {
+	filter, err := DecodeQuery(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.PrepareFilter(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// BadRequest writes err as a plain-text 400 response.
+func BadRequest(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,68 @@
+package apifilter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type preparer func(*model.StormReportFilter) error
+
+func (p preparer) PrepareFilter(f *model.StormReportFilter) error { return p(f) }
+
+var requireStates = preparer(func(f *model.StormReportFilter) error {
+	if len(f.States) == 0 {
+		return errors.New("states is required")
+	}
+	return nil
+})
+
+func TestFromBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"states":["TX"]}`))
+	f, err := FromBody(httptest.NewRecorder(), r, requireStates)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TX"}, f.States)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	_, err = FromBody(httptest.NewRecorder(), r, requireStates)
+	assert.EqualError(t, err, "states is required")
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`not json`))
+	_, err = FromBody(httptest.NewRecorder(), r, requireStates)
+	assert.ErrorContains(t, err, "invalid filter: ")
+}
+
+func TestFromBody_TooLarge(t *testing.T) {
+	body := `{"states":["` + strings.Repeat("X", MaxBodyBytes) + `"]}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	_, err := FromBody(httptest.NewRecorder(), r, requireStates)
+	assert.ErrorContains(t, err, "invalid filter: ")
+}
+
+func TestFromQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?filter="+url.QueryEscape(`{"states":["OK"]}`), nil)
+	f, err := FromQuery(r, requireStates)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OK"}, f.States)
+
+	f, err = DecodeQuery(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StormReportFilter{}, f, "missing parameter is an empty filter")
+
+	_, err = FromQuery(httptest.NewRequest(http.MethodGet, "/?filter=%7B", nil), requireStates)
+	assert.ErrorContains(t, err, "invalid filter: ")
+}
+
+func TestBadRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	BadRequest(rec, errors.New("states is required"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "states is required\n", rec.Body.String())
+}
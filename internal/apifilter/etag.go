@@ -0,0 +1,57 @@
+package apifilter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// WriteCacheable writes body as the contentType response to filter. Responses
+// for time windows already closed carry an ETag, and a request whose
+// If-None-Match matches it gets 304 Not Modified without the body.
+func WriteCacheable(w http.ResponseWriter, r *http.Request, filter *model.StormReportFilter, contentType string, body []byte) {
+	if cacheable(filter, time.Now()) {
+		tag := etag(filter, body)
+		w.Header().Set("ETag", tag)
+		if notModified(r, tag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(body)
+}
+
+// cacheable reports whether responses for filter may carry an ETag: only
+// windows that closed before now, whose reports no longer change.
+func cacheable(filter *model.StormReportFilter, now time.Time) bool {
+	return filter.TimeRange.To.Before(now)
+}
+
+// etag returns a strong validator for body served for filter. The filter is
+// hashed along with the body so different queries with equal results do not
+// share a tag.
+func etag(filter *model.StormReportFilter, body []byte) string {
+	h := sha256.New()
+	_ = json.NewEncoder(h).Encode(filter)
+	h.Write(body)
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// notModified reports whether the request's If-None-Match header matches tag.
+// As RFC 9110 requires for If-None-Match, weak tags compare equal to strong
+// ones with the same value.
+func notModified(r *http.Request, tag string) bool {
+	for _, v := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
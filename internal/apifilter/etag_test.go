@@ -0,0 +1,55 @@
+package apifilter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`"xyz"`, false},
+		{"*", true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/reports.csv", nil)
+		req.Header.Set("If-None-Match", tt.header)
+		assert.Equal(t, tt.want, notModified(req, `"abc"`), tt.header)
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	now := time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC)
+	closed := &model.StormReportFilter{TimeRange: model.TimeRange{To: now.Add(-time.Second)}}
+	open := &model.StormReportFilter{TimeRange: model.TimeRange{To: now.Add(time.Second)}}
+	assert.True(t, cacheable(closed, now))
+	assert.False(t, cacheable(open, now))
+}
+
+func TestWriteCacheable(t *testing.T) {
+	filter := &model.StormReportFilter{TimeRange: model.TimeRange{To: time.Now().Add(-time.Hour)}}
+	rec := httptest.NewRecorder()
+	WriteCacheable(rec, httptest.NewRequest(http.MethodPost, "/reports.csv", nil), filter, "text/csv", []byte("a,b\n"))
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "a,b\n", rec.Body.String())
+	tag := rec.Header().Get("ETag")
+	assert.Equal(t, etag(filter, []byte("a,b\n")), tag)
+
+	req := httptest.NewRequest(http.MethodPost, "/reports.csv", nil)
+	req.Header.Set("If-None-Match", tag)
+	rec = httptest.NewRecorder()
+	WriteCacheable(rec, req, filter, "text/csv", []byte("a,b\n"))
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Zero(t, rec.Body.Len())
+}
@@ -0,0 +1,155 @@
+// Package cache provides a small in-memory TTL cache for query results.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+	size      int64
+}
+
+// Stats is a snapshot of a cache's size.
+type Stats struct {
+	Entries int
+	// Bytes is the estimated memory held by keys and values, as reported by
+	// the sizer passed to Observe; 0 without one.
+	Bytes int64
+}
+
+// Cache is a size-bounded, TTL-expiring in-memory cache safe for concurrent use.
+// When full, expired entries are evicted first, then the entry closest to expiry.
+type Cache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]entry[V]
+	now        func() time.Time
+
+	bytes    int64
+	sizer    func(key string, value V) int64
+	observer func(Stats)
+}
+
+// New creates a cache whose entries expire after ttl, holding at most maxEntries.
+func New[V any](ttl time.Duration, maxEntries int) *Cache[V] {
+	return &Cache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]entry[V]),
+		now:        time.Now,
+	}
+}
+
+// Observe registers fn to receive the cache's Stats after every insert,
+// eviction, expiry, and flush, with sizer estimating each entry's memory
+// (nil counts entries only). fn runs with the cache locked, so it must not
+// call back into the cache. Call before the cache is used.
+func (c *Cache[V]) Observe(sizer func(key string, value V) int64, fn func(Stats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sizer = sizer
+	c.observer = fn
+	c.notifyLocked()
+}
+
+// Get returns the cached value for key if present and not expired.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if !c.now().Before(e.expiresAt) {
+		c.deleteLocked(key, e)
+		c.notifyLocked()
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key, evicting an entry first if the cache is full.
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if old, exists := c.entries[key]; exists {
+		c.deleteLocked(key, old)
+	} else if len(c.entries) >= c.maxEntries {
+		c.evictLocked(now)
+	}
+	e := entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+	if c.sizer != nil {
+		e.size = c.sizer(key, value)
+	}
+	c.entries[key] = e
+	c.bytes += e.size
+	c.notifyLocked()
+}
+
+// Flush removes all entries and returns how many were evicted.
+func (c *Cache[V]) Flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.entries)
+	c.entries = make(map[string]entry[V])
+	c.bytes = 0
+	c.notifyLocked()
+	return n
+}
+
+// Stats returns the current entry count and estimated memory.
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{Entries: len(c.entries), Bytes: c.bytes}
+}
+
+// Len returns the number of entries currently held, including expired
+// entries that have not yet been evicted.
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evictLocked drops all expired entries, or the soonest-expiring entry if none
+// have expired. Callers must hold c.mu.
+func (c *Cache[V]) evictLocked(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	expired := false
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			c.deleteLocked(k, e)
+			expired = true
+			continue
+		}
+		if oldestKey == "" || e.expiresAt.Before(oldest) {
+			oldestKey, oldest = k, e.expiresAt
+		}
+	}
+	if !expired && oldestKey != "" {
+		c.deleteLocked(oldestKey, c.entries[oldestKey])
+	}
+}
+
+// deleteLocked removes key, whose current entry is e. Callers must hold c.mu.
+func (c *Cache[V]) deleteLocked(key string, e entry[V]) {
+	delete(c.entries, key)
+	c.bytes -= e.size
+}
+
+// notifyLocked reports the current Stats to the observer, if any. Callers
+// must hold c.mu.
+func (c *Cache[V]) notifyLocked() {
+	if c.observer != nil {
+		c.observer(Stats{Entries: len(c.entries), Bytes: c.bytes})
+	}
+}
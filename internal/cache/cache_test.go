@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCache(ttl time.Duration, maxEntries int) (*Cache[int], *time.Time) {
+	now := time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC)
+	c := New[int](ttl, maxEntries)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCache_GetSet(t *testing.T) {
+	c, _ := newTestCache(time.Minute, 10)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+}
+
+func TestCache_Expiry(t *testing.T) {
+	c, now := newTestCache(time.Minute, 10)
+	c.Set("a", 1)
+
+	*now = now.Add(time.Minute)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_EvictsOldestWhenFull(t *testing.T) {
+	c, now := newTestCache(time.Minute, 2)
+	c.Set("a", 1)
+	*now = now.Add(time.Second)
+	c.Set("b", 2)
+	*now = now.Add(time.Second)
+	c.Set("c", 3)
+
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.Get("a")
+	assert.False(t, ok, "oldest entry should be evicted")
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+}
+
+func TestCache_Flush(t *testing.T) {
+	c, _ := newTestCache(time.Minute, 10)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	assert.Equal(t, 2, c.Flush())
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, 0, c.Flush())
+}
+
+func TestCache_ObserveTracksEntriesAndBytes(t *testing.T) {
+	c, now := newTestCache(time.Minute, 2)
+	var last Stats
+	calls := 0
+	c.Observe(func(key string, v int) int64 { return int64(len(key) + v) }, func(s Stats) {
+		last = s
+		calls++
+	})
+	assert.Equal(t, Stats{}, last, "initial snapshot")
+
+	c.Set("a", 10)
+	assert.Equal(t, Stats{Entries: 1, Bytes: 11}, last)
+	c.Set("a", 20)
+	assert.Equal(t, Stats{Entries: 1, Bytes: 21}, last, "overwrite replaces size")
+	*now = now.Add(time.Second)
+	c.Set("bb", 5)
+	assert.Equal(t, Stats{Entries: 2, Bytes: 28}, last)
+
+	// Full: inserting evicts the soonest-expiring entry ("a").
+	c.Set("ccc", 1)
+	assert.Equal(t, Stats{Entries: 2, Bytes: 11}, last)
+
+	// Expiry on read.
+	*now = now.Add(time.Minute)
+	_, _ = c.Get("bb")
+	assert.Equal(t, Stats{Entries: 1, Bytes: 4}, last)
+
+	c.Flush()
+	assert.Equal(t, Stats{}, last)
+	assert.Equal(t, last, c.Stats())
+	assert.Equal(t, 7, calls)
+}
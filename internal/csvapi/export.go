@@ -0,0 +1,117 @@
+package csvapi
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/httpserver"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+)
+
+// flushEvery is how many export rows are written between flushes to the
+// client.
+const flushEvery = 100
+
+// writeWindow is how long the export may take to reach its next flush. The
+// server's WriteTimeout would otherwise cut off any export that runs longer
+// than it, however steadily rows arrive, so the write deadline is pushed out
+// by this much at the start and after every flush, up to the request
+// context's deadline (see httpserver.ExtendWriteDeadline).
+const writeWindow = 30 * time.Second
+
+// ExportColumns is the header row of ExportHandler responses, named by their
+// aliases in the column whitelist.
+var ExportColumns = []string{"begin_time", "type", "magnitude", "state", "county", "lat", "lon"}
+
+// ReportStreamer yields every report matching a filter one at a time.
+type ReportStreamer interface {
+	StreamStormReports(ctx context.Context, filter *model.StormReportFilter, fn func(*model.StormReport) error) error
+}
+
+// ExportHandler serves GET /export.csv: every report matching the filter
+// query parameter (a URL-encoded JSON StormReportFilter, as for tiles) as a
+// CSV attachment, oldest first. Sorting and pagination fields are ignored.
+// Rows are written as the database cursor yields them, so the whole result is
+// never held in memory. A filter with no matches still gets the header row.
+// Once rows are written the status can no longer change, so a later failure
+// aborts the connection rather than leaving a silently truncated file. The row
+// cap (see WithMaxRows) ends the file early instead and announces it in the
+// rowcap.Header trailer. An export still running when the request context's
+// deadline passes is aborted the same way.
+func ExportHandler(s ReportStreamer, p apifilter.Preparer, opts ...Option) http.HandlerFunc {
+	o := newOptions(opts)
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := apifilter.FromQuery(r, p)
+		if err != nil {
+			apifilter.BadRequest(w, err)
+			return
+		}
+
+		rc := http.NewResponseController(w)
+		_ = httpserver.ExtendWriteDeadline(r.Context(), rc, writeWindow)
+		cw := csv.NewWriter(w)
+		written := 0
+		var limit *rowcap.Stream
+		start := func() {
+			limit = rowcap.NewStream(w, o.maxRows)
+			w.Header().Set("Content-Type", ContentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFilename(filter.TimeRange)))
+			_ = cw.Write(ExportColumns)
+		}
+		err = s.StreamStormReports(r.Context(), filter, func(rep *model.StormReport) error {
+			if written == 0 {
+				start()
+			}
+			if !limit.Next() {
+				return rowcap.ErrReached
+			}
+			if err := cw.Write(exportRow(rep)); err != nil {
+				return err
+			}
+			written++
+			if written%flushEvery == 0 {
+				cw.Flush()
+				_ = rc.Flush()
+				_ = httpserver.ExtendWriteDeadline(r.Context(), rc, writeWindow)
+			}
+			return cw.Error()
+		})
+		switch {
+		case err == nil, errors.Is(err, rowcap.ErrReached):
+			if written == 0 {
+				start()
+			}
+			cw.Flush()
+			limit.Finish()
+		case errors.Is(r.Context().Err(), context.Canceled):
+			// Client went away; nobody is left to tell.
+		case written == 0:
+			http.Error(w, "query failed", http.StatusInternalServerError)
+		default:
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// exportRow renders rep in ExportColumns order.
+func exportRow(rep *model.StormReport) []string {
+	row := make([]string, len(ExportColumns))
+	for i, c := range ExportColumns {
+		render, _ := renderer(c)
+		row[i] = render(rep)
+	}
+	return row
+}
+
+// exportFilename names the download after the dates the time range covers,
+// e.g. storm-reports-2024-04-26-to-2024-04-27.csv.
+func exportFilename(tr model.TimeRange) string {
+	const day = "2006-01-02"
+	return fmt.Sprintf("storm-reports-%s-to-%s.csv", tr.From.UTC().Format(day), tr.To.UTC().Format(day))
+}
@@ -0,0 +1,175 @@
+package csvapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/httpserver"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStreamer yields reports one at a time, then fails with err if set.
+type fakeStreamer struct {
+	reports []*model.StormReport
+	err     error
+	got     *model.StormReportFilter
+}
+
+func (f *fakeStreamer) StreamStormReports(_ context.Context, filter *model.StormReportFilter, fn func(*model.StormReport) error) error {
+	f.got = filter
+	for _, r := range f.reports {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func export(h http.Handler, filter string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/export.csv?filter="+url.QueryEscape(filter), nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExportHandler_StreamsRows(t *testing.T) {
+	var reports []*model.StormReport
+	for i := range flushEvery + 5 {
+		reports = append(reports, &model.StormReport{
+			EventType:   "hail",
+			EventTime:   time.Date(2024, 4, 26, 20, 0, i, 0, time.UTC),
+			Geo:         model.Geo{Lat: 35.2, Lon: -97.4},
+			Measurement: model.Measurement{Magnitude: 1.75},
+			Location:    model.Location{State: "OK", County: "Cleveland"},
+		})
+	}
+	s := &fakeStreamer{reports: reports}
+	rec := export(ExportHandler(s, &graph.Resolver{}), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="storm-reports-2024-04-26-to-2024-04-27.csv"`, rec.Header().Get("Content-Disposition"))
+	assert.True(t, rec.Flushed, "rows are flushed while streaming")
+
+	records := readCSV(t, rec)
+	require.Len(t, records, 1+len(reports))
+	assert.Equal(t, ExportColumns, records[0])
+	assert.Equal(t, []string{"2024-04-26T20:00:00Z", "hail", "1.75", "OK", "Cleveland", "35.2", "-97.4"}, records[1])
+	assert.Equal(t, "2024-04-26T20:01:44Z", records[len(records)-1][0])
+	assert.Equal(t, 2024, s.got.TimeRange.From.Year(), "filter prepared and passed through")
+}
+
+func TestExportHandler_NoResultsWritesHeader(t *testing.T) {
+	rec := export(ExportHandler(&fakeStreamer{}, &graph.Resolver{}), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, [][]string{ExportColumns}, readCSV(t, rec))
+}
+
+func TestExportHandler_InvalidFilter(t *testing.T) {
+	h := ExportHandler(&fakeStreamer{}, &graph.Resolver{})
+
+	rec := export(h, `{`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid filter")
+
+	rec = export(h, `{"timeRange":{"from":"2024-04-27T00:00:00Z","to":"2024-04-26T00:00:00Z"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "timeRange.to must be after timeRange.from")
+}
+
+func TestExportHandler_StoreErrorBeforeRows(t *testing.T) {
+	rec := export(ExportHandler(&fakeStreamer{err: errors.New("boom")}, &graph.Resolver{}), validBody)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "boom")
+}
+
+func TestExportHandler_StoreErrorMidStreamAborts(t *testing.T) {
+	s := &fakeStreamer{reports: []*model.StormReport{{EventType: "hail"}}, err: errors.New("boom")}
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		export(ExportHandler(s, &graph.Resolver{}), validBody)
+	})
+}
+
+func TestExportHandler_MaxRowsStopsStream(t *testing.T) {
+	reports := []*model.StormReport{{EventType: "hail"}, {EventType: "wind"}, {EventType: "tornado"}}
+	rec := export(ExportHandler(&fakeStreamer{reports: reports}, &graph.Resolver{}, WithMaxRows(2)), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	records := readCSV(t, rec)
+	require.Len(t, records, 3, "header plus two rows")
+	assert.Equal(t, "wind", records[2][1])
+	assert.Equal(t, "true", rec.Result().Trailer.Get(rowcap.Header))
+}
+
+func TestExportHandler_MaxRowsNotReached(t *testing.T) {
+	reports := []*model.StormReport{{EventType: "hail"}, {EventType: "wind"}}
+	rec := export(ExportHandler(&fakeStreamer{reports: reports}, &graph.Resolver{}, WithMaxRows(2)), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, readCSV(t, rec), 3)
+	assert.Empty(t, rec.Result().Trailer.Get(rowcap.Header))
+}
+
+// deadlineRecorder records the write deadlines set through
+// http.ResponseController.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+}
+
+func (d *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	d.deadlines = append(d.deadlines, t)
+	return nil
+}
+
+func TestExportHandler_ExtendsWriteDeadlinePerFlush(t *testing.T) {
+	reports := make([]*model.StormReport, 2*flushEvery)
+	for i := range reports {
+		reports[i] = &model.StormReport{EventType: "hail"}
+	}
+	req := httptest.NewRequest(http.MethodGet, "/export.csv?filter="+url.QueryEscape(validBody), nil)
+	rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+	ExportHandler(&fakeStreamer{reports: reports}, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, rec.deadlines, 3, "once up front, then after each flush")
+	assert.WithinDuration(t, time.Now().Add(writeWindow), rec.deadlines[2], 5*time.Second)
+}
+
+func TestExportHandler_WriteDeadlineCappedByContext(t *testing.T) {
+	reports := make([]*model.StormReport, flushEvery)
+	for i := range reports {
+		reports[i] = &model.StormReport{EventType: "hail"}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/export.csv?filter="+url.QueryEscape(validBody), nil)
+	rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+	ExportHandler(&fakeStreamer{reports: reports}, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	deadline, _ := ctx.Deadline()
+	require.Len(t, rec.deadlines, 2)
+	assert.Equal(t, deadline.Add(httpserver.FinishGrace), rec.deadlines[1])
+}
+
+func TestExportHandler_DeadlineMidStreamAborts(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	s := &fakeStreamer{reports: []*model.StormReport{{EventType: "hail"}}, err: context.DeadlineExceeded}
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/export.csv?filter="+url.QueryEscape(validBody), nil)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		ExportHandler(s, &graph.Resolver{}).ServeHTTP(httptest.NewRecorder(), req)
+	}, "a capped export is not passed off as complete")
+}
@@ -0,0 +1,185 @@
+// Package csvapi serves storm reports as CSV for spreadsheet and GIS tools,
+// with the column set and order chosen by the client.
+package csvapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+)
+
+// ContentType is the media type of CSV responses.
+const ContentType = "text/csv; charset=utf-8"
+
+// ReportLister lists filtered, paginated reports with the total count.
+type ReportLister interface {
+	ListStormReports(ctx context.Context, filter *model.StormReportFilter) ([]*model.StormReport, int, error)
+}
+
+// Option configures a handler.
+type Option func(*options)
+
+type options struct {
+	maxRows int
+}
+
+// WithMaxRows caps the reports written per response; see package rowcap.
+// Non-positive values select rowcap.DefaultMax.
+func WithMaxRows(n int) Option {
+	return func(o *options) { o.maxRows = n }
+}
+
+func newOptions(opts []Option) options {
+	o := options{maxRows: rowcap.DefaultMax}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// columns whitelists the exportable columns, named after their database
+// columns, with how each is rendered. Optional values render as "".
+var columns = map[string]func(r *model.StormReport) string{
+	"id":                    func(r *model.StormReport) string { return r.ID },
+	"event_type":            func(r *model.StormReport) string { return r.EventType },
+	"event_time":            func(r *model.StormReport) string { return formatTime(r.EventTime) },
+	"geo_lat":               func(r *model.StormReport) string { return formatFloat(r.Geo.Lat) },
+	"geo_lon":               func(r *model.StormReport) string { return formatFloat(r.Geo.Lon) },
+	"measurement_magnitude": func(r *model.StormReport) string { return formatFloat(r.Measurement.Magnitude) },
+	"measurement_unit":      func(r *model.StormReport) string { return r.Measurement.Unit },
+	"measurement_severity":  func(r *model.StormReport) string { return deref(r.Measurement.Severity) },
+	"measurement_method":    func(r *model.StormReport) string { return deref(r.Measurement.Method) },
+	"location_raw":          func(r *model.StormReport) string { return r.Location.Raw },
+	"location_name":         func(r *model.StormReport) string { return r.Location.Name },
+	"location_distance": func(r *model.StormReport) string {
+		if r.Location.Distance == nil {
+			return ""
+		}
+		return formatFloat(*r.Location.Distance)
+	},
+	"location_direction": func(r *model.StormReport) string { return deref(r.Location.Direction) },
+	"location_state":     func(r *model.StormReport) string { return r.Location.State },
+	"location_county":    func(r *model.StormReport) string { return r.Location.County },
+	"comments":           func(r *model.StormReport) string { return r.Comments },
+	"source_office":      func(r *model.StormReport) string { return r.SourceOffice },
+	"spotter_level":      func(r *model.StormReport) string { return deref(r.SpotterLevel) },
+	"time_bucket":        func(r *model.StormReport) string { return formatTime(r.TimeBucket) },
+	"processed_at":       func(r *model.StormReport) string { return formatTime(r.ProcessedAt) },
+}
+
+// aliases are short column names accepted alongside the database names, each
+// rendered like the column it maps to. ExportColumns uses them.
+var aliases = map[string]string{
+	"begin_time": "event_time",
+	"type":       "event_type",
+	"magnitude":  "measurement_magnitude",
+	"state":      "location_state",
+	"county":     "location_county",
+	"lat":        "geo_lat",
+	"lon":        "geo_lon",
+}
+
+// renderer returns how column c, a whitelisted name or alias, is rendered.
+func renderer(c string) (func(r *model.StormReport) string, bool) {
+	if name, ok := aliases[c]; ok {
+		c = name
+	}
+	render, ok := columns[c]
+	return render, ok
+}
+
+// DefaultColumns is the column order used when the request names none.
+var DefaultColumns = []string{
+	"id", "event_type", "event_time", "geo_lat", "geo_lon",
+	"measurement_magnitude", "measurement_unit", "measurement_severity",
+	"location_name", "location_state", "location_county", "comments",
+}
+
+// ParseColumns parses a comma-separated column list, keeping the given order.
+// An empty list selects DefaultColumns; unknown or repeated columns are errors.
+func ParseColumns(v string) ([]string, error) {
+	if strings.TrimSpace(v) == "" {
+		return DefaultColumns, nil
+	}
+	var out []string
+	seen := make(map[string]bool)
+	for _, c := range strings.Split(v, ",") {
+		c = strings.TrimSpace(c)
+		if _, ok := renderer(c); !ok {
+			return nil, fmt.Errorf("unknown column %q", c)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("duplicate column %q", c)
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// ReportsHandler accepts a JSON StormReportFilter (same shape as the GraphQL
+// input) and responds with the matching page of reports as CSV. The optional
+// columns query parameter selects and orders the columns, e.g.
+// ?columns=event_time,event_type,geo_lat,geo_lon. Rows past the cap (see
+// WithMaxRows) are dropped and rowcap.Header is set. Responses for closed time
+// windows carry an ETag and honor If-None-Match (see apifilter.WriteCacheable).
+func ReportsHandler(s ReportLister, p apifilter.Preparer, opts ...Option) http.HandlerFunc {
+	o := newOptions(opts)
+	return func(w http.ResponseWriter, r *http.Request) {
+		cols, err := ParseColumns(r.URL.Query().Get("columns"))
+		if err != nil {
+			http.Error(w, "invalid columns: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := apifilter.FromBody(w, r, p)
+		if err != nil {
+			apifilter.BadRequest(w, err)
+			return
+		}
+
+		reports, _, err := s.ListStormReports(r.Context(), filter)
+		if err != nil {
+			http.Error(w, "query failed", http.StatusInternalServerError)
+			return
+		}
+		reports = rowcap.Trim(w, reports, o.maxRows)
+
+		var buf bytes.Buffer
+		cw := csv.NewWriter(&buf)
+		_ = cw.Write(cols)
+		render := make([]func(*model.StormReport) string, len(cols))
+		for i, c := range cols {
+			render[i], _ = renderer(c)
+		}
+		row := make([]string, len(cols))
+		for _, rep := range reports {
+			for i, fn := range render {
+				row[i] = fn(rep)
+			}
+			_ = cw.Write(row)
+		}
+		cw.Flush()
+		apifilter.WriteCacheable(w, r, filter, ContentType, buf.Bytes())
+	}
+}
+
+func formatTime(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
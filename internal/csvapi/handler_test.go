@@ -0,0 +1,145 @@
+package csvapi
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	reports []*model.StormReport
+	err     error
+}
+
+func (f *fakeStore) ListStormReports(_ context.Context, _ *model.StormReportFilter) ([]*model.StormReport, int, error) {
+	return f.reports, len(f.reports), f.err
+}
+
+const validBody = `{"timeRange":{"from":"2024-04-26T00:00:00Z","to":"2024-04-27T00:00:00Z"}}`
+
+func serve(h http.Handler, query, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/reports.csv"+query, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func readCSV(t *testing.T, rec *httptest.ResponseRecorder) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestReportsHandler_RequestedColumnOrder(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{
+		{ID: "r1", EventType: "hail", EventTime: time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC), Geo: model.Geo{Lat: 35.2, Lon: -97.4}},
+		{ID: "r2", EventType: "wind", EventTime: time.Date(2024, 4, 26, 21, 30, 0, 0, time.UTC), Geo: model.Geo{Lat: 36.1, Lon: -96}},
+	}}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}), "?columns=geo_lon,event_time,id,event_type", validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, [][]string{
+		{"geo_lon", "event_time", "id", "event_type"},
+		{"-97.4", "2024-04-26T20:00:00Z", "r1", "hail"},
+		{"-96", "2024-04-26T21:30:00Z", "r2", "wind"},
+	}, readCSV(t, rec))
+}
+
+func TestReportsHandler_MaxRowsTruncates(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}}}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}, WithMaxRows(2)), "?columns=id", validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(rowcap.Header))
+	assert.Equal(t, [][]string{{"id"}, {"r1"}, {"r2"}}, readCSV(t, rec))
+}
+
+func TestReportsHandler_DefaultColumns(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}), "", validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, [][]string{DefaultColumns}, readCSV(t, rec))
+}
+
+func TestReportsHandler_UnknownColumn(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}), "?columns=id,password", validBody)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `unknown column "password"`)
+}
+
+func TestReportsHandler_StoreError(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{err: errors.New("boom")}, &graph.Resolver{}), "", validBody)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "boom")
+}
+
+func TestParseColumns(t *testing.T) {
+	cols, err := ParseColumns(" event_time , id ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"event_time", "id"}, cols)
+
+	_, err = ParseColumns("id,id")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `duplicate column "id"`)
+
+	_, err = ParseColumns("id,")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown column ""`)
+}
+
+func TestDefaultColumnsAreWhitelisted(t *testing.T) {
+	for _, c := range DefaultColumns {
+		assert.Contains(t, columns, c)
+	}
+}
+
+func TestExportColumnsAreAliases(t *testing.T) {
+	for _, c := range ExportColumns {
+		_, ok := renderer(c)
+		assert.True(t, ok, c)
+	}
+}
+
+func TestReportsHandler_Aliases(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{
+		{EventType: "hail", Geo: model.Geo{Lat: 35.2, Lon: -97.4}, Location: model.Location{State: "OK"}},
+	}}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}), "?columns=type,state,lat,geo_lat", validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, [][]string{
+		{"type", "state", "lat", "geo_lat"},
+		{"hail", "OK", "35.2", "35.2"},
+	}, readCSV(t, rec))
+}
+
+func TestReportsHandler_ETagNotModified(t *testing.T) {
+	h := ReportsHandler(&fakeStore{reports: []*model.StormReport{{ID: "r1"}}}, &graph.Resolver{})
+
+	first := serve(h, "?columns=id", validBody)
+	require.Equal(t, http.StatusOK, first.Code)
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag, "a closed window is cacheable")
+	assert.NotEqual(t, tag, serve(h, "?columns=id,event_type", validBody).Header().Get("ETag"), "columns change the tag")
+
+	req := httptest.NewRequest(http.MethodPost, "/reports.csv?columns=id", strings.NewReader(validBody))
+	req.Header.Set("If-None-Match", tag)
+	second := httptest.NewRecorder()
+	h.ServeHTTP(second, req)
+
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Zero(t, second.Body.Len())
+}
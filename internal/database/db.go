@@ -5,6 +5,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres" // register postgres driver for migrate
@@ -15,9 +16,58 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
+// PoolOption adjusts the pgx pool configuration before the pool is created.
+type PoolOption func(*pgxpool.Config)
+
+// WithMaxConns caps the number of open connections.
+func WithMaxConns(n int32) PoolOption {
+	return func(c *pgxpool.Config) { c.MaxConns = n }
+}
+
+// WithMinConns keeps at least n connections open, including idle ones.
+func WithMinConns(n int32) PoolOption {
+	return func(c *pgxpool.Config) { c.MinConns = n }
+}
+
+// WithMaxConnIdleTime closes connections idle for longer than d.
+func WithMaxConnIdleTime(d time.Duration) PoolOption {
+	return func(c *pgxpool.Config) { c.MaxConnIdleTime = d }
+}
+
+// WithMaxConnLifetime recycles connections older than d.
+func WithMaxConnLifetime(d time.Duration) PoolOption {
+	return func(c *pgxpool.Config) { c.MaxConnLifetime = d }
+}
+
+// poolConfig parses databaseURL and applies opts on top of the pgx defaults.
+func poolConfig(databaseURL string, opts ...PoolOption) (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.MaxConns < 1 {
+		return nil, errors.New("pool max connections must be at least 1")
+	}
+	if cfg.MinConns < 0 || cfg.MinConns > cfg.MaxConns {
+		return nil, fmt.Errorf("pool min connections must be 0-%d", cfg.MaxConns)
+	}
+	if cfg.MaxConnIdleTime <= 0 || cfg.MaxConnLifetime <= 0 {
+		return nil, errors.New("pool idle time and lifetime must be positive")
+	}
+	return cfg, nil
+}
+
 // NewPool creates a pgx connection pool and verifies connectivity with a ping.
-func NewPool(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(ctx, databaseURL)
+// Options override the pgx defaults (or pool_* parameters in the URL).
+func NewPool(ctx context.Context, databaseURL string, opts ...PoolOption) (*pgxpool.Pool, error) {
+	cfg, err := poolConfig(databaseURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("connect to database: %w", err)
 	}
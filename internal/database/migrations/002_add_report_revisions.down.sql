@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS storm_report_revisions;
+DROP INDEX IF EXISTS idx_updated_at;
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,16 @@
+-- Tracks when a report row last changed so clients can sync incrementally.
+ALTER TABLE storm_reports ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX idx_updated_at ON storm_reports (updated_at);
+
+-- One row per modification of an existing report, listing the columns that
+-- changed. Inserts are not recorded: a report created after a sync checkpoint
+-- is treated as entirely new.
+CREATE TABLE IF NOT EXISTS storm_report_revisions (
+    id                          BIGSERIAL PRIMARY KEY,
+    report_id                   TEXT NOT NULL REFERENCES storm_reports (id) ON DELETE CASCADE,
+    changed_columns             TEXT[] NOT NULL,
+    changed_at                  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_revisions_report_time ON storm_report_revisions (report_id, changed_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS populated_places;
@@ -0,0 +1,13 @@
+-- Reference table of towns and cities (e.g. loaded from the Census Gazetteer)
+-- used by the nearPopulatedPlace impact filter.
+CREATE TABLE IF NOT EXISTS populated_places (
+    id                          BIGSERIAL PRIMARY KEY,
+    name                        TEXT NOT NULL,
+    state                       TEXT NOT NULL,
+    lat                         DOUBLE PRECISION NOT NULL,
+    lon                         DOUBLE PRECISION NOT NULL,
+    population                  INTEGER NOT NULL
+);
+
+-- Population threshold first, then bounding-box pre-filter on coordinates
+CREATE INDEX idx_places_population_geo ON populated_places (population, lat, lon);
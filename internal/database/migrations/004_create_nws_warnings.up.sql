@@ -0,0 +1,16 @@
+-- NWS watch/warning polygons, used to correlate reports with the products that
+-- were in effect where and when they occurred. Uses PostgreSQL's built-in
+-- polygon type (no PostGIS); vertices are (lon, lat) points.
+CREATE TABLE IF NOT EXISTS nws_warnings (
+    id                          TEXT PRIMARY KEY,
+    warning_type                TEXT NOT NULL,
+    issued_at                   TIMESTAMPTZ NOT NULL,
+    expires_at                  TIMESTAMPTZ NOT NULL,
+    area                        POLYGON NOT NULL
+);
+
+-- Temporal overlap lookups by product type
+CREATE INDEX idx_warnings_type_time ON nws_warnings (warning_type, issued_at, expires_at);
+
+-- Point-in-polygon containment
+CREATE INDEX idx_warnings_area ON nws_warnings USING GIST (area);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_spotter_level;
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS spotter_level;
@@ -0,0 +1,5 @@
+-- Training level of the reporting source (e.g. "trained spotter", "public"),
+-- NULL when the upstream report does not carry it.
+ALTER TABLE storm_reports ADD COLUMN spotter_level TEXT;
+
+CREATE INDEX idx_spotter_level ON storm_reports (spotter_level);
@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS solar_elevation(DOUBLE PRECISION, DOUBLE PRECISION, TIMESTAMPTZ);
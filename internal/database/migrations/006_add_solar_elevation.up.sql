@@ -0,0 +1,29 @@
+-- Sun altitude in degrees at (lat, lon) and ts, using the low-precision almanac
+-- formulas. Mirrors solarref.Elevation in internal/integration/solarref; keep
+-- the two in sync.
+-- IMMUTABLE so it can be used in indexes and planner-evaluated predicates.
+CREATE OR REPLACE FUNCTION solar_elevation(lat DOUBLE PRECISION, lon DOUBLE PRECISION, ts TIMESTAMPTZ)
+RETURNS DOUBLE PRECISION
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    WITH j AS (
+        -- Days since 2000-01-01T12:00:00Z
+        SELECT (EXTRACT(EPOCH FROM ts)::DOUBLE PRECISION - 946728000) / 86400 AS d
+    ), ecl AS (
+        SELECT d,
+               23.439 - 0.00000036 * d AS e,
+               280.459 + 0.98564736 * d
+                 + 1.915 * sind(357.529 + 0.98560028 * d)
+                 + 0.020 * sind(2 * (357.529 + 0.98560028 * d)) AS l
+        FROM j
+    ), eq AS (
+        SELECT d,
+               atan2d(cosd(e) * sind(l), cosd(l)) AS ra,
+               asind(sind(e) * sind(l)) AS dec
+        FROM ecl
+    )
+    -- Clamped: rounding can push the sine just past ±1 with the sun overhead.
+    SELECT asind(LEAST(1, GREATEST(-1,
+                 sind(lat) * sind(dec)
+                 + cosd(lat) * cosd(dec) * cosd(280.46061837 + 360.98564736629 * d + lon - ra))))
+    FROM eq
+$$;
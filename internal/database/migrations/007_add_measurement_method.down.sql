@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_measurement_method;
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS measurement_method;
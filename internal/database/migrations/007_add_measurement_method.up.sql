@@ -0,0 +1,5 @@
+-- How the magnitude was obtained: "measured" (instrument, e.g. anemometer or
+-- ruler) or "estimated". NULL when the upstream report does not say.
+ALTER TABLE storm_reports ADD COLUMN measurement_method TEXT;
+
+CREATE INDEX idx_measurement_method ON storm_reports (measurement_method);
@@ -0,0 +1,3 @@
+-- Full-text index for keywordSearch on comments. The expression must match
+-- the one in the query builder exactly for the planner to use it.
+CREATE INDEX idx_comments_fts ON storm_reports USING GIN (to_tsvector('english', comments));
@@ -0,0 +1,2 @@
+-- Supports timeColumn: PROCESSED_AT range filters and the default sort.
+CREATE INDEX idx_processed_at ON storm_reports (processed_at);
@@ -0,0 +1,2 @@
+-- Supports placeName reverse geocoding: bounding box on coordinates alone.
+CREATE INDEX idx_places_geo ON populated_places (lat, lon);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_correction_status;
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS correction_status;
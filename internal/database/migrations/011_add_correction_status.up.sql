@@ -0,0 +1,7 @@
+-- NWS correction vintage for the correctionStatus filter. Ingested reports
+-- are originals; corrections are applied to existing rows out of band.
+ALTER TABLE storm_reports
+    ADD COLUMN correction_status TEXT NOT NULL DEFAULT 'original'
+    CHECK (correction_status IN ('original', 'corrected', 'deleted-supersede'));
+
+CREATE INDEX idx_correction_status ON storm_reports (correction_status);
@@ -0,0 +1,7 @@
+-- Durable delta-sync position per client, so a client that reconnects
+-- resumes from the last updatedAfter checkpoint it acknowledged.
+CREATE TABLE IF NOT EXISTS sync_checkpoints (
+    client_id                   TEXT PRIMARY KEY,
+    checkpoint                  TIMESTAMPTZ NOT NULL,
+    saved_at                    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
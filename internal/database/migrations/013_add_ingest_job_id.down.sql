@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_revisions_ingest_job;
+DROP INDEX IF EXISTS idx_ingest_job;
+ALTER TABLE storm_report_revisions DROP COLUMN IF EXISTS ingest_job_id;
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS ingest_job_id;
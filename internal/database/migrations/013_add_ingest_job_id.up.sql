@@ -0,0 +1,8 @@
+-- Ingest job that created a report, and the job behind each revision, for
+-- data-governance audits. Set by batch loaders and correction jobs; reports
+-- streamed from Kafka carry no job id and stay NULL.
+ALTER TABLE storm_reports ADD COLUMN ingest_job_id TEXT;
+ALTER TABLE storm_report_revisions ADD COLUMN ingest_job_id TEXT;
+
+CREATE INDEX idx_ingest_job ON storm_reports (ingest_job_id) WHERE ingest_job_id IS NOT NULL;
+CREATE INDEX idx_revisions_ingest_job ON storm_report_revisions (ingest_job_id) WHERE ingest_job_id IS NOT NULL;
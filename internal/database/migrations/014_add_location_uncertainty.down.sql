@@ -0,0 +1 @@
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS location_uncertainty_m;
@@ -0,0 +1,6 @@
+-- Radius, in meters, within which a report's true location lies. Set out of
+-- band by geocoding QA for reports whose coordinates were estimated from a
+-- place name; reports streamed from Kafka carry none and stay NULL.
+ALTER TABLE storm_reports
+    ADD COLUMN location_uncertainty_m DOUBLE PRECISION
+    CHECK (location_uncertainty_m >= 0);
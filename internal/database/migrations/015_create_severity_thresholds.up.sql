@@ -0,0 +1,16 @@
+-- Per-type magnitude thresholds of the severity categories, loaded once at
+-- startup. Update the rows and restart to retune them without a rebuild; the
+-- service refuses to start unless every event type has a row.
+CREATE TABLE IF NOT EXISTS severity_thresholds (
+    event_type                  TEXT PRIMARY KEY,
+    moderate                    DOUBLE PRECISION NOT NULL,
+    severe                      DOUBLE PRECISION NOT NULL,
+    extreme                     DOUBLE PRECISION NOT NULL,
+    CHECK (0 < moderate AND moderate < severe AND severe < extreme)
+);
+
+INSERT INTO severity_thresholds (event_type, moderate, severe, extreme) VALUES
+    ('hail', 0.75, 1.5, 2.5),
+    ('wind', 50, 74, 96),
+    ('tornado', 2, 3, 5)
+ON CONFLICT (event_type) DO NOTHING;
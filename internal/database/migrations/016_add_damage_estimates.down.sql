@@ -0,0 +1,3 @@
+ALTER TABLE storm_reports
+    DROP COLUMN IF EXISTS damage_crops_usd,
+    DROP COLUMN IF EXISTS damage_property_usd;
@@ -0,0 +1,6 @@
+-- Estimated property and crop damage, in US dollars. Local storm reports carry
+-- no damage figures; these are backfilled out of band from the NCEI Storm
+-- Events database. Reports streamed from Kafka stay NULL (unknown).
+ALTER TABLE storm_reports
+    ADD COLUMN damage_property_usd DOUBLE PRECISION CHECK (damage_property_usd >= 0),
+    ADD COLUMN damage_crops_usd    DOUBLE PRECISION CHECK (damage_crops_usd >= 0);
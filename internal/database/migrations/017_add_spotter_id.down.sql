@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_spotter_id;
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS spotter_id;
@@ -0,0 +1,6 @@
+-- Stable identifier of the individual spotter who filed the report (e.g. a
+-- SKYWARN call sign). Set out of band from spotter network rosters; reports
+-- streamed from Kafka carry none and stay NULL.
+ALTER TABLE storm_reports ADD COLUMN spotter_id TEXT;
+
+CREATE INDEX idx_spotter_id ON storm_reports (spotter_id) WHERE spotter_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Reference table of US counties and county equivalents with their 5-digit
+-- FIPS codes (e.g. loaded from the Census Gazetteer), used by the county
+-- field. Keyed the way reports name them: state abbreviation and county name.
+CREATE TABLE IF NOT EXISTS counties (
+    state                       TEXT NOT NULL,
+    name                        TEXT NOT NULL,
+    fips_code                   TEXT NOT NULL,
+    PRIMARY KEY (state, name)
+);
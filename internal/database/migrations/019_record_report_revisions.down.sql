@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_storm_report_revision ON storm_reports;
+DROP FUNCTION IF EXISTS record_storm_report_revision();
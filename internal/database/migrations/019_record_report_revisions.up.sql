@@ -0,0 +1,30 @@
+-- Records every change to an existing report, whoever makes it: bumps
+-- updated_at and appends the changed columns to storm_report_revisions, so
+-- delta sync sees the update. Bookkeeping columns are not changes in their
+-- own right, so an UPDATE touching only them (e.g. rewriting updated_at) is
+-- left as written. A writer attributes its revisions to an ingest job with
+-- set_config('storm.ingest_job_id', <job id>, true).
+CREATE OR REPLACE FUNCTION record_storm_report_revision() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+    changed TEXT[];
+BEGIN
+    SELECT array_agg(n.key ORDER BY n.key) INTO changed
+    FROM jsonb_each(to_jsonb(NEW)) AS n
+    JOIN jsonb_each(to_jsonb(OLD)) AS o ON o.key = n.key
+    WHERE n.value IS DISTINCT FROM o.value
+      AND n.key NOT IN ('updated_at', 'created_at', 'ingest_job_id');
+    IF changed IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    NEW.updated_at := NOW();
+    INSERT INTO storm_report_revisions (report_id, changed_columns, changed_at, ingest_job_id)
+    VALUES (NEW.id, changed, NEW.updated_at, NULLIF(current_setting('storm.ingest_job_id', true), ''));
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER trg_storm_report_revision
+    BEFORE UPDATE ON storm_reports
+    FOR EACH ROW EXECUTE FUNCTION record_storm_report_revision();
@@ -0,0 +1,7 @@
+ALTER TABLE storm_reports DISABLE TRIGGER trg_storm_report_revision;
+
+UPDATE storm_reports SET event_type = raw_event_type;
+
+ALTER TABLE storm_reports ENABLE TRIGGER trg_storm_report_revision;
+
+ALTER TABLE storm_reports DROP COLUMN IF EXISTS raw_event_type;
@@ -0,0 +1,18 @@
+-- Stores each report's event type as its canonical label (hail, wind,
+-- tornado) and keeps the upstream code in raw_event_type, so filters,
+-- aggregates, and every output format agree on the label. The label map
+-- lives in the server (model.DefaultEventTypeLabels plus EVENT_TYPE_LABELS),
+-- which relabels existing rows at startup via Store.RelabelEventTypes; this
+-- migration only records their current code. The revision trigger is
+-- disabled for the backfill because it adds a column rather than edits rows.
+ALTER TABLE storm_reports ADD COLUMN IF NOT EXISTS raw_event_type TEXT;
+
+ALTER TABLE storm_reports DISABLE TRIGGER trg_storm_report_revision;
+
+UPDATE storm_reports
+SET raw_event_type = event_type
+WHERE raw_event_type IS NULL;
+
+ALTER TABLE storm_reports ENABLE TRIGGER trg_storm_report_revision;
+
+ALTER TABLE storm_reports ALTER COLUMN raw_event_type SET NOT NULL;
@@ -2,21 +2,44 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// DefaultPingTimeout bounds how long a readiness ping may take before the
+// database is reported as not ready.
+const DefaultPingTimeout = 2 * time.Second
+
+// pinger is the subset of *pgxpool.Pool used by PoolReadiness.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
 // PoolReadiness wraps a pgxpool.Pool and implements observability.ReadinessChecker.
 type PoolReadiness struct {
-	pool *pgxpool.Pool
+	pool    pinger
+	timeout time.Duration
 }
 
 // NewPoolReadiness returns a readiness checker backed by the given pool.
 func NewPoolReadiness(pool *pgxpool.Pool) *PoolReadiness {
-	return &PoolReadiness{pool: pool}
+	return &PoolReadiness{pool: pool, timeout: DefaultPingTimeout}
 }
 
-// CheckReadiness pings the database to verify connectivity.
+// CheckReadiness pings the database to verify connectivity. The ping is
+// bounded by the checker's timeout as well as ctx, so a hung connection
+// flips readiness instead of stalling the probe.
 func (p *PoolReadiness) CheckReadiness(ctx context.Context) error {
-	return p.pool.Ping(ctx)
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if err := p.pool.Ping(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("database ping timed out after %s", p.timeout)
+		}
+		return fmt.Errorf("database ping: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePool returns err from Ping, or blocks until the context ends when
+// hang is set.
+type fakePool struct {
+	err  error
+	hang bool
+}
+
+func (f *fakePool) Ping(ctx context.Context) error {
+	if f.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return f.err
+}
+
+func probe(t *testing.T, pool pinger) (int, map[string]string) {
+	t.Helper()
+	checker := &PoolReadiness{pool: pool, timeout: 20 * time.Millisecond}
+	rec := httptest.NewRecorder()
+	observability.ReadinessHandler(checker).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec.Code, body
+}
+
+func TestPoolReadiness_Ready(t *testing.T) {
+	code, body := probe(t, &fakePool{})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body["status"])
+}
+
+func TestPoolReadiness_PingError(t *testing.T) {
+	code, body := probe(t, &fakePool{err: errors.New("connection refused")})
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not ready", body["status"])
+	assert.Equal(t, "database ping: connection refused", body["error"])
+}
+
+func TestPoolReadiness_Timeout(t *testing.T) {
+	code, body := probe(t, &fakePool{hang: true})
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not ready", body["status"])
+	assert.Equal(t, "database ping timed out after 20ms", body["error"])
+}
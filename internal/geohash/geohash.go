@@ -0,0 +1,57 @@
+// Package geohash decodes geohash strings into the latitude/longitude cell
+// they name. Each character adds five bits, alternating longitude and
+// latitude, so longer hashes name smaller cells.
+package geohash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxPrecision is the longest geohash accepted: 12 characters is a cell of a
+// few centimeters, past the precision of any report location.
+const MaxPrecision = 12
+
+const alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Box is the cell a geohash names, in decimal degrees.
+type Box struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// Decode returns the cell for hash. Hashes are case-insensitive and must be
+// 1 to MaxPrecision characters of the geohash alphabet.
+func Decode(hash string) (Box, error) {
+	if len(hash) == 0 || len(hash) > MaxPrecision {
+		return Box{}, fmt.Errorf("geohash must be 1 to %d characters", MaxPrecision)
+	}
+	b := Box{MinLat: -90, MaxLat: 90, MinLon: -180, MaxLon: 180}
+	even := true // even bits refine longitude
+	for _, c := range strings.ToLower(hash) {
+		v := strings.IndexRune(alphabet, c)
+		if v < 0 {
+			return Box{}, fmt.Errorf("geohash contains invalid character %q", c)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			on := v&(1<<bit) != 0
+			if even {
+				mid := (b.MinLon + b.MaxLon) / 2
+				if on {
+					b.MinLon = mid
+				} else {
+					b.MaxLon = mid
+				}
+			} else {
+				mid := (b.MinLat + b.MaxLat) / 2
+				if on {
+					b.MinLat = mid
+				} else {
+					b.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return b, nil
+}
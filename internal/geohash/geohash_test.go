@@ -0,0 +1,41 @@
+package geohash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode_KnownCells(t *testing.T) {
+	// First character: an eighth of the globe by longitude, a quarter by latitude.
+	b, err := Decode("9")
+	require.NoError(t, err)
+	assert.Equal(t, Box{MinLat: 0, MaxLat: 45, MinLon: -135, MaxLon: -90}, b)
+
+	// San Francisco at precision 5 (~4.9 km × 4.9 km).
+	b, err = Decode("9q8yy")
+	require.NoError(t, err)
+	assert.Equal(t, Box{MinLat: 37.7490234375, MaxLat: 37.79296875, MinLon: -122.431640625, MaxLon: -122.3876953125}, b)
+
+	upper, err := Decode("9Q8YY")
+	require.NoError(t, err)
+	assert.Equal(t, b, upper, "case-insensitive")
+}
+
+func TestDecode_PrecisionShrinksCell(t *testing.T) {
+	coarse, err := Decode("9q8")
+	require.NoError(t, err)
+	fine, err := Decode("9q8yyk")
+	require.NoError(t, err)
+	assert.Less(t, fine.MaxLat-fine.MinLat, coarse.MaxLat-coarse.MinLat)
+	assert.GreaterOrEqual(t, fine.MinLat, coarse.MinLat)
+	assert.LessOrEqual(t, fine.MaxLon, coarse.MaxLon)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, h := range []string{"", "9q8a", "9q8yyyyyyyyyy", "9q8i"} {
+		_, err := Decode(h)
+		assert.Error(t, err, h)
+	}
+}
@@ -0,0 +1,166 @@
+// Package geojsonapi serves storm reports as a GeoJSON FeatureCollection
+// that map libraries such as Leaflet and Mapbox can load directly.
+package geojsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+)
+
+// ContentType is the media type of GeoJSON responses (RFC 7946).
+const ContentType = "application/geo+json"
+
+// ReportLister lists filtered, paginated reports with the total count.
+type ReportLister interface {
+	ListStormReports(ctx context.Context, filter *model.StormReportFilter) ([]*model.StormReport, int, error)
+}
+
+// Option configures a handler.
+type Option func(*options)
+
+type options struct {
+	maxRows int
+}
+
+// WithMaxRows caps the reports written per response; see package rowcap.
+// Non-positive values select rowcap.DefaultMax.
+func WithMaxRows(n int) Option {
+	return func(o *options) { o.maxRows = n }
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection of report points. The
+// paging members are RFC 7946 foreign members, named as in the protobuf
+// StormReportConnection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+
+	// TotalCount is the number of reports matching the filter, ignoring
+	// pagination; HasMore reports whether any follow this page.
+	TotalCount int  `json:"total_count"`
+	HasMore    bool `json:"has_more"`
+	// Dropped counts the reports on this page left out for lack of usable
+	// coordinates, so features plus dropped is the page size.
+	Dropped int `json:"dropped"`
+}
+
+// Feature is a single report as a GeoJSON Point feature.
+type Feature struct {
+	Type       string     `json:"type"`
+	ID         string     `json:"id"`
+	Geometry   Point      `json:"geometry"`
+	Properties Properties `json:"properties"`
+}
+
+// Point is a GeoJSON Point. Coordinates are [longitude, latitude].
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// Properties are the report attributes carried on each feature.
+type Properties struct {
+	Type      string    `json:"type"`
+	Magnitude float64   `json:"magnitude"`
+	Unit      string    `json:"unit"`
+	BeginTime time.Time `json:"begin_time"`
+	State     string    `json:"state"`
+	County    string    `json:"county"`
+}
+
+// NewFeatureCollection converts reports to features in order. Reports without
+// usable coordinates are omitted rather than emitted with null geometry, and
+// counted in Dropped. totalCount and hasMore describe the whole result.
+func NewFeatureCollection(reports []*model.StormReport, totalCount int, hasMore bool) FeatureCollection {
+	fc := FeatureCollection{
+		Type:       "FeatureCollection",
+		Features:   make([]Feature, 0, len(reports)),
+		TotalCount: totalCount,
+		HasMore:    hasMore,
+	}
+	for _, r := range reports {
+		if !hasCoordinates(r.Geo) {
+			fc.Dropped++
+			continue
+		}
+		fc.Features = append(fc.Features, Feature{
+			Type:     "Feature",
+			ID:       r.ID,
+			Geometry: Point{Type: "Point", Coordinates: [2]float64{r.Geo.Lon, r.Geo.Lat}},
+			Properties: Properties{
+				Type:      r.EventType,
+				Magnitude: r.Measurement.Magnitude,
+				Unit:      r.Measurement.Unit,
+				BeginTime: r.EventTime.UTC(),
+				State:     r.Location.State,
+				County:    r.Location.County,
+			},
+		})
+	}
+	return fc
+}
+
+// hasCoordinates reports whether g is a plottable position. The geo columns
+// are NOT NULL, so upstream rows without a position arrive as (0, 0); that
+// point and non-finite or out-of-range values count as missing.
+func hasCoordinates(g model.Geo) bool {
+	if math.IsNaN(g.Lat) || math.IsNaN(g.Lon) || math.IsInf(g.Lat, 0) || math.IsInf(g.Lon, 0) {
+		return false
+	}
+	if g.Lat == 0 && g.Lon == 0 {
+		return false
+	}
+	return g.Lat >= -90 && g.Lat <= 90 && g.Lon >= -180 && g.Lon <= 180
+}
+
+// ReportsHandler accepts a JSON StormReportFilter (same shape as the GraphQL
+// input) and responds with the matching page of reports as a GeoJSON
+// FeatureCollection, with coordinates and magnitudes rounded as the filter's
+// coordinatePrecision and magnitudePrecision ask, like the GraphQL query.
+// Reports past the cap (see WithMaxRows) are dropped and rowcap.Header is
+// set. Responses for closed time windows carry an ETag and honor If-None-Match
+// (see apifilter.WriteCacheable).
+func ReportsHandler(s ReportLister, p apifilter.Preparer, opts ...Option) http.HandlerFunc {
+	o := options{maxRows: rowcap.DefaultMax}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := apifilter.FromBody(w, r, p)
+		if err != nil {
+			apifilter.BadRequest(w, err)
+			return
+		}
+
+		reports, total, err := s.ListStormReports(r.Context(), filter)
+		if err != nil {
+			http.Error(w, "query failed", http.StatusInternalServerError)
+			return
+		}
+		reports = rowcap.Trim(w, reports, o.maxRows)
+
+		offset := 0
+		if filter.Offset != nil {
+			offset = *filter.Offset
+		}
+		hasMore := offset+len(reports) < total
+		reports = model.RoundCoordinates(reports, *filter.CoordinatePrecision)
+		if filter.MagnitudePrecision != nil {
+			reports = model.RoundMagnitudes(reports, *filter.MagnitudePrecision)
+		}
+
+		body, err := json.Marshal(NewFeatureCollection(reports, total, hasMore))
+		if err != nil {
+			http.Error(w, "encode failed", http.StatusInternalServerError)
+			return
+		}
+		apifilter.WriteCacheable(w, r, filter, ContentType, append(body, '\n'))
+	}
+}
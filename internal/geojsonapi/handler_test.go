@@ -0,0 +1,181 @@
+package geojsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	reports []*model.StormReport
+	total   int // 0 means len(reports)
+	err     error
+}
+
+func (f *fakeStore) ListStormReports(_ context.Context, _ *model.StormReportFilter) ([]*model.StormReport, int, error) {
+	if f.total == 0 {
+		return f.reports, len(f.reports), f.err
+	}
+	return f.reports, f.total, f.err
+}
+
+const validBody = `{"timeRange":{"from":"2024-04-26T00:00:00Z","to":"2024-04-27T00:00:00Z"}}`
+
+func serve(h http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/reports.geojson", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReportsHandler_FeatureCollection(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{{
+		ID:          "r1",
+		EventType:   "hail",
+		EventTime:   time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC),
+		Geo:         model.Geo{Lat: 35.2, Lon: -97.4},
+		Measurement: model.Measurement{Magnitude: 1.75, Unit: "in"},
+		Location:    model.Location{State: "OK", County: "Cleveland"},
+	}}}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"id": "r1",
+			"geometry": {"type": "Point", "coordinates": [-97.4, 35.2]},
+			"properties": {
+				"type": "hail",
+				"magnitude": 1.75,
+				"unit": "in",
+				"begin_time": "2024-04-26T20:00:00Z",
+				"state": "OK",
+				"county": "Cleveland"
+			}
+		}],
+		"total_count": 1,
+		"has_more": false,
+		"dropped": 0
+	}`, rec.Body.String())
+}
+
+func TestReportsHandler_EmptyFeatures(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"type":"FeatureCollection","features":[],"total_count":0,"has_more":false,"dropped":0}`, rec.Body.String())
+}
+
+func TestReportsHandler_MaxRowsTruncates(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{
+		{ID: "r1", Geo: model.Geo{Lat: 35, Lon: -97}},
+		{ID: "r2", Geo: model.Geo{Lat: 35, Lon: -97}},
+		{ID: "r3", Geo: model.Geo{Lat: 35, Lon: -97}},
+	}}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}, WithMaxRows(2)), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(rowcap.Header))
+	var fc FeatureCollection
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+	assert.Len(t, fc.Features, 2)
+}
+
+func TestReportsHandler_InvalidFilter(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}), `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}), `not json`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid filter")
+}
+
+func TestReportsHandler_StoreError(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{err: errors.New("boom")}, &graph.Resolver{}), validBody)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "boom")
+}
+
+func TestNewFeatureCollection_OmitsMissingCoordinates(t *testing.T) {
+	fc := NewFeatureCollection([]*model.StormReport{
+		{ID: "zero", Geo: model.Geo{}},
+		{ID: "nan", Geo: model.Geo{Lat: math.NaN(), Lon: -97}},
+		{ID: "range", Geo: model.Geo{Lat: 95, Lon: -97}},
+		{ID: "ok", Geo: model.Geo{Lat: 0, Lon: -97}},
+	}, 4, false)
+
+	require.Len(t, fc.Features, 1)
+	assert.Equal(t, "ok", fc.Features[0].ID)
+	assert.Equal(t, 3, fc.Dropped)
+	_, err := json.Marshal(fc)
+	require.NoError(t, err)
+}
+
+func TestReportsHandler_Precision(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{{
+		ID:          "r1",
+		EventType:   "hail",
+		Geo:         model.Geo{Lat: 35.123456, Lon: -97.987654},
+		Measurement: model.Measurement{Magnitude: 1.756},
+	}}}
+	body := `{"timeRange":{"from":"2024-04-26T00:00:00Z","to":"2024-04-27T00:00:00Z"},` +
+		`"coordinatePrecision":2,"magnitudePrecision":{"hail":1}}`
+	rec := serve(ReportsHandler(s, &graph.Resolver{}), body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var fc FeatureCollection
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+	require.Len(t, fc.Features, 1)
+	assert.Equal(t, [2]float64{-97.99, 35.12}, fc.Features[0].Geometry.Coordinates)
+	assert.InDelta(t, 1.8, fc.Features[0].Properties.Magnitude, 1e-9)
+	assert.InDelta(t, 35.123456, s.reports[0].Geo.Lat, 1e-12, "store results are not modified")
+}
+
+func TestReportsHandler_PagingMetadata(t *testing.T) {
+	s := &fakeStore{total: 30, reports: []*model.StormReport{
+		{ID: "r1", Geo: model.Geo{Lat: 35, Lon: -97}},
+		{ID: "r2"}, // (0, 0): no position
+	}}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var fc FeatureCollection
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+	assert.Len(t, fc.Features, 1)
+	assert.Equal(t, 30, fc.TotalCount)
+	assert.True(t, fc.HasMore)
+	assert.Equal(t, 1, fc.Dropped)
+}
+
+func TestReportsHandler_ETagNotModified(t *testing.T) {
+	h := ReportsHandler(&fakeStore{reports: []*model.StormReport{{ID: "r1", Geo: model.Geo{Lat: 35.2, Lon: -97.4}}}}, &graph.Resolver{})
+
+	first := serve(h, validBody)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, ContentType, first.Header().Get("Content-Type"))
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag, "a closed window is cacheable")
+
+	req := httptest.NewRequest(http.MethodPost, "/reports.geojson", strings.NewReader(validBody))
+	req.Header.Set("If-None-Match", tag)
+	second := httptest.NewRecorder()
+	h.ServeHTTP(second, req)
+
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Zero(t, second.Body.Len())
+}
@@ -0,0 +1,23 @@
+package graph
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/store"
+)
+
+// QueryBudgetLimit attaches a fresh store.QueryBudget to every request, so all
+// resolvers serving it draw on one shared allowance of queries and DB time.
+// With both limits zero it is a no-op.
+func QueryBudgetLimit(maxQueries int, maxDBTime time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxQueries == 0 && maxDBTime == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b := &store.QueryBudget{MaxQueries: maxQueries, MaxDBTime: maxDBTime}
+			next.ServeHTTP(w, r.WithContext(store.WithQueryBudget(r.Context(), b)))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBudgetLimit_AttachesFreshBudget(t *testing.T) {
+	var budgets []*store.QueryBudget
+	handler := QueryBudgetLimit(3, time.Second)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		budgets = append(budgets, store.QueryBudgetFromContext(r.Context()))
+	}))
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+	}
+
+	require.Len(t, budgets, 2)
+	require.NotNil(t, budgets[0])
+	assert.Equal(t, 3, budgets[0].MaxQueries)
+	assert.Equal(t, time.Second, budgets[0].MaxDBTime)
+	assert.NotSame(t, budgets[0], budgets[1])
+}
+
+func TestQueryBudgetLimit_DisabledWhenZero(t *testing.T) {
+	handler := QueryBudgetLimit(0, 0)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, store.QueryBudgetFromContext(r.Context()))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+}
@@ -3,10 +3,20 @@ package graph
 import "github.com/couchcryptid/storm-data-api/internal/model"
 
 // NewComplexityRoot returns complexity estimators for expensive fields.
-// gqlgen computes total query complexity bottom-up and rejects queries exceeding
-// the budget (600). Multipliers estimate the maximum number of child items each
-// field can return:
-//   - Reports: up to MaxPageSize (20) items per query
+// ComplexityLimit computes total query complexity bottom-up and rejects queries
+// exceeding the budget (QUERY_MAX_COMPLEXITY, 600 by default). Multipliers
+// estimate the maximum number of child items each field can return:
+//   - Reports/Deltas: up to MaxPageSize (20) items per query; ComplexityLimit
+//     rescales them to the page size the filter requests
+//   - WarningLeadTimes/WarningsWithoutReports: up to MaxLeadTimeWarnings (50) warnings per query
+//   - MagnitudePercentiles/StormReportCountsByType: one row per event type (3)
+//   - SeverityDistribution: one row per severity category (4)
+//   - NearbyReports: up to MaxPageSize (20) neighbours per anchor
+//   - CoverageGaps: up to MaxCoverageBuckets (200) empty buckets
+//   - DiurnalCycle: one bucket per hour of the day (24)
+//   - DamageTotals: up to MaxDamageBuckets (100) buckets
+//   - ReportHistogram: up to MaxHistogramBuckets (200) intervals
+//   - ReportRate intervals: up to MaxRateIntervals (24) per window
 //   - ByEventType/ByState/ByHour: up to 10 groups each
 //   - Counties: up to 5 per state
 //
@@ -20,23 +30,73 @@ import "github.com/couchcryptid/storm-data-api/internal/model"
 func NewComplexityRoot() ComplexityRoot {
 	return ComplexityRoot{
 		Query: struct {
-			StormReports func(childComplexity int, filter model.StormReportFilter) int
+			CoverageGaps            func(childComplexity int, filter model.StormReportFilter, bucketMinutes int) int
+			DamageTotals            func(childComplexity int, filter model.StormReportFilter, bucket model.BucketUnit) int
+			DataRange               func(childComplexity int, eventTypes []model.EventType, states []string) int
+			DiurnalCycle            func(childComplexity int, filter model.StormReportFilter, timezone string) int
+			MagnitudePercentiles    func(childComplexity int, filter model.StormReportFilter, percentile float64) int
+			NearbyReports           func(childComplexity int, id string, windowHours int, limit int) int
+			ReportHistogram         func(childComplexity int, filter model.StormReportFilter, interval model.BucketUnit) int
+			ReportRate              func(childComplexity int, filter model.StormReportFilter, windowMinutes int, intervals int) int
+			SeverityDistribution    func(childComplexity int, filter model.StormReportFilter) int
+			SpotterRank             func(childComplexity int, filter model.StormReportFilter, spotterID string) int
+			StormReportCountsByType func(childComplexity int, filter model.StormReportFilter) int
+			StormReports            func(childComplexity int, filter model.StormReportFilter) int
+			WarningLeadTimes        func(childComplexity int, timeRange model.TimeRange, types []string) int
+			WarningsWithoutReports  func(childComplexity int, timeRange model.TimeRange, types []string) int
 		}{
+			CoverageGaps: func(childComplexity int, _ model.StormReportFilter, _ int) int {
+				return MaxCoverageBuckets * childComplexity
+			},
+			DamageTotals: func(childComplexity int, _ model.StormReportFilter, _ model.BucketUnit) int {
+				return MaxDamageBuckets * childComplexity
+			},
+			DiurnalCycle: func(childComplexity int, _ model.StormReportFilter, _ string) int {
+				return 24 * childComplexity
+			},
+			MagnitudePercentiles: func(childComplexity int, _ model.StormReportFilter, _ float64) int {
+				return 3 * childComplexity
+			},
+			NearbyReports: func(childComplexity int, _ string, _ int, _ int) int {
+				return MaxPageSize * childComplexity
+			},
+			ReportHistogram: func(childComplexity int, _ model.StormReportFilter, _ model.BucketUnit) int {
+				return MaxHistogramBuckets * childComplexity
+			},
+			SeverityDistribution: func(childComplexity int, _ model.StormReportFilter) int {
+				return 4 * childComplexity
+			},
+			StormReportCountsByType: func(childComplexity int, _ model.StormReportFilter) int {
+				return 3 * childComplexity
+			},
 			StormReports: func(childComplexity int, _ model.StormReportFilter) int {
 				return 1 + childComplexity
 			},
+			WarningLeadTimes: func(childComplexity int, _ model.TimeRange, _ []string) int {
+				return MaxLeadTimeWarnings * childComplexity
+			},
+			WarningsWithoutReports: func(childComplexity int, _ model.TimeRange, _ []string) int {
+				return MaxLeadTimeWarnings * childComplexity
+			},
 		},
 
 		StormReportsResult: struct {
 			Aggregations func(childComplexity int) int
+			ConvexHull   func(childComplexity int) int
+			Deltas       func(childComplexity int) int
 			HasMore      func(childComplexity int) int
 			Meta         func(childComplexity int) int
+			NextCursor   func(childComplexity int) int
+			PageInfo     func(childComplexity int) int
 			Reports      func(childComplexity int) int
 			TotalCount   func(childComplexity int) int
 		}{
 			Reports: func(childComplexity int) int {
 				return MaxPageSize * childComplexity
 			},
+			Deltas: func(childComplexity int) int {
+				return MaxPageSize * childComplexity
+			},
 		},
 
 		StormAggregations: struct {
@@ -56,6 +116,18 @@ func NewComplexityRoot() ComplexityRoot {
 			},
 		},
 
+		ReportRate: struct {
+			Count       func(childComplexity int) int
+			Intervals   func(childComplexity int) int
+			PerHour     func(childComplexity int) int
+			WindowEnd   func(childComplexity int) int
+			WindowStart func(childComplexity int) int
+		}{
+			Intervals: func(childComplexity int) int {
+				return MaxRateIntervals * childComplexity
+			},
+		},
+
 		StateGroup: struct {
 			Count    func(childComplexity int) int
 			Counties func(childComplexity int) int
@@ -14,11 +14,74 @@ func TestNewComplexityRoot_QueryStormReports(t *testing.T) {
 	assert.Equal(t, 1, c.Query.StormReports(0, model.StormReportFilter{}))
 }
 
+func TestNewComplexityRoot_WarningLeadTimesMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// MaxLeadTimeWarnings × child
+	assert.Equal(t, MaxLeadTimeWarnings*6, c.Query.WarningLeadTimes(6, model.TimeRange{}, nil))
+	assert.Equal(t, MaxLeadTimeWarnings*4, c.Query.WarningsWithoutReports(4, model.TimeRange{}, nil))
+}
+
+func TestNewComplexityRoot_CoverageGapsMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// MaxCoverageBuckets × child
+	assert.Equal(t, MaxCoverageBuckets*2, c.Query.CoverageGaps(2, model.StormReportFilter{}, 60))
+}
+
+func TestNewComplexityRoot_DamageTotalsMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// MaxDamageBuckets × child
+	assert.Equal(t, MaxDamageBuckets*3, c.Query.DamageTotals(3, model.StormReportFilter{}, model.BucketUnitDay))
+}
+
+func TestNewComplexityRoot_ReportHistogramMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// MaxHistogramBuckets × child
+	assert.Equal(t, MaxHistogramBuckets*2, c.Query.ReportHistogram(2, model.StormReportFilter{}, model.BucketUnitDay))
+}
+
+func TestNewComplexityRoot_DiurnalCycleMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// one bucket per hour of the day
+	assert.Equal(t, 48, c.Query.DiurnalCycle(2, model.StormReportFilter{}, "UTC"))
+}
+
+func TestNewComplexityRoot_NearbyReportsMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// MaxPageSize × child
+	assert.Equal(t, MaxPageSize*23, c.Query.NearbyReports(23, "r1", 24, 10))
+}
+
+func TestNewComplexityRoot_MagnitudePercentilesMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// one row per event type
+	assert.Equal(t, 15, c.Query.MagnitudePercentiles(5, model.StormReportFilter{}, 0.9))
+}
+
+func TestNewComplexityRoot_StormReportCountsByTypeMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// one row per event type
+	assert.Equal(t, 6, c.Query.StormReportCountsByType(2, model.StormReportFilter{}))
+}
+
+func TestNewComplexityRoot_SeverityDistributionMultiplier(t *testing.T) {
+	c := NewComplexityRoot()
+	// one row per severity category
+	assert.Equal(t, 8, c.Query.SeverityDistribution(2, model.StormReportFilter{}))
+}
+
+func TestNewComplexityRoot_ReportRateIntervals(t *testing.T) {
+	c := NewComplexityRoot()
+	// MaxRateIntervals × child
+	assert.Equal(t, MaxRateIntervals*4, c.ReportRate.Intervals(4))
+	assert.Nil(t, c.Query.ReportRate, "default 1 + child")
+}
+
 func TestNewComplexityRoot_ReportsMultiplier(t *testing.T) {
 	c := NewComplexityRoot()
 	// MaxPageSize × child
 	assert.Equal(t, MaxPageSize*16, c.StormReportsResult.Reports(16))
 	assert.Equal(t, 0, c.StormReportsResult.Reports(0))
+	assert.Equal(t, MaxPageSize*3, c.StormReportsResult.Deltas(3))
 }
 
 func TestNewComplexityRoot_AggregationMultipliers(t *testing.T) {
@@ -0,0 +1,169 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ComplexityLimit rejects operations whose estimated cost exceeds
+// MaxComplexity before any resolver runs. Fields are priced like gqlgen's
+// extension.ComplexityLimit, from the schema's ComplexityRoot, except that
+// the reports and deltas lists of stormReports are priced at the page size
+// the filter requests (first or limit) instead of MaxPageSize, so small pages
+// leave room for more of the rest of the query.
+type ComplexityLimit struct {
+	MaxComplexity int
+
+	es graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &ComplexityLimit{}
+
+// ExtensionName implements graphql.HandlerExtension. It matches gqlgen's, so
+// extension.GetComplexityStats reports this limit's figures.
+func (c *ComplexityLimit) ExtensionName() string {
+	return "ComplexityLimit"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (c *ComplexityLimit) Validate(es graphql.ExecutableSchema) error {
+	if c.MaxComplexity < 1 {
+		return fmt.Errorf("ComplexityLimit: MaxComplexity must be >= 1")
+	}
+	c.es = es
+	return nil
+}
+
+// MutateOperationContext implements graphql.OperationContextMutator.
+func (c *ComplexityLimit) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	op := oc.Doc.Operations.ForName(oc.OperationName)
+	w := complexityWalker{es: c.es, schema: c.es.Schema(), vars: oc.Variables}
+	cost := w.selectionSet(ctx, op.SelectionSet, 0)
+
+	oc.Stats.SetExtension(c.ExtensionName(), &extension.ComplexityStats{
+		Complexity:      cost,
+		ComplexityLimit: c.MaxComplexity,
+	})
+	if cost > c.MaxComplexity {
+		err := gqlerror.Errorf("operation has complexity %d, which exceeds the limit of %d", cost, c.MaxComplexity)
+		errcode.Set(err, "COMPLEXITY_LIMIT_EXCEEDED")
+		return err
+	}
+	return nil
+}
+
+// complexityWalker sums field costs bottom-up over an operation.
+type complexityWalker struct {
+	es     graphql.ExecutableSchema
+	schema *ast.Schema
+	vars   map[string]any
+}
+
+// selectionSet returns the cost of selSet. pageSize is the page requested by
+// the enclosing stormReports field, or 0 outside one.
+func (w complexityWalker) selectionSet(ctx context.Context, selSet ast.SelectionSet, pageSize int) int {
+	total := 0
+	for _, sel := range selSet {
+		switch s := sel.(type) {
+		case *ast.Field:
+			total = saturatingAdd(total, w.field(ctx, s, pageSize))
+		case *ast.FragmentSpread:
+			total = saturatingAdd(total, w.selectionSet(ctx, s.Definition.SelectionSet, pageSize))
+		case *ast.InlineFragment:
+			total = saturatingAdd(total, w.selectionSet(ctx, s.SelectionSet, pageSize))
+		}
+	}
+	return total
+}
+
+func (w complexityWalker) field(ctx context.Context, f *ast.Field, pageSize int) int {
+	def := w.schema.Types[f.Definition.Type.Name()]
+	if def.Name == "__Schema" {
+		return 0
+	}
+	parent := f.ObjectDefinition.Name
+	args := f.ArgumentMap(w.vars)
+	if parent == "Query" && f.Name == "stormReports" {
+		pageSize = requestedPageSize(args)
+	}
+
+	child := 0
+	switch def.Kind {
+	case ast.Object, ast.Interface, ast.Union:
+		child = w.selectionSet(ctx, f.SelectionSet, pageSize)
+	}
+
+	cost, ok := w.es.Complexity(ctx, parent, f.Name, child, args)
+	if !ok || cost < 1 {
+		return saturatingAdd(1, child)
+	}
+	// ComplexityRoot prices these lists at MaxPageSize items.
+	if parent == "StormReportsResult" && (f.Name == "reports" || f.Name == "deltas") && pageSize > 0 {
+		cost = saturatingMul(cost, pageSize) / MaxPageSize
+	}
+	return cost
+}
+
+// requestedPageSize returns the page size a stormReports filter argument
+// asks for: first or limit, capped at MaxPageSize, or MaxPageSize when
+// neither is set. Out-of-range values are rejected later by ValidateFilter.
+func requestedPageSize(args map[string]any) int {
+	filter, _ := args["filter"].(map[string]any)
+	for _, key := range []string{"first", "limit"} {
+		n, ok := intArg(filter[key])
+		if !ok {
+			continue
+		}
+		if n < 1 || n > MaxPageSize {
+			return MaxPageSize
+		}
+		return int(n)
+	}
+	return MaxPageSize
+}
+
+// intArg converts a literal or variable Int argument value.
+func intArg(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// saturatingAdd adds non-negative costs, capping at the maximum int so a
+// crafted query cannot overflow past the limit.
+func saturatingAdd(a, b int) int {
+	if c := a + b; c >= a {
+		return c
+	}
+	return int(^uint(0) >> 1)
+}
+
+// saturatingMul multiplies non-negative costs, capping at the maximum int.
+func saturatingMul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	if maxInt := int(^uint(0) >> 1); a > maxInt/b {
+		return maxInt
+	}
+	return a * b
+}
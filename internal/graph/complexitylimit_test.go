@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const reportsQuery = `query($first: Int) {
+	stormReports(filter: { timeRange: { from: "2024-04-26T00:00:00Z", to: "2024-04-27T00:00:00Z" }, first: $first }) {
+		totalCount
+		reports { id eventType geo { lat lon } }
+	}
+}`
+
+// operationComplexity runs ComplexityLimit over query and returns the cost it
+// computed and the error it reported.
+func operationComplexity(t *testing.T, limit int, query string, vars map[string]any) (int, *gqlerror.Error) {
+	t.Helper()
+	es := NewExecutableSchema(Config{Resolvers: &Resolver{}, Complexity: NewComplexityRoot()})
+	doc, errs := gqlparser.LoadQuery(es.Schema(), query)
+	require.Empty(t, errs)
+
+	l := &ComplexityLimit{MaxComplexity: limit}
+	require.NoError(t, l.Validate(es))
+	oc := &graphql.OperationContext{Doc: doc, Variables: vars}
+	err := l.MutateOperationContext(context.Background(), oc)
+	stats, ok := oc.Stats.GetExtension("ComplexityLimit").(*extension.ComplexityStats)
+	require.True(t, ok)
+	assert.Equal(t, limit, stats.ComplexityLimit)
+	return stats.Complexity, err
+}
+
+func TestComplexityLimit_ScalesWithPageSize(t *testing.T) {
+	// stormReports(1) + totalCount(1) + reports(n × (id + eventType + geo(1+2)) = n × 5)
+	full, err := operationComplexity(t, 600, reportsQuery, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2+MaxPageSize*5, full, "no first/limit prices a full page")
+
+	small, err := operationComplexity(t, 600, reportsQuery, map[string]any{"first": json.Number("2")})
+	assert.Nil(t, err)
+	assert.Equal(t, 2+2*5, small)
+
+	literalQuery := strings.NewReplacer("query($first: Int)", "query", "first: $first", "limit: 4").Replace(reportsQuery)
+	literal, err := operationComplexity(t, 600, literalQuery, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2+4*5, literal)
+
+	over, err := operationComplexity(t, 600, reportsQuery, map[string]any{"first": 500})
+	assert.Nil(t, err)
+	assert.Equal(t, full, over, "oversized pages are priced at MaxPageSize")
+}
+
+func TestSaturatingMul(t *testing.T) {
+	maxInt := int(^uint(0) >> 1)
+	assert.Equal(t, 12, saturatingMul(3, 4))
+	assert.Equal(t, 0, saturatingMul(0, maxInt))
+	assert.Equal(t, maxInt, saturatingMul(maxInt/2+1, 2))
+	assert.Equal(t, maxInt, saturatingMul(maxInt, maxInt))
+
+	// Scaling multiplies before dividing, so a cost that is not a multiple of
+	// MaxPageSize keeps its remainder.
+	assert.Equal(t, 5, saturatingMul(25, 4)/MaxPageSize)
+}
+
+func TestComplexityLimit_RejectsOverBudget(t *testing.T) {
+	_, err := operationComplexity(t, 50, reportsQuery, nil)
+	require.NotNil(t, err)
+	assert.Equal(t, "operation has complexity 102, which exceeds the limit of 50", err.Message)
+	assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", err.Extensions["code"])
+
+	_, err = operationComplexity(t, 50, reportsQuery, map[string]any{"first": 5})
+	assert.Nil(t, err, "a smaller page fits the same budget")
+}
+
+func TestComplexityLimitValidate(t *testing.T) {
+	require.Error(t, (&ComplexityLimit{}).Validate(nil))
+}
+
+// limitedServer serves the schema with a nil store behind the depth and
+// complexity limits, counting the fields that reach a resolver.
+func limitedServer(t *testing.T, maxDepth, maxComplexity int) (*httptest.Server, *int) {
+	t.Helper()
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{}, Complexity: NewComplexityRoot()}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(&ComplexityLimit{MaxComplexity: maxComplexity})
+	srv.Use(DepthLimit{MaxDepth: maxDepth})
+	resolved := 0
+	srv.AroundFields(func(ctx context.Context, next graphql.Resolver) (any, error) {
+		resolved++
+		return next(ctx)
+	})
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts, &resolved
+}
+
+func postQuery(t *testing.T, url, query string) []string {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	resp, err := http.Post(url, "application/json", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	msgs := make([]string, len(result.Errors))
+	for i, e := range result.Errors {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+func TestLimits_RejectBeforeResolvers(t *testing.T) {
+	ts, resolved := limitedServer(t, 3, 600)
+
+	// Depth 4: stormReports > reports > geo > lat
+	errs := postQuery(t, ts.URL, `{ stormReports(filter: { timeRange: { from: "2024-04-26T00:00:00Z", to: "2024-04-27T00:00:00Z" } }) { reports { geo { lat } } } }`)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "query depth 4 exceeds maximum allowed depth of 3")
+	assert.Zero(t, *resolved, "no resolver ran, so the store was not queried")
+
+	ts, resolved = limitedServer(t, 7, 50)
+	errs = postQuery(t, ts.URL, strings.NewReplacer("query($first: Int)", "query", ", first: $first", "").Replace(reportsQuery))
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "exceeds the limit of 50")
+	assert.Zero(t, *resolved)
+}
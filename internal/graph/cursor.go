@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+)
+
+// endCursor returns the token for resuming after the last of reports, or nil
+// when the page is empty, was fetched by offset, or is in an ordering keyset
+// pagination does not support. A scan keeps the snapshot time of its first
+// page.
+func endCursor(filter *model.StormReportFilter, reports []*model.StormReport, stats store.PageStats) (*string, error) {
+	if len(reports) == 0 || filter.Offset != nil || !store.KeysetSortable(filter) {
+		return nil, nil
+	}
+	asOf := stats.QueriedAt
+	if filter.Cursor != nil {
+		asOf = filter.Cursor.AsOf
+	}
+	token, err := store.EncodeCursor(filter, reports[len(reports)-1], asOf)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
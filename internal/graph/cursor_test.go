@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndCursor(t *testing.T) {
+	queried := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+	page := []*model.StormReport{{ID: "r-1"}, {ID: "r-2"}}
+	stats := store.PageStats{TotalCount: 5, Remaining: 5, QueriedAt: queried}
+
+	t.Run("first page pins the snapshot", func(t *testing.T) {
+		f := validFilter()
+		token, err := endCursor(f, page, stats)
+		require.NoError(t, err)
+		require.NotNil(t, token)
+
+		c, err := store.DecodeCursor(f, *token)
+		require.NoError(t, err)
+		assert.Equal(t, "r-2", c.ID, "cursor resumes after the last report")
+		assert.True(t, queried.Equal(c.AsOf))
+	})
+
+	t.Run("later pages keep the first page's snapshot", func(t *testing.T) {
+		asOf := queried.Add(-time.Hour)
+		f := validFilter()
+		f.Cursor = &model.PageCursor{AsOf: asOf, SortKey: time.Time{}, ID: "r-0"}
+		token, err := endCursor(f, page, stats)
+		require.NoError(t, err)
+
+		c, err := store.DecodeCursor(f, *token)
+		require.NoError(t, err)
+		assert.True(t, asOf.Equal(c.AsOf))
+	})
+
+	t.Run("none without a keyset position", func(t *testing.T) {
+		token, err := endCursor(validFilter(), nil, stats)
+		require.NoError(t, err)
+		assert.Nil(t, token, "empty page")
+
+		f := validFilter()
+		offset := 0
+		f.Offset = &offset
+		token, err = endCursor(f, page, stats)
+		require.NoError(t, err)
+		assert.Nil(t, token, "offset pagination")
+
+		f = validFilter()
+		normalized := model.SortFieldMagnitudeNormalized
+		f.SortBy = &normalized
+		token, err = endCursor(f, page, stats)
+		require.NoError(t, err)
+		assert.Nil(t, token, "MAGNITUDE_NORMALIZED")
+	})
+}
@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// ReportEnricher attaches derived data, such as custom categories or external
+// lookups, to reports after they are fetched and before they are serialized.
+// It runs with the request context, so it sees the request's deadline and
+// values. Reports passed in are per-request copies and may be modified; an
+// error fails the query.
+type ReportEnricher interface {
+	EnrichReports(ctx context.Context, reports []*model.StormReport) error
+}
+
+// enrich runs the configured enricher, if any, over reports.
+func (r *Resolver) enrich(ctx context.Context, reports []*model.StormReport) error {
+	if r.Enricher == nil || len(reports) == 0 {
+		return nil
+	}
+	if err := r.Enricher.EnrichReports(ctx, reports); err != nil {
+		return fmt.Errorf("enrich reports: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+// sizeClassEnricher tags each report with a category derived from its
+// magnitude, and records the tenant it saw on the context.
+type sizeClassEnricher struct {
+	tenant any
+	err    error
+}
+
+func (e *sizeClassEnricher) EnrichReports(ctx context.Context, reports []*model.StormReport) error {
+	e.tenant = ctx.Value(ctxKey{})
+	if e.err != nil {
+		return e.err
+	}
+	for _, rep := range reports {
+		class := "small"
+		if rep.Measurement.Magnitude >= 2 {
+			class = "large"
+		}
+		rep.Attributes = append(rep.Attributes, &model.Attribute{Name: "sizeClass", Value: class})
+	}
+	return nil
+}
+
+func TestEnrich_AddsDerivedField(t *testing.T) {
+	e := &sizeClassEnricher{}
+	r := &Resolver{Enricher: e}
+	reports := []*model.StormReport{
+		{ID: "a", Measurement: model.Measurement{Magnitude: 1.0}},
+		{ID: "b", Measurement: model.Measurement{Magnitude: 2.75}},
+	}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "acme")
+
+	require.NoError(t, r.enrich(ctx, reports))
+
+	assert.Equal(t, "acme", e.tenant, "enricher sees the request context")
+	for i, want := range []string{"small", "large"} {
+		require.Len(t, reports[i].Attributes, 1)
+		assert.Equal(t, model.Attribute{Name: "sizeClass", Value: want}, *reports[i].Attributes[0])
+	}
+}
+
+func TestEnrich_Error(t *testing.T) {
+	r := &Resolver{Enricher: &sizeClassEnricher{err: errors.New("lookup down")}}
+
+	err := r.enrich(context.Background(), []*model.StormReport{{ID: "a"}})
+
+	require.Error(t, err)
+	assert.Equal(t, "enrich reports: lookup down", err.Error())
+}
+
+func TestEnrich_NoEnricher(t *testing.T) {
+	rep := &model.StormReport{ID: "a"}
+	require.NoError(t, (&Resolver{}).enrich(context.Background(), []*model.StormReport{rep}))
+	assert.Nil(t, rep.Attributes)
+}
+
+func TestEnrich_RoundedCopiesLeaveSourceUntouched(t *testing.T) {
+	cached := &model.StormReport{ID: "a", Measurement: model.Measurement{Magnitude: 3}}
+	page := model.RoundCoordinates([]*model.StormReport{cached}, DefaultCoordinatePrecision)
+
+	require.NoError(t, (&Resolver{Enricher: &sizeClassEnricher{}}).enrich(context.Background(), page))
+
+	assert.Len(t, page[0].Attributes, 1)
+	assert.Nil(t, cached.Attributes, "cached report must not be modified")
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter extends gqlgen's default presenter so a read cut short by
+// the store's query timeout reaches the client as a QUERY_TIMEOUT error it can
+// act on (by narrowing the filter or retrying), rather than as an opaque
+// internal failure. A mutation refused for want of an admin key is coded
+// UNAUTHORIZED.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	switch {
+	case errors.Is(err, store.ErrQueryTimeout):
+		gqlErr.Message = "query timed out; narrow the filter or try again"
+		errcode.Set(gqlErr, "QUERY_TIMEOUT")
+	case errors.Is(err, errUnauthorized):
+		errcode.Set(gqlErr, "UNAUTHORIZED")
+	}
+	return gqlErr
+}
+
+// errUnauthorized rejects mutations from requests without a valid admin key.
+var errUnauthorized = errors.New("unauthorized: mutations need a valid X-Admin-Key")
+
+// patchError gives a PatchStormReport failure the code matching the status
+// admin.PatchReportHandler sends for it: a stale expectedUpdatedAt and a lock
+// timeout are both CONFLICT, the first with the report's current updatedAt.
+// Other errors are returned unchanged.
+func patchError(ctx context.Context, err error, updatedAt time.Time) error {
+	gqlErr := gqlerror.WrapPath(graphql.GetPath(ctx), err)
+	var lockErr *store.LockTimeoutError
+	switch {
+	case errors.Is(err, store.ErrEmptyPatch):
+		errcode.Set(gqlErr, "BAD_USER_INPUT")
+	case errors.Is(err, store.ErrReportNotFound):
+		errcode.Set(gqlErr, "NOT_FOUND")
+	case errors.Is(err, store.ErrVersionConflict):
+		errcode.Set(gqlErr, "CONFLICT")
+		gqlErr.Extensions["updatedAt"] = updatedAt.UTC().Format(time.RFC3339Nano)
+	case errors.As(err, &lockErr):
+		errcode.Set(gqlErr, "CONFLICT")
+	default:
+		return err
+	}
+	return gqlErr
+}
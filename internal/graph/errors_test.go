@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/couchcryptid/storm-data-api/internal/admin"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorPresenter_QueryTimeout(t *testing.T) {
+	err := fmt.Errorf("list storm reports: %w", fmt.Errorf("%w: %w", store.ErrQueryTimeout, context.DeadlineExceeded))
+	gqlErr := ErrorPresenter(context.Background(), err)
+	assert.Equal(t, "QUERY_TIMEOUT", gqlErr.Extensions["code"])
+	assert.Contains(t, gqlErr.Message, "query timed out")
+}
+
+func TestErrorPresenter_OtherErrors(t *testing.T) {
+	gqlErr := ErrorPresenter(context.Background(), errors.New("query storm reports: connection reset"))
+	assert.Nil(t, gqlErr.Extensions["code"])
+	assert.Equal(t, "query storm reports: connection reset", gqlErr.Message)
+}
+
+func TestPatchError(t *testing.T) {
+	updatedAt := time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		err  error
+		code string
+	}{
+		{store.ErrEmptyPatch, "BAD_USER_INPUT"},
+		{store.ErrReportNotFound, "NOT_FOUND"},
+		{store.ErrVersionConflict, "CONFLICT"},
+		{&store.LockTimeoutError{ID: "r1", Timeout: time.Second}, "CONFLICT"},
+	} {
+		t.Run(tc.code, func(t *testing.T) {
+			var gqlErr *gqlerror.Error
+			require.ErrorAs(t, patchError(context.Background(), tc.err, updatedAt), &gqlErr)
+			assert.Equal(t, tc.code, gqlErr.Extensions["code"])
+			assert.Equal(t, tc.err.Error(), gqlErr.Message)
+		})
+	}
+
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, patchError(context.Background(), store.ErrVersionConflict, updatedAt), &gqlErr)
+	assert.Equal(t, "2024-04-26T20:00:00Z", gqlErr.Extensions["updatedAt"], "a stale version reports the current one")
+
+	other := errors.New("patch storm report: connection reset")
+	assert.Equal(t, other, patchError(context.Background(), other, updatedAt))
+}
+
+// postMutation sends query to the schema with a nil store, behind
+// admin.IdentifyKey("secret"), and returns the response's errors.
+func postMutation(t *testing.T, key, query string) []*gqlerror.Error {
+	t.Helper()
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{}, Complexity: NewComplexityRoot()}))
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(ErrorPresenter)
+
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/query", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(admin.HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	admin.IdentifyKey("secret")(srv).ServeHTTP(rec, req)
+
+	var result struct {
+		Errors []*gqlerror.Error `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	return result.Errors
+}
+
+func TestPatchStormReport_RequiresAdminKey(t *testing.T) {
+	const mutation = `mutation { patchStormReport(id: "r1", patch: { expectedUpdatedAt: "2024-04-26T20:00:00Z", magnitude: 2.0 }) { id updatedAt } }`
+	for _, key := range []string{"", "wrong"} {
+		errs := postMutation(t, key, mutation)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "UNAUTHORIZED", errs[0].Extensions["code"])
+	}
+}
+
+func TestPatchStormReport_ValidatesPatch(t *testing.T) {
+	errs := postMutation(t, "secret", `mutation { patchStormReport(id: "r1", patch: { expectedUpdatedAt: "2024-04-26T20:00:00Z", lat: 91 }) { id } }`)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "lat out of range", errs[0].Message, "rejected before the store is reached")
+}
@@ -24,6 +24,14 @@ func collectFields(ctx context.Context) map[string]bool {
 	return fields
 }
 
+// countOnly reports whether a stormReports selection needs only counts
+// (totalCount, hasMore, aggregations.totalCount), so a plain COUNT(*) can
+// stand in for fetching the page. A keyset page still needs its rows: what
+// remains after the cursor is counted by the page query.
+func countOnly(fields map[string]bool, filter *model.StormReportFilter) bool {
+	return !fields["reports"] && !fields["nextCursor"] && !fields["pageInfo"] && filter.Cursor == nil
+}
+
 // applyMeta fetches and assigns lastUpdated and dataLagMinutes to the QueryMeta.
 func applyMeta(ctx context.Context, s *store.Store, meta *model.QueryMeta) error {
 	lastUpdated, err := s.LastUpdated(ctx)
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountOnly(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		want   bool
+	}{
+		{"totalCount alone", []string{"totalCount"}, true},
+		{"counts and aggregations", []string{"totalCount", "hasMore", "aggregations", "aggregations.byState"}, true},
+		{"reports selected", []string{"totalCount", "reports"}, false},
+		{"nextCursor needs the last row", []string{"totalCount", "nextCursor"}, false},
+		{"pageInfo needs the last row", []string{"pageInfo"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := map[string]bool{}
+			for _, f := range tt.fields {
+				fields[f] = true
+			}
+			assert.Equal(t, tt.want, countOnly(fields, validFilter()))
+		})
+	}
+
+	t.Run("keyset page", func(t *testing.T) {
+		f := validFilter()
+		f.Cursor = &model.PageCursor{AsOf: time.Now(), ID: "r-1"}
+		assert.False(t, countOnly(map[string]bool{"totalCount": true}, f))
+	})
+}
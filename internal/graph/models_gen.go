@@ -2,5 +2,20 @@
 
 package graph
 
+import (
+	"time"
+)
+
+type Mutation struct {
+}
+
 type Query struct {
 }
+
+// Result of patchStormReport.
+type ReportPatchResult struct {
+	// ID of the patched report.
+	ID string `json:"id"`
+	// The report's new updatedAt, to send as expectedUpdatedAt on the next patch.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"net/http"
+
+	"github.com/couchcryptid/storm-data-api/internal/store"
+)
+
+// PlaceLookupCache attaches a fresh store.PlaceCache to every request, so
+// placeName lookups for reports sharing coordinates hit the database once.
+func PlaceLookupCache() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(store.WithPlaceCache(r.Context(), store.NewPlaceCache())))
+		})
+	}
+}
+
+// CountyLookupLoader attaches a fresh store.CountyLoader to every request, so
+// county lookups for the reports in one response are batched into a single
+// query instead of one per report.
+func CountyLookupLoader(s *store.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(store.WithCountyLoader(r.Context(), store.NewCountyLoader(s.CountiesByKey))))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceLookupCache_FreshCachePerRequest(t *testing.T) {
+	var caches []*store.PlaceCache
+	handler := PlaceLookupCache()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		caches = append(caches, store.PlaceCacheFromContext(r.Context()))
+	}))
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+	}
+
+	require.Len(t, caches, 2)
+	require.NotNil(t, caches[0])
+	assert.NotSame(t, caches[0], caches[1])
+}
+
+func TestCountyLookupLoader_FreshLoaderPerRequest(t *testing.T) {
+	var loaders []*store.CountyLoader
+	handler := CountyLookupLoader(nil)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		loaders = append(loaders, store.CountyLoaderFromContext(r.Context()))
+	}))
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+	}
+
+	require.Len(t, loaders, 2)
+	require.NotNil(t, loaders[0])
+	assert.NotSame(t, loaders[0], loaders[1])
+}
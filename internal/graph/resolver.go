@@ -1,10 +1,105 @@
 package graph
 
-import "github.com/couchcryptid/storm-data-api/internal/store"
+import (
+	"log/slog"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+)
 
 //go:generate go run github.com/99designs/gqlgen generate
 
 // Resolver is the root resolver for the GraphQL schema.
 type Resolver struct {
 	Store *store.Store
+
+	// DefaultWindow is applied as the trailing time range (ending now) when a
+	// filter has no timeRange. Zero rejects such filters instead.
+	DefaultWindow time.Duration
+
+	// MaxTimeRange caps the span of a filter's time range, so a mistaken
+	// open-ended window cannot scan the whole table. Zero disables the cap.
+	MaxTimeRange time.Duration
+
+	// TimeRounding widens timeRange bounds to this granularity before the
+	// query is built. Zero disables rounding.
+	TimeRounding time.Duration
+
+	// AllowFutureReports disables the default exclusion of reports whose
+	// event time is in the future (an operator override for bad-data triage).
+	AllowFutureReports bool
+
+	// GeoConflictMode decides how filters setting both near and bbox are
+	// handled. The zero value rejects them.
+	GeoConflictMode GeoConflictMode
+
+	// SeverityWeights drives severityScore and SEVERITY_SCORE sorting. Zero
+	// weights mean model.DefaultSeverityWeights; the reference magnitudes
+	// come from the loaded severity thresholds.
+	SeverityWeights model.SeverityWeights
+
+	// Enricher, if set, attaches derived data to fetched reports before they
+	// are serialized.
+	Enricher ReportEnricher
+
+	// Metrics and Logger, if set, record requests whose limit exceeds
+	// MaxPageSize, to show whether the cap is too low for clients.
+	Metrics *observability.Metrics
+	Logger  *slog.Logger
+}
+
+// severityWeights returns the configured weights, or the default weights
+// with the configured reference magnitudes.
+func (r *Resolver) severityWeights() model.SeverityWeights {
+	w := r.SeverityWeights
+	if w.IsZero() {
+		w.Hail, w.Wind, w.Tornado = model.DefaultSeverityWeights.Hail, model.DefaultSeverityWeights.Wind, model.DefaultSeverityWeights.Tornado
+	}
+	return w
+}
+
+// PrepareFilter validates the filter, applies defaults and limits, and applies
+// server-side query policy (default window, time rounding, future-report
+// exclusion). Shared by
+// every API surface so they enforce identical rules.
+func (r *Resolver) PrepareFilter(filter *model.StormReportFilter) error {
+	if err := ApplyDefaultWindow(filter, r.DefaultWindow, time.Now()); err != nil {
+		return err
+	}
+	r.observeLimit(filter)
+	if err := ValidateFilter(filter); err != nil {
+		return err
+	}
+	if err := filter.Validate(r.MaxTimeRange); err != nil {
+		return err
+	}
+	if err := ResolveGeoConflict(filter, r.GeoConflictMode); err != nil {
+		return err
+	}
+	RoundTimeRange(&filter.TimeRange, r.TimeRounding)
+	excludeFuture := !r.AllowFutureReports
+	filter.ExcludeFuture = &excludeFuture
+	weights := r.severityWeights()
+	filter.SeverityWeights = &weights
+	return nil
+}
+
+// observeLimit counts and logs a requested limit (or first) above
+// MaxPageSize. ValidateFilter rejects such filters; nothing is clamped.
+func (r *Resolver) observeLimit(filter *model.StormReportFilter) {
+	requested := filter.Limit
+	if requested == nil {
+		requested = filter.First
+	}
+	if requested == nil || *requested <= MaxPageSize {
+		return
+	}
+	if r.Metrics != nil {
+		r.Metrics.PageLimitExceeded.Inc()
+	}
+	if r.Logger != nil {
+		r.Logger.Debug("page limit exceeds maximum", "requested", *requested, "max", MaxPageSize)
+	}
 }
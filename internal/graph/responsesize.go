@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// ResponseSizeLimit replaces responses whose serialized data exceeds MaxBytes
+// with an error, so one oversized result is not sent to the client. The data
+// has already been resolved by then; the page size and complexity limits
+// keep that cost bounded, and this caps what goes over the network.
+type ResponseSizeLimit struct {
+	MaxBytes int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = ResponseSizeLimit{}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (l ResponseSizeLimit) ExtensionName() string {
+	return "ResponseSizeLimit"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (l ResponseSizeLimit) Validate(graphql.ExecutableSchema) error {
+	if l.MaxBytes < 1 {
+		return fmt.Errorf("ResponseSizeLimit: MaxBytes must be >= 1")
+	}
+	return nil
+}
+
+// InterceptResponse implements graphql.ResponseInterceptor.
+func (l ResponseSizeLimit) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if resp == nil || len(resp.Data) <= l.MaxBytes {
+		return resp
+	}
+	return graphql.ErrorResponse(ctx,
+		"response of %d bytes exceeds the maximum of %d bytes; narrow the filter (time range, states, event types), request a smaller page, or select fewer fields",
+		len(resp.Data), l.MaxBytes)
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func respondWith(data string) graphql.ResponseHandler {
+	return func(context.Context) *graphql.Response {
+		return &graphql.Response{Data: json.RawMessage(data)}
+	}
+}
+
+func TestResponseSizeLimit_PassesWithinLimit(t *testing.T) {
+	data := `{"stormReports":{"totalCount":1}}`
+	limit := ResponseSizeLimit{MaxBytes: len(data)}
+
+	resp := limit.InterceptResponse(context.Background(), respondWith(data))
+
+	require.NotNil(t, resp)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, data, string(resp.Data), "exactly MaxBytes is allowed")
+}
+
+func TestResponseSizeLimit_RejectsOversizedResponse(t *testing.T) {
+	limit := ResponseSizeLimit{MaxBytes: 1024}
+	data := `{"stormReports":{"reports":[` + strings.Repeat(`{"id":"r"},`, 200) + `{}]}}`
+
+	resp := limit.InterceptResponse(context.Background(), respondWith(data))
+
+	require.NotNil(t, resp)
+	assert.Nil(t, resp.Data, "oversized data is dropped")
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "exceeds the maximum of 1024 bytes")
+	assert.Contains(t, resp.Errors[0].Message, "narrow the filter")
+	assert.Contains(t, resp.Errors[0].Message, "smaller page")
+}
+
+func TestResponseSizeLimit_PassesEndOfStream(t *testing.T) {
+	limit := ResponseSizeLimit{MaxBytes: 1}
+	resp := limit.InterceptResponse(context.Background(), func(context.Context) *graphql.Response { return nil })
+	assert.Nil(t, resp)
+}
+
+func TestResponseSizeLimitValidate(t *testing.T) {
+	require.Error(t, ResponseSizeLimit{}.Validate(nil))
+	require.NoError(t, ResponseSizeLimit{MaxBytes: 1}.Validate(nil))
+}
@@ -7,39 +7,86 @@ package graph
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/couchcryptid/storm-data-api/internal/admin"
 	"github.com/couchcryptid/storm-data-api/internal/model"
 	"golang.org/x/sync/errgroup"
 )
 
 // StormReports is the resolver for the stormReports field.
 func (r *queryResolver) StormReports(ctx context.Context, filter model.StormReportFilter) (*model.StormReportsResult, error) {
-	if err := ValidateFilter(&filter); err != nil {
+	if err := r.PrepareFilter(&filter); err != nil {
 		return nil, err
 	}
 
 	result := &model.StormReportsResult{
 		Aggregations: &model.StormAggregations{},
-		Meta:         &model.QueryMeta{},
+		PageInfo:     &model.PageInfo{},
+		Meta: &model.QueryMeta{
+			AppliedTimeRange: &model.AppliedTimeRange{
+				From:      filter.TimeRange.From,
+				To:        filter.TimeRange.To,
+				Defaulted: filter.TimeRangeDefaulted,
+			},
+		},
 	}
 
 	g, gCtx := errgroup.WithContext(ctx)
 	fields := collectFields(ctx)
 
-	// Reports + count
+	// Reports (or deltas) + count
 	g.Go(func() error {
-		reports, count, err := r.Store.ListStormReports(gCtx, &filter)
-		if err != nil {
-			return err
+		var pageLen, count, remaining int
+		if filter.DeltaOnly != nil && *filter.DeltaOnly {
+			deltas, total, err := r.Store.ListReportDeltas(gCtx, &filter)
+			if err != nil {
+				return err
+			}
+			result.Reports = []*model.StormReport{}
+			result.Deltas = deltas
+			pageLen, count, remaining = len(deltas), total, total
+			if filter.Offset != nil {
+				remaining -= *filter.Offset
+			}
+		} else if countOnly(fields, &filter) {
+			total, err := r.Store.CountStormReports(gCtx, &filter)
+			if err != nil {
+				return err
+			}
+			result.Reports = []*model.StormReport{}
+			count, remaining = total, total
+			if filter.Offset != nil {
+				remaining -= *filter.Offset
+			}
+			pageLen = max(min(*filter.Limit, remaining), 0)
+		} else {
+			reports, stats, err := r.Store.ListStormReportsWithStats(gCtx, &filter)
+			if err != nil {
+				return err
+			}
+			result.Reports = model.RoundCoordinates(reports, *filter.CoordinatePrecision)
+			if filter.MagnitudePrecision != nil {
+				result.Reports = model.RoundMagnitudes(result.Reports, *filter.MagnitudePrecision)
+			}
+			if err := r.enrich(gCtx, result.Reports); err != nil {
+				return err
+			}
+			pageLen, count, remaining = len(reports), stats.TotalCount, stats.Remaining
+			end, err := endCursor(&filter, reports, stats)
+			if err != nil {
+				return err
+			}
+			result.PageInfo.EndCursor = end
 		}
-		result.Reports = reports
 		result.TotalCount = count
 		result.Aggregations.TotalCount = count
-		offset := 0
-		if filter.Offset != nil {
-			offset = *filter.Offset
+		result.HasMore = pageLen < remaining
+		result.PageInfo.HasNextPage = result.HasMore
+		if result.HasMore {
+			result.NextCursor = result.PageInfo.EndCursor
 		}
-		result.HasMore = offset+len(reports) < count
 		return nil
 	})
 
@@ -63,6 +110,30 @@ func (r *queryResolver) StormReports(ctx context.Context, filter model.StormRepo
 		})
 	}
 
+	// Convex hull (if requested)
+	if fields["convexHull"] {
+		g.Go(func() error {
+			hull, err := r.Store.ConvexHull(gCtx, &filter)
+			if err != nil {
+				return err
+			}
+			result.ConvexHull = hull
+			return nil
+		})
+	}
+
+	// Reports the area filters left out for lack of coordinates (if requested)
+	if fields["meta.excludedMissingCoordinates"] {
+		g.Go(func() error {
+			n, err := r.Store.ExcludedMissingCoordinates(gCtx, &filter)
+			if err != nil {
+				return err
+			}
+			result.Meta.ExcludedMissingCoordinates = n
+			return nil
+		})
+	}
+
 	// Meta (if requested)
 	if fields["meta"] {
 		g.Go(func() error {
@@ -76,16 +147,197 @@ func (r *queryResolver) StormReports(ctx context.Context, filter model.StormRepo
 	return result, nil
 }
 
+// WarningLeadTimes is the resolver for the warningLeadTimes field.
+func (r *queryResolver) WarningLeadTimes(ctx context.Context, timeRange model.TimeRange, types []string) ([]*model.WarningLeadTime, error) {
+	if !timeRange.To.After(timeRange.From) {
+		return nil, fmt.Errorf("timeRange.to must be after timeRange.from")
+	}
+	return r.Store.WarningLeadTimes(ctx, timeRange, types, MaxLeadTimeWarnings)
+}
+
+// WarningsWithoutReports is the resolver for the warningsWithoutReports field.
+func (r *queryResolver) WarningsWithoutReports(ctx context.Context, timeRange model.TimeRange, types []string) ([]*model.UnverifiedWarning, error) {
+	if !timeRange.To.After(timeRange.From) {
+		return nil, fmt.Errorf("timeRange.to must be after timeRange.from")
+	}
+	return r.Store.WarningsWithoutReports(ctx, timeRange, types, MaxLeadTimeWarnings)
+}
+
+// MagnitudePercentiles is the resolver for the magnitudePercentiles field.
+func (r *queryResolver) MagnitudePercentiles(ctx context.Context, filter model.StormReportFilter, percentile float64) ([]*model.MagnitudePercentile, error) {
+	if err := ValidatePercentile(percentile); err != nil {
+		return nil, err
+	}
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	return r.Store.MagnitudePercentiles(ctx, &filter, percentile)
+}
+
+// StormReportCountsByType is the resolver for the stormReportCountsByType field.
+func (r *queryResolver) StormReportCountsByType(ctx context.Context, filter model.StormReportFilter) ([]*model.TypeCount, error) {
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	return r.Store.CountByType(ctx, &filter)
+}
+
+// SeverityDistribution is the resolver for the severityDistribution field.
+func (r *queryResolver) SeverityDistribution(ctx context.Context, filter model.StormReportFilter) ([]*model.SeverityCount, error) {
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	return r.Store.SeverityDistribution(ctx, &filter)
+}
+
+// SpotterRank is the resolver for the spotterRank field.
+func (r *queryResolver) SpotterRank(ctx context.Context, filter model.StormReportFilter, spotterID string) (*model.SpotterRank, error) {
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := ValidateSpotterID(&spotterID); err != nil {
+		return nil, err
+	}
+	return r.Store.SpotterRank(ctx, &filter, spotterID)
+}
+
+// ReportRate is the resolver for the reportRate field.
+func (r *queryResolver) ReportRate(ctx context.Context, filter model.StormReportFilter, windowMinutes int, intervals int) (*model.ReportRate, error) {
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := ValidateReportRate(filter.TimeRange, windowMinutes, intervals); err != nil {
+		return nil, err
+	}
+	window := time.Duration(windowMinutes) * time.Minute
+	return r.Store.ReportRate(ctx, &filter, filter.TimeRange.To, window, intervals)
+}
+
+// NearbyReports is the resolver for the nearbyReports field.
+func (r *queryResolver) NearbyReports(ctx context.Context, id string, windowHours int, limit int) ([]*model.NearbyReport, error) {
+	if err := ValidateNearbyReports(windowHours, limit); err != nil {
+		return nil, err
+	}
+	window := time.Duration(windowHours) * time.Hour
+	withDistance := collectFields(ctx)["distanceMiles"]
+	nearby, err := r.Store.NearbyReports(ctx, id, window, limit, !r.AllowFutureReports, withDistance)
+	if err != nil {
+		return nil, err
+	}
+	if nearby == nil {
+		return nil, fmt.Errorf("report %q not found", id)
+	}
+	reports := make([]*model.StormReport, len(nearby))
+	for i, n := range nearby {
+		reports[i] = n.Report
+	}
+	if err := r.enrich(ctx, reports); err != nil {
+		return nil, err
+	}
+	return nearby, nil
+}
+
+// CoverageGaps is the resolver for the coverageGaps field.
+func (r *queryResolver) CoverageGaps(ctx context.Context, filter model.StormReportFilter, bucketMinutes int) ([]*model.CoverageGap, error) {
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := ValidateCoverageGaps(filter.TimeRange, bucketMinutes); err != nil {
+		return nil, err
+	}
+	return r.Store.CoverageGaps(ctx, &filter, time.Duration(bucketMinutes)*time.Minute)
+}
+
+// DiurnalCycle is the resolver for the diurnalCycle field.
+func (r *queryResolver) DiurnalCycle(ctx context.Context, filter model.StormReportFilter, timezone string) ([]*model.HourOfDayCount, error) {
+	if err := ValidateTimezone(timezone); err != nil {
+		return nil, err
+	}
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	return r.Store.DiurnalCycle(ctx, &filter, timezone)
+}
+
+// DamageTotals is the resolver for the damageTotals field.
+func (r *queryResolver) DamageTotals(ctx context.Context, filter model.StormReportFilter, bucket model.BucketUnit) ([]*model.DamageTotal, error) {
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := ValidateDamageTotals(filter.TimeRange, bucket); err != nil {
+		return nil, err
+	}
+	return r.Store.DamageTotals(ctx, &filter, bucket)
+}
+
+// ReportHistogram is the resolver for the reportHistogram field.
+func (r *queryResolver) ReportHistogram(ctx context.Context, filter model.StormReportFilter, interval model.BucketUnit) ([]*model.TimeBucketCount, error) {
+	if err := r.PrepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := ValidateReportHistogram(filter.TimeRange, interval); err != nil {
+		return nil, err
+	}
+	return r.Store.CountByTimeBucket(ctx, &filter, interval)
+}
+
+// DataRange is the resolver for the dataRange field.
+func (r *queryResolver) DataRange(ctx context.Context, eventTypes []model.EventType, states []string) (*model.DataRange, error) {
+	return r.Store.DataRange(ctx, eventTypes, states, !r.AllowFutureReports)
+}
+
+// PatchStormReport is the resolver for the patchStormReport field.
+func (r *mutationResolver) PatchStormReport(ctx context.Context, id string, patch model.ReportPatch) (*ReportPatchResult, error) {
+	if !admin.Authorized(ctx) {
+		return nil, errUnauthorized
+	}
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+	updatedAt, err := r.Store.PatchStormReport(ctx, id, &patch)
+	if err != nil {
+		return nil, patchError(ctx, err, updatedAt)
+	}
+	return &ReportPatchResult{ID: id, UpdatedAt: updatedAt}, nil
+}
+
 // EventType is the resolver for the eventType field.
 func (r *stormReportResolver) EventType(ctx context.Context, obj *model.StormReport) (string, error) {
 	return obj.EventType, nil
 }
 
+// RawEventType is the resolver for the rawEventType field.
+func (r *stormReportResolver) RawEventType(ctx context.Context, obj *model.StormReport) (string, error) {
+	return obj.RawEventType, nil
+}
+
+// SeverityScore is the resolver for the severityScore field.
+func (r *stormReportResolver) SeverityScore(ctx context.Context, obj *model.StormReport) (float64, error) {
+	return r.severityWeights().Score(obj), nil
+}
+
+// PlaceName is the resolver for the placeName field.
+func (r *stormReportResolver) PlaceName(ctx context.Context, obj *model.StormReport) (*string, error) {
+	return r.Store.NearestPlaceName(ctx, obj.Geo.Lat, obj.Geo.Lon)
+}
+
+// County is the resolver for the county field.
+func (r *stormReportResolver) County(ctx context.Context, obj *model.StormReport) (*model.County, error) {
+	if obj.Location.County == "" {
+		return nil, nil
+	}
+	return r.Store.County(ctx, obj.Location.State, obj.Location.County)
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
 // StormReport returns StormReportResolver implementation.
 func (r *Resolver) StormReport() StormReportResolver { return &stormReportResolver{r} }
 
+type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type stormReportResolver struct{ *Resolver }
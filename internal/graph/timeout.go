@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TimeoutHeader lets a client bound the server work for one request, in
+// milliseconds.
+const TimeoutHeader = "X-Timeout-Ms"
+
+// RequestTimeout derives the request context deadline from the TimeoutHeader,
+// clamped to maxTimeout, so store queries stop once the client has given up.
+// Requests without the header are left alone. Values that are not a positive
+// integer are rejected with 400.
+func RequestTimeout(maxTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			v := r.Header.Get(TimeoutHeader)
+			if v == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ms, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || ms <= 0 {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":[{"message":"invalid X-Timeout-Ms: must be a positive integer"}]}`))
+				return
+			}
+			timeout := maxTimeout
+			if ms < maxTimeout.Milliseconds() {
+				timeout = time.Duration(ms) * time.Millisecond
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// MaxDuration caps the request context at d regardless of the TimeoutHeader.
+// It guards streaming routes, which bypass the server's TimeoutHandler and
+// would otherwise hold a pooled connection for as long as the client keeps
+// reading.
+func MaxDuration(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
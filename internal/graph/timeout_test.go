@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeout_ShortHeaderTimesOutPromptly(t *testing.T) {
+	var ctxErr error
+	handler := RequestTimeout(time.Minute)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			ctxErr = r.Context().Err()
+		case <-time.After(5 * time.Second):
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/query", nil)
+	req.Header.Set(TimeoutHeader, "20")
+	start := time.Now()
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.ErrorIs(t, ctxErr, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRequestTimeout_ClampedToMax(t *testing.T) {
+	var remaining time.Duration
+	handler := RequestTimeout(2 * time.Second)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		require.True(t, ok)
+		remaining = time.Until(deadline)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/query", nil)
+	req.Header.Set(TimeoutHeader, "3600000")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.LessOrEqual(t, remaining, 2*time.Second)
+	assert.Greater(t, remaining, time.Second)
+}
+
+func TestRequestTimeout_NoHeaderNoDeadline(t *testing.T) {
+	handler := RequestTimeout(time.Second)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.False(t, ok)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+}
+
+func TestRequestTimeout_RejectsInvalid(t *testing.T) {
+	for _, v := range []string{"0", "-5", "abc", "1.5", "99999999999999999999"} {
+		t.Run(v, func(t *testing.T) {
+			called := false
+			handler := RequestTimeout(time.Second)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				called = true
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			req.Header.Set(TimeoutHeader, v)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "invalid X-Timeout-Ms")
+			assert.False(t, called)
+		})
+	}
+}
+
+func TestMaxDuration_SetsDeadline(t *testing.T) {
+	var remaining time.Duration
+	handler := MaxDuration(2 * time.Second)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		require.True(t, ok)
+		remaining = time.Until(deadline)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/export.csv", nil))
+
+	assert.LessOrEqual(t, remaining, 2*time.Second)
+	assert.Greater(t, remaining, time.Second)
+}
@@ -2,16 +2,75 @@ package graph
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/couchcryptid/storm-data-api/internal/geohash"
 	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/store"
 )
 
 // Query protection limits.
 const (
-	MaxEventTypeFilters = 3
-	MaxPageSize         = 20
-	MaxRadiusMiles      = 200.0
-	DefaultRadiusMiles  = 20.0
+	MaxEventTypeFilters   = 3
+	MaxTypeSeverityGroups = 5
+	MaxPageSize           = 20
+	MaxRadiusMiles        = 200.0
+	DefaultRadiusMiles    = 20.0
+
+	// Warning verification: warnings returned per warningLeadTimes or
+	// warningsWithoutReports query.
+	MaxLeadTimeWarnings = 50
+
+	// Report rate: window length cap and sub-intervals per window.
+	MaxRateWindowMinutes = 1440
+	MaxRateIntervals     = 24
+
+	// Nearby reports: time window around the anchor, in hours.
+	MaxNearbyWindowHours = 168
+
+	// Coverage gaps: bucket length cap and buckets per time range.
+	MaxCoverageBucketMinutes = 1440
+	MaxCoverageBuckets       = 200
+
+	// Damage totals: buckets per time range. Kept low enough that selecting
+	// every DamageTotal field fits the complexity budget.
+	MaxDamageBuckets = 100
+
+	// Report histogram: intervals per time range.
+	MaxHistogramBuckets = 200
+
+	// Keyword search term length, in characters.
+	MaxKeywordLength = 100
+
+	// Remarks phrases per include/exclude list.
+	MaxRemarkTerms = 10
+
+	// Polygon filter vertices, including the closing vertex.
+	MaxPolygonVertices = 200
+
+	// County outlier threshold, in standard deviations.
+	MaxCountyOutlierStdDevs = 10.0
+
+	// County name prefix length, in characters.
+	MaxCountyPrefixLength = 64
+
+	// Spotter identifier length, in characters.
+	MaxSpotterIDLength = 64
+
+	// Output coordinate rounding. 5 decimals is ~1m at the equator.
+	DefaultCoordinatePrecision = 5
+	MaxCoordinatePrecision     = 10
+
+	// Output magnitude rounding, per event type.
+	MaxMagnitudePrecision = 4
+
+	// Storm track length bounds.
+	MinTrackPoints = 2
+	MaxTrackPoints = 100
 )
 
 // ValidateFilter validates a single filter, enforcing limits and applying defaults.
@@ -32,6 +91,145 @@ func ValidateFilter(filter *model.StormReportFilter) error {
 		}
 	}
 
+	// Bounding box: well-formed and on the globe
+	if b := filter.BBox; b != nil {
+		if b.MinLat < -90 || b.MaxLat > 90 || b.MinLon < -180 || b.MaxLon > 180 {
+			return fmt.Errorf("bbox coordinates out of range")
+		}
+		if b.MinLat > b.MaxLat || b.MinLon > b.MaxLon {
+			return fmt.Errorf("bbox min must not exceed max")
+		}
+	}
+
+	// Geohash: decodable, normalized to lower case
+	if filter.Geohash != nil {
+		h := strings.ToLower(strings.TrimSpace(*filter.Geohash))
+		if _, err := geohash.Decode(h); err != nil {
+			return err
+		}
+		filter.Geohash = &h
+	}
+
+	// Exact coordinate: both halves, on the globe, and not a radius search
+	if filter.AtLat != nil || filter.AtLon != nil {
+		if filter.AtLat == nil || filter.AtLon == nil {
+			return fmt.Errorf("atLat and atLon must be set together")
+		}
+		if *filter.AtLat < -90 || *filter.AtLat > 90 || *filter.AtLon < -180 || *filter.AtLon > 180 {
+			return fmt.Errorf("atLat/atLon out of range")
+		}
+		if filter.Near != nil {
+			return fmt.Errorf("atLat/atLon cannot be combined with near")
+		}
+	}
+
+	if filter.Polygon != nil {
+		ring, err := closePolygon(filter.Polygon)
+		if err != nil {
+			return err
+		}
+		filter.Polygon = ring
+	}
+
+	if filter.TimeColumn != nil && !filter.TimeColumn.IsValid() {
+		return fmt.Errorf("invalid timeColumn %q", *filter.TimeColumn)
+	}
+
+	// Correction status: known values; by default superseded reports are hidden
+	if len(filter.CorrectionStatus) == 0 {
+		filter.CorrectionStatus = []string{model.CorrectionStatusOriginal, model.CorrectionStatusCorrected}
+	}
+	for _, cs := range filter.CorrectionStatus {
+		if !slices.Contains(model.CorrectionStatuses, cs) {
+			return fmt.Errorf("invalid correctionStatus %q", cs)
+		}
+	}
+
+	if filter.DayNight != nil && !filter.DayNight.IsValid() {
+		return fmt.Errorf("invalid dayNight %q", *filter.DayNight)
+	}
+
+	// County prefix: trimmed, non-empty, bounded
+	if filter.CountyPrefix != nil {
+		prefix := strings.TrimSpace(*filter.CountyPrefix)
+		if prefix == "" {
+			return fmt.Errorf("countyPrefix must not be empty")
+		}
+		if utf8.RuneCountInString(prefix) > MaxCountyPrefixLength {
+			return fmt.Errorf("countyPrefix must be at most %d characters", MaxCountyPrefixLength)
+		}
+		filter.CountyPrefix = &prefix
+	}
+
+	// Keyword search: trimmed, non-empty, bounded
+	if filter.KeywordSearch != nil {
+		kw := strings.TrimSpace(*filter.KeywordSearch)
+		if kw == "" {
+			return fmt.Errorf("keywordSearch must not be empty")
+		}
+		if utf8.RuneCountInString(kw) > MaxKeywordLength {
+			return fmt.Errorf("keywordSearch must be at most %d characters", MaxKeywordLength)
+		}
+		filter.KeywordSearch = &kw
+	}
+	if err := validateRemarkTerms("remarksInclude", filter.RemarksInclude); err != nil {
+		return err
+	}
+	if err := validateRemarkTerms("remarksExclude", filter.RemarksExclude); err != nil {
+		return err
+	}
+	if filter.MinRemarksLength != nil && *filter.MinRemarksLength < 1 {
+		return fmt.Errorf("minRemarksLength must be at least 1")
+	}
+	if filter.MaxLocationUncertainty != nil && *filter.MaxLocationUncertainty < 0 {
+		return fmt.Errorf("maxLocationUncertainty must not be negative")
+	}
+	if k := filter.CountyOutlierStdDevs; k != nil && (!(*k > 0) || *k > MaxCountyOutlierStdDevs) {
+		return fmt.Errorf("countyOutlierStdDevs must be greater than 0 and at most %g", MaxCountyOutlierStdDevs)
+	}
+
+	// Populated place proximity: same radius cap as near
+	if p := filter.NearPopulatedPlace; p != nil {
+		if p.RadiusMiles <= 0 || p.RadiusMiles > MaxRadiusMiles {
+			return fmt.Errorf("nearPopulatedPlace.radiusMiles must be between 0 and %.0f", MaxRadiusMiles)
+		}
+		if p.MinPopulation < 0 {
+			return fmt.Errorf("nearPopulatedPlace.minPopulation must not be negative")
+		}
+	}
+
+	// Storm track: bounded length, on the globe, strictly in time order
+	if tr := filter.StormTrack; tr != nil {
+		if n := len(tr.Points); n < MinTrackPoints || n > MaxTrackPoints {
+			return fmt.Errorf("stormTrack.points must have between %d and %d points", MinTrackPoints, MaxTrackPoints)
+		}
+		if tr.RadiusMiles <= 0 || tr.RadiusMiles > MaxRadiusMiles {
+			return fmt.Errorf("stormTrack.radiusMiles must be between 0 and %.0f", MaxRadiusMiles)
+		}
+		for i, p := range tr.Points {
+			if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+				return fmt.Errorf("stormTrack.points[%d]: coordinates out of range", i)
+			}
+			if i > 0 && !p.Time.After(tr.Points[i-1].Time) {
+				return fmt.Errorf("stormTrack.points[%d]: time must be after the previous point", i)
+			}
+		}
+	}
+
+	// Warning correlation needs at least one product selector; the unwarned
+	// anti-join may instead consider every product.
+	if w := filter.Warning; w != nil && w.ID == nil && len(w.Types) == 0 &&
+		(w.UnwarnedOnly == nil || !*w.UnwarnedOnly) {
+		return fmt.Errorf("warning requires id or types")
+	}
+
+	if filter.MinMagnitude != nil && filter.MaxMagnitude != nil && *filter.MaxMagnitude < *filter.MinMagnitude {
+		return fmt.Errorf("maxMagnitude is below minMagnitude")
+	}
+	if err := validateMagnitudeUnit(filter); err != nil {
+		return err
+	}
+
 	// EventTypeFilters: max 3, no duplicate types
 	if len(filter.EventTypeFilters) > MaxEventTypeFilters {
 		return fmt.Errorf("at most %d eventTypeFilters allowed", MaxEventTypeFilters)
@@ -43,13 +241,51 @@ func ValidateFilter(filter *model.StormReportFilter) error {
 		}
 		seen[typeFilter.EventType] = true
 
+		// Per-type magnitude range must not be empty
+		lo, hi := filter.MinMagnitude, filter.MaxMagnitude
+		if typeFilter.MinMagnitude != nil {
+			lo = typeFilter.MinMagnitude
+		}
+		if typeFilter.MaxMagnitude != nil {
+			hi = typeFilter.MaxMagnitude
+		}
+		if lo != nil && hi != nil && *hi < *lo {
+			return fmt.Errorf("eventTypeFilters[%d]: maxMagnitude is below minMagnitude", i)
+		}
+
 		// Per-type radius cap
 		if typeFilter.RadiusMiles != nil && *typeFilter.RadiusMiles > MaxRadiusMiles {
 			return fmt.Errorf("eventTypeFilters[%d]: radiusMiles exceeds maximum of %.0f", i, MaxRadiusMiles)
 		}
 	}
 
+	// TypeSeverityGroups replace eventTypes/severity in simple mode
+	if len(filter.TypeSeverityGroups) > 0 {
+		if len(filter.EventTypeFilters) > 0 || len(filter.EventTypes) > 0 || len(filter.Severity) > 0 {
+			return fmt.Errorf("typeSeverityGroups cannot be combined with eventTypes, severity, or eventTypeFilters")
+		}
+		if len(filter.TypeSeverityGroups) > MaxTypeSeverityGroups {
+			return fmt.Errorf("at most %d typeSeverityGroups allowed", MaxTypeSeverityGroups)
+		}
+		for i, g := range filter.TypeSeverityGroups {
+			if len(g.EventTypes) == 0 && len(g.Severity) == 0 {
+				return fmt.Errorf("typeSeverityGroups[%d]: requires eventTypes or severity", i)
+			}
+		}
+	}
+
+	// Delta sync needs a checkpoint to diff against
+	if filter.DeltaOnly != nil && *filter.DeltaOnly && filter.UpdatedAfter == nil {
+		return fmt.Errorf("deltaOnly requires updatedAfter")
+	}
+
 	// Pagination defaults and caps
+	if filter.First != nil {
+		if filter.Limit != nil {
+			return fmt.Errorf("first cannot be combined with limit")
+		}
+		filter.Limit, filter.First = filter.First, nil
+	}
 	if filter.Limit == nil {
 		d := MaxPageSize
 		filter.Limit = &d
@@ -57,5 +293,342 @@ func ValidateFilter(filter *model.StormReportFilter) error {
 		return fmt.Errorf("limit exceeds maximum of %d", MaxPageSize)
 	}
 
+	// Keyset pagination replaces offset and needs a fixed per-row sort key
+	if filter.After != nil {
+		if filter.Offset != nil {
+			return fmt.Errorf("after cannot be combined with offset")
+		}
+		if filter.DeltaOnly != nil && *filter.DeltaOnly {
+			return fmt.Errorf("after cannot be combined with deltaOnly")
+		}
+		if !store.KeysetSortable(filter) {
+			return fmt.Errorf("after is not supported with sortBy %s", model.SortFieldMagnitudeNormalized)
+		}
+		cursor, err := store.DecodeCursor(filter, *filter.After)
+		if err != nil {
+			return err
+		}
+		filter.Cursor = cursor
+	}
+
+	// Output coordinate precision
+	if filter.CoordinatePrecision == nil {
+		d := DefaultCoordinatePrecision
+		filter.CoordinatePrecision = &d
+	} else if p := *filter.CoordinatePrecision; p < 0 || p > MaxCoordinatePrecision {
+		return fmt.Errorf("coordinatePrecision must be between 0 and %d", MaxCoordinatePrecision)
+	}
+
+	// Output magnitude precision
+	if mp := filter.MagnitudePrecision; mp != nil {
+		for _, t := range []model.EventType{model.EventTypeHail, model.EventTypeWind, model.EventTypeTornado} {
+			if p := mp.For(t.DBValue()); p != nil && (*p < 0 || *p > MaxMagnitudePrecision) {
+				return fmt.Errorf("magnitudePrecision.%s must be between 0 and %d", t.DBValue(), MaxMagnitudePrecision)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidatePercentile checks a percentile argument is in [0, 1].
+func ValidatePercentile(p float64) error {
+	if math.IsNaN(p) || p < 0 || p > 1 {
+		return fmt.Errorf("percentile must be between 0 and 1")
+	}
+	return nil
+}
+
+// ValidateReportRate checks the reportRate window and sub-interval count, and
+// that the window fits inside the time range it ends at.
+func ValidateReportRate(tr model.TimeRange, windowMinutes, intervals int) error {
+	if windowMinutes < 1 || windowMinutes > MaxRateWindowMinutes {
+		return fmt.Errorf("windowMinutes must be between 1 and %d", MaxRateWindowMinutes)
+	}
+	if intervals < 1 || intervals > MaxRateIntervals {
+		return fmt.Errorf("intervals must be between 1 and %d", MaxRateIntervals)
+	}
+	if time.Duration(windowMinutes)*time.Minute > tr.To.Sub(tr.From) {
+		return fmt.Errorf("windowMinutes must not exceed the timeRange span")
+	}
+	return nil
+}
+
+// ValidateNearbyReports checks the nearbyReports time window and result count.
+func ValidateNearbyReports(windowHours, limit int) error {
+	if windowHours < 1 || windowHours > MaxNearbyWindowHours {
+		return fmt.Errorf("windowHours must be between 1 and %d", MaxNearbyWindowHours)
+	}
+	if limit < 1 || limit > MaxPageSize {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+	}
+	return nil
+}
+
+// validateMagnitudeUnit checks the global magnitude bounds compare like with
+// like: hail is measured in inches, wind in mph, and tornadoes on the F/EF
+// scale. Without a magnitudeUnit the bounds must fall back to event types
+// sharing one unit; with one, at least one of those types must use it.
+func validateMagnitudeUnit(filter *model.StormReportFilter) error {
+	unit := model.MagnitudeUnitUnspecified
+	if filter.MagnitudeUnit != nil {
+		if !filter.MagnitudeUnit.IsValid() {
+			return fmt.Errorf("invalid magnitudeUnit %q", *filter.MagnitudeUnit)
+		}
+		unit = *filter.MagnitudeUnit
+	}
+	if filter.MinMagnitude == nil && filter.MaxMagnitude == nil {
+		return nil
+	}
+	types := globalMagnitudeTypes(filter)
+	if len(types) == 0 {
+		return nil
+	}
+
+	if unit != model.MagnitudeUnitUnspecified {
+		for _, t := range types {
+			if t.MagnitudeUnit() == unit {
+				return nil
+			}
+		}
+		return fmt.Errorf("magnitudeUnit %s matches none of the event types minMagnitude/maxMagnitude apply to", unit)
+	}
+
+	var mixed []string
+	seen := make(map[model.MagnitudeUnit]bool)
+	for _, t := range types {
+		u := t.MagnitudeUnit()
+		if !seen[u] {
+			seen[u] = true
+			mixed = append(mixed, fmt.Sprintf("%s %s", t, u.DBValue()))
+		}
+	}
+	if len(mixed) > 1 {
+		return fmt.Errorf("minMagnitude/maxMagnitude apply to event types measured in different units (%s); select event types sharing one unit or set magnitudeUnit",
+			strings.Join(mixed, ", "))
+	}
+	return nil
+}
+
+// globalMagnitudeTypes returns the event types the global minMagnitude and
+// maxMagnitude fall back to. In simple mode that is eventTypes (or those of
+// the typeSeverityGroups), or every type when none is selected. With
+// eventTypeFilters it is the overrides that leave a set global bound unset,
+// plus the unoverridden eventTypes.
+func globalMagnitudeTypes(filter *model.StormReportFilter) []model.EventType {
+	allTypes := []model.EventType{model.EventTypeHail, model.EventTypeWind, model.EventTypeTornado}
+	if len(filter.TypeSeverityGroups) > 0 {
+		var out []model.EventType
+		for _, g := range filter.TypeSeverityGroups {
+			if len(g.EventTypes) == 0 {
+				return allTypes
+			}
+			out = append(out, g.EventTypes...)
+		}
+		return out
+	}
+	if len(filter.EventTypeFilters) == 0 {
+		if len(filter.EventTypes) == 0 {
+			return allTypes
+		}
+		return filter.EventTypes
+	}
+	var out []model.EventType
+	overridden := make(map[model.EventType]bool)
+	for _, tf := range filter.EventTypeFilters {
+		overridden[tf.EventType] = true
+		if (filter.MinMagnitude != nil && tf.MinMagnitude == nil) || (filter.MaxMagnitude != nil && tf.MaxMagnitude == nil) {
+			out = append(out, tf.EventType)
+		}
+	}
+	for _, et := range filter.EventTypes {
+		if !overridden[et] {
+			out = append(out, et)
+		}
+	}
+	return out
+}
+
+// closePolygon checks a polygon filter is on the globe, bounded, and encloses
+// an area, and returns the ring closed (last vertex equal to the first).
+func closePolygon(ring []model.LatLon) ([]model.LatLon, error) {
+	for _, p := range ring {
+		if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+			return nil, fmt.Errorf("polygon coordinates out of range")
+		}
+	}
+	if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+		ring = append(slices.Clip(ring), ring[0])
+	}
+	if len(ring) < 4 {
+		return nil, fmt.Errorf("polygon must have at least 3 vertices")
+	}
+	if len(ring) > MaxPolygonVertices {
+		return nil, fmt.Errorf("polygon must have at most %d vertices", MaxPolygonVertices)
+	}
+	// Shoelace formula: zero area means every vertex is collinear.
+	var area float64
+	for i := range len(ring) - 1 {
+		area += ring[i].Lon*ring[i+1].Lat - ring[i+1].Lon*ring[i].Lat
+	}
+	if area == 0 {
+		return nil, fmt.Errorf("polygon must enclose an area")
+	}
+	return ring, nil
+}
+
+// validateRemarkTerms trims each remarks phrase in place and checks the list
+// is bounded and every phrase is non-empty and at most MaxKeywordLength.
+func validateRemarkTerms(field string, terms []string) error {
+	if len(terms) > MaxRemarkTerms {
+		return fmt.Errorf("%s must have at most %d terms", field, MaxRemarkTerms)
+	}
+	for i, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return fmt.Errorf("%s terms must not be empty", field)
+		}
+		if utf8.RuneCountInString(term) > MaxKeywordLength {
+			return fmt.Errorf("%s terms must be at most %d characters", field, MaxKeywordLength)
+		}
+		terms[i] = term
+	}
+	return nil
+}
+
+// ValidateSpotterID trims the spotterRank identifier and checks it is
+// non-empty and bounded.
+func ValidateSpotterID(id *string) error {
+	*id = strings.TrimSpace(*id)
+	if *id == "" {
+		return fmt.Errorf("spotterId is required")
+	}
+	if utf8.RuneCountInString(*id) > MaxSpotterIDLength {
+		return fmt.Errorf("spotterId must be at most %d characters", MaxSpotterIDLength)
+	}
+	return nil
+}
+
+// ValidateTimezone checks tz names an IANA time zone. "Local" is rejected
+// because it would depend on the server's zone.
+func ValidateTimezone(tz string) error {
+	if tz == "" || tz == "Local" {
+		return fmt.Errorf("invalid timezone %q", tz)
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("invalid timezone %q", tz)
+	}
+	return nil
+}
+
+// ValidateCoverageGaps checks the coverageGaps bucket length and that the
+// time range splits into at most MaxCoverageBuckets buckets.
+func ValidateCoverageGaps(tr model.TimeRange, bucketMinutes int) error {
+	if bucketMinutes < 1 || bucketMinutes > MaxCoverageBucketMinutes {
+		return fmt.Errorf("bucketMinutes must be between 1 and %d", MaxCoverageBucketMinutes)
+	}
+	bucket := time.Duration(bucketMinutes) * time.Minute
+	if n := (tr.To.Sub(tr.From) + bucket - 1) / bucket; n > MaxCoverageBuckets {
+		return fmt.Errorf("timeRange spans %d buckets; at most %d allowed", n, MaxCoverageBuckets)
+	}
 	return nil
 }
+
+// bucketSpans is the shortest length of each bucket unit, so bucket counts
+// derived from it are upper bounds.
+var bucketSpans = map[model.BucketUnit]time.Duration{
+	model.BucketUnitHour:  time.Hour,
+	model.BucketUnitDay:   24 * time.Hour,
+	model.BucketUnitWeek:  7 * 24 * time.Hour,
+	model.BucketUnitMonth: 28 * 24 * time.Hour,
+	model.BucketUnitYear:  365 * 24 * time.Hour,
+}
+
+// ValidateDamageTotals checks the bucket unit and that the time range
+// touches at most MaxDamageBuckets buckets of it.
+func ValidateDamageTotals(tr model.TimeRange, bucket model.BucketUnit) error {
+	span, ok := bucketSpans[bucket]
+	if !ok {
+		return fmt.Errorf("invalid bucket %q", bucket)
+	}
+	// A range not aligned to bucket boundaries touches one partial bucket
+	// at each end.
+	if n := tr.To.Sub(tr.From)/span + 2; n > MaxDamageBuckets {
+		return fmt.Errorf("timeRange spans up to %d %s buckets; at most %d allowed", n, strings.ToLower(bucket.String()), MaxDamageBuckets)
+	}
+	return nil
+}
+
+// ValidateReportHistogram checks the histogram interval and that the time
+// range touches at most MaxHistogramBuckets intervals of it.
+func ValidateReportHistogram(tr model.TimeRange, interval model.BucketUnit) error {
+	span, ok := bucketSpans[interval]
+	if !ok || interval == model.BucketUnitYear {
+		return fmt.Errorf("invalid interval %q; must be HOUR, DAY, WEEK, or MONTH", interval)
+	}
+	if n := tr.To.Sub(tr.From)/span + 2; n > MaxHistogramBuckets {
+		return fmt.Errorf("timeRange spans up to %d %s intervals; at most %d allowed", n, strings.ToLower(interval.String()), MaxHistogramBuckets)
+	}
+	return nil
+}
+
+// GeoConflictMode controls how a filter that sets both near (radius) and bbox
+// is handled.
+type GeoConflictMode string
+
+// GeoConflictMode values.
+const (
+	// GeoConflictError rejects the filter. The zero value behaves the same.
+	GeoConflictError GeoConflictMode = "error"
+	// GeoConflictIntersect keeps both: reports in the box AND within the radius.
+	GeoConflictIntersect GeoConflictMode = "intersect"
+	// GeoConflictBBoxOnly drops near and filters by the box alone.
+	GeoConflictBBoxOnly GeoConflictMode = "bbox"
+)
+
+// ResolveGeoConflict applies the configured mode when both near and bbox are
+// set. Filters with at most one of them are left unchanged.
+func ResolveGeoConflict(filter *model.StormReportFilter, mode GeoConflictMode) error {
+	if filter.Near == nil || filter.BBox == nil {
+		return nil
+	}
+	switch mode {
+	case GeoConflictIntersect:
+		return nil
+	case GeoConflictBBoxOnly:
+		filter.Near = nil
+		return nil
+	default:
+		return fmt.Errorf("near and bbox cannot be combined")
+	}
+}
+
+// ApplyDefaultWindow fills in a missing timeRange with the window ending at
+// now and marks the filter as defaulted. With a zero window a missing
+// timeRange is rejected, so unbounded queries never reach the store.
+func ApplyDefaultWindow(filter *model.StormReportFilter, window time.Duration, now time.Time) error {
+	if !filter.TimeRange.IsZero() {
+		return nil
+	}
+	if window <= 0 {
+		return fmt.Errorf("timeRange is required")
+	}
+	filter.TimeRange = model.TimeRange{From: now.Add(-window), To: now}
+	filter.TimeRangeDefaulted = true
+	return nil
+}
+
+// RoundTimeRange widens the time range outward to the given granularity:
+// from is floored and to is ceiled, so the rounded window always contains the
+// requested one. Clients sending over-precise timestamps (e.g. "now" with
+// milliseconds) then produce identical query parameters within a bucket.
+// A non-positive granularity leaves the range unchanged.
+func RoundTimeRange(tr *model.TimeRange, granularity time.Duration) {
+	if granularity <= 0 {
+		return
+	}
+	tr.From = tr.From.Truncate(granularity)
+	if to := tr.To.Truncate(granularity); !to.Equal(tr.To) {
+		tr.To = to.Add(granularity)
+	}
+}
@@ -1,10 +1,16 @@
 package graph
 
 import (
+	"math"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -122,6 +128,60 @@ func TestValidateFilter_EventTypeFiltersValid(t *testing.T) {
 	require.NoError(t, ValidateFilter(f))
 }
 
+func TestValidateFilter_TypeSeverityGroups(t *testing.T) {
+	f := validFilter()
+	f.TypeSeverityGroups = []model.TypeSeverityGroup{
+		{EventTypes: []model.EventType{model.EventTypeTornado}},
+		{EventTypes: []model.EventType{model.EventTypeHail}, Severity: []model.Severity{model.SeveritySevere}},
+	}
+	require.NoError(t, ValidateFilter(f))
+
+	tests := []struct {
+		name    string
+		modify  func(*model.StormReportFilter)
+		wantErr string
+	}{
+		{"with eventTypes", func(f *model.StormReportFilter) {
+			f.EventTypes = []model.EventType{model.EventTypeWind}
+		}, "typeSeverityGroups cannot be combined"},
+		{"with eventTypeFilters", func(f *model.StormReportFilter) {
+			f.EventTypeFilters = []*model.EventTypeFilter{{EventType: model.EventTypeWind}}
+		}, "typeSeverityGroups cannot be combined"},
+		{"empty group", func(f *model.StormReportFilter) {
+			f.TypeSeverityGroups = append(f.TypeSeverityGroups, model.TypeSeverityGroup{})
+		}, "typeSeverityGroups[2]: requires eventTypes or severity"},
+		{"too many", func(f *model.StormReportFilter) {
+			for range MaxTypeSeverityGroups {
+				f.TypeSeverityGroups = append(f.TypeSeverityGroups, f.TypeSeverityGroups[0])
+			}
+		}, "at most 5 typeSeverityGroups"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := *f
+			g.TypeSeverityGroups = slices.Clone(f.TypeSeverityGroups)
+			tt.modify(&g)
+			err := ValidateFilter(&g)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateFilter_TypeSeverityGroupsMagnitudeUnits(t *testing.T) {
+	f := validFilter()
+	minMag := 1.0
+	f.MinMagnitude = &minMag
+	f.TypeSeverityGroups = []model.TypeSeverityGroup{
+		{EventTypes: []model.EventType{model.EventTypeHail}},
+		{EventTypes: []model.EventType{model.EventTypeWind}, Severity: []model.Severity{model.SeveritySevere}},
+	}
+
+	err := ValidateFilter(f)
+	require.Error(t, err, "the groups' event types are measured in different units")
+	assert.Contains(t, err.Error(), "different units")
+}
+
 func TestValidateFilter_LimitExceedsMax(t *testing.T) {
 	f := validFilter()
 	limit := 100
@@ -140,3 +200,784 @@ func TestValidateFilter_LimitPreservedWhenValid(t *testing.T) {
 	require.NoError(t, ValidateFilter(f))
 	assert.Equal(t, 10, *f.Limit)
 }
+
+func TestRoundTimeRange_WidensWindow(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 12, 30, 45, 123, time.UTC),
+		To:   time.Date(2024, 4, 26, 18, 15, 5, 0, time.UTC),
+	}
+
+	RoundTimeRange(&tr, time.Minute)
+
+	assert.Equal(t, time.Date(2024, 4, 26, 12, 30, 0, 0, time.UTC), tr.From, "from is floored")
+	assert.Equal(t, time.Date(2024, 4, 26, 18, 16, 0, 0, time.UTC), tr.To, "to is ceiled")
+}
+
+func TestRoundTimeRange_AlignedBoundsUnchanged(t *testing.T) {
+	f := validFilter()
+	want := f.TimeRange
+
+	RoundTimeRange(&f.TimeRange, time.Hour)
+
+	assert.Equal(t, want, f.TimeRange)
+}
+
+func TestRoundTimeRange_StabilizesQueryParams(t *testing.T) {
+	a := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 1, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 59, 500, time.UTC),
+	}
+	b := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 42, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 3, 0, time.UTC),
+	}
+
+	RoundTimeRange(&a, time.Minute)
+	RoundTimeRange(&b, time.Minute)
+
+	assert.Equal(t, a, b, "requests within the same minute should produce identical bounds")
+}
+
+func TestRoundTimeRange_Disabled(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 12, 30, 45, 0, time.UTC),
+		To:   time.Date(2024, 4, 26, 18, 15, 5, 0, time.UTC),
+	}
+	want := tr
+
+	RoundTimeRange(&tr, 0)
+
+	assert.Equal(t, want, tr)
+}
+
+func TestValidateFilter_DeltaOnlyRequiresUpdatedAfter(t *testing.T) {
+	f := validFilter()
+	deltaOnly := true
+	f.DeltaOnly = &deltaOnly
+
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deltaOnly requires updatedAfter")
+
+	since := f.TimeRange.From
+	f.UpdatedAfter = &since
+	require.NoError(t, ValidateFilter(f))
+}
+
+func TestValidateFilter_NearPopulatedPlaceRadius(t *testing.T) {
+	f := validFilter()
+	f.NearPopulatedPlace = &model.PopulatedPlaceFilter{MinPopulation: 10000, RadiusMiles: 250}
+
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nearPopulatedPlace.radiusMiles")
+
+	f.NearPopulatedPlace.RadiusMiles = 15
+	require.NoError(t, ValidateFilter(f))
+}
+
+func TestValidateFilter_CoordinatePrecision(t *testing.T) {
+	f := validFilter()
+	require.NoError(t, ValidateFilter(f))
+	require.NotNil(t, f.CoordinatePrecision)
+	assert.Equal(t, DefaultCoordinatePrecision, *f.CoordinatePrecision)
+
+	p := MaxCoordinatePrecision + 1
+	f.CoordinatePrecision = &p
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "coordinatePrecision")
+}
+
+func TestValidateFilter_MagnitudeRange(t *testing.T) {
+	f := validFilter()
+	f.EventTypes = []model.EventType{model.EventTypeHail}
+	lo, hi := 2.0, 1.0
+	f.MinMagnitude, f.MaxMagnitude = &lo, &hi
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maxMagnitude is below minMagnitude")
+
+	hi = 2.0
+	require.NoError(t, ValidateFilter(f), "equal bounds match exactly that magnitude")
+}
+
+func TestValidateFilter_MagnitudeUnit(t *testing.T) {
+	unit := func(u model.MagnitudeUnit) *model.MagnitudeUnit { return &u }
+	one := 1.0
+
+	tests := []struct {
+		name   string
+		modify func(f *model.StormReportFilter)
+		msg    string // empty when valid
+	}{
+		{"bound across hail and wind", func(f *model.StormReportFilter) {
+			f.EventTypes = []model.EventType{model.EventTypeHail, model.EventTypeWind}
+			f.MinMagnitude = &one
+		}, "different units (HAIL in, WIND mph)"},
+		{"bound across all types", func(f *model.StormReportFilter) {
+			f.MaxMagnitude = &one
+		}, "set magnitudeUnit"},
+		{"explicit UNSPECIFIED across types", func(f *model.StormReportFilter) {
+			f.EventTypes = []model.EventType{model.EventTypeWind, model.EventTypeTornado}
+			f.MinMagnitude = &one
+			f.MagnitudeUnit = unit(model.MagnitudeUnitUnspecified)
+		}, "different units (WIND mph, TORNADO f_scale)"},
+		{"inherited by mixed per-type conditions", func(f *model.StormReportFilter) {
+			f.MinMagnitude = &one
+			f.EventTypes = []model.EventType{model.EventTypeWind}
+			f.EventTypeFilters = []*model.EventTypeFilter{{EventType: model.EventTypeHail}}
+		}, "different units (HAIL in, WIND mph)"},
+		{"unit matches no selected type", func(f *model.StormReportFilter) {
+			f.EventTypes = []model.EventType{model.EventTypeWind}
+			f.MinMagnitude = &one
+			f.MagnitudeUnit = unit(model.MagnitudeUnitInches)
+		}, "magnitudeUnit INCHES matches none"},
+		{"unknown unit", func(f *model.StormReportFilter) {
+			f.MagnitudeUnit = unit("FURLONGS")
+		}, `invalid magnitudeUnit "FURLONGS"`},
+		{"single type", func(f *model.StormReportFilter) {
+			f.EventTypes = []model.EventType{model.EventTypeHail}
+			f.MinMagnitude = &one
+		}, ""},
+		{"unit scopes a bound across types", func(f *model.StormReportFilter) {
+			f.EventTypes = []model.EventType{model.EventTypeHail, model.EventTypeWind}
+			f.MinMagnitude = &one
+			f.MagnitudeUnit = unit(model.MagnitudeUnitInches)
+		}, ""},
+		{"unit scopes a bound across all types", func(f *model.StormReportFilter) {
+			f.MinMagnitude = &one
+			f.MagnitudeUnit = unit(model.MagnitudeUnitFScale)
+		}, ""},
+		{"every per-type condition overrides the bound", func(f *model.StormReportFilter) {
+			f.MinMagnitude = &one
+			f.EventTypeFilters = []*model.EventTypeFilter{
+				{EventType: model.EventTypeHail, MinMagnitude: &one},
+				{EventType: model.EventTypeWind, MinMagnitude: &one},
+			}
+		}, ""},
+		{"unit without a bound", func(f *model.StormReportFilter) {
+			f.MagnitudeUnit = unit(model.MagnitudeUnitMph)
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := validFilter()
+			tt.modify(f)
+			err := ValidateFilter(f)
+			if tt.msg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
+
+func TestValidateFilter_EventTypeFilterMagnitudeRange(t *testing.T) {
+	f := validFilter()
+	lo, hi := 2.0, 1.0
+	f.EventTypeFilters = []*model.EventTypeFilter{{EventType: model.EventTypeHail, MinMagnitude: &lo, MaxMagnitude: &hi}}
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "eventTypeFilters[0]: maxMagnitude is below minMagnitude")
+
+	// The global minMagnitude applies when the type sets none.
+	f = validFilter()
+	f.MinMagnitude = &lo
+	f.EventTypeFilters = []*model.EventTypeFilter{{EventType: model.EventTypeHail, MaxMagnitude: &hi}}
+	require.Error(t, ValidateFilter(f))
+
+	hi = 3.0
+	require.NoError(t, ValidateFilter(f))
+}
+
+func TestValidateFilter_MinRemarksLength(t *testing.T) {
+	f := validFilter()
+	n := 1
+	f.MinRemarksLength = &n
+	require.NoError(t, ValidateFilter(f))
+
+	n = 0
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "minRemarksLength must be at least 1")
+}
+
+func TestValidateFilter_MagnitudePrecision(t *testing.T) {
+	f := validFilter()
+	require.NoError(t, ValidateFilter(f))
+	assert.Nil(t, f.MagnitudePrecision, "no default: magnitudes unrounded")
+
+	ok, bad := MaxMagnitudePrecision, -1
+	f.MagnitudePrecision = &model.MagnitudePrecision{Hail: &ok, Tornado: &bad}
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "magnitudePrecision.tornado")
+}
+
+func TestValidateFilter_WarningRequiresSelector(t *testing.T) {
+	f := validFilter()
+	f.Warning = &model.WarningFilter{}
+
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "warning requires id or types")
+
+	f.Warning.Types = []string{"TO.W"}
+	require.NoError(t, ValidateFilter(f))
+}
+
+func TestValidateFilter_UnwarnedOnlyWithoutSelector(t *testing.T) {
+	f := validFilter()
+	unwarned := true
+	f.Warning = &model.WarningFilter{UnwarnedOnly: &unwarned}
+	require.NoError(t, ValidateFilter(f))
+}
+
+func bboxAndNearFilter() *model.StormReportFilter {
+	f := validFilter()
+	radius := 25.0
+	f.Near = &model.GeoRadiusFilter{Lat: 35.2, Lon: -97.4, RadiusMiles: &radius}
+	f.BBox = &model.BoundingBoxFilter{MinLat: 35, MaxLat: 36, MinLon: -98, MaxLon: -97}
+	return f
+}
+
+func TestResolveGeoConflict_ErrorByDefault(t *testing.T) {
+	for _, mode := range []GeoConflictMode{"", GeoConflictError} {
+		err := ResolveGeoConflict(bboxAndNearFilter(), mode)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "near and bbox cannot be combined")
+	}
+}
+
+func TestResolveGeoConflict_Intersect(t *testing.T) {
+	f := bboxAndNearFilter()
+	require.NoError(t, ResolveGeoConflict(f, GeoConflictIntersect))
+	assert.NotNil(t, f.Near)
+	assert.NotNil(t, f.BBox)
+}
+
+func TestResolveGeoConflict_BBoxOnly(t *testing.T) {
+	f := bboxAndNearFilter()
+	require.NoError(t, ResolveGeoConflict(f, GeoConflictBBoxOnly))
+	assert.Nil(t, f.Near)
+	assert.NotNil(t, f.BBox)
+}
+
+func TestResolveGeoConflict_SingleGeoFilterUnchanged(t *testing.T) {
+	f := bboxAndNearFilter()
+	f.Near = nil
+	require.NoError(t, ResolveGeoConflict(f, GeoConflictError))
+}
+
+func TestValidateFilter_BBoxInverted(t *testing.T) {
+	f := validFilter()
+	f.BBox = &model.BoundingBoxFilter{MinLat: 36, MaxLat: 35, MinLon: -98, MaxLon: -97}
+
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bbox min must not exceed max")
+}
+
+func TestValidateFilter_CorrectionStatusDefaultExcludesSuperseded(t *testing.T) {
+	f := validFilter()
+	require.NoError(t, ValidateFilter(f))
+	assert.Equal(t, []string{model.CorrectionStatusOriginal, model.CorrectionStatusCorrected}, f.CorrectionStatus)
+	assert.NotContains(t, f.CorrectionStatus, model.CorrectionStatusSuperseded)
+
+	f = validFilter()
+	f.CorrectionStatus = []string{model.CorrectionStatusSuperseded}
+	require.NoError(t, ValidateFilter(f))
+	assert.Equal(t, []string{"deleted-supersede"}, f.CorrectionStatus, "explicit statuses kept")
+}
+
+func TestValidateFilter_InvalidCorrectionStatus(t *testing.T) {
+	f := validFilter()
+	f.CorrectionStatus = []string{"original", "retracted"}
+
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid correctionStatus "retracted"`)
+}
+
+func TestValidateFilter_InvalidTimeColumn(t *testing.T) {
+	f := validFilter()
+	tc := model.TimeColumn("BEGIN_TIME")
+	f.TimeColumn = &tc
+
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid timeColumn")
+}
+
+func TestValidateFilter_InvalidDayNight(t *testing.T) {
+	f := validFilter()
+	dn := model.DayNight("DUSK")
+	f.DayNight = &dn
+
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid dayNight")
+}
+
+func TestValidatePercentile(t *testing.T) {
+	for _, p := range []float64{0, 0.5, 0.9, 1} {
+		assert.NoError(t, ValidatePercentile(p), p)
+	}
+	for _, p := range []float64{-0.1, 1.01, 90, math.NaN()} {
+		assert.Error(t, ValidatePercentile(p), p)
+	}
+}
+
+func TestValidateReportRate(t *testing.T) {
+	day := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, ValidateReportRate(day, 60, 1))
+	require.NoError(t, ValidateReportRate(day, MaxRateWindowMinutes, MaxRateIntervals))
+
+	tests := []struct {
+		name              string
+		window, intervals int
+		msg               string
+	}{
+		{"zero window", 0, 1, "windowMinutes must be between"},
+		{"window too long", MaxRateWindowMinutes + 1, 1, "windowMinutes must be between"},
+		{"zero intervals", 60, 0, "intervals must be between"},
+		{"too many intervals", 60, MaxRateIntervals + 1, "intervals must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReportRate(day, tt.window, tt.intervals)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+
+	hour := model.TimeRange{From: day.From, To: day.From.Add(time.Hour)}
+	err := ValidateReportRate(hour, 90, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not exceed the timeRange span")
+}
+
+func TestValidateDamageTotals(t *testing.T) {
+	year := model.TimeRange{
+		From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, ValidateDamageTotals(year, model.BucketUnitWeek))
+	require.NoError(t, ValidateDamageTotals(year, model.BucketUnitMonth))
+
+	err := ValidateDamageTotals(year, model.BucketUnitDay)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spans up to 368 day buckets")
+
+	err = ValidateDamageTotals(year, "FORTNIGHT")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid bucket")
+}
+
+func TestValidateReportHistogram(t *testing.T) {
+	week := model.TimeRange{
+		From: time.Date(2024, 4, 22, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, ValidateReportHistogram(week, model.BucketUnitHour))
+	require.NoError(t, ValidateReportHistogram(week, model.BucketUnitDay))
+
+	month := model.TimeRange{From: week.From, To: week.From.AddDate(0, 1, 0)}
+	err := ValidateReportHistogram(month, model.BucketUnitHour)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spans up to 722 hour intervals")
+
+	for _, interval := range []model.BucketUnit{model.BucketUnitYear, "FORTNIGHT"} {
+		err = ValidateReportHistogram(week, interval)
+		require.Error(t, err, interval)
+		assert.Contains(t, err.Error(), "must be HOUR, DAY, WEEK, or MONTH")
+	}
+}
+
+func TestValidateCoverageGaps(t *testing.T) {
+	day := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, ValidateCoverageGaps(day, 60))
+	require.NoError(t, ValidateCoverageGaps(day, MaxCoverageBucketMinutes))
+
+	tests := []struct {
+		name          string
+		bucketMinutes int
+		msg           string
+	}{
+		{"zero bucket", 0, "bucketMinutes must be between"},
+		{"bucket too long", MaxCoverageBucketMinutes + 1, "bucketMinutes must be between"},
+		{"too many buckets", 7, "spans 206 buckets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCoverageGaps(day, tt.bucketMinutes)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	require.NoError(t, ValidateTimezone("UTC"))
+	require.NoError(t, ValidateTimezone("America/Chicago"))
+
+	for _, tz := range []string{"", "Local", "Mars/Olympus_Mons", "CST6CDT; DROP TABLE"} {
+		err := ValidateTimezone(tz)
+		require.Error(t, err, tz)
+		assert.Contains(t, err.Error(), "invalid timezone")
+	}
+}
+
+func TestValidateSpotterID(t *testing.T) {
+	id := "  KC5ABC "
+	require.NoError(t, ValidateSpotterID(&id))
+	assert.Equal(t, "KC5ABC", id)
+
+	blank := "   "
+	err := ValidateSpotterID(&blank)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spotterId is required")
+
+	long := strings.Repeat("é", MaxSpotterIDLength+1)
+	err = ValidateSpotterID(&long)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at most 64 characters")
+}
+
+func TestValidateNearbyReports(t *testing.T) {
+	require.NoError(t, ValidateNearbyReports(24, 10))
+	require.NoError(t, ValidateNearbyReports(MaxNearbyWindowHours, MaxPageSize))
+
+	tests := []struct {
+		name               string
+		windowHours, limit int
+		msg                string
+	}{
+		{"zero window", 0, 10, "windowHours must be between"},
+		{"window too long", MaxNearbyWindowHours + 1, 10, "windowHours must be between"},
+		{"zero limit", 24, 0, "limit must be between"},
+		{"limit too high", 24, MaxPageSize + 1, "limit must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNearbyReports(tt.windowHours, tt.limit)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
+
+func TestApplyDefaultWindow(t *testing.T) {
+	now := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+
+	f := &model.StormReportFilter{}
+	require.NoError(t, ApplyDefaultWindow(f, 24*time.Hour, now))
+	assert.Equal(t, now.Add(-24*time.Hour), f.TimeRange.From)
+	assert.Equal(t, now, f.TimeRange.To)
+	assert.True(t, f.TimeRangeDefaulted)
+
+	// A supplied range is left alone
+	f = validFilter()
+	want := f.TimeRange
+	require.NoError(t, ApplyDefaultWindow(f, 24*time.Hour, now))
+	assert.Equal(t, want, f.TimeRange)
+	assert.False(t, f.TimeRangeDefaulted)
+}
+
+func TestApplyDefaultWindow_DisabledRejects(t *testing.T) {
+	f := &model.StormReportFilter{}
+	err := ApplyDefaultWindow(f, 0, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeRange is required")
+}
+
+func TestPrepareFilter_DefaultWindow(t *testing.T) {
+	r := &Resolver{DefaultWindow: 6 * time.Hour, TimeRounding: time.Minute}
+	f := &model.StormReportFilter{}
+	before := time.Now()
+	require.NoError(t, r.PrepareFilter(f))
+
+	assert.True(t, f.TimeRangeDefaulted)
+	assert.Equal(t, 6*time.Hour+time.Minute, f.TimeRange.To.Sub(f.TimeRange.From), "window rounded outward")
+	assert.False(t, f.TimeRange.To.Before(before))
+}
+
+func TestPrepareFilter_PageLimitExceeded(t *testing.T) {
+	m := observability.NewTestMetrics()
+	r := &Resolver{Metrics: m}
+
+	atMax, overMax := MaxPageSize, MaxPageSize+1
+	f := validFilter()
+	f.Limit = &atMax
+	require.NoError(t, r.PrepareFilter(f))
+	assert.Zero(t, testutil.ToFloat64(m.PageLimitExceeded))
+
+	f = validFilter()
+	f.Limit = &overMax
+	require.Error(t, r.PrepareFilter(f))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.PageLimitExceeded))
+
+	f = validFilter()
+	f.First = &overMax
+	require.Error(t, r.PrepareFilter(f))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.PageLimitExceeded))
+}
+
+func TestPrepareFilter_TimeRangeBounds(t *testing.T) {
+	r := &Resolver{MaxTimeRange: 366 * 24 * time.Hour}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	inverted := &model.StormReportFilter{TimeRange: model.TimeRange{From: from, To: from.Add(-time.Hour)}}
+	assert.ErrorContains(t, r.PrepareFilter(inverted), "timeRange.to must be after timeRange.from")
+
+	unbounded := &model.StormReportFilter{}
+	assert.ErrorContains(t, r.PrepareFilter(unbounded), "timeRange is required")
+
+	tooWide := &model.StormReportFilter{TimeRange: model.TimeRange{From: from, To: from.AddDate(1, 0, 1)}}
+	assert.ErrorContains(t, r.PrepareFilter(tooWide), "timeRange spans more than 366 days")
+
+	// A zero from with a set to is a huge span, not an open bound.
+	openStart := &model.StormReportFilter{TimeRange: model.TimeRange{To: from}}
+	assert.ErrorContains(t, r.PrepareFilter(openStart), "timeRange spans more than")
+
+	leapYear := &model.StormReportFilter{TimeRange: model.TimeRange{From: from, To: from.AddDate(1, 0, 0)}}
+	require.NoError(t, r.PrepareFilter(leapYear), "exactly the maximum is allowed")
+}
+
+func TestValidateFilter_KeywordSearch(t *testing.T) {
+	f := validFilter()
+	kw := "  hail damage "
+	f.KeywordSearch = &kw
+	require.NoError(t, ValidateFilter(f))
+	assert.Equal(t, "hail damage", *f.KeywordSearch, "trimmed")
+
+	blank := "   "
+	f.KeywordSearch = &blank
+	err := ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "keywordSearch must not be empty")
+
+	long := strings.Repeat("a", MaxKeywordLength+1)
+	f.KeywordSearch = &long
+	err = ValidateFilter(f)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at most 100 characters")
+}
+
+func TestValidateFilter_CountyPrefix(t *testing.T) {
+	f := validFilter()
+	prefix := "  Dall "
+	f.CountyPrefix = &prefix
+	require.NoError(t, ValidateFilter(f))
+	assert.Equal(t, "Dall", *f.CountyPrefix, "trimmed")
+
+	blank := "   "
+	f.CountyPrefix = &blank
+	assert.ErrorContains(t, ValidateFilter(f), "countyPrefix must not be empty")
+
+	long := strings.Repeat("a", MaxCountyPrefixLength+1)
+	f.CountyPrefix = &long
+	assert.ErrorContains(t, ValidateFilter(f), "countyPrefix must be at most 64 characters")
+}
+
+func TestValidateFilter_CountyOutlierStdDevs(t *testing.T) {
+	for _, k := range []float64{0.5, 3, MaxCountyOutlierStdDevs} {
+		f := validFilter()
+		f.CountyOutlierStdDevs = &k
+		require.NoError(t, ValidateFilter(f), k)
+	}
+	for _, k := range []float64{0, -1, MaxCountyOutlierStdDevs + 0.1, math.NaN()} {
+		f := validFilter()
+		f.CountyOutlierStdDevs = &k
+		assert.ErrorContains(t, ValidateFilter(f), "countyOutlierStdDevs must be greater than 0 and at most 10", k)
+	}
+}
+
+func TestValidateFilter_PolygonClosesRing(t *testing.T) {
+	f := validFilter()
+	f.Polygon = []model.LatLon{{Lat: 35, Lon: -98}, {Lat: 36, Lon: -97}, {Lat: 35, Lon: -96}}
+	require.NoError(t, ValidateFilter(f))
+	assert.Equal(t, []model.LatLon{{Lat: 35, Lon: -98}, {Lat: 36, Lon: -97}, {Lat: 35, Lon: -96}, {Lat: 35, Lon: -98}}, f.Polygon)
+
+	closed := []model.LatLon{{Lat: 35, Lon: -98}, {Lat: 36, Lon: -97}, {Lat: 35, Lon: -96}, {Lat: 35, Lon: -98}}
+	f = validFilter()
+	f.Polygon = closed
+	require.NoError(t, ValidateFilter(f))
+	assert.Len(t, f.Polygon, 4, "already closed ring unchanged")
+}
+
+func TestValidateFilter_PolygonRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		ring    []model.LatLon
+		wantErr string
+	}{
+		{"empty", []model.LatLon{}, "at least 3 vertices"},
+		{"two vertices", []model.LatLon{{Lat: 35, Lon: -98}, {Lat: 36, Lon: -97}}, "at least 3 vertices"},
+		{"two vertices closed", []model.LatLon{{Lat: 35, Lon: -98}, {Lat: 36, Lon: -97}, {Lat: 35, Lon: -98}}, "at least 3 vertices"},
+		{"collinear", []model.LatLon{{Lat: 35, Lon: -98}, {Lat: 36, Lon: -97}, {Lat: 37, Lon: -96}}, "must enclose an area"},
+		{"out of range", []model.LatLon{{Lat: 95, Lon: -98}, {Lat: 36, Lon: -97}, {Lat: 35, Lon: -96}}, "out of range"},
+		{"too many", make([]model.LatLon, MaxPolygonVertices+1), "at most 200 vertices"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := validFilter()
+			f.Polygon = tt.ring
+			assert.ErrorContains(t, ValidateFilter(f), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateFilter_RemarkTerms(t *testing.T) {
+	f := validFilter()
+	f.RemarksInclude = []string{" golf ball "}
+	f.RemarksExclude = []string{"marble"}
+	require.NoError(t, ValidateFilter(f))
+	assert.Equal(t, []string{"golf ball"}, f.RemarksInclude, "trimmed")
+
+	f = validFilter()
+	f.RemarksExclude = []string{"  "}
+	assert.ErrorContains(t, ValidateFilter(f), "remarksExclude terms must not be empty")
+
+	f = validFilter()
+	f.RemarksInclude = []string{strings.Repeat("a", MaxKeywordLength+1)}
+	assert.ErrorContains(t, ValidateFilter(f), "remarksInclude terms must be at most 100 characters")
+
+	f = validFilter()
+	f.RemarksInclude = make([]string, MaxRemarkTerms+1)
+	assert.ErrorContains(t, ValidateFilter(f), "remarksInclude must have at most 10 terms")
+}
+
+func TestValidateFilter_After(t *testing.T) {
+	token, err := store.EncodeCursor(validFilter(), &model.StormReport{ID: "r-1"}, time.Now())
+	require.NoError(t, err)
+
+	f := validFilter()
+	f.After = &token
+	require.NoError(t, ValidateFilter(f))
+	require.NotNil(t, f.Cursor)
+	assert.Equal(t, "r-1", f.Cursor.ID)
+
+	offset := 20
+	f = validFilter()
+	f.After, f.Offset = &token, &offset
+	assert.ErrorContains(t, ValidateFilter(f), "after cannot be combined with offset")
+
+	deltaOnly := true
+	updated := time.Now()
+	f = validFilter()
+	f.After, f.DeltaOnly, f.UpdatedAfter = &token, &deltaOnly, &updated
+	assert.ErrorContains(t, ValidateFilter(f), "after cannot be combined with deltaOnly")
+
+	normalized := model.SortFieldMagnitudeNormalized
+	f = validFilter()
+	f.After, f.SortBy = &token, &normalized
+	assert.ErrorContains(t, ValidateFilter(f), "not supported with sortBy MAGNITUDE_NORMALIZED")
+
+	garbage := "garbage"
+	f = validFilter()
+	f.After = &garbage
+	assert.ErrorContains(t, ValidateFilter(f), "not a valid cursor")
+}
+
+func TestValidateFilter_MaxLocationUncertainty(t *testing.T) {
+	f := validFilter()
+	negative := -1.0
+	f.MaxLocationUncertainty = &negative
+	assert.ErrorContains(t, ValidateFilter(f), "maxLocationUncertainty must not be negative")
+
+	zero := 0.0
+	f.MaxLocationUncertainty = &zero
+	assert.NoError(t, ValidateFilter(f))
+}
+
+func TestValidateFilter_First(t *testing.T) {
+	f := validFilter()
+	first := 5
+	f.First = &first
+	require.NoError(t, ValidateFilter(f))
+	require.NotNil(t, f.Limit)
+	assert.Equal(t, 5, *f.Limit, "first is an alias of limit")
+
+	f = validFilter()
+	f.First = &first
+	f.Limit = &first
+	assert.ErrorContains(t, ValidateFilter(f), "first cannot be combined with limit")
+
+	over := MaxPageSize + 1
+	f = validFilter()
+	f.First = &over
+	assert.ErrorContains(t, ValidateFilter(f), "limit exceeds maximum")
+}
+
+func TestValidateFilter_StormTrack(t *testing.T) {
+	t0 := time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC)
+	track := func(points ...*model.TrackPoint) *model.StormReportFilter {
+		f := validFilter()
+		f.StormTrack = &model.StormTrackFilter{Points: points, RadiusMiles: 10}
+		return f
+	}
+	a := &model.TrackPoint{Time: t0, Lat: 35, Lon: -98}
+	b := &model.TrackPoint{Time: t0.Add(time.Hour), Lat: 35.4, Lon: -97.2}
+
+	require.NoError(t, ValidateFilter(track(a, b)))
+
+	assert.ErrorContains(t, ValidateFilter(track(a)), "between 2 and 100 points")
+
+	assert.ErrorContains(t, ValidateFilter(track(b, a)), "stormTrack.points[1]: time must be after the previous point")
+	assert.ErrorContains(t, ValidateFilter(track(a, a)), "time must be after the previous point")
+
+	off := &model.TrackPoint{Time: t0.Add(2 * time.Hour), Lat: 91, Lon: 0}
+	assert.ErrorContains(t, ValidateFilter(track(a, b, off)), "stormTrack.points[2]: coordinates out of range")
+
+	f := track(a, b)
+	f.StormTrack.RadiusMiles = MaxRadiusMiles + 1
+	assert.ErrorContains(t, ValidateFilter(f), "stormTrack.radiusMiles must be between 0 and 200")
+}
+
+func TestValidateFilter_AtCoordinate(t *testing.T) {
+	lat, lon := 35.2271, -97.4395
+	f := validFilter()
+	f.AtLat, f.AtLon = &lat, &lon
+	require.NoError(t, ValidateFilter(f))
+
+	f = validFilter()
+	f.AtLat = &lat
+	assert.ErrorContains(t, ValidateFilter(f), "atLat and atLon must be set together")
+	f = validFilter()
+	f.AtLon = &lon
+	assert.ErrorContains(t, ValidateFilter(f), "atLat and atLon must be set together")
+
+	off := 95.0
+	f = validFilter()
+	f.AtLat, f.AtLon = &off, &lon
+	assert.ErrorContains(t, ValidateFilter(f), "atLat/atLon out of range")
+
+	f = validFilter()
+	f.AtLat, f.AtLon = &lat, &lon
+	f.Near = &model.GeoRadiusFilter{Lat: lat, Lon: lon}
+	assert.ErrorContains(t, ValidateFilter(f), "cannot be combined with near")
+}
+
+func TestValidateFilter_Geohash(t *testing.T) {
+	f := validFilter()
+	h := " 9Q8YY "
+	f.Geohash = &h
+	require.NoError(t, ValidateFilter(f))
+	assert.Equal(t, "9q8yy", *f.Geohash, "normalized")
+
+	for _, bad := range []string{"", "9q8a", "9q8yyyyyyyyyy"} {
+		f = validFilter()
+		f.Geohash = &bad
+		assert.ErrorContains(t, ValidateFilter(f), "geohash", bad)
+	}
+}
@@ -0,0 +1,73 @@
+package grpcapi
+
+import (
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/pb"
+)
+
+// FilterFromProto maps a request filter onto the domain filter used by the
+// store and GraphQL layer. Enum-valued strings must use the GraphQL enum names.
+// Limits and defaults are applied afterwards by PrepareFilter, not here.
+func FilterFromProto(f *pb.StormReportFilter) (model.StormReportFilter, error) {
+	var out model.StormReportFilter
+	if f == nil {
+		return out, fmt.Errorf("filter is required")
+	}
+	// Both bounds or neither; with neither, PrepareFilter applies the
+	// server's default window (or rejects the filter).
+	switch {
+	case f.GetFrom() == nil && f.GetTo() == nil:
+	case f.GetFrom() == nil || f.GetTo() == nil:
+		return out, fmt.Errorf("filter.from and filter.to are required")
+	default:
+		out.TimeRange = model.TimeRange{From: f.GetFrom().AsTime(), To: f.GetTo().AsTime()}
+	}
+
+	if n := f.GetNear(); n != nil {
+		out.Near = &model.GeoRadiusFilter{Lat: n.GetLat(), Lon: n.GetLon(), RadiusMiles: n.RadiusMiles}
+	}
+	out.States = f.GetStates()
+	out.Counties = f.GetCounties()
+
+	for _, v := range f.GetEventTypes() {
+		et := model.EventType(v)
+		if !et.IsValid() {
+			return out, fmt.Errorf("invalid eventType %q", v)
+		}
+		out.EventTypes = append(out.EventTypes, et)
+	}
+	for _, v := range f.GetSeverity() {
+		sev := model.Severity(v)
+		if !sev.IsValid() {
+			return out, fmt.Errorf("invalid severity %q", v)
+		}
+		out.Severity = append(out.Severity, sev)
+	}
+	out.MinMagnitude = f.MinMagnitude
+
+	if f.SortBy != nil {
+		sf := model.SortField(f.GetSortBy())
+		if !sf.IsValid() {
+			return out, fmt.Errorf("invalid sortBy %q", f.GetSortBy())
+		}
+		out.SortBy = &sf
+	}
+	if f.SortOrder != nil {
+		so := model.SortOrder(f.GetSortOrder())
+		if !so.IsValid() {
+			return out, fmt.Errorf("invalid sortOrder %q", f.GetSortOrder())
+		}
+		out.SortOrder = &so
+	}
+	if f.Limit != nil {
+		limit := int(f.GetLimit())
+		out.Limit = &limit
+	}
+	if f.Offset != nil {
+		offset := int(f.GetOffset())
+		out.Offset = &offset
+	}
+	return out, nil
+}
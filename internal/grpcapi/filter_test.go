@@ -0,0 +1,105 @@
+package grpcapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	testFrom = time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC)
+)
+
+func protoFilter() *pb.StormReportFilter {
+	return &pb.StormReportFilter{
+		From: timestamppb.New(testFrom),
+		To:   timestamppb.New(testTo),
+	}
+}
+
+func TestFilterFromProto_AllFields(t *testing.T) {
+	f := protoFilter()
+	f.Near = &pb.GeoRadiusFilter{Lat: 35.2, Lon: -97.4, RadiusMiles: proto.Float64(30)}
+	f.States = []string{"OK", "TX"}
+	f.Counties = []string{"Cleveland"}
+	f.EventTypes = []string{"HAIL", "TORNADO"}
+	f.Severity = []string{"SEVERE"}
+	f.MinMagnitude = proto.Float64(1.5)
+	f.SortBy = proto.String("MAGNITUDE")
+	f.SortOrder = proto.String("ASC")
+	f.Limit = proto.Int32(10)
+	f.Offset = proto.Int32(20)
+
+	got, err := FilterFromProto(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, model.TimeRange{From: testFrom, To: testTo}, got.TimeRange)
+	require.NotNil(t, got.Near)
+	assert.InDelta(t, 35.2, got.Near.Lat, 1e-9)
+	assert.InDelta(t, -97.4, got.Near.Lon, 1e-9)
+	require.NotNil(t, got.Near.RadiusMiles)
+	assert.InDelta(t, 30.0, *got.Near.RadiusMiles, 1e-9)
+	assert.Equal(t, []string{"OK", "TX"}, got.States)
+	assert.Equal(t, []string{"Cleveland"}, got.Counties)
+	assert.Equal(t, []model.EventType{model.EventTypeHail, model.EventTypeTornado}, got.EventTypes)
+	assert.Equal(t, []model.Severity{model.SeveritySevere}, got.Severity)
+	require.NotNil(t, got.MinMagnitude)
+	assert.InDelta(t, 1.5, *got.MinMagnitude, 1e-9)
+	assert.Equal(t, model.SortFieldMagnitude, *got.SortBy)
+	assert.Equal(t, model.SortOrderAsc, *got.SortOrder)
+	assert.Equal(t, 10, *got.Limit)
+	assert.Equal(t, 20, *got.Offset)
+}
+
+func TestFilterFromProto_UnsetOptionalsStayNil(t *testing.T) {
+	got, err := FilterFromProto(protoFilter())
+	require.NoError(t, err)
+
+	assert.Nil(t, got.Near)
+	assert.Nil(t, got.MinMagnitude)
+	assert.Nil(t, got.SortBy)
+	assert.Nil(t, got.SortOrder)
+	assert.Nil(t, got.Limit)
+	assert.Nil(t, got.Offset)
+}
+
+func TestFilterFromProto_NoTimeRange(t *testing.T) {
+	f := protoFilter()
+	f.From, f.To = nil, nil
+	got, err := FilterFromProto(f)
+	require.NoError(t, err)
+	assert.True(t, got.TimeRange.IsZero(), "left for PrepareFilter's default window")
+}
+
+func TestFilterFromProto_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*pb.StormReportFilter)
+		wantErr string
+	}{
+		{"missing from", func(f *pb.StormReportFilter) { f.From = nil }, "filter.from and filter.to are required"},
+		{"bad event type", func(f *pb.StormReportFilter) { f.EventTypes = []string{"hail"} }, `invalid eventType "hail"`},
+		{"bad severity", func(f *pb.StormReportFilter) { f.Severity = []string{"BAD"} }, `invalid severity "BAD"`},
+		{"bad sort field", func(f *pb.StormReportFilter) { f.SortBy = proto.String("ID") }, `invalid sortBy "ID"`},
+		{"bad sort order", func(f *pb.StormReportFilter) { f.SortOrder = proto.String("UP") }, `invalid sortOrder "UP"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := protoFilter()
+			tt.mutate(f)
+			_, err := FilterFromProto(f)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := FilterFromProto(nil)
+	require.Error(t, err)
+}
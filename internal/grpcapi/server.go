@@ -0,0 +1,90 @@
+// Package grpcapi exposes the storm report queries as a gRPC service
+// alongside the GraphQL API, sharing the store and filter validation.
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Store is the subset of store.Store used by the gRPC service.
+type Store interface {
+	ListStormReports(ctx context.Context, filter *model.StormReportFilter) ([]*model.StormReport, int, error)
+	GetStormReport(ctx context.Context, id string) (*model.StormReport, error)
+	CountStormReports(ctx context.Context, filter *model.StormReportFilter) (int, error)
+}
+
+// Server implements pb.StormReportServiceServer.
+type Server struct {
+	pb.UnimplementedStormReportServiceServer
+
+	store   Store
+	prepare apifilter.Preparer
+}
+
+// NewServer creates a gRPC service backed by the given store.
+func NewServer(s Store, p apifilter.Preparer) *Server {
+	return &Server{store: s, prepare: p}
+}
+
+// ListStormReports returns one page of reports matching the filter.
+func (s *Server) ListStormReports(ctx context.Context, req *pb.ListStormReportsRequest) (*pb.StormReportConnection, error) {
+	filter, err := s.filter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	reports, total, err := s.store.ListStormReports(ctx, &filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "list storm reports failed")
+	}
+	offset := 0
+	if filter.Offset != nil {
+		offset = *filter.Offset
+	}
+	return pb.NewConnection(reports, total, offset+len(reports) < total), nil
+}
+
+// GetStormReport returns a single report by ID.
+func (s *Server) GetStormReport(ctx context.Context, req *pb.GetStormReportRequest) (*pb.StormReport, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	report, err := s.store.GetStormReport(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "get storm report failed")
+	}
+	if report == nil {
+		return nil, status.Errorf(codes.NotFound, "storm report %q not found", req.GetId())
+	}
+	return pb.FromReport(report), nil
+}
+
+// CountStormReports returns the number of reports matching the filter.
+func (s *Server) CountStormReports(ctx context.Context, req *pb.CountStormReportsRequest) (*pb.CountStormReportsResponse, error) {
+	filter, err := s.filter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.store.CountStormReports(ctx, &filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "count storm reports failed")
+	}
+	return &pb.CountStormReportsResponse{Count: int32(n)}, nil //nolint:gosec // bounded by table size
+}
+
+// filter maps and validates a request filter, returning InvalidArgument on failure.
+func (s *Server) filter(f *pb.StormReportFilter) (model.StormReportFilter, error) {
+	filter, err := FilterFromProto(f)
+	if err != nil {
+		return filter, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.prepare.PrepareFilter(&filter); err != nil {
+		return filter, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return filter, nil
+}
@@ -0,0 +1,115 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeStore struct {
+	reports []*model.StormReport
+	total   int
+	got     *model.StormReportFilter
+}
+
+func (f *fakeStore) ListStormReports(_ context.Context, filter *model.StormReportFilter) ([]*model.StormReport, int, error) {
+	f.got = filter
+	return f.reports, f.total, nil
+}
+
+func (f *fakeStore) GetStormReport(_ context.Context, id string) (*model.StormReport, error) {
+	for _, r := range f.reports {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStore) CountStormReports(_ context.Context, filter *model.StormReportFilter) (int, error) {
+	f.got = filter
+	return f.total, nil
+}
+
+// dial starts the service on an in-memory listener and returns a client.
+func dial(t *testing.T, s Store) pb.StormReportServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterStormReportServiceServer(srv, NewServer(s, &graph.Resolver{}))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewStormReportServiceClient(conn)
+}
+
+func TestServer_ListStormReports(t *testing.T) {
+	s := &fakeStore{
+		reports: []*model.StormReport{{ID: "r1", EventType: "hail"}, {ID: "r2", EventType: "wind"}},
+		total:   5,
+	}
+	client := dial(t, s)
+
+	f := protoFilter()
+	f.States = []string{"TX"}
+	resp, err := client.ListStormReports(context.Background(), &pb.ListStormReportsRequest{Filter: f})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(5), resp.GetTotalCount())
+	assert.True(t, resp.GetHasMore())
+	require.Len(t, resp.GetReports(), 2)
+	assert.Equal(t, "r1", resp.GetReports()[0].GetId())
+
+	// Shared GraphQL validation applied defaults before the store call
+	require.NotNil(t, s.got.Limit)
+	assert.Equal(t, graph.MaxPageSize, *s.got.Limit)
+	assert.Equal(t, []string{"TX"}, s.got.States)
+}
+
+func TestServer_ListStormReports_InvalidArgument(t *testing.T) {
+	client := dial(t, &fakeStore{})
+
+	f := protoFilter()
+	f.Limit = proto.Int32(graph.MaxPageSize + 1)
+	_, err := client.ListStormReports(context.Background(), &pb.ListStormReportsRequest{Filter: f})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "limit exceeds maximum")
+}
+
+func TestServer_GetStormReport(t *testing.T) {
+	client := dial(t, &fakeStore{reports: []*model.StormReport{{ID: "r1", EventType: "hail"}}})
+
+	resp, err := client.GetStormReport(context.Background(), &pb.GetStormReportRequest{Id: "r1"})
+	require.NoError(t, err)
+	assert.Equal(t, "hail", resp.GetEventType())
+
+	_, err = client.GetStormReport(context.Background(), &pb.GetStormReportRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_CountStormReports(t *testing.T) {
+	client := dial(t, &fakeStore{total: 271})
+
+	resp, err := client.CountStormReports(context.Background(), &pb.CountStormReportsRequest{Filter: protoFilter()})
+	require.NoError(t, err)
+	assert.Equal(t, int32(271), resp.GetCount())
+}
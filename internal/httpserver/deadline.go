@@ -0,0 +1,24 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// FinishGrace is how long a streaming handler may keep writing past its
+// request context's deadline, enough to report the failure to the client.
+const FinishGrace = 5 * time.Second
+
+// ExtendWriteDeadline moves the write deadline of a streaming response to
+// window from now. Handlers call it before the first row and after each
+// flush, so the server's WriteTimeout only cuts off a stream that stalls. The
+// deadline never goes past the deadline of ctx plus FinishGrace, so a stream
+// with a capped context cannot outlive the cap by extending itself.
+func ExtendWriteDeadline(ctx context.Context, rc *http.ResponseController, window time.Duration) error {
+	deadline := time.Now().Add(window)
+	if d, ok := ctx.Deadline(); ok && d.Add(FinishGrace).Before(deadline) {
+		deadline = d.Add(FinishGrace)
+	}
+	return rc.SetWriteDeadline(deadline)
+}
@@ -0,0 +1,38 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadlineRecorder records the write deadlines set through
+// http.ResponseController.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadline time.Time
+}
+
+func (d *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	d.deadline = t
+	return nil
+}
+
+func TestExtendWriteDeadline(t *testing.T) {
+	rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+	rc := http.NewResponseController(rec)
+
+	require.NoError(t, ExtendWriteDeadline(context.Background(), rc, time.Minute))
+	assert.WithinDuration(t, time.Now().Add(time.Minute), rec.deadline, 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, ExtendWriteDeadline(ctx, rc, time.Minute))
+	deadline, _ := ctx.Deadline()
+	assert.Equal(t, deadline.Add(FinishGrace), rec.deadline, "capped by the context deadline")
+}
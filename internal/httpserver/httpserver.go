@@ -0,0 +1,119 @@
+// Package httpserver runs the HTTP server and drains in-flight requests when
+// it shuts down.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+)
+
+// DefaultGracePeriod is how long in-flight requests get to finish once
+// shutdown begins.
+const DefaultGracePeriod = 15 * time.Second
+
+// ErrShuttingDown is the readiness error reported once shutdown has begun.
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Readiness wraps a readiness checker and reports not-ready from the moment
+// shutdown begins, so the load balancer deregisters the instance while its
+// in-flight requests drain.
+type Readiness struct {
+	checker  observability.ReadinessChecker
+	draining atomic.Bool
+}
+
+// NewReadiness returns a Readiness that defers to checker until shutdown.
+func NewReadiness(checker observability.ReadinessChecker) *Readiness {
+	return &Readiness{checker: checker}
+}
+
+// CheckReadiness implements observability.ReadinessChecker.
+func (r *Readiness) CheckReadiness(ctx context.Context) error {
+	if r.draining.Load() {
+		return ErrShuttingDown
+	}
+	return r.checker.CheckReadiness(ctx)
+}
+
+// Option configures RunServer.
+type Option func(*options)
+
+type options struct {
+	grace time.Duration
+	delay time.Duration
+	ready *Readiness
+}
+
+// WithGracePeriod sets how long in-flight requests get to finish once the
+// listener closes. Non-positive values select DefaultGracePeriod.
+func WithGracePeriod(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.grace = d
+		}
+	}
+}
+
+// WithPreShutdownDelay keeps serving for d after readiness turns not-ready
+// and before the listener closes, giving the load balancer time to notice and
+// stop routing new requests here. Non-positive values mean no delay.
+func WithPreShutdownDelay(d time.Duration) Option {
+	return func(o *options) { o.delay = max(d, 0) }
+}
+
+// WithReadiness makes ready report not-ready from the moment shutdown begins.
+func WithReadiness(ready *Readiness) Option {
+	return func(o *options) { o.ready = ready }
+}
+
+// RunServer serves srv on lis until ctx is done, then marks the readiness
+// (see WithReadiness) not-ready, keeps serving for the pre-shutdown delay,
+// stops accepting connections, and waits up to the grace period for in-flight
+// requests to finish. It returns nil after a clean drain, the Serve error if
+// the server fails first, or an error wrapping context.DeadlineExceeded if
+// requests were still running when the grace period ran out.
+func RunServer(ctx context.Context, srv *http.Server, lis net.Listener, opts ...Option) error {
+	o := options{grace: DefaultGracePeriod}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(lis) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	if o.ready != nil {
+		o.ready.draining.Store(true)
+	}
+	if o.delay > 0 {
+		t := time.NewTimer(o.delay)
+		select {
+		case err := <-serveErr:
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), o.grace)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("drain in-flight requests: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockChecker struct {
+	err error
+}
+
+func (m *mockChecker) CheckReadiness(_ context.Context) error {
+	return m.err
+}
+
+// startServer runs h under RunServer on a loopback port and returns its base
+// URL, the function that begins shutdown, and RunServer's result.
+func startServer(t *testing.T, h http.Handler, opts ...Option) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	done := make(chan error, 1)
+	go func() {
+		done <- RunServer(ctx, &http.Server{Handler: h, ReadHeaderTimeout: time.Second}, lis, opts...)
+	}()
+	return "http://" + lis.Addr().String(), cancel, done
+}
+
+func TestRunServer_DrainsInFlightRequests(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+	ready := NewReadiness(&mockChecker{})
+	url, shutdown, done := startServer(t, h, WithGracePeriod(5*time.Second), WithReadiness(ready))
+
+	type result struct {
+		body string
+		err  error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		b, err := io.ReadAll(resp.Body)
+		inFlight <- result{body: string(b), err: err}
+	}()
+	<-started
+	require.NoError(t, ready.CheckReadiness(context.Background()))
+
+	shutdown()
+	require.Eventually(t, func() bool {
+		return errors.Is(ready.CheckReadiness(context.Background()), ErrShuttingDown)
+	}, time.Second, 5*time.Millisecond, "not ready once shutdown begins")
+
+	// New connections are refused while the in-flight request is still running.
+	addr := url[len("http://"):]
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return true
+		}
+		_ = conn.Close()
+		return false
+	}, time.Second, 5*time.Millisecond)
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	_, err := client.Get(url)
+	require.Error(t, err, "new request refused")
+
+	close(release)
+	got := <-inFlight
+	require.NoError(t, got.err)
+	assert.Equal(t, "done", got.body, "in-flight request completes")
+	require.NoError(t, <-done)
+}
+
+func TestRunServer_GracePeriodExpires(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	h := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(started)
+		<-release
+	})
+	url, shutdown, done := startServer(t, h, WithGracePeriod(50*time.Millisecond))
+
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+
+	shutdown()
+	require.ErrorIs(t, <-done, context.DeadlineExceeded)
+}
+
+func TestRunServer_PreShutdownDelayKeepsServing(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	ready := NewReadiness(&mockChecker{})
+	url, shutdown, done := startServer(t, h, WithPreShutdownDelay(200*time.Millisecond), WithReadiness(ready))
+
+	shutdown()
+	require.Eventually(t, func() bool {
+		return errors.Is(ready.CheckReadiness(context.Background()), ErrShuttingDown)
+	}, time.Second, 5*time.Millisecond)
+
+	// Not ready, but still accepting requests until the delay passes.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, <-done)
+	_, err = client.Get(url)
+	require.Error(t, err, "listener closed after the delay")
+}
+
+func TestRunServer_DefaultGracePeriod(t *testing.T) {
+	o := options{grace: DefaultGracePeriod}
+	WithGracePeriod(0)(&o)
+	WithGracePeriod(-time.Second)(&o)
+	assert.Equal(t, DefaultGracePeriod, o.grace)
+}
+
+func TestReadiness_DefersToChecker(t *testing.T) {
+	errDB := errors.New("db not connected")
+	ready := NewReadiness(&mockChecker{err: errDB})
+	require.ErrorIs(t, ready.CheckReadiness(context.Background()), errDB)
+}
@@ -8,7 +8,6 @@ import (
 	"testing"
 
 	"github.com/99designs/gqlgen/graphql/handler"
-	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/couchcryptid/storm-data-api/internal/database"
 	"github.com/couchcryptid/storm-data-api/internal/graph"
 	"github.com/couchcryptid/storm-data-api/internal/model"
@@ -24,8 +23,9 @@ func startGraphQLServer(t *testing.T, s *store.Store) *httptest.Server {
 		Resolvers:  &graph.Resolver{Store: s},
 		Complexity: graph.NewComplexityRoot(),
 	}))
-	srv.Use(extension.FixedComplexityLimit(600))
+	srv.Use(&graph.ComplexityLimit{MaxComplexity: 600})
 	srv.Use(graph.DepthLimit{MaxDepth: 7})
+	srv.SetErrorPresenter(graph.ErrorPresenter)
 	return httptest.NewServer(srv)
 }
 
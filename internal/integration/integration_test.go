@@ -3,6 +3,7 @@
 package integration_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -16,13 +17,16 @@ import (
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
-	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/couchcryptid/storm-data-api/internal/admin"
 	"github.com/couchcryptid/storm-data-api/internal/database"
 	"github.com/couchcryptid/storm-data-api/internal/graph"
 	"github.com/couchcryptid/storm-data-api/internal/model"
 	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/couchcryptid/storm-data-api/internal/integration/solarref"
 	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/couchcryptid/storm-data-api/internal/tileapi"
 
+	"github.com/jackc/pgx/v5"
 	kafkago "github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -250,6 +254,31 @@ func TestStoreFilters(t *testing.T) {
 		}
 	})
 
+	t.Run("county prefix", func(t *testing.T) {
+		f := wideFilter()
+		prefix := "tarr"
+		f.CountyPrefix = &prefix
+		reports, count, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.Equal(t, 4, count, "prefix match is case-insensitive")
+		for _, r := range reports {
+			assert.Equal(t, "Tarrant", r.Location.County, testReportMsg, r.ID)
+		}
+	})
+
+	t.Run("keyword search", func(t *testing.T) {
+		f := wideFilter()
+		kw := "tarrant"
+		f.KeywordSearch = &kw
+		reports, count, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, count, 4, "county match is case-insensitive")
+		for _, r := range reports {
+			text := strings.ToLower(r.Location.County + " " + r.Comments)
+			assert.Contains(t, text, kw, testReportMsg, r.ID)
+		}
+	})
+
 	t.Run("minMagnitude filter", func(t *testing.T) {
 		f := wideFilter()
 		min := 1.75
@@ -262,127 +291,1348 @@ func TestStoreFilters(t *testing.T) {
 		}
 	})
 
-	t.Run("combined filters", func(t *testing.T) {
-		f := wideFilter()
-		f.EventTypes = []model.EventType{model.EventTypeHail}
-		f.States = []string{"TX"}
-		f.Severity = []model.Severity{model.SeveritySevere}
-		reports, count, err := s.ListStormReports(ctx, f)
-		require.NoError(t, err)
-		assert.Equal(t, 3, count)
-		for _, r := range reports {
-			assert.Equal(t, "hail", r.EventType, testReportMsg, r.ID)
-			assert.Equal(t, "TX", r.Location.State, testReportMsg, r.ID)
-			require.NotNil(t, r.Measurement.Severity, testReportMsg, r.ID)
-			assert.Equal(t, "severe", *r.Measurement.Severity, testReportMsg, r.ID)
-		}
-	})
+	t.Run("combined filters", func(t *testing.T) {
+		f := wideFilter()
+		f.EventTypes = []model.EventType{model.EventTypeHail}
+		f.States = []string{"TX"}
+		f.Severity = []model.Severity{model.SeveritySevere}
+		reports, count, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		for _, r := range reports {
+			assert.Equal(t, "hail", r.EventType, testReportMsg, r.ID)
+			assert.Equal(t, "TX", r.Location.State, testReportMsg, r.ID)
+			require.NotNil(t, r.Measurement.Severity, testReportMsg, r.ID)
+			assert.Equal(t, "severe", *r.Measurement.Severity, testReportMsg, r.ID)
+		}
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		f := wideFilter()
+		f.EventTypes = []model.EventType{model.EventType("BLIZZARD")}
+		reports, count, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("multiple event types filter", func(t *testing.T) {
+		f := wideFilter()
+		f.EventTypes = []model.EventType{model.EventTypeHail, model.EventTypeTornado}
+		_, count, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.Equal(t, 228, count)
+	})
+}
+
+func TestStoreSortingAndPagination(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	t.Run("sort by magnitude DESC", func(t *testing.T) {
+		f := wideFilter()
+		sortBy := model.SortFieldMagnitude
+		sortOrder := model.SortOrderDesc
+		f.SortBy = &sortBy
+		f.SortOrder = &sortOrder
+		reports, _, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(reports), 2)
+		for i := 1; i < len(reports); i++ {
+			assert.GreaterOrEqual(t, reports[i-1].Measurement.Magnitude, reports[i].Measurement.Magnitude,
+				"reports[%d] > reports[%d]", i-1, i)
+		}
+	})
+
+	t.Run("sort by magnitude ASC", func(t *testing.T) {
+		f := wideFilter()
+		sortBy := model.SortFieldMagnitude
+		sortOrder := model.SortOrderAsc
+		f.SortBy = &sortBy
+		f.SortOrder = &sortOrder
+		reports, _, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		for i := 1; i < len(reports); i++ {
+			assert.LessOrEqual(t, reports[i-1].Measurement.Magnitude, reports[i].Measurement.Magnitude,
+				"reports[%d] < reports[%d]", i-1, i)
+		}
+	})
+
+	t.Run("sort by state ASC", func(t *testing.T) {
+		f := wideFilter()
+		sortBy := model.SortFieldLocationState
+		sortOrder := model.SortOrderAsc
+		f.SortBy = &sortBy
+		f.SortOrder = &sortOrder
+		reports, _, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		for i := 1; i < len(reports); i++ {
+			assert.LessOrEqual(t, reports[i-1].Location.State, reports[i].Location.State,
+				"reports[%d] < reports[%d]", i-1, i)
+		}
+	})
+
+	t.Run("pagination limit", func(t *testing.T) {
+		f := wideFilter()
+		limit := 5
+		f.Limit = &limit
+		reports, totalCount, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.Len(t, reports, 5)
+		assert.Equal(t, 271, totalCount, "totalCount should ignore limit")
+	})
+
+	t.Run("pagination offset", func(t *testing.T) {
+		f := wideFilter()
+		limit := 5
+		f.Limit = &limit
+
+		page1, _, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+
+		offset := 5
+		f.Offset = &offset
+		page2, _, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.Len(t, page2, 5)
+
+		for _, r1 := range page1 {
+			for _, r2 := range page2 {
+				assert.NotEqual(t, r1.ID, r2.ID, "report should not appear on both pages")
+			}
+		}
+	})
+
+	t.Run("offset beyond total", func(t *testing.T) {
+		f := wideFilter()
+		offset := 300
+		f.Offset = &offset
+		reports, totalCount, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+		assert.Equal(t, 271, totalCount, "totalCount should still be 271")
+	})
+}
+
+func TestStoreListWithStats(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	f := wideFilter()
+	f.EventTypes = []model.EventType{model.EventTypeHail}
+	want, wantCount, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+
+	reports, stats, err := s.ListStormReportsWithStats(ctx, f)
+	require.NoError(t, err)
+	assert.Len(t, reports, len(want))
+	assert.Equal(t, wantCount, stats.TotalCount, "window count should match COUNT(*)")
+
+	agg, err := s.Aggregations(ctx, f)
+	require.NoError(t, err)
+	require.Len(t, agg.ByEventType, 1)
+	require.NotNil(t, stats.MaxMagnitude)
+	assert.InDelta(t, agg.ByEventType[0].MaxMeasurement.Magnitude, *stats.MaxMagnitude, 1e-9,
+		"window max should cover all matching rows, not just the page")
+
+	t.Run("offset beyond total", func(t *testing.T) {
+		f := wideFilter()
+		offset := 300
+		f.Offset = &offset
+		reports, stats, err := s.ListStormReportsWithStats(ctx, f)
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+		assert.Equal(t, 271, stats.TotalCount)
+	})
+}
+
+func TestStoreKeysetPaginationSnapshot(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+	f := wideFilter()
+
+	first, stats, err := s.ListStormReportsWithStats(ctx, f)
+	require.NoError(t, err)
+	require.Len(t, first, graph.MaxPageSize)
+	total := stats.TotalCount
+	last := first[len(first)-1]
+	token, err := store.EncodeCursor(f, last, stats.QueriedAt)
+	require.NoError(t, err)
+
+	// A report lands mid-scan, sorting just after the first page.
+	late := loadMockReports(t)[0]
+	late.ID = "inserted-mid-scan"
+	late.EventTime = last.EventTime.Add(-time.Second)
+	require.NoError(t, s.InsertStormReport(ctx, &late))
+
+	seen := map[string]bool{}
+	for _, r := range first {
+		seen[r.ID] = true
+	}
+	for token != "" {
+		cursor, err := store.DecodeCursor(f, token)
+		require.NoError(t, err)
+		f.Cursor = cursor
+
+		page, stats, err := s.ListStormReportsWithStats(ctx, f)
+		require.NoError(t, err)
+		assert.Equal(t, total, stats.TotalCount, "total is pinned to the snapshot")
+		for _, r := range page {
+			assert.False(t, seen[r.ID], "report %s returned twice", r.ID)
+			seen[r.ID] = true
+		}
+
+		token = ""
+		if len(page) > 0 && len(page) < stats.Remaining {
+			token, err = store.EncodeCursor(f, page[len(page)-1], cursor.AsOf)
+			require.NoError(t, err)
+		}
+	}
+	assert.Len(t, seen, total, "every snapshot report is returned once")
+	assert.False(t, seen[late.ID], "report inserted mid-scan is not in the snapshot")
+
+	// A new scan sees it.
+	_, stats, err = s.ListStormReportsWithStats(ctx, wideFilter())
+	require.NoError(t, err)
+	assert.Equal(t, total+1, stats.TotalCount)
+}
+
+func TestStoreReportRate(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	f := wideFilter()
+	_, total, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+
+	window := f.TimeRange.To.Sub(f.TimeRange.From)
+	rate, err := s.ReportRate(ctx, f, f.TimeRange.To, window, 8)
+	require.NoError(t, err)
+	assert.Equal(t, total, rate.Count, "window spanning the time range counts every report")
+	require.Len(t, rate.Intervals, 8)
+	sum := 0
+	for _, iv := range rate.Intervals {
+		sum += iv.Count
+	}
+	assert.Equal(t, rate.Count, sum, "sub-intervals partition the window")
+}
+
+func TestStoreCoverageGaps(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	// The mock reports all fall on 2024-04-26; the range runs a day either
+	// side, so those days are gaps and 04-26 has at least some coverage.
+	f := wideFilter()
+	f.TimeRange = model.TimeRange{
+		From: time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 28, 0, 0, 0, 0, time.UTC),
+	}
+	gaps, err := s.CoverageGaps(ctx, f, time.Hour)
+	require.NoError(t, err)
+
+	require.GreaterOrEqual(t, len(gaps), 48, "the empty days around the data are gaps")
+	assert.Less(t, len(gaps), 72, "hours with reports are not gaps")
+
+	for i, g := range gaps {
+		if i > 0 {
+			assert.True(t, g.Start.After(gaps[i-1].Start), "oldest first")
+		}
+		assert.LessOrEqual(t, g.End.Sub(g.Start), time.Hour)
+
+		// Each gap bucket really is empty for the same filter.
+		bf := *f
+		bf.TimeRange = model.TimeRange{From: g.Start, To: g.End.Add(-time.Microsecond)}
+		n, err := s.CountStormReports(ctx, &bf)
+		require.NoError(t, err)
+		assert.Zero(t, n, "gap %s..%s", g.Start, g.End)
+	}
+
+	// A range with no reports at all is one gap per bucket.
+	empty := *f
+	empty.TimeRange = model.TimeRange{From: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2000, 1, 1, 3, 0, 0, 0, time.UTC)}
+	gaps, err = s.CoverageGaps(ctx, &empty, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, gaps, 3)
+	assert.Equal(t, empty.TimeRange.From, gaps[0].Start.UTC())
+	assert.Equal(t, empty.TimeRange.To, gaps[2].End.UTC())
+}
+
+func TestStorePolygonFilter(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	all, _, err := s.ListStormReports(ctx, wideFilter())
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	anchor := all[0]
+	lat, lon := anchor.Geo.Lat, anchor.Geo.Lon
+
+	ids := func(ring []model.LatLon) []string {
+		f := wideFilter()
+		f.Polygon = ring
+		require.NoError(t, graph.ValidateFilter(f))
+		reports, _, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		out := make([]string, len(reports))
+		for i, r := range reports {
+			out[i] = r.ID
+		}
+		return out
+	}
+
+	// Open ring around the anchor; validation closes it.
+	around := []model.LatLon{{Lat: lat - 0.01, Lon: lon - 0.01}, {Lat: lat - 0.01, Lon: lon + 0.01}, {Lat: lat + 0.01, Lon: lon}}
+	assert.Contains(t, ids(around), anchor.ID)
+
+	// The anchor is inside this triangle's bounding box but on the far side
+	// of its diagonal, so only the containment test excludes it.
+	beside := []model.LatLon{{Lat: lat - 0.005, Lon: lon + 0.01}, {Lat: lat + 0.01, Lon: lon + 0.01}, {Lat: lat + 0.01, Lon: lon - 0.005}}
+	assert.NotContains(t, ids(beside), anchor.ID)
+}
+
+func TestStoreCountByType(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	f := wideFilter()
+	_, total, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+
+	counts, err := s.CountByType(ctx, f)
+	require.NoError(t, err)
+	sum := 0
+	for _, c := range counts {
+		sum += c.Count
+	}
+	assert.Equal(t, total, sum, "every report counted once")
+
+	f.States = []string{"TX"}
+	_, txTotal, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+	txCounts, err := s.CountByType(ctx, f)
+	require.NoError(t, err)
+	sum = 0
+	for _, c := range txCounts {
+		sum += c.Count
+	}
+	assert.Equal(t, txTotal, sum, "state filter honored")
+
+	f.States = []string{"ZZ"}
+	none, err := s.CountByType(ctx, f)
+	require.NoError(t, err)
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+}
+
+func TestStoreDiurnalCycle(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	f := wideFilter()
+	_, total, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+
+	utc, err := s.DiurnalCycle(ctx, f, "UTC")
+	require.NoError(t, err)
+	require.Len(t, utc, 24)
+	sum := 0
+	for _, b := range utc {
+		sum += b.Count
+	}
+	assert.Equal(t, total, sum, "every report lands in one hour")
+
+	// The mock reports are all in April, when Chicago is on CDT (UTC-5).
+	chicago, err := s.DiurnalCycle(ctx, f, "America/Chicago")
+	require.NoError(t, err)
+	require.Len(t, chicago, 24)
+	for h := range 24 {
+		assert.Equal(t, utc[h].Count, chicago[(h+19)%24].Count, "UTC hour %d", h)
+	}
+}
+
+func TestStoreNearbyReports(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	reports, _, err := s.ListStormReports(ctx, wideFilter())
+	require.NoError(t, err)
+	require.NotEmpty(t, reports)
+	anchor := reports[0]
+
+	window := 7 * 24 * time.Hour
+	nearby, err := s.NearbyReports(ctx, anchor.ID, window, 5, false, true)
+	require.NoError(t, err)
+	require.NotEmpty(t, nearby)
+	assert.LessOrEqual(t, len(nearby), 5)
+	for i, n := range nearby {
+		assert.NotEqual(t, anchor.ID, n.Report.ID, "anchor excluded")
+		assert.GreaterOrEqual(t, n.DistanceMiles, 0.0)
+		assert.LessOrEqual(t, n.Report.EventTime.Sub(anchor.EventTime).Abs(), window)
+		if i > 0 {
+			assert.GreaterOrEqual(t, n.DistanceMiles, nearby[i-1].DistanceMiles, "closest first")
+		}
+	}
+
+	missing, err := s.NearbyReports(ctx, "no-such-report", window, 5, false, true)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestStoreExcludedMissingCoordinates(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	for i := range reports[:8] {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	// Two TX reports and one IA report lose their position.
+	for _, i := range []int{0, 1, 2} {
+		_, err = pool.Exec(ctx, "UPDATE storm_reports SET geo_lat = 0, geo_lon = 0 WHERE id = $1", reports[i].ID)
+		require.NoError(t, err)
+	}
+
+	radius := 50.0
+	f := wideFilter()
+	f.States = []string{"TX"}
+	f.Near = &model.GeoRadiusFilter{Lat: 32.7, Lon: -97.2, RadiusMiles: &radius}
+	n, err := s.ExcludedMissingCoordinates(ctx, f)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "only the position-less reports matching the state filter")
+
+	f.Near = nil
+	f.BBox = &model.BoundingBoxFilter{MinLat: -1, MaxLat: 40, MinLon: -100, MaxLon: 1}
+	n, err = s.ExcludedMissingCoordinates(ctx, f)
+	require.NoError(t, err)
+	assert.Zero(t, n, "a box containing (0, 0) keeps them")
+
+	f.BBox = nil
+	f.Polygon = []model.LatLon{{Lat: 25, Lon: -106}, {Lat: 36, Lon: -106}, {Lat: 36, Lon: -93}, {Lat: 25, Lon: -93}, {Lat: 25, Lon: -106}}
+	n, err = s.ExcludedMissingCoordinates(ctx, f)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "a polygon is a position filter too")
+}
+
+func TestStoreStreamCountyGroups(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	f := wideFilter()
+	agg, err := s.Aggregations(ctx, f)
+	require.NoError(t, err)
+	want := 0
+	total := 0
+	for _, sg := range agg.ByState {
+		want += len(sg.Counties)
+		total += sg.Count
+	}
+
+	var got []*model.CountyStateGroup
+	err = s.StreamCountyGroups(ctx, f, func(g *model.CountyStateGroup) error {
+		got = append(got, g)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, want, "one row per state/county group")
+	sum := 0
+	for i, g := range got {
+		sum += g.Count
+		if i > 0 {
+			prev := got[i-1]
+			assert.True(t, prev.State < g.State || (prev.State == g.State && prev.County < g.County), "ordered by state, county")
+		}
+	}
+	assert.Equal(t, total, sum)
+
+	// A cancelled context stops the cursor.
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = s.StreamCountyGroups(cctx, f, func(*model.CountyStateGroup) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStoreSeverityThresholds(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	require.NoError(t, s.LoadSeverityThresholds(ctx))
+	assert.Equal(t, model.DefaultSeverityThresholds, s.SeverityThresholds(), "seeded with the defaults")
+
+	// Retuned thresholds take effect once loaded from the table.
+	_, err = pool.Exec(ctx, "UPDATE severity_thresholds SET extreme = 5 WHERE event_type = 'hail'")
+	require.NoError(t, err)
+	require.NoError(t, s.LoadSeverityThresholds(ctx))
+	w := model.DefaultSeverityWeights.WithThresholds(s.SeverityThresholds())
+	hail := &model.StormReport{EventType: "hail", Measurement: model.Measurement{Magnitude: 5}}
+	assert.InDelta(t, 1.0, w.Score(hail), 1e-9)
+
+	_, err = pool.Exec(ctx, "DELETE FROM severity_thresholds WHERE event_type = 'tornado'")
+	require.NoError(t, err)
+	require.Error(t, s.LoadSeverityThresholds(ctx), "every event type needs a row")
+}
+
+func TestStoreStreamStormReports(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	f := wideFilter()
+	total, err := s.CountStormReports(ctx, f)
+	require.NoError(t, err)
+
+	var got []*model.StormReport
+	err = s.StreamStormReports(ctx, f, func(r *model.StormReport) error {
+		got = append(got, r)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, total, "every match streamed, not one page")
+	for i := 1; i < len(got); i++ {
+		assert.False(t, got[i].EventTime.Before(got[i-1].EventTime), "oldest first")
+	}
+
+	// Each cursor statement runs under the query timeout.
+	s.SetQueryTimeout(time.Nanosecond)
+	err = s.StreamStormReports(ctx, f, func(*model.StormReport) error { return nil })
+	assert.ErrorIs(t, err, store.ErrQueryTimeout)
+}
+
+func TestStoreNearestPlaceName(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(ctx, `INSERT INTO populated_places (name, state, lat, lon, population) VALUES
+		('Plano', 'TX', 33.0198, -96.6989, 285494),
+		('Dallas', 'TX', 32.7767, -96.7970, 1304379)`)
+	require.NoError(t, err)
+
+	s := store.New(pool, observability.NewTestMetrics())
+
+	name, err := s.NearestPlaceName(ctx, 33.05, -96.70)
+	require.NoError(t, err)
+	require.NotNil(t, name)
+	assert.Equal(t, "Plano, TX", *name)
+
+	name, err = s.NearestPlaceName(ctx, 32.78, -96.80)
+	require.NoError(t, err)
+	require.NotNil(t, name)
+	assert.Equal(t, "Dallas, TX", *name)
+
+	name, err = s.NearestPlaceName(ctx, 45.0, -110.0)
+	require.NoError(t, err)
+	assert.Nil(t, name, "no place within range")
+
+	// Cached per request: once looked up, the answer survives the row going away.
+	reqCtx := store.WithPlaceCache(ctx, store.NewPlaceCache())
+	_, err = s.NearestPlaceName(reqCtx, 33.05, -96.70)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "DELETE FROM populated_places")
+	require.NoError(t, err)
+	name, err = s.NearestPlaceName(reqCtx, 33.05, -96.70)
+	require.NoError(t, err)
+	require.NotNil(t, name)
+	assert.Equal(t, "Plano, TX", *name)
+}
+
+func TestStoreInsertPartial(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+
+	reports := loadMockReports(t)
+	good1, bad, good2 := reports[0], reports[1], reports[2]
+	bad.Comments = "nul byte \x00 is rejected by Postgres text"
+
+	rejected, err := s.InsertStormReportsPartial(ctx, []*model.StormReport{&good1, &bad, &good2})
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, 1, rejected[0].Index)
+	assert.Equal(t, bad.ID, rejected[0].ID)
+	require.Error(t, rejected[0].Err)
+
+	for _, want := range []model.StormReport{good1, good2} {
+		got, err := s.GetStormReport(ctx, want.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, got, "valid row %s should be inserted", want.ID)
+	}
+	got, err := s.GetStormReport(ctx, bad.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got, "rejected row should not be inserted")
+}
+
+func TestStoreSyncCheckpointResume(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	for i := range reports {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	synced := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	_, err = pool.Exec(ctx, "UPDATE storm_reports SET updated_at = $1", synced)
+	require.NoError(t, err)
+
+	cp, err := s.SyncCheckpoint(ctx, "dashboard")
+	require.NoError(t, err)
+	assert.Nil(t, cp, "new client has no checkpoint")
+
+	// First connection: full sync, then acknowledge up to the newest change.
+	f := wideFilter()
+	before := synced.Add(-time.Hour)
+	f.UpdatedAfter = &before
+	_, total, err := s.ListReportDeltas(ctx, f)
+	require.NoError(t, err)
+	assert.Equal(t, len(reports), total)
+	require.NoError(t, s.SaveSyncCheckpoint(ctx, "dashboard", synced))
+
+	// One report changes while the client is away.
+	changed := reports[0].ID
+	_, err = pool.Exec(ctx, "UPDATE storm_reports SET updated_at = $1 WHERE id = $2", synced.Add(time.Hour), changed)
+	require.NoError(t, err)
+
+	// Reconnect through a fresh store: the checkpoint survives and resumes
+	// with only the change made after it.
+	resumed := store.New(pool, observability.NewTestMetrics())
+	cp, err = resumed.SyncCheckpoint(ctx, "dashboard")
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	assert.True(t, synced.Equal(*cp))
+
+	f.UpdatedAfter = cp
+	deltas, total, err := resumed.ListReportDeltas(ctx, f)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, changed, deltas[0].ID)
+
+	// A stale acknowledgement does not rewind the checkpoint.
+	require.NoError(t, resumed.SaveSyncCheckpoint(ctx, "dashboard", before))
+	cp, err = resumed.SyncCheckpoint(ctx, "dashboard")
+	require.NoError(t, err)
+	assert.True(t, synced.Equal(*cp))
+
+	other, err := resumed.SyncCheckpoint(ctx, "other")
+	require.NoError(t, err)
+	assert.Nil(t, other, "checkpoints are per client")
+}
+
+func TestStoreDeltaOnlyReturnsChangedFields(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	for i := range reports[:2] {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	// Bookkeeping-only writes are not revisions.
+	synced := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	_, err = pool.Exec(ctx, "UPDATE storm_reports SET created_at = $1, updated_at = $1", synced)
+	require.NoError(t, err)
+
+	// Any writer's update is recorded; assigning a column its current value
+	// is not a change.
+	updated := reports[0]
+	_, err = pool.Exec(ctx,
+		"UPDATE storm_reports SET comments = $1, measurement_magnitude = measurement_magnitude WHERE id = $2",
+		"Corrected: golf ball hail", updated.ID)
+	require.NoError(t, err)
+
+	f := wideFilter()
+	since := synced.Add(time.Minute)
+	f.UpdatedAfter = &since
+	deltas, total, err := s.ListReportDeltas(ctx, f)
+	require.NoError(t, err)
+	require.Equal(t, 1, total, "only the updated report changed after the checkpoint")
+	require.Len(t, deltas, 1)
+	assert.Equal(t, updated.ID, deltas[0].ID)
+	require.Len(t, deltas[0].Fields, 1)
+	assert.Equal(t, "comments", deltas[0].Fields[0].Name)
+	require.NotNil(t, deltas[0].Fields[0].Value)
+	assert.Equal(t, "Corrected: golf ball hail", *deltas[0].Fields[0].Value)
+}
+
+func TestStoreMaxLocationUncertainty(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	for i := range reports[:3] {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	// reports[2] keeps NULL (unknown) uncertainty.
+	_, err = pool.Exec(ctx, "UPDATE storm_reports SET location_uncertainty_m = CASE id WHEN $1 THEN 50 ELSE 2000 END WHERE id = ANY($2)",
+		reports[0].ID, []string{reports[0].ID, reports[1].ID})
+	require.NoError(t, err)
+
+	f := wideFilter()
+	maxUncertainty := 100.0
+	f.MaxLocationUncertainty = &maxUncertainty
+	got, total, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+	require.Equal(t, 1, total, "unknown uncertainty is excluded")
+	assert.Equal(t, reports[0].ID, got[0].ID)
+}
+
+func TestStoreDamageTotals(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+
+	reports := loadMockReports(t)
+	for i := range reports[:3] {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	// reports[2] keeps NULL (unknown) damage.
+	_, err = pool.Exec(ctx, "UPDATE storm_reports SET damage_property_usd = 1000, damage_crops_usd = CASE id WHEN $1 THEN 250 END WHERE id = ANY($2)",
+		reports[0].ID, []string{reports[0].ID, reports[1].ID})
+	require.NoError(t, err)
+
+	totals, err := s.DamageTotals(ctx, wideFilter(), model.BucketUnitYear)
+	require.NoError(t, err)
+	require.Len(t, totals, 1, "the mock reports are all in 2024")
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), totals[0].Bucket.UTC())
+	assert.InDelta(t, 2000, totals[0].PropertyDamage, 1e-9)
+	assert.InDelta(t, 250, totals[0].CropDamage, 1e-9)
+	assert.InDelta(t, 2250, totals[0].TotalDamage, 1e-9)
+	assert.Equal(t, 3, totals[0].ReportCount, "reports without estimates still count")
+}
+
+func TestStoreCountByTimeBucket(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+
+	reports := loadMockReports(t)
+	for i := range reports {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+
+	counts, err := s.CountByTimeBucket(ctx, wideFilter(), model.BucketUnitHour)
+	require.NoError(t, err)
+	require.NotEmpty(t, counts)
+	total := 0
+	for i, c := range counts {
+		assert.Equal(t, c.Bucket.UTC().Truncate(time.Hour), c.Bucket.UTC(), "truncated to the hour")
+		if i > 0 {
+			assert.True(t, c.Bucket.After(counts[i-1].Bucket), "oldest first")
+		}
+		total += c.Count
+	}
+	assert.Equal(t, len(reports), total)
+
+	_, err = s.CountByTimeBucket(ctx, wideFilter(), model.BucketUnitYear)
+	require.Error(t, err)
+}
+
+func TestStoreSpotterRank(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+
+	reports := loadMockReports(t)
+	for i := range reports[:8] {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	// Synthetic leaderboard: alpha 3, bravo and charlie tied on 2, and
+	// reports[7] without a spotter.
+	spotters := []string{"alpha", "alpha", "alpha", "bravo", "bravo", "charlie", "charlie"}
+	for i, id := range spotters {
+		_, err = pool.Exec(ctx, "UPDATE storm_reports SET spotter_id = $1 WHERE id = $2", id, reports[i].ID)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		id          string
+		rank, count int
+	}{
+		{"alpha", 1, 3},
+		{"bravo", 2, 2},
+		{"charlie", 2, 2},
+	}
+	for _, tt := range tests {
+		got, err := s.SpotterRank(ctx, wideFilter(), tt.id)
+		require.NoError(t, err)
+		require.NotNil(t, got, tt.id)
+		assert.Equal(t, tt.rank, got.Rank, tt.id)
+		assert.Equal(t, tt.count, got.Count, tt.id)
+		assert.Equal(t, 3, got.Spotters, "unattributed reports are not a spotter")
+	}
+
+	missing, err := s.SpotterRank(ctx, wideFilter(), "delta")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestStoreSeverityDistribution(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+
+	reports := loadMockReports(t)
+	for i := range reports[:7] {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	// Hail on each side of the documented thresholds, plus an unknown
+	// (zero) magnitude that is left out.
+	hail := []float64{0.5, 0.75, 1.25, 2.0, 2.5, 4.0, 0}
+	for i, mag := range hail {
+		_, err = pool.Exec(ctx, "UPDATE storm_reports SET event_type = 'hail', measurement_magnitude = $1 WHERE id = $2", mag, reports[i].ID)
+		require.NoError(t, err)
+	}
+
+	got, err := s.SeverityDistribution(ctx, wideFilter())
+	require.NoError(t, err)
+	assert.Equal(t, []*model.SeverityCount{
+		{Category: model.SeverityMinor, Count: 1},
+		{Category: model.SeverityModerate, Count: 2},
+		{Category: model.SeveritySevere, Count: 1},
+		{Category: model.SeverityExtreme, Count: 2},
+	}, got)
+
+	f := wideFilter()
+	f.States = []string{"ZZ"}
+	none, err := s.SeverityDistribution(ctx, f)
+	require.NoError(t, err)
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+
+	// Retuned thresholds take effect once loaded from the table.
+	_, err = pool.Exec(ctx, "UPDATE severity_thresholds SET extreme = 5 WHERE event_type = 'hail'")
+	require.NoError(t, err)
+	require.NoError(t, s.LoadSeverityThresholds(ctx))
+	got, err = s.SeverityDistribution(ctx, wideFilter())
+	require.NoError(t, err)
+	assert.Equal(t, []*model.SeverityCount{
+		{Category: model.SeverityMinor, Count: 1},
+		{Category: model.SeverityModerate, Count: 2},
+		{Category: model.SeveritySevere, Count: 3},
+	}, got)
+}
+
+func TestStoreCountyOutliers(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	for i := range reports[:6] {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+	// One county of hail: five 1" reports and one 4" report. The norm is
+	// mean 1.5 and sample stddev ~1.22, so 4" is ~2.04 stddevs above it.
+	for i, mag := range []float64{1, 1, 1, 1, 1, 4} {
+		_, err = pool.Exec(ctx, `UPDATE storm_reports SET event_type = 'hail', measurement_magnitude = $1,
+			location_state = 'TX', location_county = 'Outlier' WHERE id = $2`, mag, reports[i].ID)
+		require.NoError(t, err)
+	}
+
+	outliers := func(k float64) []*model.StormReport {
+		f := wideFilter()
+		f.CountyOutlierStdDevs = &k
+		got, _, err := s.ListStormReports(ctx, f)
+		require.NoError(t, err)
+		return got
+	}
+
+	two := outliers(2)
+	require.Len(t, two, 1)
+	assert.Equal(t, reports[5].ID, two[0].ID)
+	assert.Empty(t, outliers(2.5), "4\" is within 2.5 stddevs")
+}
+
+func TestStoreCounty(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	_, err = pool.Exec(ctx, `INSERT INTO counties (state, name, fips_code) VALUES
+		('TX', 'Dallas', '48113'), ('OK', 'Cleveland', '40027')`)
+	require.NoError(t, err)
+
+	got, err := s.CountiesByKey(ctx, []store.CountyKey{
+		{State: "TX", Name: "Dallas"}, {State: "OK", Name: "Cleveland"}, {State: "OK", Name: "Dallas"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, got, 2, "state and name must both match")
+	assert.Equal(t, "48113", got[store.CountyKey{State: "TX", Name: "Dallas"}].FIPSCode)
+
+	loaderCtx := store.WithCountyLoader(ctx, store.NewCountyLoader(s.CountiesByKey))
+	c, err := s.County(loaderCtx, "OK", "Cleveland")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, "40027", c.FIPSCode)
+
+	missing, err := s.County(ctx, "ZZ", "Nowhere")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestStoreEditStormReportLocking(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	report := loadMockReports(t)[0]
+	require.NoError(t, s.InsertStormReport(ctx, &report))
+
+	setComments := func(comments string) func(context.Context, pgx.Tx, *model.StormReport) error {
+		return func(ctx context.Context, tx pgx.Tx, r *model.StormReport) error {
+			_, err := tx.Exec(ctx, "UPDATE storm_reports SET comments = $1 WHERE id = $2", comments, r.ID)
+			return err
+		}
+	}
+
+	// The first edit holds the lock until release is closed.
+	locked, release := make(chan struct{}), make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- s.EditStormReport(ctx, report.ID, time.Second, func(ctx context.Context, tx pgx.Tx, r *model.StormReport) error {
+			close(locked)
+			<-release
+			return setComments("first")(ctx, tx, r)
+		})
+	}()
+	<-locked
+
+	// A short timeout gives up while the lock is held.
+	err = s.EditStormReport(ctx, report.ID, 100*time.Millisecond, setComments("too late"))
+	var lockErr *store.LockTimeoutError
+	require.ErrorAs(t, err, &lockErr)
+	assert.Equal(t, report.ID, lockErr.ID)
+
+	// A longer timeout blocks until the first edit commits, then sees its write.
+	second := make(chan error, 1)
+	var seen string
+	go func() {
+		second <- s.EditStormReport(ctx, report.ID, 10*time.Second, func(ctx context.Context, tx pgx.Tx, r *model.StormReport) error {
+			seen = r.Comments
+			return setComments("second")(ctx, tx, r)
+		})
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-second)
+	assert.Equal(t, "first", seen, "second edit read the row after the first committed")
+
+	got, err := s.GetStormReport(ctx, report.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "second", got.Comments)
+
+	var revisions int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT count(*) FROM storm_report_revisions
+		WHERE report_id = $1 AND changed_columns = ARRAY['comments']`, report.ID).Scan(&revisions))
+	assert.Equal(t, 2, revisions, "each committed edit is recorded by the revision trigger")
+
+	err = s.EditStormReport(ctx, "missing", time.Second, setComments("x"))
+	assert.ErrorIs(t, err, store.ErrReportNotFound)
+}
+
+func TestStoreClusterTileReports(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	for i := range reports {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+
+	filter := wideFilter()
+	world := tileapi.TileBounds(0, 0, 0)
+	filter.BBox = &world
+	all, err := s.ListTileReports(ctx, filter, tileapi.MaxFeatures)
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	one, err := s.ClusterTileReports(ctx, filter, 0, 0, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, one, 1, "a 1x1 grid is a single cluster")
+	assert.Equal(t, len(all), one[0].Count)
+
+	clusters, err := s.ClusterTileReports(ctx, filter, 0, 0, 0, tileapi.DefaultClusterGrid)
+	require.NoError(t, err)
+	assert.Greater(t, len(clusters), 1, "mock reports span several cells")
+	total := 0
+	for _, c := range clusters {
+		total += c.Count
+		assert.True(t, world.MinLat <= c.Lat && c.Lat <= world.MaxLat && world.MinLon <= c.Lon && c.Lon <= world.MaxLon)
+	}
+	assert.Equal(t, len(all), total, "every report in exactly one cluster")
+}
+
+func TestStorePatchStormReport(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	report := loadMockReports(t)[0]
+	require.NoError(t, s.InsertStormReport(ctx, &report))
+
+	var version time.Time
+	require.NoError(t, pool.QueryRow(ctx, "SELECT updated_at FROM storm_reports WHERE id = $1", report.ID).Scan(&version))
 
-	t.Run("empty result", func(t *testing.T) {
-		f := wideFilter()
-		f.EventTypes = []model.EventType{model.EventType("BLIZZARD")}
-		reports, count, err := s.ListStormReports(ctx, f)
-		require.NoError(t, err)
-		assert.Equal(t, 0, count)
-		assert.Empty(t, reports)
-	})
+	mag := report.Measurement.Magnitude + 0.25
+	patched, err := s.PatchStormReport(ctx, report.ID, &model.ReportPatch{ExpectedUpdatedAt: version, Magnitude: &mag})
+	require.NoError(t, err)
+	assert.True(t, patched.After(version))
 
-	t.Run("multiple event types filter", func(t *testing.T) {
-		f := wideFilter()
-		f.EventTypes = []model.EventType{model.EventTypeHail, model.EventTypeTornado}
-		_, count, err := s.ListStormReports(ctx, f)
-		require.NoError(t, err)
-		assert.Equal(t, 228, count)
-	})
+	got, err := s.GetStormReport(ctx, report.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, mag, got.Measurement.Magnitude, 1e-9)
+	assert.Equal(t, report.Comments, got.Comments, "unpatched fields unchanged")
+
+	var cols []string
+	require.NoError(t, pool.QueryRow(ctx, "SELECT changed_columns FROM storm_report_revisions WHERE report_id = $1", report.ID).Scan(&cols))
+	assert.Equal(t, []string{"measurement_magnitude"}, cols)
+
+	// A second patch from the stale version is refused.
+	current, err := s.PatchStormReport(ctx, report.ID, &model.ReportPatch{ExpectedUpdatedAt: version, Magnitude: &mag})
+	require.ErrorIs(t, err, store.ErrVersionConflict)
+	assert.True(t, patched.Equal(current))
+
+	_, err = s.PatchStormReport(ctx, "missing", &model.ReportPatch{ExpectedUpdatedAt: version, Magnitude: &mag})
+	assert.ErrorIs(t, err, store.ErrReportNotFound)
+
+	// A patch waits for an edit holding the row lock, then sees its version.
+	locked, release := make(chan struct{}), make(chan struct{})
+	edit := make(chan error, 1)
+	go func() {
+		edit <- s.EditStormReport(ctx, report.ID, time.Second, func(context.Context, pgx.Tx, *model.StormReport) error {
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+	comments := "locked edit"
+	patchDone := make(chan error, 1)
+	go func() {
+		_, err := s.PatchStormReport(ctx, report.ID, &model.ReportPatch{ExpectedUpdatedAt: patched, Comments: &comments})
+		patchDone <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	require.NoError(t, <-edit)
+	require.NoError(t, <-patchDone)
 }
 
-func TestStoreSortingAndPagination(t *testing.T) {
+func TestGraphQLPatchStormReport(t *testing.T) {
 	ctx := context.Background()
-	s := setupStoreWithData(ctx, t)
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
 
-	t.Run("sort by magnitude DESC", func(t *testing.T) {
-		f := wideFilter()
-		sortBy := model.SortFieldMagnitude
-		sortOrder := model.SortOrderDesc
-		f.SortBy = &sortBy
-		f.SortOrder = &sortOrder
-		reports, _, err := s.ListStormReports(ctx, f)
-		require.NoError(t, err)
-		require.GreaterOrEqual(t, len(reports), 2)
-		for i := 1; i < len(reports); i++ {
-			assert.GreaterOrEqual(t, reports[i-1].Measurement.Magnitude, reports[i].Measurement.Magnitude,
-				"reports[%d] > reports[%d]", i-1, i)
-		}
-	})
+	require.NoError(t, database.RunMigrations(dsn))
 
-	t.Run("sort by magnitude ASC", func(t *testing.T) {
-		f := wideFilter()
-		sortBy := model.SortFieldMagnitude
-		sortOrder := model.SortOrderAsc
-		f.SortBy = &sortBy
-		f.SortOrder = &sortOrder
-		reports, _, err := s.ListStormReports(ctx, f)
-		require.NoError(t, err)
-		for i := 1; i < len(reports); i++ {
-			assert.LessOrEqual(t, reports[i-1].Measurement.Magnitude, reports[i].Measurement.Magnitude,
-				"reports[%d] < reports[%d]", i-1, i)
-		}
-	})
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
 
-	t.Run("sort by state ASC", func(t *testing.T) {
-		f := wideFilter()
-		sortBy := model.SortFieldLocationState
-		sortOrder := model.SortOrderAsc
-		f.SortBy = &sortBy
-		f.SortOrder = &sortOrder
-		reports, _, err := s.ListStormReports(ctx, f)
-		require.NoError(t, err)
-		for i := 1; i < len(reports); i++ {
-			assert.LessOrEqual(t, reports[i-1].Location.State, reports[i].Location.State,
-				"reports[%d] < reports[%d]", i-1, i)
-		}
-	})
+	s := store.New(pool, observability.NewTestMetrics())
+	report := loadMockReports(t)[0]
+	require.NoError(t, s.InsertStormReport(ctx, &report))
 
-	t.Run("pagination limit", func(t *testing.T) {
-		f := wideFilter()
-		limit := 5
-		f.Limit = &limit
-		reports, totalCount, err := s.ListStormReports(ctx, f)
+	var version time.Time
+	require.NoError(t, pool.QueryRow(ctx, "SELECT updated_at FROM storm_reports WHERE id = $1", report.ID).Scan(&version))
+
+	srv := startGraphQLServer(t, s)
+	defer srv.Close()
+	h := admin.IdentifyKey("secret")(srv.Config.Handler)
+
+	type result struct {
+		Data *struct {
+			PatchStormReport struct {
+				ID        string    `json:"id"`
+				UpdatedAt time.Time `json:"updatedAt"`
+			} `json:"patchStormReport"`
+		} `json:"data"`
+		Errors []struct {
+			Extensions map[string]any `json:"extensions"`
+		} `json:"errors"`
+	}
+	patch := func(expected time.Time) result {
+		t.Helper()
+		query := fmt.Sprintf(`mutation { patchStormReport(id: %q, patch: { expectedUpdatedAt: %q, comments: "graphql fix" }) { id updatedAt } }`,
+			report.ID, expected.Format(time.RFC3339Nano))
+		body, err := json.Marshal(map[string]string{"query": query})
 		require.NoError(t, err)
-		assert.Len(t, reports, 5)
-		assert.Equal(t, 271, totalCount, "totalCount should ignore limit")
-	})
+		req := httptest.NewRequest(http.MethodPost, graphQLPath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentJSON)
+		req.Header.Set(admin.HeaderKey, "secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var res result
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		return res
+	}
 
-	t.Run("pagination offset", func(t *testing.T) {
-		f := wideFilter()
-		limit := 5
-		f.Limit = &limit
+	ok := patch(version)
+	require.Empty(t, ok.Errors)
+	require.NotNil(t, ok.Data)
+	assert.Equal(t, report.ID, ok.Data.PatchStormReport.ID)
+	assert.True(t, ok.Data.PatchStormReport.UpdatedAt.After(version))
 
-		page1, _, err := s.ListStormReports(ctx, f)
-		require.NoError(t, err)
+	got, err := s.GetStormReport(ctx, report.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "graphql fix", got.Comments)
 
-		offset := 5
-		f.Offset = &offset
-		page2, _, err := s.ListStormReports(ctx, f)
+	// Patching from the stale version is a conflict carrying the current one.
+	stale := patch(version)
+	require.Len(t, stale.Errors, 1)
+	assert.Equal(t, "CONFLICT", stale.Errors[0].Extensions["code"])
+	current, err := time.Parse(time.RFC3339Nano, stale.Errors[0].Extensions["updatedAt"].(string))
+	require.NoError(t, err)
+	assert.True(t, current.Equal(ok.Data.PatchStormReport.UpdatedAt))
+}
+
+func TestStoreStormTrack(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+
+	// The storm moves ~110 miles north over two hours.
+	t0 := time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC)
+	start := &model.TrackPoint{Time: t0, Lat: 35.0, Lon: -98.0}
+	end := &model.TrackPoint{Time: t0.Add(2 * time.Hour), Lat: 36.6, Lon: -98.0}
+
+	at := func(id string, when time.Time, lat float64) {
+		r := loadMockReports(t)[0]
+		r.ID, r.EventTime, r.Geo.Lat, r.Geo.Lon = id, when, lat, -98.0
+		require.NoError(t, s.InsertStormReport(ctx, &r))
+	}
+	at("under-storm-early", t0.Add(10*time.Minute), 35.05)        // nearest start, 3.5 mi away
+	at("under-storm-late", t0.Add(110*time.Minute), 36.55)        // nearest end, 3.5 mi away
+	at("start-location-too-late", t0.Add(110*time.Minute), 35.05) // at start's spot, but nearest end in time
+	at("before-track", t0.Add(-10*time.Minute), 35.0)             // at start's spot, before the track
+
+	f := wideFilter()
+	f.StormTrack = &model.StormTrackFilter{Points: []*model.TrackPoint{start, end}, RadiusMiles: 10}
+	got, total, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	ids := []string{got[0].ID, got[1].ID}
+	assert.ElementsMatch(t, []string{"under-storm-early", "under-storm-late"}, ids)
+}
+
+func TestStoreIngestJobAudit(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	// Reports 0 and 1 arrive from the load-1 job; report 2 is a stream event.
+	loadJob, fixJob := "load-1", "fix-7"
+	reports[0].IngestJobID, reports[1].IngestJobID = &loadJob, &loadJob
+	require.NoError(t, s.InsertStormReports(ctx, []*model.StormReport{&reports[0], &reports[1]}))
+	require.NoError(t, s.InsertStormReport(ctx, &reports[2]))
+
+	patch := func(id, job string, p model.ReportPatch) {
+		t.Helper()
+		require.NoError(t, pool.QueryRow(ctx, "SELECT updated_at FROM storm_reports WHERE id = $1", id).Scan(&p.ExpectedUpdatedAt))
+		p.IngestJobID = &job
+		_, err := s.PatchStormReport(ctx, id, &p)
 		require.NoError(t, err)
-		assert.Len(t, page2, 5)
+	}
+	mag := reports[2].Measurement.Magnitude + 0.25
+	patch(reports[2].ID, fixJob, model.ReportPatch{Magnitude: &mag})
+	comments := "corrected by load-1"
+	patch(reports[0].ID, loadJob, model.ReportPatch{Comments: &comments})
 
-		for _, r1 := range page1 {
-			for _, r2 := range page2 {
-				assert.NotEqual(t, r1.ID, r2.ID, "report should not appear on both pages")
-			}
-		}
-	})
+	entries, err := s.IngestJobAudit(ctx, "load-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, e := range entries[:2] {
+		assert.Equal(t, model.AuditOperationInsert, e.Operation)
+		assert.Nil(t, e.ChangedColumns)
+	}
+	assert.Equal(t, model.AuditOperationUpdate, entries[2].Operation)
+	assert.Equal(t, reports[0].ID, entries[2].ReportID)
+	assert.Equal(t, []string{"comments"}, entries[2].ChangedColumns)
 
-	t.Run("offset beyond total", func(t *testing.T) {
+	entries, err = s.IngestJobAudit(ctx, "fix-7")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, reports[2].ID, entries[0].ReportID)
+
+	entries, err = s.IngestJobAudit(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSolarElevationSQLMatchesGo(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	for i := range reports {
+		require.NoError(t, s.InsertStormReport(ctx, &reports[i]))
+	}
+
+	for _, r := range reports[:25] {
+		var got float64
+		require.NoError(t, pool.QueryRow(ctx, "SELECT solar_elevation($1, $2, $3)",
+			r.Geo.Lat, r.Geo.Lon, r.EventTime).Scan(&got))
+		assert.InDelta(t, solarref.Elevation(r.EventTime, r.Geo.Lat, r.Geo.Lon), got, 1e-6, "report %s", r.ID)
+	}
+
+	// Day and night partition the result set.
+	var day, night int
+	for _, dn := range []model.DayNight{model.DayNightDay, model.DayNightNight} {
 		f := wideFilter()
-		offset := 300
-		f.Offset = &offset
-		reports, totalCount, err := s.ListStormReports(ctx, f)
+		f.DayNight = &dn
+		_, total, err := s.ListStormReports(ctx, f)
 		require.NoError(t, err)
-		assert.Empty(t, reports)
-		assert.Equal(t, 271, totalCount, "totalCount should still be 271")
-	})
+		if dn == model.DayNightDay {
+			day = total
+		} else {
+			night = total
+		}
+	}
+	assert.Equal(t, 271, day+night)
 }
 
 func TestGraphQLAggregations(t *testing.T) {
@@ -602,6 +1852,60 @@ func TestGraphQLEndpoint(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&filtered))
 	assert.Len(t, filtered.Data.StormReports.Reports, 20) // default page size
 	assert.Equal(t, 79, filtered.Data.StormReports.TotalCount)
+
+	// Counts only: answered by COUNT(*) without fetching a page
+	body = `{"query":"{ stormReports(filter: { timeRange: { from: \"2020-01-01T00:00:00Z\", to: \"2030-01-01T00:00:00Z\" }, eventTypes: [HAIL], offset: 60 }) { totalCount hasMore } }"}`
+	resp3, err := http.Post(srv.URL+graphQLPath, contentJSON, strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp3.Body.Close()
+
+	var counted struct {
+		Data struct {
+			StormReports struct {
+				TotalCount int  `json:"totalCount"`
+				HasMore    bool `json:"hasMore"`
+			} `json:"stormReports"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp3.Body).Decode(&counted))
+	assert.Equal(t, 79, counted.Data.StormReports.TotalCount)
+	assert.False(t, counted.Data.StormReports.HasMore, "offset 60 + 19 remaining fits one page")
+}
+
+func TestGraphQLDefaultTimeWindow(t *testing.T) {
+	ctx := context.Background()
+	s := setupStoreWithData(ctx, t)
+
+	srv := httptest.NewServer(handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
+		Resolvers: &graph.Resolver{Store: s, DefaultWindow: 24 * time.Hour},
+	})))
+	defer srv.Close()
+
+	body := `{"query":"{ stormReports(filter: {}) { totalCount meta { appliedTimeRange { from to defaulted } } } }"}`
+	resp, err := http.Post(srv.URL+graphQLPath, contentJSON, strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result struct {
+		Data struct {
+			StormReports struct {
+				Meta struct {
+					AppliedTimeRange struct {
+						From      time.Time `json:"from"`
+						To        time.Time `json:"to"`
+						Defaulted bool      `json:"defaulted"`
+					} `json:"appliedTimeRange"`
+				} `json:"meta"`
+			} `json:"stormReports"`
+		} `json:"data"`
+		Errors []json.RawMessage `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Empty(t, result.Errors)
+	applied := result.Data.StormReports.Meta.AppliedTimeRange
+	assert.True(t, applied.Defaulted, "default window is echoed as defaulted")
+	assert.Equal(t, 24*time.Hour, applied.To.Sub(applied.From))
+	assert.WithinDuration(t, time.Now(), applied.To, time.Minute)
 }
 
 func TestGraphQLDepthExceeded(t *testing.T) {
@@ -613,7 +1917,7 @@ func TestGraphQLDepthExceeded(t *testing.T) {
 		Resolvers:  &graph.Resolver{Store: s},
 		Complexity: graph.NewComplexityRoot(),
 	}))
-	srv.Use(extension.FixedComplexityLimit(600))
+	srv.Use(&graph.ComplexityLimit{MaxComplexity: 600})
 	srv.Use(graph.DepthLimit{MaxDepth: 3})
 	ts := httptest.NewServer(srv)
 	defer ts.Close()
@@ -633,3 +1937,81 @@ func TestGraphQLDepthExceeded(t *testing.T) {
 	require.NotEmpty(t, result.Errors, "expected depth limit error")
 	assert.Contains(t, result.Errors[0].Message, "exceeds maximum allowed depth")
 }
+
+func TestStoreEventTypeNormalization(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s := store.New(pool, observability.NewTestMetrics())
+	reports := loadMockReports(t)
+	reports[0].EventType = "TOR"
+	reports[0].Measurement.Magnitude = 2
+	require.NoError(t, s.InsertStormReport(ctx, &reports[0]))
+
+	got, err := s.GetStormReport(ctx, reports[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "tornado", got.EventType)
+	assert.Equal(t, "TOR", got.RawEventType)
+
+	f := wideFilter()
+	f.EventTypes = []model.EventType{model.EventTypeTornado}
+	list, _, err := s.ListStormReports(ctx, f)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(list))
+	for _, r := range list {
+		ids = append(ids, r.ID)
+	}
+	assert.Contains(t, ids, reports[0].ID, "the eventTypes filter matches the normalized label")
+}
+
+func TestStoreRelabelEventTypes(t *testing.T) {
+	ctx := context.Background()
+	dsn, pg := startPostgres(ctx, t)
+	defer func() { _ = pg.Terminate(ctx) }()
+
+	require.NoError(t, database.RunMigrations(dsn))
+
+	pool, err := database.NewPool(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	// Insert under a map that does not know the code, as an older server
+	// configuration would have.
+	s := store.New(pool, observability.NewTestMetrics())
+	s.SetEventTypeLabels(model.EventTypeLabels{})
+	reports := loadMockReports(t)
+	reports[0].EventType = "TOR"
+	reports[0].Measurement.Magnitude = 2
+	require.NoError(t, s.InsertStormReport(ctx, &reports[0]))
+
+	got, err := s.GetStormReport(ctx, reports[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "TOR", got.EventType)
+
+	s.SetEventTypeLabels(model.DefaultEventTypeLabels)
+	n, err := s.RelabelEventTypes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	got, err = s.GetStormReport(ctx, reports[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "tornado", got.EventType)
+	assert.Equal(t, "TOR", got.RawEventType)
+
+	var cols []string
+	require.NoError(t, pool.QueryRow(ctx, "SELECT changed_columns FROM storm_report_revisions WHERE report_id = $1", reports[0].ID).Scan(&cols))
+	assert.Equal(t, []string{"event_type"}, cols, "the revision trigger records the relabel for delta sync")
+
+	n, err = s.RelabelEventTypes(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "reports already carrying their label are not rewritten")
+}
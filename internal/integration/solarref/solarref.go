@@ -0,0 +1,50 @@
+// Package solarref is a Go reference implementation of the sun's position,
+// used by the integration tests to check the solar_elevation SQL function
+// from migration 006_add_solar_elevation. It uses the low-precision almanac
+// formulas (accurate to about 0.01° over 1950--2050), which is ample for
+// comparing against the horizon. Keep the two in sync.
+package solarref
+
+import (
+	"math"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/solar"
+)
+
+// j2000Unix is 2000-01-01T12:00:00Z, the epoch of the almanac formulas.
+const j2000Unix = 946728000
+
+// Elevation returns the sun's altitude above the horizon in degrees at the
+// given time and location. Negative values mean the sun is below the horizon.
+func Elevation(t time.Time, lat, lon float64) float64 {
+	d := float64(t.Unix()-j2000Unix) / 86400
+
+	g := 357.529 + 0.98560028*d // mean anomaly
+	q := 280.459 + 0.98564736*d // mean longitude
+	e := 23.439 - 0.00000036*d  // obliquity of the ecliptic
+	l := q + 1.915*sinDeg(g) + 0.020*sinDeg(2*g)
+
+	ra := atan2Deg(cosDeg(e)*sinDeg(l), cosDeg(l))
+	dec := asinDeg(sinDeg(e) * sinDeg(l))
+
+	// Local hour angle from Greenwich mean sidereal time.
+	gmst := 280.46061837 + 360.98564736629*d
+	h := gmst + lon - ra
+
+	// Clamped: rounding can push the sine just past ±1 with the sun overhead.
+	sinAlt := sinDeg(lat)*sinDeg(dec) + cosDeg(lat)*cosDeg(dec)*cosDeg(h)
+	return asinDeg(max(-1, min(1, sinAlt)))
+}
+
+// IsDaylight reports whether the sun is above the horizon.
+func IsDaylight(t time.Time, lat, lon float64) bool {
+	return Elevation(t, lat, lon) > solar.HorizonDegrees
+}
+
+func sinDeg(x float64) float64 { return math.Sin(x * math.Pi / 180) }
+func cosDeg(x float64) float64 { return math.Cos(x * math.Pi / 180) }
+
+func asinDeg(x float64) float64 { return math.Asin(x) * 180 / math.Pi }
+
+func atan2Deg(y, x float64) float64 { return math.Atan2(y, x) * 180 / math.Pi }
@@ -0,0 +1,54 @@
+package solarref
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestElevation_KnownCases(t *testing.T) {
+	// Greenwich at the June solstice: solar noon altitude ≈ 90 − 51.48 + 23.44.
+	assert.InDelta(t, 61.96, Elevation(at("2024-06-21T12:00:00Z"), 51.4769, 0), 0.2)
+	// Greenwich at midnight: sun below the horizon by the same geometry.
+	assert.InDelta(t, -15.08, Elevation(at("2024-06-21T00:00:00Z"), 51.4769, 0), 0.2)
+	// Equator at the March equinox: sun nearly overhead at noon.
+	assert.InDelta(t, 88.0, Elevation(at("2024-03-20T12:07:00Z"), 0, 0), 2.5)
+}
+
+func TestIsDaylight_Thresholds(t *testing.T) {
+	// Oklahoma City, 2024-04-26: sunrise 6:45 CDT (11:45Z), sunset 20:12 CDT (01:12Z next day).
+	const lat, lon = 35.4676, -97.5164
+	tests := []struct {
+		name string
+		time string
+		want bool
+	}{
+		{"before sunrise", "2024-04-26T11:40:00Z", false},
+		{"after sunrise", "2024-04-26T11:50:00Z", true},
+		{"afternoon", "2024-04-26T21:00:00Z", true},
+		{"before sunset", "2024-04-27T01:08:00Z", true},
+		{"after sunset", "2024-04-27T01:18:00Z", false},
+		{"midnight", "2024-04-27T05:00:00Z", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsDaylight(at(tt.time), lat, lon))
+		})
+	}
+}
+
+func TestIsDaylight_PolarDayAndNight(t *testing.T) {
+	// Utqiaġvik, Alaska: midnight sun in June, polar night in December.
+	const lat, lon = 71.29, -156.79
+	assert.True(t, IsDaylight(at("2024-06-21T10:00:00Z"), lat, lon), "local midnight in June")
+	assert.False(t, IsDaylight(at("2024-12-21T22:00:00Z"), lat, lon), "local noon in December")
+}
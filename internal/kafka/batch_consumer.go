@@ -2,7 +2,6 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
 	"log/slog"
 	"time"
 
@@ -28,6 +27,10 @@ type BatchConsumer struct {
 	flushInterval time.Duration
 	logger        *slog.Logger
 	metrics       *observability.Metrics
+
+	// partialInsert inserts each row under its own savepoint so one rejected
+	// report does not fail the whole batch.
+	partialInsert bool
 }
 
 // NewBatchConsumer creates a batch consumer with time-bounded fetching.
@@ -59,6 +62,12 @@ func NewBatchConsumer(
 	}
 }
 
+// EnablePartialInsert switches batch inserts to per-row mode: valid reports
+// are persisted and rejected ones are logged and skipped, like poison pills.
+func (bc *BatchConsumer) EnablePartialInsert() {
+	bc.partialInsert = true
+}
+
 // Run consumes messages in batches until the context is cancelled.
 func (bc *BatchConsumer) Run(ctx context.Context) error {
 	bc.logger.Info("kafka batch consumer started",
@@ -130,12 +139,8 @@ func (bc *BatchConsumer) fetchBatch(ctx context.Context) ([]batchItem, error) {
 			return nil, err
 		}
 
-		var report model.StormReport
-		if unmarshalErr := json.Unmarshal(msg.Value, &report); unmarshalErr != nil {
-			items = append(items, batchItem{msg: msg, err: unmarshalErr})
-		} else {
-			items = append(items, batchItem{msg: msg, report: &report})
-		}
+		report, decodeErr := decodeReport(msg)
+		items = append(items, batchItem{msg: msg, report: report, err: decodeErr})
 	}
 
 	bc.metrics.KafkaBatchSize.WithLabelValues(bc.topic).Observe(float64(len(items)))
@@ -177,6 +182,11 @@ func (bc *BatchConsumer) processBatch(ctx context.Context, items []batchItem) {
 		return
 	}
 
+	if bc.partialInsert {
+		bc.insertPartial(ctx, validReports, validMsgs)
+		return
+	}
+
 	if err := bc.store.InsertStormReports(ctx, validReports); err != nil {
 		bc.logger.Error("batch insert storm reports", "error", err, "count", len(validReports))
 		bc.metrics.KafkaConsumerErrors.WithLabelValues(bc.topic, "batch_insert").Inc()
@@ -191,6 +201,31 @@ func (bc *BatchConsumer) processBatch(ctx context.Context, items []batchItem) {
 	bc.logger.Debug("consumed batch", "count", len(validReports))
 }
 
+// insertPartial inserts reports row by row. Rejected rows are logged with
+// their offset and committed along with the rest so they are not re-delivered.
+func (bc *BatchConsumer) insertPartial(ctx context.Context, reports []*model.StormReport, msgs []kafkago.Message) {
+	rejected, err := bc.store.InsertStormReportsPartial(ctx, reports)
+	if err != nil {
+		bc.logger.Error("partial insert storm reports", "error", err, "count", len(reports))
+		bc.metrics.KafkaConsumerErrors.WithLabelValues(bc.topic, "batch_insert").Inc()
+		return
+	}
+
+	for _, re := range rejected {
+		bc.logger.Error("reject storm report in batch",
+			"error", re.Err, "id", re.ID, "offset", msgs[re.Index].Offset)
+		bc.metrics.KafkaConsumerErrors.WithLabelValues(bc.topic, "insert_row").Inc()
+	}
+
+	if err := bc.reader.CommitMessages(ctx, msgs...); err != nil {
+		bc.logger.Error("commit batch offsets", "error", err, "count", len(msgs))
+	}
+
+	inserted := len(reports) - len(rejected)
+	bc.metrics.KafkaMessagesConsumed.WithLabelValues(bc.topic).Add(float64(inserted))
+	bc.logger.Debug("consumed batch", "count", inserted, "rejected", len(rejected))
+}
+
 // Close shuts down the underlying Kafka reader.
 func (bc *BatchConsumer) Close() error {
 	return bc.reader.Close()
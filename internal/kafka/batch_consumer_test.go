@@ -53,6 +53,19 @@ func TestFetchBatch_FullBatch(t *testing.T) {
 	}
 }
 
+func TestFetchBatch_IngestJobHeader(t *testing.T) {
+	msg := kafkaMsg(validMessageBytes(t), 0)
+	msg.Headers = []kafkago.Header{{Key: IngestJobHeader, Value: []byte("load-1")}}
+	bc := newTestBatchConsumer(&mockReader{msgs: []kafkago.Message{msg}}, &mockStore{})
+	bc.batchSize = 1
+
+	items, err := bc.fetchBatch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.NotNil(t, items[0].report.IngestJobID)
+	assert.Equal(t, "load-1", *items[0].report.IngestJobID)
+}
+
 func TestFetchBatch_FlushTimeout(t *testing.T) {
 	// Only one message available, should return partial batch after flush interval.
 	data := validMessageBytes(t)
@@ -171,6 +184,50 @@ func TestProcessBatch_InsertError(t *testing.T) {
 	assert.Empty(t, reader.committed)
 }
 
+func TestProcessBatch_PartialInsertMixedBatch(t *testing.T) {
+	reader := &mockReader{}
+	store := &mockStore{rowErrs: map[string]error{"bad": errors.New("invalid byte sequence")}}
+	bc := newTestBatchConsumer(reader, store)
+	bc.EnablePartialInsert()
+
+	items := []batchItem{
+		{msg: kafkaMsg(nil, 10), report: &model.StormReport{ID: "ok-1"}},
+		{msg: kafkaMsg(nil, 11), report: &model.StormReport{ID: "bad"}},
+		{msg: kafkaMsg(nil, 12), report: &model.StormReport{ID: "ok-2"}},
+	}
+
+	bc.processBatch(context.Background(), items)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	require.Len(t, store.partialInserted, 2)
+	assert.Equal(t, "ok-1", store.partialInserted[0].ID)
+	assert.Equal(t, "ok-2", store.partialInserted[1].ID)
+	assert.Empty(t, store.batchInserted, "all-or-nothing insert should not be used")
+
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+	// Rejected rows are committed like poison pills so they are not re-delivered.
+	assert.Len(t, reader.committed, 3)
+}
+
+func TestProcessBatch_PartialInsertTxError(t *testing.T) {
+	reader := &mockReader{}
+	store := &mockStore{partialErr: errors.New("db connection lost")}
+	bc := newTestBatchConsumer(reader, store)
+	bc.EnablePartialInsert()
+
+	items := []batchItem{
+		{msg: kafkaMsg(nil, 0), report: &model.StormReport{ID: "ok-1"}},
+	}
+
+	bc.processBatch(context.Background(), items)
+
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+	assert.Empty(t, reader.committed)
+}
+
 // --- Run tests ---
 
 func TestBatchRun_ContextCancelled(t *testing.T) {
@@ -238,3 +295,20 @@ func (m *mockStore) InsertStormReports(_ context.Context, reports []*model.Storm
 	m.batchInserted = append(m.batchInserted, reports...)
 	return nil
 }
+
+func (m *mockStore) InsertStormReportsPartial(_ context.Context, reports []*model.StormReport) ([]model.RowError, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.partialErr != nil {
+		return nil, m.partialErr
+	}
+	var rejected []model.RowError
+	for i, r := range reports {
+		if err, ok := m.rowErrs[r.ID]; ok {
+			rejected = append(rejected, model.RowError{Index: i, ID: r.ID, Err: err})
+			continue
+		}
+		m.partialInserted = append(m.partialInserted, r)
+	}
+	return rejected, nil
+}
@@ -11,6 +11,26 @@ import (
 	kafkago "github.com/segmentio/kafka-go"
 )
 
+// IngestJobHeader is the message header naming the batch load or correction
+// job that published a report. Its value is stored as the report's
+// ingest_job_id; messages without it are stream events with no job.
+const IngestJobHeader = "ingest-job-id"
+
+// decodeReport unmarshals msg into a report and attaches its ingest job.
+func decodeReport(msg kafkago.Message) (*model.StormReport, error) {
+	var report model.StormReport
+	if err := json.Unmarshal(msg.Value, &report); err != nil {
+		return nil, err
+	}
+	for _, h := range msg.Headers {
+		if h.Key == IngestJobHeader && len(h.Value) > 0 {
+			job := string(h.Value)
+			report.IngestJobID = &job
+		}
+	}
+	return &report, nil
+}
+
 // MessageReader abstracts the kafka reader for testability.
 type MessageReader interface {
 	FetchMessage(ctx context.Context) (kafkago.Message, error)
@@ -22,6 +42,7 @@ type MessageReader interface {
 type StoreInserter interface {
 	InsertStormReport(ctx context.Context, report *model.StormReport) error
 	InsertStormReports(ctx context.Context, reports []*model.StormReport) error
+	InsertStormReportsPartial(ctx context.Context, reports []*model.StormReport) ([]model.RowError, error)
 }
 
 // Consumer reads storm reports from a Kafka topic and persists them to the store.
@@ -88,8 +109,8 @@ func (c *Consumer) Run(ctx context.Context) error {
 // handleMessage processes a single Kafka message: unmarshal, insert, commit.
 // Returns true if the consumer should stop (context cancelled).
 func (c *Consumer) handleMessage(ctx context.Context, msg kafkago.Message) bool {
-	var report model.StormReport
-	if err := json.Unmarshal(msg.Value, &report); err != nil {
+	report, err := decodeReport(msg)
+	if err != nil {
 		c.logger.Error("unmarshal kafka message", "error", err, "offset", msg.Offset)
 		c.metrics.KafkaConsumerErrors.WithLabelValues(c.topic, "unmarshal").Inc()
 		// Commit bad messages to avoid reprocessing poison pills
@@ -103,7 +124,7 @@ func (c *Consumer) handleMessage(ctx context.Context, msg kafkago.Message) bool
 		return true
 	}
 
-	if err := c.store.InsertStormReport(ctx, &report); err != nil {
+	if err := c.store.InsertStormReport(ctx, report); err != nil {
 		c.logger.Error("insert storm report", "error", err, "id", report.ID)
 		c.metrics.KafkaConsumerErrors.WithLabelValues(c.topic, "insert").Inc()
 		return ctx.Err() != nil
@@ -67,6 +67,11 @@ type mockStore struct {
 	insertErr      error
 	batchInserted  []*model.StormReport
 	batchInsertErr error
+
+	// Partial insert: rows whose ID is in rowErrs are rejected.
+	partialInserted []*model.StormReport
+	partialErr      error
+	rowErrs         map[string]error
 }
 
 func (m *mockStore) InsertStormReport(_ context.Context, report *model.StormReport) error {
@@ -146,6 +151,21 @@ func TestHandleMessage_HappyPath(t *testing.T) {
 	assert.Equal(t, int64(42), reader.committed[0].Offset)
 }
 
+func TestHandleMessage_IngestJobHeader(t *testing.T) {
+	store := &mockStore{}
+	c := newTestConsumer(&mockReader{}, store)
+
+	msg := kafkaMsg(validMessageBytes(t), 1)
+	msg.Headers = []kafkago.Header{{Key: IngestJobHeader, Value: []byte("load-1")}}
+	c.handleMessage(context.Background(), msg)
+	c.handleMessage(context.Background(), kafkaMsg(validMessageBytes(t), 2))
+
+	require.Len(t, store.inserted, 2)
+	require.NotNil(t, store.inserted[0].IngestJobID)
+	assert.Equal(t, "load-1", *store.inserted[0].IngestJobID)
+	assert.Nil(t, store.inserted[1].IngestJobID, "stream events have no job")
+}
+
 func TestHandleMessage_UnmarshalError(t *testing.T) {
 	store := &mockStore{}
 	reader := &mockReader{}
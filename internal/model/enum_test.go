@@ -12,6 +12,9 @@ func TestSortFieldIsValid(t *testing.T) {
 		model.SortFieldMagnitude,
 		model.SortFieldLocationState,
 		model.SortFieldEventType,
+		model.SortFieldSeverityScore,
+		model.SortFieldSeverity,
+		model.SortFieldMagnitudeNormalized,
 	}
 	for _, sf := range valid {
 		if !sf.IsValid() {
@@ -36,6 +39,9 @@ func TestSortFieldString(t *testing.T) {
 		{model.SortFieldMagnitude, "MAGNITUDE"},
 		{model.SortFieldLocationState, "LOCATION_STATE"},
 		{model.SortFieldEventType, "EVENT_TYPE"},
+		{model.SortFieldSeverityScore, "SEVERITY_SCORE"},
+		{model.SortFieldSeverity, "SEVERITY"},
+		{model.SortFieldMagnitudeNormalized, "MAGNITUDE_NORMALIZED"},
 	}
 	for _, tt := range tests {
 		if got := tt.field.String(); got != tt.want {
@@ -68,3 +74,141 @@ func TestSortOrderString(t *testing.T) {
 		t.Errorf("SortOrderDesc.String() = %q, want DESC", got)
 	}
 }
+
+func TestSeverityWeightsScore(t *testing.T) {
+	w := model.DefaultSeverityWeights
+	tests := []struct {
+		eventType string
+		magnitude float64
+		want      float64
+	}{
+		{"hail", model.SeverityRefHail, 1},       // EXTREME threshold scores the weight
+		{"wind", model.SeverityRefWind / 2, 0.5}, // linear in magnitude
+		{"tornado", model.SeverityRefTornado, 3},
+		{"tornado", 0, 0},
+		{"unknown", 100, 0},
+	}
+	for _, tt := range tests {
+		r := &model.StormReport{EventType: tt.eventType, Measurement: model.Measurement{Magnitude: tt.magnitude}}
+		if got := w.Score(r); got != tt.want {
+			t.Errorf("Score(%s, %v) = %v, want %v", tt.eventType, tt.magnitude, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityWeightsWithThresholds(t *testing.T) {
+	thresholds := []model.SeverityThreshold{
+		{EventType: model.EventTypeHail, Moderate: 1, Severe: 2, Extreme: 4},
+		{EventType: model.EventTypeWind, Moderate: 50, Severe: 74, Extreme: model.SeverityRefWind},
+		{EventType: model.EventTypeTornado, Moderate: 1, Severe: 2, Extreme: 4},
+	}
+	w := model.DefaultSeverityWeights.WithThresholds(thresholds)
+	tests := []struct {
+		eventType string
+		magnitude float64
+		want      float64
+	}{
+		{"hail", 4, 1}, // the loaded EXTREME threshold scores the weight
+		{"hail", model.SeverityRefHail, 0.625},
+		{"wind", model.SeverityRefWind, 1},
+		{"tornado", 4, 3},
+	}
+	for _, tt := range tests {
+		r := &model.StormReport{EventType: tt.eventType, Measurement: model.Measurement{Magnitude: tt.magnitude}}
+		if got := w.Score(r); got != tt.want {
+			t.Errorf("Score(%s, %v) = %v, want %v", tt.eventType, tt.magnitude, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSeverityThresholds(t *testing.T) {
+	if err := model.ValidateSeverityThresholds(model.DefaultSeverityThresholds); err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+	hail := model.SeverityThreshold{EventType: model.EventTypeHail, Moderate: 1, Severe: 2, Extreme: 3}
+	wind := model.SeverityThreshold{EventType: model.EventTypeWind, Moderate: 1, Severe: 2, Extreme: 3}
+	tornado := model.SeverityThreshold{EventType: model.EventTypeTornado, Moderate: 1, Severe: 2, Extreme: 3}
+	unordered := tornado
+	unordered.Severe = 4
+	tests := []struct {
+		name       string
+		thresholds []model.SeverityThreshold
+	}{
+		{"missing type", []model.SeverityThreshold{hail, wind}},
+		{"duplicate type", []model.SeverityThreshold{hail, wind, tornado, hail}},
+		{"unknown type", []model.SeverityThreshold{hail, wind, tornado, {EventType: "FLOOD", Moderate: 1, Severe: 2, Extreme: 3}}},
+		{"unordered", []model.SeverityThreshold{hail, wind, unordered}},
+		{"zero moderate", []model.SeverityThreshold{hail, wind, {EventType: model.EventTypeTornado, Severe: 2, Extreme: 3}}},
+	}
+	for _, tt := range tests {
+		if err := model.ValidateSeverityThresholds(tt.thresholds); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestTimeColumnIsValid(t *testing.T) {
+	for _, v := range []model.TimeColumn{model.TimeColumnEventTime, model.TimeColumnProcessedAt} {
+		if !v.IsValid() {
+			t.Errorf("expected %q to be valid", v)
+		}
+	}
+	for _, v := range []model.TimeColumn{"", "event_time", "BEGIN_TIME"} {
+		if v.IsValid() {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestBucketUnitIsValid(t *testing.T) {
+	for _, v := range []model.BucketUnit{model.BucketUnitHour, model.BucketUnitDay, model.BucketUnitWeek, model.BucketUnitMonth, model.BucketUnitYear} {
+		if !v.IsValid() {
+			t.Errorf("expected %q to be valid", v)
+		}
+	}
+	for _, v := range []model.BucketUnit{"", "day", "QUARTER"} {
+		if v.IsValid() {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestMagnitudeUnitIsValid(t *testing.T) {
+	for _, v := range []model.MagnitudeUnit{model.MagnitudeUnitInches, model.MagnitudeUnitMph, model.MagnitudeUnitFScale, model.MagnitudeUnitUnspecified} {
+		if !v.IsValid() {
+			t.Errorf("expected %q to be valid", v)
+		}
+	}
+	for _, v := range []model.MagnitudeUnit{"", "in", "KNOTS"} {
+		if v.IsValid() {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestEventTypeMagnitudeUnit(t *testing.T) {
+	tests := map[model.EventType]string{
+		model.EventTypeHail:    "in",
+		model.EventTypeWind:    "mph",
+		model.EventTypeTornado: "f_scale",
+		"HAZE":                 "",
+	}
+	for et, want := range tests {
+		if got := et.MagnitudeUnit().DBValue(); got != want {
+			t.Errorf("%s unit = %q, want %q", et, got, want)
+		}
+	}
+}
+
+func TestDayNightIsValid(t *testing.T) {
+	for _, v := range []model.DayNight{model.DayNightDay, model.DayNightNight} {
+		if !v.IsValid() {
+			t.Errorf("expected %q to be valid", v)
+		}
+	}
+	for _, v := range []model.DayNight{"", "day", "DUSK"} {
+		if v.IsValid() {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/couchcryptid/storm-data-api/internal/model"
 )
@@ -127,3 +128,104 @@ func TestMockDataHailReport(t *testing.T) {
 		t.Errorf("expected state TX, got %s", first.Location.State)
 	}
 }
+
+func TestReportPatchValidate(t *testing.T) {
+	version := time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC)
+	neg, lat := -1.0, 91.0
+	bad := "retracted"
+	tests := []struct {
+		name  string
+		patch model.ReportPatch
+		ok    bool
+	}{
+		{"valid", model.ReportPatch{ExpectedUpdatedAt: version}, true},
+		{"missing version", model.ReportPatch{}, false},
+		{"negative magnitude", model.ReportPatch{ExpectedUpdatedAt: version, Magnitude: &neg}, false},
+		{"lat out of range", model.ReportPatch{ExpectedUpdatedAt: version, Lat: &lat}, false},
+		{"unknown correction status", model.ReportPatch{ExpectedUpdatedAt: version, CorrectionStatus: &bad}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.patch.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestStormReportFilterValidate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := &model.StormReportFilter{TimeRange: model.TimeRange{From: from, To: from.Add(2 * time.Hour)}}
+	tests := []struct {
+		maxSpan time.Duration
+		want    string
+	}{
+		{0, ""},
+		{2 * time.Hour, ""},
+		{90 * time.Minute, "timeRange spans more than 1h30m0s"},
+		{-time.Hour, ""},
+	}
+	for _, tt := range tests {
+		err := f.Validate(tt.maxSpan)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("Validate(%s) = %q, want %q", tt.maxSpan, got, tt.want)
+		}
+	}
+
+	f.TimeRange.To = from.AddDate(0, 0, 40)
+	if err := f.Validate(30 * 24 * time.Hour); err == nil || err.Error() != "timeRange spans more than 30 days" {
+		t.Errorf("Validate(30 days) = %v, want whole days in the message", err)
+	}
+}
+
+func TestEventTypeLabelsCanonical(t *testing.T) {
+	for raw, want := range map[string]string{
+		"TOR":          "tornado",
+		"tornado":      "tornado",
+		"Tornado":      "tornado",
+		" HAIL ":       "hail",
+		"TSTM WND GST": "wind",
+		"funnel cloud": "funnel cloud", // unknown codes pass through
+	} {
+		if got := model.DefaultEventTypeLabels.Canonical(raw); got != want {
+			t.Errorf("Canonical(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestRoundCoordinates(t *testing.T) {
+	original := &model.StormReport{ID: "r1", Geo: model.Geo{Lat: 35.123456789, Lon: -97.987654321}}
+
+	rounded := model.RoundCoordinates([]*model.StormReport{original}, 2)
+
+	out, err := json.Marshal(rounded[0].Geo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"lat":35.12,"lon":-97.99}` {
+		t.Errorf("rounded geo = %s", out)
+	}
+	if original.Geo.Lat != 35.123456789 {
+		t.Error("source report (possibly cached) must be left untouched")
+	}
+}
+
+func TestRoundMagnitudes_MixedTypes(t *testing.T) {
+	two, zero := 2, 0
+	hail := &model.StormReport{ID: "h", EventType: "hail", Measurement: model.Measurement{Magnitude: 1.756}}
+	wind := &model.StormReport{ID: "w", EventType: "wind", Measurement: model.Measurement{Magnitude: 62.5}}
+	tornado := &model.StormReport{ID: "t", EventType: "tornado", Measurement: model.Measurement{Magnitude: 2.25}}
+
+	rounded := model.RoundMagnitudes([]*model.StormReport{hail, wind, tornado}, model.MagnitudePrecision{Hail: &two, Wind: &zero})
+
+	for i, want := range []float64{1.76, 63, 2.25} { // tornado precision unset
+		if got := rounded[i].Measurement.Magnitude; got != want {
+			t.Errorf("%s magnitude = %v, want %v", rounded[i].ID, got, want)
+		}
+	}
+	if hail.Measurement.Magnitude != 1.756 || wind.Measurement.Magnitude != 62.5 {
+		t.Error("source reports (possibly cached) must be left untouched")
+	}
+}
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"slices"
 	"strings"
 	"time"
 )
@@ -32,6 +35,27 @@ type StormReport struct {
 	SourceOffice string      `json:"source_office"`
 	TimeBucket   time.Time   `json:"time_bucket"`
 	ProcessedAt  time.Time   `json:"processed_at"`
+	SpotterLevel *string     `json:"spotter_level,omitempty"`
+
+	// RawEventType is the upstream event type code, before EventType was
+	// normalized to its canonical label on insert. Empty on input means
+	// EventType is the raw code.
+	RawEventType string `json:"raw_event_type,omitempty"`
+
+	// IngestJobID names the batch load or correction job that delivered the
+	// report, for data-governance audits. It is written on insert only;
+	// reports read back leave it nil.
+	IngestJobID *string `json:"ingest_job_id,omitempty"`
+
+	// Derived values attached after fetch by a server-side enricher; never
+	// stored.
+	Attributes []*Attribute `json:"attributes,omitempty"`
+}
+
+// Attribute is a derived name/value pair attached to a report.
+type Attribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // Geo holds latitude and longitude coordinates. Nested as a struct because
@@ -62,8 +86,26 @@ type Measurement struct {
 	Magnitude float64 `json:"magnitude"`
 	Unit      string  `json:"unit"`
 	Severity  *string `json:"severity,omitempty"`
+	Method    *string `json:"method,omitempty"`
 }
 
+// Measurement method values.
+const (
+	MeasurementMethodMeasured  = "measured"
+	MeasurementMethodEstimated = "estimated"
+)
+
+// Correction status values. NWS re-issues corrected reports; a report replaced
+// by its correction is kept as deleted-supersede.
+const (
+	CorrectionStatusOriginal   = "original"
+	CorrectionStatusCorrected  = "corrected"
+	CorrectionStatusSuperseded = "deleted-supersede"
+)
+
+// CorrectionStatuses lists the known correction status values.
+var CorrectionStatuses = []string{CorrectionStatusOriginal, CorrectionStatusCorrected, CorrectionStatusSuperseded}
+
 // ─── Enums ──────────────────────────────────────────────────
 
 // EventType enumerates the types of severe weather events.
@@ -76,6 +118,9 @@ const (
 	EventTypeTornado EventType = "TORNADO"
 )
 
+// EventTypes lists the known event types.
+var EventTypes = []EventType{EventTypeHail, EventTypeWind, EventTypeTornado}
+
 // IsValid returns true if the event type is a known value.
 func (e EventType) IsValid() bool {
 	switch e {
@@ -90,6 +135,20 @@ func (e EventType) String() string { return string(e) }
 // DBValue returns the lowercase DB representation of the event type.
 func (e EventType) DBValue() string { return strings.ToLower(string(e)) }
 
+// MagnitudeUnit returns the unit the event type's magnitudes are measured in,
+// or MagnitudeUnitUnspecified for an unknown type.
+func (e EventType) MagnitudeUnit() MagnitudeUnit {
+	switch e {
+	case EventTypeHail:
+		return MagnitudeUnitInches
+	case EventTypeWind:
+		return MagnitudeUnitMph
+	case EventTypeTornado:
+		return MagnitudeUnitFScale
+	}
+	return MagnitudeUnitUnspecified
+}
+
 // UnmarshalGQL implements the graphql.Unmarshaler interface.
 func (e *EventType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
@@ -160,12 +219,20 @@ const (
 	SortFieldMagnitude     SortField = "MAGNITUDE"
 	SortFieldLocationState SortField = "LOCATION_STATE"
 	SortFieldEventType     SortField = "EVENT_TYPE"
+	SortFieldSeverityScore SortField = "SEVERITY_SCORE"
+	// SortFieldSeverity orders by severity level, MINOR lowest and EXTREME
+	// highest, rather than alphabetically.
+	SortFieldSeverity SortField = "SEVERITY"
+	// SortFieldMagnitudeNormalized orders by the magnitude's percentile rank
+	// within its event type, so units are comparable across types.
+	SortFieldMagnitudeNormalized SortField = "MAGNITUDE_NORMALIZED"
 )
 
 // IsValid returns true if the sort field is a known value.
 func (e SortField) IsValid() bool {
 	switch e {
-	case SortFieldEventTime, SortFieldMagnitude, SortFieldLocationState, SortFieldEventType:
+	case SortFieldEventTime, SortFieldMagnitude, SortFieldLocationState, SortFieldEventType,
+		SortFieldSeverityScore, SortFieldSeverity, SortFieldMagnitudeNormalized:
 		return true
 	}
 	return false
@@ -193,6 +260,250 @@ func (e SortOrder) IsValid() bool {
 
 func (e SortOrder) String() string { return string(e) }
 
+// TimeColumn selects which timestamp the time range and default sort apply to.
+type TimeColumn string
+
+// TimeColumn enum values.
+const (
+	TimeColumnEventTime   TimeColumn = "EVENT_TIME"
+	TimeColumnProcessedAt TimeColumn = "PROCESSED_AT"
+)
+
+// IsValid returns true if the time column is a known value.
+func (e TimeColumn) IsValid() bool {
+	switch e {
+	case TimeColumnEventTime, TimeColumnProcessedAt:
+		return true
+	}
+	return false
+}
+
+func (e TimeColumn) String() string { return string(e) }
+
+// DayNight selects reports by whether the sun was up at the report's location.
+type DayNight string
+
+// DayNight enum values.
+const (
+	DayNightDay   DayNight = "DAY"
+	DayNightNight DayNight = "NIGHT"
+)
+
+// IsValid returns true if the value is a known day/night selector.
+func (e DayNight) IsValid() bool {
+	switch e {
+	case DayNightDay, DayNightNight:
+		return true
+	}
+	return false
+}
+
+func (e DayNight) String() string { return string(e) }
+
+// BucketUnit is the calendar unit a time series is truncated to.
+type BucketUnit string
+
+// BucketUnit enum values.
+const (
+	BucketUnitHour  BucketUnit = "HOUR"
+	BucketUnitDay   BucketUnit = "DAY"
+	BucketUnitWeek  BucketUnit = "WEEK"
+	BucketUnitMonth BucketUnit = "MONTH"
+	BucketUnitYear  BucketUnit = "YEAR"
+)
+
+// IsValid returns true if the bucket unit is a known value.
+func (e BucketUnit) IsValid() bool {
+	switch e {
+	case BucketUnitHour, BucketUnitDay, BucketUnitWeek, BucketUnitMonth, BucketUnitYear:
+		return true
+	}
+	return false
+}
+
+func (e BucketUnit) String() string { return string(e) }
+
+// MagnitudeUnit is the unit a global magnitude bound is expressed in.
+type MagnitudeUnit string
+
+// MagnitudeUnit enum values.
+const (
+	MagnitudeUnitInches      MagnitudeUnit = "INCHES"
+	MagnitudeUnitMph         MagnitudeUnit = "MPH"
+	MagnitudeUnitFScale      MagnitudeUnit = "F_SCALE"
+	MagnitudeUnitUnspecified MagnitudeUnit = "UNSPECIFIED"
+)
+
+// IsValid returns true if the magnitude unit is a known value.
+func (e MagnitudeUnit) IsValid() bool {
+	switch e {
+	case MagnitudeUnitInches, MagnitudeUnitMph, MagnitudeUnitFScale, MagnitudeUnitUnspecified:
+		return true
+	}
+	return false
+}
+
+func (e MagnitudeUnit) String() string { return string(e) }
+
+// DBValue returns the measurement_unit value for the unit ("in", "mph",
+// "f_scale"), or "" when unspecified.
+func (e MagnitudeUnit) DBValue() string {
+	switch e {
+	case MagnitudeUnitInches:
+		return "in"
+	case MagnitudeUnitMph:
+		return "mph"
+	case MagnitudeUnitFScale:
+		return "f_scale"
+	}
+	return ""
+}
+
+// Default reference magnitudes that normalize each event type so 1.0
+// corresponds to the EXTREME severity threshold.
+const (
+	SeverityRefHail    = 2.5  // inches
+	SeverityRefWind    = 96.0 // mph
+	SeverityRefTornado = 5.0  // EF scale
+)
+
+// SeverityThreshold holds the minimum magnitudes of the MODERATE, SEVERE, and
+// EXTREME categories for one event type.
+type SeverityThreshold struct {
+	EventType EventType
+	Moderate  float64
+	Severe    float64
+	Extreme   float64
+}
+
+// DefaultSeverityThresholds mirrors the thresholds documented on Severity and
+// seeded into the severity_thresholds table.
+var DefaultSeverityThresholds = []SeverityThreshold{
+	{EventType: EventTypeHail, Moderate: 0.75, Severe: 1.5, Extreme: SeverityRefHail},
+	{EventType: EventTypeWind, Moderate: 50, Severe: 74, Extreme: SeverityRefWind},
+	{EventType: EventTypeTornado, Moderate: 2, Severe: 3, Extreme: SeverityRefTornado},
+}
+
+// ValidateSeverityThresholds checks that thresholds has exactly one entry per
+// known event type, each with 0 < Moderate < Severe < Extreme.
+func ValidateSeverityThresholds(thresholds []SeverityThreshold) error {
+	seen := make(map[EventType]bool, len(thresholds))
+	for _, th := range thresholds {
+		if !th.EventType.IsValid() {
+			return fmt.Errorf("severity thresholds: unknown event type %q", th.EventType)
+		}
+		if seen[th.EventType] {
+			return fmt.Errorf("severity thresholds: duplicate event type %s", th.EventType)
+		}
+		seen[th.EventType] = true
+		if !(0 < th.Moderate && th.Moderate < th.Severe && th.Severe < th.Extreme) {
+			return fmt.Errorf("severity thresholds: %s must satisfy 0 < moderate < severe < extreme", th.EventType)
+		}
+	}
+	for _, e := range EventTypes {
+		if !seen[e] {
+			return fmt.Errorf("severity thresholds: missing event type %s", e)
+		}
+	}
+	return nil
+}
+
+// SeverityWeights scales each event type's normalized magnitude in the
+// severity score:
+//
+//	score = weight[type] × magnitude / reference[type]
+//
+// so an EXTREME-threshold report scores exactly its type's weight.
+type SeverityWeights struct {
+	Hail    float64
+	Wind    float64
+	Tornado float64
+
+	// Reference magnitudes, set from loaded thresholds by WithThresholds.
+	// Zero means the SeverityRef default for that type.
+	RefHail    float64
+	RefWind    float64
+	RefTornado float64
+}
+
+// DefaultSeverityWeights ranks tornadoes three times heavier than hail or
+// wind of equivalent relative magnitude.
+var DefaultSeverityWeights = SeverityWeights{Hail: 1, Wind: 1, Tornado: 3}
+
+// IsZero reports whether no weight is set, ignoring the reference magnitudes.
+func (w SeverityWeights) IsZero() bool {
+	return w.Hail == 0 && w.Wind == 0 && w.Tornado == 0
+}
+
+// WithThresholds returns w normalized by each type's EXTREME threshold in
+// thresholds, so retuning the thresholds moves the score's reference points.
+func (w SeverityWeights) WithThresholds(thresholds []SeverityThreshold) SeverityWeights {
+	for _, th := range thresholds {
+		switch th.EventType {
+		case EventTypeHail:
+			w.RefHail = th.Extreme
+		case EventTypeWind:
+			w.RefWind = th.Extreme
+		case EventTypeTornado:
+			w.RefTornado = th.Extreme
+		}
+	}
+	return w
+}
+
+// Factor returns the per-unit-magnitude multiplier for a lowercase event type,
+// or 0 for unknown types.
+func (w SeverityWeights) Factor(eventType string) float64 {
+	switch eventType {
+	case EventTypeHail.DBValue():
+		return w.Hail / orDefault(w.RefHail, SeverityRefHail)
+	case EventTypeWind.DBValue():
+		return w.Wind / orDefault(w.RefWind, SeverityRefWind)
+	case EventTypeTornado.DBValue():
+		return w.Tornado / orDefault(w.RefTornado, SeverityRefTornado)
+	}
+	return 0
+}
+
+func orDefault(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// Score computes the severity score of a report from its event type and
+// magnitude only. Damage estimates are left out because most reports have
+// none; reports carry no injury figures.
+func (w SeverityWeights) Score(r *StormReport) float64 {
+	return w.Factor(r.EventType) * r.Measurement.Magnitude
+}
+
+// EventTypeLabels maps upstream event type codes, keyed in lower case, to
+// the canonical lowercase event type shown to clients.
+type EventTypeLabels map[string]string
+
+// DefaultEventTypeLabels covers the codes seen from upstream feeds: the
+// canonical names in any case, and NWS local storm report abbreviations.
+var DefaultEventTypeLabels = EventTypeLabels{
+	"hail":             EventTypeHail.DBValue(),
+	"wind":             EventTypeWind.DBValue(),
+	"tornado":          EventTypeTornado.DBValue(),
+	"tor":              EventTypeTornado.DBValue(),
+	"tstm wnd gst":     EventTypeWind.DBValue(),
+	"tstm wnd dmg":     EventTypeWind.DBValue(),
+	"non-tstm wnd gst": EventTypeWind.DBValue(),
+}
+
+// Canonical returns the label for a raw event type code, matched
+// case-insensitively after trimming. Unknown codes are returned unchanged.
+func (l EventTypeLabels) Canonical(raw string) string {
+	if label, ok := l[strings.ToLower(strings.TrimSpace(raw))]; ok {
+		return label
+	}
+	return raw
+}
+
 // ─── Filter inputs ──────────────────────────────────────────
 
 // TimeRange specifies a time window for filtering.
@@ -201,6 +512,11 @@ type TimeRange struct {
 	To   time.Time `json:"to"`
 }
 
+// IsZero reports whether neither bound is set (no time filter supplied).
+func (t TimeRange) IsZero() bool {
+	return t.From.IsZero() && t.To.IsZero()
+}
+
 // GeoRadiusFilter specifies a geographic radius filter.
 type GeoRadiusFilter struct {
 	Lat         float64  `json:"lat"`
@@ -208,45 +524,305 @@ type GeoRadiusFilter struct {
 	RadiusMiles *float64 `json:"radiusMiles,omitempty"`
 }
 
-// EventTypeFilter allows per-type overrides for severity, magnitude, and radius.
+// BoundingBoxFilter restricts results to a latitude/longitude rectangle.
+type BoundingBoxFilter struct {
+	MinLat float64 `json:"minLat"`
+	MaxLat float64 `json:"maxLat"`
+	MinLon float64 `json:"minLon"`
+	MaxLon float64 `json:"maxLon"`
+}
+
+// County is a US county (or equivalent) from the counties reference table.
+type County struct {
+	State    string `json:"state"`
+	Name     string `json:"name"`
+	FIPSCode string `json:"fipsCode"`
+}
+
+// LatLon is a point in decimal degrees.
+type LatLon struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// PopulatedPlaceFilter keeps reports within a distance of any populated place
+// at or above a population threshold.
+type PopulatedPlaceFilter struct {
+	MinPopulation int     `json:"minPopulation"`
+	RadiusMiles   float64 `json:"radiusMiles"`
+}
+
+// StormTrackFilter keeps reports near a moving storm. Each report is compared
+// with the track point nearest its event time; reports outside the track's
+// time span are excluded.
+type StormTrackFilter struct {
+	Points      []*TrackPoint `json:"points"`
+	RadiusMiles float64       `json:"radiusMiles"`
+}
+
+// TrackPoint is a storm's position at a moment in time.
+type TrackPoint struct {
+	Time time.Time `json:"time"`
+	Lat  float64   `json:"lat"`
+	Lon  float64   `json:"lon"`
+}
+
+// MagnitudePrecision sets the decimal places used for returned magnitudes,
+// per event type. A nil entry leaves that type's magnitudes unrounded.
+type MagnitudePrecision struct {
+	Hail    *int `json:"hail,omitempty"`
+	Wind    *int `json:"wind,omitempty"`
+	Tornado *int `json:"tornado,omitempty"`
+}
+
+// For returns the precision for an event type DB value, or nil if unset.
+func (p MagnitudePrecision) For(eventType string) *int {
+	switch eventType {
+	case EventTypeHail.DBValue():
+		return p.Hail
+	case EventTypeWind.DBValue():
+		return p.Wind
+	case EventTypeTornado.DBValue():
+		return p.Tornado
+	}
+	return nil
+}
+
+// RoundCoordinates returns copies of the reports with geo.lat/geo.lon rounded
+// to the given number of decimal places. Reports are copied rather than
+// modified because store results may be shared through the query cache.
+func RoundCoordinates(reports []*StormReport, precision int) []*StormReport {
+	scale := math.Pow10(precision)
+	rounded := make([]*StormReport, len(reports))
+	for i, r := range reports {
+		c := *r
+		c.Geo.Lat = math.Round(r.Geo.Lat*scale) / scale
+		c.Geo.Lon = math.Round(r.Geo.Lon*scale) / scale
+		rounded[i] = &c
+	}
+	return rounded
+}
+
+// RoundMagnitudes returns copies of the reports with measurement.magnitude
+// rounded to the precision set for each report's event type. Types without a
+// precision keep their stored value. Like RoundCoordinates it never modifies
+// the input reports.
+func RoundMagnitudes(reports []*StormReport, p MagnitudePrecision) []*StormReport {
+	rounded := make([]*StormReport, len(reports))
+	for i, r := range reports {
+		c := *r
+		if prec := p.For(r.EventType); prec != nil {
+			scale := math.Pow10(*prec)
+			c.Measurement.Magnitude = math.Round(r.Measurement.Magnitude*scale) / scale
+		}
+		rounded[i] = &c
+	}
+	return rounded
+}
+
+// EventTypeFilter allows per-type overrides for severity, magnitude range, and radius.
 type EventTypeFilter struct {
 	EventType    EventType  `json:"eventType"`
 	Severity     []Severity `json:"severity,omitempty"`
 	MinMagnitude *float64   `json:"minMagnitude,omitempty"`
+	MaxMagnitude *float64   `json:"maxMagnitude,omitempty"`
 	RadiusMiles  *float64   `json:"radiusMiles,omitempty"`
 }
 
+// TypeSeverityGroup is one alternative of a filter's typeSeverityGroups:
+// reports of one of EventTypes with one of Severity. An empty list leaves that
+// attribute unconstrained.
+type TypeSeverityGroup struct {
+	EventTypes []EventType `json:"eventTypes,omitempty"`
+	Severity   []Severity  `json:"severity,omitempty"`
+}
+
+// WarningFilter restricts results to reports that fell inside an NWS watch or
+// warning polygon while that product was in effect. With UnwarnedOnly set it
+// inverts: only reports outside every selected product (potential missed
+// events) are kept.
+type WarningFilter struct {
+	ID           *string  `json:"id,omitempty"`
+	Types        []string `json:"types,omitempty"`
+	UnwarnedOnly *bool    `json:"unwarnedOnly,omitempty"`
+}
+
 // StormReportFilter specifies time range, event, location, sorting, and pagination criteria.
 type StormReportFilter struct {
-	TimeRange TimeRange        `json:"timeRange"`
-	Near      *GeoRadiusFilter `json:"near,omitempty"`
-	States    []string         `json:"states,omitempty"`
-	Counties  []string         `json:"counties,omitempty"`
+	TimeRange TimeRange `json:"timeRange"`
+	// Timestamp that TimeRange and the default sort apply to; nil means
+	// EVENT_TIME.
+	TimeColumn *TimeColumn        `json:"timeColumn,omitempty"`
+	Near       *GeoRadiusFilter   `json:"near,omitempty"`
+	BBox       *BoundingBoxFilter `json:"bbox,omitempty"`
+	// Geohash cell, applied as a bounding box; longer hashes are smaller
+	// cells. AND-ed with near and bbox.
+	Geohash *string `json:"geohash,omitempty"`
+	// Exact coordinate, matched within a small tolerance; for checking
+	// whether a report at a point already exists. Set both or neither.
+	AtLat    *float64 `json:"atLat,omitempty"`
+	AtLon    *float64 `json:"atLon,omitempty"`
+	States   []string `json:"states,omitempty"`
+	Counties []string `json:"counties,omitempty"`
+	// Case-insensitive county name prefix; OR-ed with Counties.
+	CountyPrefix *string `json:"countyPrefix,omitempty"`
+	// NWS Weather Forecast Offices that issued the report (source_office).
+	Offices []string `json:"offices,omitempty"`
+
+	// Polygon vertices; reports inside (or on the edge of) the ring are kept.
+	// ValidateFilter closes the ring. AND-ed with near, bbox, and geohash.
+	Polygon []LatLon `json:"polygon,omitempty"`
+
+	// Keyword matched against the county name (substring) OR the comments
+	// (full-text).
+	KeywordSearch *string `json:"keywordSearch,omitempty"`
+
+	// Remarks phrases: comments must match every include phrase and none of
+	// the exclude phrases (full-text).
+	RemarksInclude []string `json:"remarksInclude,omitempty"`
+	RemarksExclude []string `json:"remarksExclude,omitempty"`
+
+	// Detailed reports: minimum comments length in characters.
+	MinRemarksLength *int `json:"minRemarksLength,omitempty"`
+
+	// Location precision: maximum uncertainty radius in meters. Reports with
+	// unknown uncertainty are excluded.
+	MaxLocationUncertainty *float64 `json:"maxLocationUncertainty,omitempty"`
+
+	// Quality control: keep reports whose magnitude exceeds their county's
+	// mean for the event type by more than this many standard deviations.
+	CountyOutlierStdDevs *float64 `json:"countyOutlierStdDevs,omitempty"`
+
+	// Solar position at the report's location and event time.
+	DayNight *DayNight `json:"dayNight,omitempty"`
+
+	// Source reliability: spotter training levels to include.
+	SpotterLevels []string `json:"spotterLevels,omitempty"`
+	// Measured keeps only instrument-measured (true) or only not-measured
+	// (false, including unknown) magnitudes.
+	Measured           *bool    `json:"measured,omitempty"`
+	MeasurementMethods []string `json:"measurementMethods,omitempty"`
+
+	// Data vintage: correction statuses to include. Defaults to excluding
+	// superseded reports.
+	CorrectionStatus []string `json:"correctionStatus,omitempty"`
+
+	// Impact filter: near towns/cities above a population threshold.
+	NearPopulatedPlace *PopulatedPlaceFilter `json:"nearPopulatedPlace,omitempty"`
+
+	// Case studies: near a storm's time-varying track.
+	StormTrack *StormTrackFilter `json:"stormTrack,omitempty"`
+
+	// Correlation with NWS watch/warning polygons.
+	Warning *WarningFilter `json:"warning,omitempty"`
+	// TriggeredWarning keeps reports a watch/warning was issued over within
+	// an hour after (true), or reports no product followed (false).
+	TriggeredWarning *bool `json:"triggeredWarning,omitempty"`
 
 	// Global defaults — apply to any type not overridden.
 	EventTypes   []EventType `json:"eventTypes,omitempty"`
 	Severity     []Severity  `json:"severity,omitempty"`
 	MinMagnitude *float64    `json:"minMagnitude,omitempty"`
+	MaxMagnitude *float64    `json:"maxMagnitude,omitempty"`
+	// Unit of the global magnitude bounds. When set, the bounds only apply
+	// to reports measured in that unit. Unset or UNSPECIFIED requires the
+	// bounds to apply to event types sharing one unit.
+	MagnitudeUnit *MagnitudeUnit `json:"magnitudeUnit,omitempty"`
 
 	// Per-type overrides (max 3).
 	EventTypeFilters []*EventTypeFilter `json:"eventTypeFilters,omitempty"`
 
+	// Type/severity alternatives, OR-ed together, in place of EventTypes and
+	// Severity (max 5).
+	TypeSeverityGroups []TypeSeverityGroup `json:"typeSeverityGroups,omitempty"`
+
+	// Data quality: when true, reports with event_time in the future are
+	// excluded. Set by the resolver from server config, not by clients.
+	ExcludeFuture *bool `json:"-"`
+
+	// Weights for SEVERITY_SCORE sorting. Set by the resolver from server
+	// config; nil means DefaultSeverityWeights.
+	SeverityWeights *SeverityWeights `json:"-"`
+
+	// TimeRangeDefaulted records that the client sent no timeRange and the
+	// server's default window was applied. Set by the resolver.
+	TimeRangeDefaulted bool `json:"-"`
+
+	// Incremental sync.
+	UpdatedAfter *time.Time `json:"updatedAfter,omitempty"`
+	DeltaOnly    *bool      `json:"deltaOnly,omitempty"`
+
 	// Sorting & pagination.
 	SortBy    *SortField `json:"sortBy,omitempty"`
 	SortOrder *SortOrder `json:"sortOrder,omitempty"`
 	Limit     *int       `json:"limit,omitempty"`
+	First     *int       `json:"first,omitempty"` // Relay-style alias of Limit
 	Offset    *int       `json:"offset,omitempty"`
+	After     *string    `json:"after,omitempty"`
+
+	// Cursor is After decoded. Set during validation, not by clients.
+	Cursor *PageCursor `json:"-"`
+
+	// Output formatting (does not affect matching).
+	CoordinatePrecision *int                `json:"coordinatePrecision,omitempty"`
+	MagnitudePrecision  *MagnitudePrecision `json:"magnitudePrecision,omitempty"`
+}
+
+// Validate rejects a time range longer than maxSpan. A non-positive maxSpan
+// disables the check.
+func (f *StormReportFilter) Validate(maxSpan time.Duration) error {
+	if maxSpan > 0 && f.TimeRange.To.Sub(f.TimeRange.From) > maxSpan {
+		return fmt.Errorf("timeRange spans more than %s", formatSpan(maxSpan))
+	}
+	return nil
+}
+
+// formatSpan renders whole days as "N days" and anything else as a Go
+// duration.
+func formatSpan(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d%day == 0 {
+		return fmt.Sprintf("%d days", d/day)
+	}
+	return d.String()
+}
+
+// PageCursor marks where a keyset-paginated scan left off: the sort key and
+// ID of the last row returned. AsOf is the time the scan's first page was
+// queried; every later page only sees reports created by then, so rows
+// ingested mid-scan cannot shift pages or change the total.
+type PageCursor struct {
+	AsOf    time.Time
+	SortKey any
+	ID      string
 }
 
 // ─── Result envelope ────────────────────────────────────────
 
+// PageInfo is the Relay-style pagination summary of a page.
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
 // StormReportsResult is the top-level GraphQL response.
 type StormReportsResult struct {
 	TotalCount   int                `json:"totalCount"`
 	HasMore      bool               `json:"hasMore"`
+	NextCursor   *string            `json:"nextCursor,omitempty"`
+	PageInfo     *PageInfo          `json:"pageInfo"`
 	Reports      []*StormReport     `json:"reports"`
 	Aggregations *StormAggregations `json:"aggregations"`
 	Meta         *QueryMeta         `json:"meta"`
+	Deltas       []*ReportDelta     `json:"deltas,omitempty"`
+	ConvexHull   *GeoJSONPolygon    `json:"convexHull,omitempty"`
+}
+
+// GeoJSONPolygon is a GeoJSON Polygon geometry. Coordinates are rings of
+// [lon, lat] positions; the first and last position of each ring are equal.
+type GeoJSONPolygon struct {
+	Type        string        `json:"type"`
+	Coordinates [][][]float64 `json:"coordinates"`
 }
 
 // StormAggregations groups aggregation results by event type, state, and hour.
@@ -259,8 +835,37 @@ type StormAggregations struct {
 
 // QueryMeta provides metadata about the query result.
 type QueryMeta struct {
-	LastUpdated    *time.Time `json:"lastUpdated,omitempty"`
-	DataLagMinutes *int       `json:"dataLagMinutes,omitempty"`
+	LastUpdated      *time.Time        `json:"lastUpdated,omitempty"`
+	DataLagMinutes   *int              `json:"dataLagMinutes,omitempty"`
+	AppliedTimeRange *AppliedTimeRange `json:"appliedTimeRange"`
+
+	// ExcludedMissingCoordinates counts reports left out by the area filters
+	// because they are stored without a position; only computed when
+	// requested.
+	ExcludedMissingCoordinates int `json:"excludedMissingCoordinates"`
+}
+
+// AppliedTimeRange echoes the time window a query actually ran with, after
+// the server's default window and rounding were applied.
+type AppliedTimeRange struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Defaulted bool      `json:"defaulted"`
+}
+
+// ReportDelta carries only the fields of a report that changed since the
+// filter's updatedAfter checkpoint. Reports created after the checkpoint
+// include every field.
+type ReportDelta struct {
+	ID     string        `json:"id"`
+	Fields []*FieldValue `json:"fields"`
+}
+
+// FieldValue is a single changed field, named by its GraphQL path
+// (e.g. "measurement.magnitude") with the value rendered as a string.
+type FieldValue struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value,omitempty"`
 }
 
 // ─── Aggregation types ──────────────────────────────────────
@@ -285,8 +890,204 @@ type CountyGroup struct {
 	Count  int    `json:"count"`
 }
 
+// CountyStateGroup is one flattened state/county group, as emitted by the
+// streaming aggregation endpoint.
+type CountyStateGroup struct {
+	State  string `json:"state"`
+	County string `json:"county"`
+	Count  int    `json:"count"`
+}
+
 // TimeGroup aggregates storm reports by hourly time bucket.
 type TimeGroup struct {
 	Bucket time.Time `json:"bucket"`
 	Count  int       `json:"count"`
 }
+
+// TypeCount is the number of filtered reports of one event type.
+type TypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// SeverityCount is the number of filtered reports in one severity category,
+// derived from magnitude and event type.
+type SeverityCount struct {
+	Category Severity `json:"category"`
+	Count    int      `json:"count"`
+}
+
+// MagnitudePercentile is a magnitude percentile for one event type over the
+// filtered set (e.g. the 90th percentile hail size).
+type MagnitudePercentile struct {
+	EventType  string  `json:"eventType"`
+	Percentile float64 `json:"percentile"`
+	Magnitude  float64 `json:"magnitude"`
+	Unit       string  `json:"unit"`
+	Count      int     `json:"count"`
+}
+
+// ReportRate is the rate of matching reports over a window, overall and per
+// equal sub-interval (oldest first).
+type ReportRate struct {
+	WindowStart time.Time       `json:"windowStart"`
+	WindowEnd   time.Time       `json:"windowEnd"`
+	Count       int             `json:"count"`
+	PerHour     float64         `json:"perHour"`
+	Intervals   []*RateInterval `json:"intervals"`
+}
+
+// NearbyReport is a report near an anchor report, with its great-circle
+// distance from the anchor.
+type NearbyReport struct {
+	Report        *StormReport `json:"report"`
+	DistanceMiles float64      `json:"distanceMiles"`
+}
+
+// CoverageGap is a time bucket with no matching reports.
+type CoverageGap struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// DataRange is the span of event times in the dataset. Both bounds are nil
+// when no report matches.
+type DataRange struct {
+	Earliest *time.Time `json:"earliest"`
+	Latest   *time.Time `json:"latest"`
+}
+
+// AuditOperation is how an ingest job touched a report.
+type AuditOperation string
+
+// AuditOperation values.
+const (
+	AuditOperationInsert AuditOperation = "INSERT"
+	AuditOperationUpdate AuditOperation = "UPDATE"
+)
+
+// IngestAuditEntry records one report created or modified by an ingest job.
+// ChangedColumns is nil for inserts, which set every column.
+type IngestAuditEntry struct {
+	ReportID       string         `json:"reportId"`
+	Operation      AuditOperation `json:"operation"`
+	At             time.Time      `json:"at"`
+	ChangedColumns []string       `json:"changedColumns,omitempty"`
+}
+
+// DamageTotal is the estimated damage of the matching reports whose event
+// time falls in one calendar bucket. Unknown damage counts as zero.
+type DamageTotal struct {
+	Bucket         time.Time `json:"bucket"`
+	PropertyDamage float64   `json:"propertyDamage"`
+	CropDamage     float64   `json:"cropDamage"`
+	TotalDamage    float64   `json:"totalDamage"`
+	ReportCount    int       `json:"reportCount"`
+}
+
+// TimeBucketCount is the number of matching reports whose time falls in one
+// histogram interval.
+type TimeBucketCount struct {
+	Bucket time.Time `json:"bucket"`
+	Count  int       `json:"count"`
+}
+
+// SpotterRank is one spotter's place on the report-count leaderboard of the
+// filtered set.
+type SpotterRank struct {
+	SpotterID string `json:"spotterId"`
+	Rank      int    `json:"rank"`
+	Count     int    `json:"count"`
+	Spotters  int    `json:"spotters"`
+}
+
+// HourOfDayCount is the number of matching reports in one local clock hour
+// (0-23) of the day, across all days in the time range.
+type HourOfDayCount struct {
+	Hour  int `json:"hour"`
+	Count int `json:"count"`
+}
+
+// RateInterval is one sub-interval of a ReportRate window.
+type RateInterval struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Count   int       `json:"count"`
+	PerHour float64   `json:"perHour"`
+}
+
+// ─── Warning verification ───────────────────────────────────
+
+// WarningLeadTime relates an NWS watch/warning to the first storm report that
+// fell inside its polygon while it was in effect.
+type WarningLeadTime struct {
+	WarningID       string     `json:"warningId"`
+	WarningType     string     `json:"warningType"`
+	IssuedAt        time.Time  `json:"issuedAt"`
+	FirstReportTime *time.Time `json:"firstReportTime,omitempty"`
+	LeadTimeMinutes *int       `json:"leadTimeMinutes,omitempty"`
+	ReportCount     int        `json:"reportCount"`
+}
+
+// UnverifiedWarning is an NWS watch/warning with no storm report inside its
+// polygon while it was in effect (a potential false alarm).
+type UnverifiedWarning struct {
+	WarningID   string    `json:"warningId"`
+	WarningType string    `json:"warningType"`
+	IssuedAt    time.Time `json:"issuedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// RowError reports a single rejected row from a partial batch insert.
+type RowError struct {
+	Index int    // position in the submitted batch
+	ID    string // report ID
+	Err   error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d (%s): %v", e.Index, e.ID, e.Err)
+}
+
+// ReportPatch is an admin correction to a stored report. Only non-nil fields
+// are written. ExpectedUpdatedAt is the report's updated_at as last read by
+// the caller; the patch is refused if the row has changed since. IngestJobID,
+// when set, is recorded on the revision the patch produces.
+type ReportPatch struct {
+	ExpectedUpdatedAt time.Time `json:"expectedUpdatedAt"`
+	IngestJobID       *string   `json:"ingestJobId,omitempty"`
+
+	Magnitude         *float64   `json:"magnitude,omitempty"`
+	Severity          *string    `json:"severity,omitempty"`
+	MeasurementMethod *string    `json:"measurementMethod,omitempty"`
+	EventTime         *time.Time `json:"eventTime,omitempty"`
+	Lat               *float64   `json:"lat,omitempty"`
+	Lon               *float64   `json:"lon,omitempty"`
+	LocationName      *string    `json:"locationName,omitempty"`
+	LocationCounty    *string    `json:"locationCounty,omitempty"`
+	LocationState     *string    `json:"locationState,omitempty"`
+	Comments          *string    `json:"comments,omitempty"`
+	SpotterLevel      *string    `json:"spotterLevel,omitempty"`
+	CorrectionStatus  *string    `json:"correctionStatus,omitempty"`
+}
+
+// Validate checks the expected version is set and the supplied values are in
+// range.
+func (p *ReportPatch) Validate() error {
+	if p.ExpectedUpdatedAt.IsZero() {
+		return errors.New("expectedUpdatedAt is required")
+	}
+	if p.Magnitude != nil && *p.Magnitude < 0 {
+		return errors.New("magnitude must not be negative")
+	}
+	if p.Lat != nil && (*p.Lat < -90 || *p.Lat > 90) {
+		return errors.New("lat out of range")
+	}
+	if p.Lon != nil && (*p.Lon < -180 || *p.Lon > 180) {
+		return errors.New("lon out of range")
+	}
+	if p.CorrectionStatus != nil && !slices.Contains(CorrectionStatuses, *p.CorrectionStatus) {
+		return fmt.Errorf("invalid correctionStatus %q", *p.CorrectionStatus)
+	}
+	return nil
+}
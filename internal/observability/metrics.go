@@ -2,12 +2,15 @@ package observability
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-const namespace = "storm_api"
+// DefaultNamespace prefixes every metric name when no namespace is configured.
+const DefaultNamespace = "storm_api"
 
 // ReadinessChecker reports whether a dependency is ready to serve traffic.
 type ReadinessChecker interface {
@@ -17,8 +20,9 @@ type ReadinessChecker interface {
 // Metrics holds all Prometheus collectors for the application.
 type Metrics struct {
 	// HTTP
-	HTTPRequestsTotal   *prometheus.CounterVec
-	HTTPRequestDuration *prometheus.HistogramVec
+	HTTPRequestsTotal    *prometheus.CounterVec
+	HTTPRequestDuration  *prometheus.HistogramVec
+	HTTPRequestsInFlight *prometheus.GaugeVec
 
 	// Kafka
 	KafkaMessagesConsumed *prometheus.CounterVec
@@ -30,20 +34,39 @@ type Metrics struct {
 	// Database
 	DBQueryDuration   *prometheus.HistogramVec
 	DBPoolConnections *prometheus.GaugeVec
+
+	// Query cache
+	CacheEntries *prometheus.GaugeVec
+	CacheBytes   *prometheus.GaugeVec
+
+	// Query limits
+	PageLimitExceeded prometheus.Counter
 }
 
-// NewMetrics creates and registers all application metrics with the default registry.
-func NewMetrics() *Metrics {
-	return newMetrics(promauto.With(prometheus.DefaultRegisterer))
+// NewMetrics creates and registers all application metrics with the default
+// registry, prefixing every series with namespace (e.g. "stormapi" yields
+// stormapi_http_requests_total). An empty namespace uses DefaultNamespace.
+func NewMetrics(namespace string) *Metrics {
+	return newMetrics(promauto.With(prometheus.DefaultRegisterer), namespace)
 }
 
 // NewTestMetrics creates metrics backed by a throw-away registry.
 // Safe to call from multiple tests without duplicate-registration panics.
 func NewTestMetrics() *Metrics {
-	return newMetrics(promauto.With(prometheus.NewRegistry()))
+	return newMetrics(promauto.With(prometheus.NewRegistry()), DefaultNamespace)
+}
+
+// MetricsHandler serves the default registry, which NewMetrics registers
+// with, in the Prometheus exposition format.
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
 }
 
-func newMetrics(factory promauto.Factory) *Metrics {
+func newMetrics(factory promauto.Factory, namespace string) *Metrics {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
 	return &Metrics{
 		HTTPRequestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace,
@@ -56,6 +79,12 @@ func newMetrics(factory promauto.Factory) *Metrics {
 			Name:      "http_request_duration_seconds",
 			Help:      "HTTP request duration in seconds.",
 			Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+		}, []string{"method", "path", "status"}),
+
+		HTTPRequestsInFlight: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served.",
 		}, []string{"method", "path"}),
 
 		KafkaMessagesConsumed: factory.NewCounterVec(prometheus.CounterOpts{
@@ -102,5 +131,23 @@ func newMetrics(factory promauto.Factory) *Metrics {
 			Name:      "db_pool_connections",
 			Help:      "Database connection pool statistics.",
 		}, []string{"state"}),
+
+		CacheEntries: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_entries",
+			Help:      "Entries currently held by each query cache.",
+		}, []string{"cache"}),
+
+		CacheBytes: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_memory_bytes",
+			Help:      "Estimated memory held by each query cache.",
+		}, []string{"cache"}),
+
+		PageLimitExceeded: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "page_limit_exceeded_total",
+			Help:      "Requests whose limit exceeded the maximum page size.",
+		}),
 	}
 }
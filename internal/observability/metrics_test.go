@@ -0,0 +1,33 @@
+package observability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler_ExposesNamespacedSeries(t *testing.T) {
+	// Registers with the default registry; the namespace must not be reused
+	// by another test in this package.
+	metrics := NewMetrics("stormapi")
+	metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/healthz", "200").Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(http.MethodGet, "/healthz", "200").Observe(0.01)
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `stormapi_http_requests_total{method="GET",path="/healthz",status="200"} 1`)
+	assert.Contains(t, body, "stormapi_http_request_duration_seconds_bucket")
+	assert.NotContains(t, body, "storm_api_http_requests_total")
+}
+
+func TestNewTestMetrics_UsesDefaultNamespace(t *testing.T) {
+	metrics := NewTestMetrics()
+	desc := metrics.PageLimitExceeded.Desc().String()
+	assert.Contains(t, desc, `"`+DefaultNamespace+`_page_limit_exceeded_total"`)
+}
@@ -1,6 +1,7 @@
 package observability
 
 import (
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
@@ -8,33 +9,106 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-// MetricsMiddleware records HTTP request duration and count.
+// MetricsMiddleware records HTTP request duration and count, and tracks
+// requests in flight. The in-flight gauge is decremented in a deferred call
+// so a panicking handler does not leave it raised.
 func MetricsMiddleware(m *Metrics) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 			ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
+			inFlight := m.HTTPRequestsInFlight.WithLabelValues(r.Method, matchRoutePattern(r))
+			inFlight.Inc()
+			defer inFlight.Dec()
+
 			next.ServeHTTP(ww, r)
 
-			// Use chi's route pattern to avoid unbounded label cardinality
-			// from dynamic path parameters.
-			path := r.URL.Path
-			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
-				path = rctx.RoutePattern()
-			}
+			path := routeLabel(r)
 			method := r.Method
 			status := strconv.Itoa(ww.statusCode)
 
-			m.HTTPRequestDuration.WithLabelValues(method, path).Observe(time.Since(start).Seconds())
+			m.HTTPRequestDuration.WithLabelValues(method, path, status).Observe(time.Since(start).Seconds())
 			m.HTTPRequestsTotal.WithLabelValues(method, path, status).Inc()
 		})
 	}
 }
 
+// LoggingMiddleware logs one structured line per request with the method,
+// route, status, duration, and response size. 5xx responses log at Error,
+// 4xx at Warn, and everything else at Info.
+func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(ww, r)
+
+			level := slog.LevelInfo
+			switch {
+			case ww.statusCode >= 500:
+				level = slog.LevelError
+			case ww.statusCode >= 400:
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(r.Context(), level, "http request",
+				slog.String("method", r.Method),
+				slog.String("route", routePattern(r)),
+				slog.Int("status", ww.statusCode),
+				slog.Duration("duration", time.Since(start)),
+				slog.Int("bytes", ww.bytesWritten),
+			)
+		})
+	}
+}
+
+// unknownRoute labels metrics for requests that matched no route, such as
+// 404s from scanners probing arbitrary paths.
+const unknownRoute = "unknown"
+
+// routePattern returns chi's route pattern for r, falling back to the raw
+// path. The pattern keeps log fields free of dynamic path parameters.
+func routePattern(r *http.Request) string {
+	if pattern := chiRoutePattern(r); pattern != "" {
+		return pattern
+	}
+	return r.URL.Path
+}
+
+// routeLabel returns chi's route pattern for r, or unknownRoute. Unlike
+// routePattern it never falls back to the raw path, so a metric label's
+// cardinality is bounded by the routes the router defines.
+func routeLabel(r *http.Request) string {
+	if pattern := chiRoutePattern(r); pattern != "" {
+		return pattern
+	}
+	return unknownRoute
+}
+
+func chiRoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
+
+// matchRoutePattern resolves r's route label before chi has routed it, so
+// it is known when the request starts. It falls back to routeLabel when the
+// middleware is not mounted on a chi router or nothing matches.
+func matchRoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.Routes != nil {
+		if pattern := rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path); pattern != "" {
+			return pattern
+		}
+	}
+	return routeLabel(r)
+}
+
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode   int
+	bytesWritten int
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -42,6 +116,14 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Write counts the bytes the underlying writer accepted, which is less than
+// len(b) on a short write.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytesWritten += n
+	return n, err
+}
+
 // Flush implements http.Flusher for streaming responses (e.g., GraphQL SSE).
 func (rw *responseWriter) Flush() {
 	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
@@ -1,11 +1,20 @@
 package observability
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetricsMiddleware_RecordsMetrics(t *testing.T) {
@@ -24,6 +33,188 @@ func TestMetricsMiddleware_RecordsMetrics(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestMetricsMiddleware_ObservesDurationByRouteAndStatus(t *testing.T) {
+	metrics := NewTestMetrics()
+	r := chi.NewRouter()
+	r.Use(MetricsMiddleware(metrics))
+	r.Get("/reports/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/abc-123", nil))
+
+	var m dto.Metric
+	obs, err := metrics.HTTPRequestDuration.GetMetricWithLabelValues(http.MethodGet, "/reports/{id}", "404")
+	require.NoError(t, err)
+	require.NoError(t, obs.(prometheus.Histogram).Write(&m))
+	assert.Equal(t, uint64(1), m.GetHistogram().GetSampleCount())
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestDuration), "raw path not used as a label")
+}
+
+func TestMetricsMiddleware_CountsByRoutePattern(t *testing.T) {
+	metrics := NewTestMetrics()
+	r := chi.NewRouter()
+	r.Use(MetricsMiddleware(metrics))
+	r.Get("/reports/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, id := range []string{"abc-123", "def-456"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/"+id, nil))
+	}
+
+	assert.InDelta(t, 2, testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/reports/{id}", "200")), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestsTotal), "one series per route, not per ID")
+}
+
+func TestMetricsMiddleware_UnmatchedRouteIsUnknown(t *testing.T) {
+	metrics := NewTestMetrics()
+	r := chi.NewRouter()
+	r.Use(MetricsMiddleware(metrics))
+	r.Get("/reports/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, path := range []string{"/wp-login.php", "/.env", "/admin/config.php"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.InDelta(t, 3, testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "unknown", "404")), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestsTotal))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestDuration))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestsInFlight))
+}
+
+func TestMetricsMiddleware_TracksInFlightByRoute(t *testing.T) {
+	metrics := NewTestMetrics()
+	entered, release := make(chan struct{}), make(chan struct{})
+	r := chi.NewRouter()
+	r.Use(MetricsMiddleware(metrics))
+	r.Get("/reports/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		close(entered)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/abc-123", nil))
+	}()
+
+	gauge := metrics.HTTPRequestsInFlight.WithLabelValues(http.MethodGet, "/reports/{id}")
+	<-entered
+	assert.InDelta(t, 1, testutil.ToFloat64(gauge), 0)
+	close(release)
+	<-done
+	assert.InDelta(t, 0, testutil.ToFloat64(gauge), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestsInFlight), "raw path not used as a label")
+}
+
+func TestMetricsMiddleware_InFlightDecrementedOnPanic(t *testing.T) {
+	metrics := NewTestMetrics()
+	r := chi.NewRouter()
+	r.Use(MetricsMiddleware(metrics))
+	r.Get("/boom", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+
+	assert.Panics(t, func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+	})
+	assert.InDelta(t, 0, testutil.ToFloat64(metrics.HTTPRequestsInFlight.WithLabelValues(http.MethodGet, "/boom")), 0)
+}
+
+func TestLoggingMiddleware_LogsRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	r := chi.NewRouter()
+	r.Use(LoggingMiddleware(logger))
+	r.Get("/reports/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/abc-123", nil))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, http.MethodGet, entry["method"])
+	assert.Equal(t, "/reports/{id}", entry["route"])
+	assert.InDelta(t, http.StatusNotFound, entry["status"], 0)
+	assert.InDelta(t, len("not found"), entry["bytes"], 0)
+	assert.Contains(t, entry, "duration")
+}
+
+func TestLoggingMiddleware_LevelByStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		level  string
+	}{
+		{http.StatusOK, "INFO"},
+		{http.StatusFound, "INFO"},
+		{http.StatusBadRequest, "WARN"},
+		{http.StatusServiceUnavailable, "ERROR"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		h := LoggingMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, tt.level, entry["level"], "status %d", tt.status)
+		assert.Equal(t, "/query", entry["route"], "raw path without a chi route")
+	}
+}
+
+func TestResponseWriter_BytesWritten(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: rec, statusCode: http.StatusOK}
+	payload := []byte(`{"data":{"stormReports":{"totalCount":3}}}`)
+
+	n, err := rw.Write(payload[:10])
+	require.NoError(t, err)
+	assert.Equal(t, 10, n)
+	_, err = rw.Write(payload[10:])
+	require.NoError(t, err)
+
+	assert.Equal(t, len(payload), rw.bytesWritten)
+	assert.Equal(t, payload, rec.Body.Bytes())
+	assert.Equal(t, http.StatusOK, rw.statusCode, "write before WriteHeader keeps the 200 default")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+type shortWriter struct{ nonFlusher }
+
+func (shortWriter) Write(b []byte) (int, error) { return len(b) / 2, errors.New("short write") }
+
+func TestResponseWriter_BytesWrittenCountsShortWrites(t *testing.T) {
+	rw := &responseWriter{ResponseWriter: shortWriter{}, statusCode: http.StatusOK}
+
+	n, err := rw.Write([]byte("abcdef"))
+
+	require.Error(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 3, rw.bytesWritten, "counts what reached the client")
+}
+
+func TestResponseWriter_FlushAfterWrite(t *testing.T) {
+	mf := &mockFlusher{ResponseWriter: httptest.NewRecorder()}
+	rw := &responseWriter{ResponseWriter: mf, statusCode: http.StatusOK}
+
+	_, _ = rw.Write([]byte("chunk"))
+	rw.Flush()
+
+	assert.True(t, mf.flushed)
+	assert.Equal(t, 5, rw.bytesWritten)
+}
+
 func TestResponseWriter_WriteHeader(t *testing.T) {
 	rec := httptest.NewRecorder()
 	rw := &responseWriter{ResponseWriter: rec, statusCode: http.StatusOK}
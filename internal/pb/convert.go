@@ -0,0 +1,83 @@
+// Package pb holds the protobuf encoding of storm reports for high-throughput
+// consumers and the gRPC service definition. storm.pb.go and storm_grpc.pb.go
+// are generated from storm.proto; this file converts between the generated
+// messages and the domain model.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative storm.proto
+
+import (
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromReport converts a domain report to its protobuf message.
+func FromReport(r *model.StormReport) *StormReport {
+	return &StormReport{
+		Id:        r.ID,
+		EventType: r.EventType,
+		Geo:       &Geo{Lat: r.Geo.Lat, Lon: r.Geo.Lon},
+		Measurement: &Measurement{
+			Magnitude: r.Measurement.Magnitude,
+			Unit:      r.Measurement.Unit,
+			Severity:  r.Measurement.Severity,
+			Method:    r.Measurement.Method,
+		},
+		EventTime:    timestamppb.New(r.EventTime),
+		SourceOffice: r.SourceOffice,
+		Location: &Location{
+			Raw:       r.Location.Raw,
+			Name:      r.Location.Name,
+			Distance:  r.Location.Distance,
+			Direction: r.Location.Direction,
+			State:     r.Location.State,
+			County:    r.Location.County,
+		},
+		Comments:     r.Comments,
+		TimeBucket:   timestamppb.New(r.TimeBucket),
+		ProcessedAt:  timestamppb.New(r.ProcessedAt),
+		SpotterLevel: r.SpotterLevel,
+	}
+}
+
+// ToReport converts a protobuf message back to a domain report.
+func ToReport(m *StormReport) *model.StormReport {
+	return &model.StormReport{
+		ID:        m.GetId(),
+		EventType: m.GetEventType(),
+		Geo:       model.Geo{Lat: m.GetGeo().GetLat(), Lon: m.GetGeo().GetLon()},
+		Measurement: model.Measurement{
+			Magnitude: m.GetMeasurement().GetMagnitude(),
+			Unit:      m.GetMeasurement().GetUnit(),
+			Severity:  m.GetMeasurement().Severity,
+			Method:    m.GetMeasurement().Method,
+		},
+		EventTime:    m.GetEventTime().AsTime(),
+		SourceOffice: m.GetSourceOffice(),
+		Location: model.Location{
+			Raw:       m.GetLocation().GetRaw(),
+			Name:      m.GetLocation().GetName(),
+			Distance:  m.GetLocation().Distance,
+			Direction: m.GetLocation().Direction,
+			State:     m.GetLocation().GetState(),
+			County:    m.GetLocation().GetCounty(),
+		},
+		Comments:     m.GetComments(),
+		TimeBucket:   m.GetTimeBucket().AsTime(),
+		ProcessedAt:  m.GetProcessedAt().AsTime(),
+		SpotterLevel: m.SpotterLevel,
+	}
+}
+
+// NewConnection wraps a page of reports with its pagination metadata.
+func NewConnection(reports []*model.StormReport, totalCount int, hasMore bool) *StormReportConnection {
+	msgs := make([]*StormReport, len(reports))
+	for i, r := range reports {
+		msgs[i] = FromReport(r)
+	}
+	return &StormReportConnection{
+		TotalCount: int32(totalCount), //nolint:gosec // bounded by table size
+		HasMore:    hasMore,
+		Reports:    msgs,
+	}
+}
@@ -0,0 +1,79 @@
+package pb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func sampleReport() *model.StormReport {
+	severity := "severe"
+	distance := 8.0
+	direction := "ESE"
+	spotter := "trained spotter"
+	method := model.MeasurementMethodMeasured
+	return &model.StormReport{
+		ID:          "abc123",
+		EventType:   "hail",
+		Geo:         model.Geo{Lat: 41.1, Lon: -102.47},
+		Measurement: model.Measurement{Magnitude: 1.75, Unit: "in", Severity: &severity, Method: &method},
+		EventTime:   time.Date(2024, 4, 26, 20, 15, 0, 0, time.UTC),
+		Location: model.Location{
+			Raw: "8 ESE Chappel", Name: "Chappel", Distance: &distance, Direction: &direction,
+			State: "NE", County: "Deuel",
+		},
+		Comments:     "Quarter to golf ball size hail.",
+		SourceOffice: "LBF",
+		TimeBucket:   time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC),
+		ProcessedAt:  time.Date(2024, 4, 26, 21, 0, 0, 0, time.UTC),
+		SpotterLevel: &spotter,
+	}
+}
+
+func TestReport_RoundTrip(t *testing.T) {
+	want := sampleReport()
+
+	data, err := proto.Marshal(FromReport(want))
+	require.NoError(t, err)
+
+	var msg StormReport
+	require.NoError(t, proto.Unmarshal(data, &msg))
+	assert.Equal(t, want, ToReport(&msg))
+}
+
+func TestReport_RoundTripOptionalFieldsUnset(t *testing.T) {
+	want := sampleReport()
+	want.Measurement.Severity = nil
+	want.Measurement.Method = nil
+	want.Location.Distance = nil
+	want.Location.Direction = nil
+
+	data, err := proto.Marshal(FromReport(want))
+	require.NoError(t, err)
+
+	var msg StormReport
+	require.NoError(t, proto.Unmarshal(data, &msg))
+	got := ToReport(&msg)
+	assert.Nil(t, got.Measurement.Severity)
+	assert.Nil(t, got.Measurement.Method)
+	assert.Nil(t, got.Location.Distance)
+	assert.Nil(t, got.Location.Direction)
+}
+
+func TestNewConnection(t *testing.T) {
+	conn := NewConnection([]*model.StormReport{sampleReport(), sampleReport()}, 42, true)
+
+	data, err := proto.Marshal(conn)
+	require.NoError(t, err)
+
+	var got StormReportConnection
+	require.NoError(t, proto.Unmarshal(data, &got))
+	assert.Equal(t, int32(42), got.GetTotalCount())
+	assert.True(t, got.GetHasMore())
+	require.Len(t, got.GetReports(), 2)
+	assert.Equal(t, "abc123", got.GetReports()[0].GetId())
+}
@@ -0,0 +1,975 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: storm.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListStormReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *StormReportFilter     `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStormReportsRequest) Reset() {
+	*x = ListStormReportsRequest{}
+	mi := &file_storm_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStormReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStormReportsRequest) ProtoMessage() {}
+
+func (x *ListStormReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStormReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListStormReportsRequest) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListStormReportsRequest) GetFilter() *StormReportFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type GetStormReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStormReportRequest) Reset() {
+	*x = GetStormReportRequest{}
+	mi := &file_storm_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStormReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStormReportRequest) ProtoMessage() {}
+
+func (x *GetStormReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStormReportRequest.ProtoReflect.Descriptor instead.
+func (*GetStormReportRequest) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetStormReportRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CountStormReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *StormReportFilter     `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountStormReportsRequest) Reset() {
+	*x = CountStormReportsRequest{}
+	mi := &file_storm_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountStormReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountStormReportsRequest) ProtoMessage() {}
+
+func (x *CountStormReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountStormReportsRequest.ProtoReflect.Descriptor instead.
+func (*CountStormReportsRequest) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CountStormReportsRequest) GetFilter() *StormReportFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type CountStormReportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountStormReportsResponse) Reset() {
+	*x = CountStormReportsResponse{}
+	mi := &file_storm_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountStormReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountStormReportsResponse) ProtoMessage() {}
+
+func (x *CountStormReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountStormReportsResponse.ProtoReflect.Descriptor instead.
+func (*CountStormReportsResponse) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CountStormReportsResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Subset of the GraphQL StormReportFilter. Enum-valued fields take the GraphQL
+// enum names (e.g. "HAIL", "SEVERE", "EVENT_TIME", "ASC").
+type StormReportFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required time window, both bounds inclusive.
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Near          *GeoRadiusFilter       `protobuf:"bytes,3,opt,name=near,proto3" json:"near,omitempty"`
+	States        []string               `protobuf:"bytes,4,rep,name=states,proto3" json:"states,omitempty"`
+	Counties      []string               `protobuf:"bytes,5,rep,name=counties,proto3" json:"counties,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,6,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Severity      []string               `protobuf:"bytes,7,rep,name=severity,proto3" json:"severity,omitempty"`
+	MinMagnitude  *float64               `protobuf:"fixed64,8,opt,name=min_magnitude,json=minMagnitude,proto3,oneof" json:"min_magnitude,omitempty"`
+	SortBy        *string                `protobuf:"bytes,9,opt,name=sort_by,json=sortBy,proto3,oneof" json:"sort_by,omitempty"`
+	SortOrder     *string                `protobuf:"bytes,10,opt,name=sort_order,json=sortOrder,proto3,oneof" json:"sort_order,omitempty"`
+	Limit         *int32                 `protobuf:"varint,11,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	Offset        *int32                 `protobuf:"varint,12,opt,name=offset,proto3,oneof" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StormReportFilter) Reset() {
+	*x = StormReportFilter{}
+	mi := &file_storm_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StormReportFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StormReportFilter) ProtoMessage() {}
+
+func (x *StormReportFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StormReportFilter.ProtoReflect.Descriptor instead.
+func (*StormReportFilter) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StormReportFilter) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *StormReportFilter) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *StormReportFilter) GetNear() *GeoRadiusFilter {
+	if x != nil {
+		return x.Near
+	}
+	return nil
+}
+
+func (x *StormReportFilter) GetStates() []string {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *StormReportFilter) GetCounties() []string {
+	if x != nil {
+		return x.Counties
+	}
+	return nil
+}
+
+func (x *StormReportFilter) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *StormReportFilter) GetSeverity() []string {
+	if x != nil {
+		return x.Severity
+	}
+	return nil
+}
+
+func (x *StormReportFilter) GetMinMagnitude() float64 {
+	if x != nil && x.MinMagnitude != nil {
+		return *x.MinMagnitude
+	}
+	return 0
+}
+
+func (x *StormReportFilter) GetSortBy() string {
+	if x != nil && x.SortBy != nil {
+		return *x.SortBy
+	}
+	return ""
+}
+
+func (x *StormReportFilter) GetSortOrder() string {
+	if x != nil && x.SortOrder != nil {
+		return *x.SortOrder
+	}
+	return ""
+}
+
+func (x *StormReportFilter) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *StormReportFilter) GetOffset() int32 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+// Center point and radius for geographic search.
+type GeoRadiusFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Lat   float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon   float64                `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`
+	// Defaults to 20, maximum 200.
+	RadiusMiles   *float64 `protobuf:"fixed64,3,opt,name=radius_miles,json=radiusMiles,proto3,oneof" json:"radius_miles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoRadiusFilter) Reset() {
+	*x = GeoRadiusFilter{}
+	mi := &file_storm_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoRadiusFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoRadiusFilter) ProtoMessage() {}
+
+func (x *GeoRadiusFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoRadiusFilter.ProtoReflect.Descriptor instead.
+func (*GeoRadiusFilter) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GeoRadiusFilter) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *GeoRadiusFilter) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *GeoRadiusFilter) GetRadiusMiles() float64 {
+	if x != nil && x.RadiusMiles != nil {
+		return *x.RadiusMiles
+	}
+	return 0
+}
+
+// A single severe weather event reported by the National Weather Service.
+// Mirrors the GraphQL StormReport type.
+type StormReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deterministic SHA-256 hash of type, state, coordinates, and time.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Event type: hail, wind, or tornado.
+	EventType   string       `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Geo         *Geo         `protobuf:"bytes,3,opt,name=geo,proto3" json:"geo,omitempty"`
+	Measurement *Measurement `protobuf:"bytes,4,opt,name=measurement,proto3" json:"measurement,omitempty"`
+	// When the weather event occurred (UTC).
+	EventTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`
+	// NWS Weather Forecast Office code (e.g. OUN, FWD, SJT).
+	SourceOffice string    `protobuf:"bytes,6,opt,name=source_office,json=sourceOffice,proto3" json:"source_office,omitempty"`
+	Location     *Location `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"`
+	// Free-text NWS remarks about the event.
+	Comments string `protobuf:"bytes,8,opt,name=comments,proto3" json:"comments,omitempty"`
+	// Begin time truncated to the hour (UTC).
+	TimeBucket *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=time_bucket,json=timeBucket,proto3" json:"time_bucket,omitempty"`
+	// When the ETL pipeline processed this event (UTC).
+	ProcessedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	// Training level of the reporting source. Unset if unknown.
+	SpotterLevel  *string `protobuf:"bytes,11,opt,name=spotter_level,json=spotterLevel,proto3,oneof" json:"spotter_level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StormReport) Reset() {
+	*x = StormReport{}
+	mi := &file_storm_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StormReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StormReport) ProtoMessage() {}
+
+func (x *StormReport) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StormReport.ProtoReflect.Descriptor instead.
+func (*StormReport) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StormReport) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StormReport) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *StormReport) GetGeo() *Geo {
+	if x != nil {
+		return x.Geo
+	}
+	return nil
+}
+
+func (x *StormReport) GetMeasurement() *Measurement {
+	if x != nil {
+		return x.Measurement
+	}
+	return nil
+}
+
+func (x *StormReport) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *StormReport) GetSourceOffice() string {
+	if x != nil {
+		return x.SourceOffice
+	}
+	return ""
+}
+
+func (x *StormReport) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *StormReport) GetComments() string {
+	if x != nil {
+		return x.Comments
+	}
+	return ""
+}
+
+func (x *StormReport) GetTimeBucket() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TimeBucket
+	}
+	return nil
+}
+
+func (x *StormReport) GetProcessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return nil
+}
+
+func (x *StormReport) GetSpotterLevel() string {
+	if x != nil && x.SpotterLevel != nil {
+		return *x.SpotterLevel
+	}
+	return ""
+}
+
+// WGS-84 geographic coordinates.
+type Geo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64                `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Geo) Reset() {
+	*x = Geo{}
+	mi := &file_storm_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Geo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Geo) ProtoMessage() {}
+
+func (x *Geo) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Geo.ProtoReflect.Descriptor instead.
+func (*Geo) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Geo) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Geo) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+// Measurement data for a storm event. Units vary by event type.
+type Measurement struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Inches (hail), mph (wind), or EF-scale 0-5 (tornado).
+	Magnitude float64 `protobuf:"fixed64,1,opt,name=magnitude,proto3" json:"magnitude,omitempty"`
+	// "in", "mph", or "f_scale".
+	Unit string `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	// minor, moderate, severe, or extreme. Unset when unknown.
+	Severity *string `protobuf:"bytes,3,opt,name=severity,proto3,oneof" json:"severity,omitempty"`
+	// "measured" or "estimated". Unset when unknown.
+	Method        *string `protobuf:"bytes,4,opt,name=method,proto3,oneof" json:"method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Measurement) Reset() {
+	*x = Measurement{}
+	mi := &file_storm_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Measurement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Measurement) ProtoMessage() {}
+
+func (x *Measurement) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Measurement.ProtoReflect.Descriptor instead.
+func (*Measurement) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Measurement) GetMagnitude() float64 {
+	if x != nil {
+		return x.Magnitude
+	}
+	return 0
+}
+
+func (x *Measurement) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Measurement) GetSeverity() string {
+	if x != nil && x.Severity != nil {
+		return *x.Severity
+	}
+	return ""
+}
+
+func (x *Measurement) GetMethod() string {
+	if x != nil && x.Method != nil {
+		return *x.Method
+	}
+	return ""
+}
+
+// Parsed NWS location, e.g. "8 ESE Chappel".
+type Location struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Raw   string                 `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Miles from the named place. Unset if the report is at the location.
+	Distance *float64 `protobuf:"fixed64,3,opt,name=distance,proto3,oneof" json:"distance,omitempty"`
+	// Compass direction from the named place. Unset if at the location.
+	Direction     *string `protobuf:"bytes,4,opt,name=direction,proto3,oneof" json:"direction,omitempty"`
+	State         string  `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	County        string  `protobuf:"bytes,6,opt,name=county,proto3" json:"county,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_storm_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Location) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+func (x *Location) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Location) GetDistance() float64 {
+	if x != nil && x.Distance != nil {
+		return *x.Distance
+	}
+	return 0
+}
+
+func (x *Location) GetDirection() string {
+	if x != nil && x.Direction != nil {
+		return *x.Direction
+	}
+	return ""
+}
+
+func (x *Location) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Location) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+// One page of reports matching a filter.
+type StormReportConnection struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Total number of reports matching the filter (before pagination).
+	TotalCount int32 `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// True if there are more results beyond this page.
+	HasMore       bool           `protobuf:"varint,2,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	Reports       []*StormReport `protobuf:"bytes,3,rep,name=reports,proto3" json:"reports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StormReportConnection) Reset() {
+	*x = StormReportConnection{}
+	mi := &file_storm_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StormReportConnection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StormReportConnection) ProtoMessage() {}
+
+func (x *StormReportConnection) ProtoReflect() protoreflect.Message {
+	mi := &file_storm_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StormReportConnection.ProtoReflect.Descriptor instead.
+func (*StormReportConnection) Descriptor() ([]byte, []int) {
+	return file_storm_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StormReportConnection) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *StormReportConnection) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *StormReportConnection) GetReports() []*StormReport {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+var File_storm_proto protoreflect.FileDescriptor
+
+const file_storm_proto_rawDesc = "" +
+	"\n" +
+	"\vstorm.proto\x12\bstorm.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"N\n" +
+	"\x17ListStormReportsRequest\x123\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1b.storm.v1.StormReportFilterR\x06filter\"'\n" +
+	"\x15GetStormReportRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"O\n" +
+	"\x18CountStormReportsRequest\x123\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1b.storm.v1.StormReportFilterR\x06filter\"1\n" +
+	"\x19CountStormReportsResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\"\xf5\x03\n" +
+	"\x11StormReportFilter\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12-\n" +
+	"\x04near\x18\x03 \x01(\v2\x19.storm.v1.GeoRadiusFilterR\x04near\x12\x16\n" +
+	"\x06states\x18\x04 \x03(\tR\x06states\x12\x1a\n" +
+	"\bcounties\x18\x05 \x03(\tR\bcounties\x12\x1f\n" +
+	"\vevent_types\x18\x06 \x03(\tR\n" +
+	"eventTypes\x12\x1a\n" +
+	"\bseverity\x18\a \x03(\tR\bseverity\x12(\n" +
+	"\rmin_magnitude\x18\b \x01(\x01H\x00R\fminMagnitude\x88\x01\x01\x12\x1c\n" +
+	"\asort_by\x18\t \x01(\tH\x01R\x06sortBy\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"sort_order\x18\n" +
+	" \x01(\tH\x02R\tsortOrder\x88\x01\x01\x12\x19\n" +
+	"\x05limit\x18\v \x01(\x05H\x03R\x05limit\x88\x01\x01\x12\x1b\n" +
+	"\x06offset\x18\f \x01(\x05H\x04R\x06offset\x88\x01\x01B\x10\n" +
+	"\x0e_min_magnitudeB\n" +
+	"\n" +
+	"\b_sort_byB\r\n" +
+	"\v_sort_orderB\b\n" +
+	"\x06_limitB\t\n" +
+	"\a_offset\"n\n" +
+	"\x0fGeoRadiusFilter\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x02 \x01(\x01R\x03lon\x12&\n" +
+	"\fradius_miles\x18\x03 \x01(\x01H\x00R\vradiusMiles\x88\x01\x01B\x0f\n" +
+	"\r_radius_miles\"\xfa\x03\n" +
+	"\vStormReport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x1f\n" +
+	"\x03geo\x18\x03 \x01(\v2\r.storm.v1.GeoR\x03geo\x127\n" +
+	"\vmeasurement\x18\x04 \x01(\v2\x15.storm.v1.MeasurementR\vmeasurement\x129\n" +
+	"\n" +
+	"event_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\teventTime\x12#\n" +
+	"\rsource_office\x18\x06 \x01(\tR\fsourceOffice\x12.\n" +
+	"\blocation\x18\a \x01(\v2\x12.storm.v1.LocationR\blocation\x12\x1a\n" +
+	"\bcomments\x18\b \x01(\tR\bcomments\x12;\n" +
+	"\vtime_bucket\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"timeBucket\x12=\n" +
+	"\fprocessed_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedAt\x12(\n" +
+	"\rspotter_level\x18\v \x01(\tH\x00R\fspotterLevel\x88\x01\x01B\x10\n" +
+	"\x0e_spotter_level\")\n" +
+	"\x03Geo\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x02 \x01(\x01R\x03lon\"\x95\x01\n" +
+	"\vMeasurement\x12\x1c\n" +
+	"\tmagnitude\x18\x01 \x01(\x01R\tmagnitude\x12\x12\n" +
+	"\x04unit\x18\x02 \x01(\tR\x04unit\x12\x1f\n" +
+	"\bseverity\x18\x03 \x01(\tH\x00R\bseverity\x88\x01\x01\x12\x1b\n" +
+	"\x06method\x18\x04 \x01(\tH\x01R\x06method\x88\x01\x01B\v\n" +
+	"\t_severityB\t\n" +
+	"\a_method\"\xbd\x01\n" +
+	"\bLocation\x12\x10\n" +
+	"\x03raw\x18\x01 \x01(\tR\x03raw\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\bdistance\x18\x03 \x01(\x01H\x00R\bdistance\x88\x01\x01\x12!\n" +
+	"\tdirection\x18\x04 \x01(\tH\x01R\tdirection\x88\x01\x01\x12\x14\n" +
+	"\x05state\x18\x05 \x01(\tR\x05state\x12\x16\n" +
+	"\x06county\x18\x06 \x01(\tR\x06countyB\v\n" +
+	"\t_distanceB\f\n" +
+	"\n" +
+	"_direction\"\x84\x01\n" +
+	"\x15StormReportConnection\x12\x1f\n" +
+	"\vtotal_count\x18\x01 \x01(\x05R\n" +
+	"totalCount\x12\x19\n" +
+	"\bhas_more\x18\x02 \x01(\bR\ahasMore\x12/\n" +
+	"\areports\x18\x03 \x03(\v2\x15.storm.v1.StormReportR\areports2\x94\x02\n" +
+	"\x12StormReportService\x12V\n" +
+	"\x10ListStormReports\x12!.storm.v1.ListStormReportsRequest\x1a\x1f.storm.v1.StormReportConnection\x12H\n" +
+	"\x0eGetStormReport\x12\x1f.storm.v1.GetStormReportRequest\x1a\x15.storm.v1.StormReport\x12\\\n" +
+	"\x11CountStormReports\x12\".storm.v1.CountStormReportsRequest\x1a#.storm.v1.CountStormReportsResponseB4Z2github.com/couchcryptid/storm-data-api/internal/pbb\x06proto3"
+
+var (
+	file_storm_proto_rawDescOnce sync.Once
+	file_storm_proto_rawDescData []byte
+)
+
+func file_storm_proto_rawDescGZIP() []byte {
+	file_storm_proto_rawDescOnce.Do(func() {
+		file_storm_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_storm_proto_rawDesc), len(file_storm_proto_rawDesc)))
+	})
+	return file_storm_proto_rawDescData
+}
+
+var file_storm_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_storm_proto_goTypes = []any{
+	(*ListStormReportsRequest)(nil),   // 0: storm.v1.ListStormReportsRequest
+	(*GetStormReportRequest)(nil),     // 1: storm.v1.GetStormReportRequest
+	(*CountStormReportsRequest)(nil),  // 2: storm.v1.CountStormReportsRequest
+	(*CountStormReportsResponse)(nil), // 3: storm.v1.CountStormReportsResponse
+	(*StormReportFilter)(nil),         // 4: storm.v1.StormReportFilter
+	(*GeoRadiusFilter)(nil),           // 5: storm.v1.GeoRadiusFilter
+	(*StormReport)(nil),               // 6: storm.v1.StormReport
+	(*Geo)(nil),                       // 7: storm.v1.Geo
+	(*Measurement)(nil),               // 8: storm.v1.Measurement
+	(*Location)(nil),                  // 9: storm.v1.Location
+	(*StormReportConnection)(nil),     // 10: storm.v1.StormReportConnection
+	(*timestamppb.Timestamp)(nil),     // 11: google.protobuf.Timestamp
+}
+var file_storm_proto_depIdxs = []int32{
+	4,  // 0: storm.v1.ListStormReportsRequest.filter:type_name -> storm.v1.StormReportFilter
+	4,  // 1: storm.v1.CountStormReportsRequest.filter:type_name -> storm.v1.StormReportFilter
+	11, // 2: storm.v1.StormReportFilter.from:type_name -> google.protobuf.Timestamp
+	11, // 3: storm.v1.StormReportFilter.to:type_name -> google.protobuf.Timestamp
+	5,  // 4: storm.v1.StormReportFilter.near:type_name -> storm.v1.GeoRadiusFilter
+	7,  // 5: storm.v1.StormReport.geo:type_name -> storm.v1.Geo
+	8,  // 6: storm.v1.StormReport.measurement:type_name -> storm.v1.Measurement
+	11, // 7: storm.v1.StormReport.event_time:type_name -> google.protobuf.Timestamp
+	9,  // 8: storm.v1.StormReport.location:type_name -> storm.v1.Location
+	11, // 9: storm.v1.StormReport.time_bucket:type_name -> google.protobuf.Timestamp
+	11, // 10: storm.v1.StormReport.processed_at:type_name -> google.protobuf.Timestamp
+	6,  // 11: storm.v1.StormReportConnection.reports:type_name -> storm.v1.StormReport
+	0,  // 12: storm.v1.StormReportService.ListStormReports:input_type -> storm.v1.ListStormReportsRequest
+	1,  // 13: storm.v1.StormReportService.GetStormReport:input_type -> storm.v1.GetStormReportRequest
+	2,  // 14: storm.v1.StormReportService.CountStormReports:input_type -> storm.v1.CountStormReportsRequest
+	10, // 15: storm.v1.StormReportService.ListStormReports:output_type -> storm.v1.StormReportConnection
+	6,  // 16: storm.v1.StormReportService.GetStormReport:output_type -> storm.v1.StormReport
+	3,  // 17: storm.v1.StormReportService.CountStormReports:output_type -> storm.v1.CountStormReportsResponse
+	15, // [15:18] is the sub-list for method output_type
+	12, // [12:15] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_storm_proto_init() }
+func file_storm_proto_init() {
+	if File_storm_proto != nil {
+		return
+	}
+	file_storm_proto_msgTypes[4].OneofWrappers = []any{}
+	file_storm_proto_msgTypes[5].OneofWrappers = []any{}
+	file_storm_proto_msgTypes[6].OneofWrappers = []any{}
+	file_storm_proto_msgTypes[8].OneofWrappers = []any{}
+	file_storm_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_storm_proto_rawDesc), len(file_storm_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_storm_proto_goTypes,
+		DependencyIndexes: file_storm_proto_depIdxs,
+		MessageInfos:      file_storm_proto_msgTypes,
+	}.Build()
+	File_storm_proto = out.File
+	file_storm_proto_goTypes = nil
+	file_storm_proto_depIdxs = nil
+}
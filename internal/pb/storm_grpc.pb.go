@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             (unknown)
+// source: storm.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	StormReportService_ListStormReports_FullMethodName  = "/storm.v1.StormReportService/ListStormReports"
+	StormReportService_GetStormReport_FullMethodName    = "/storm.v1.StormReportService/GetStormReport"
+	StormReportService_CountStormReports_FullMethodName = "/storm.v1.StormReportService/CountStormReports"
+)
+
+// StormReportServiceClient is the client API for StormReportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Query service mirroring the GraphQL stormReports query. Filters go through
+// the same validation, defaults, and limits as the GraphQL path.
+type StormReportServiceClient interface {
+	// Filtered, sorted, paginated reports with the total count.
+	ListStormReports(ctx context.Context, in *ListStormReportsRequest, opts ...grpc.CallOption) (*StormReportConnection, error)
+	// A single report by ID. Returns NOT_FOUND if it does not exist.
+	GetStormReport(ctx context.Context, in *GetStormReportRequest, opts ...grpc.CallOption) (*StormReport, error)
+	// Number of reports matching a filter.
+	CountStormReports(ctx context.Context, in *CountStormReportsRequest, opts ...grpc.CallOption) (*CountStormReportsResponse, error)
+}
+
+type stormReportServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStormReportServiceClient(cc grpc.ClientConnInterface) StormReportServiceClient {
+	return &stormReportServiceClient{cc}
+}
+
+func (c *stormReportServiceClient) ListStormReports(ctx context.Context, in *ListStormReportsRequest, opts ...grpc.CallOption) (*StormReportConnection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StormReportConnection)
+	err := c.cc.Invoke(ctx, StormReportService_ListStormReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stormReportServiceClient) GetStormReport(ctx context.Context, in *GetStormReportRequest, opts ...grpc.CallOption) (*StormReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StormReport)
+	err := c.cc.Invoke(ctx, StormReportService_GetStormReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stormReportServiceClient) CountStormReports(ctx context.Context, in *CountStormReportsRequest, opts ...grpc.CallOption) (*CountStormReportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountStormReportsResponse)
+	err := c.cc.Invoke(ctx, StormReportService_CountStormReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StormReportServiceServer is the server API for StormReportService service.
+// All implementations must embed UnimplementedStormReportServiceServer
+// for forward compatibility.
+//
+// Query service mirroring the GraphQL stormReports query. Filters go through
+// the same validation, defaults, and limits as the GraphQL path.
+type StormReportServiceServer interface {
+	// Filtered, sorted, paginated reports with the total count.
+	ListStormReports(context.Context, *ListStormReportsRequest) (*StormReportConnection, error)
+	// A single report by ID. Returns NOT_FOUND if it does not exist.
+	GetStormReport(context.Context, *GetStormReportRequest) (*StormReport, error)
+	// Number of reports matching a filter.
+	CountStormReports(context.Context, *CountStormReportsRequest) (*CountStormReportsResponse, error)
+	mustEmbedUnimplementedStormReportServiceServer()
+}
+
+// UnimplementedStormReportServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStormReportServiceServer struct{}
+
+func (UnimplementedStormReportServiceServer) ListStormReports(context.Context, *ListStormReportsRequest) (*StormReportConnection, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListStormReports not implemented")
+}
+func (UnimplementedStormReportServiceServer) GetStormReport(context.Context, *GetStormReportRequest) (*StormReport, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStormReport not implemented")
+}
+func (UnimplementedStormReportServiceServer) CountStormReports(context.Context, *CountStormReportsRequest) (*CountStormReportsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CountStormReports not implemented")
+}
+func (UnimplementedStormReportServiceServer) mustEmbedUnimplementedStormReportServiceServer() {}
+func (UnimplementedStormReportServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeStormReportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StormReportServiceServer will
+// result in compilation errors.
+type UnsafeStormReportServiceServer interface {
+	mustEmbedUnimplementedStormReportServiceServer()
+}
+
+func RegisterStormReportServiceServer(s grpc.ServiceRegistrar, srv StormReportServiceServer) {
+	// If the following call panics, it indicates UnimplementedStormReportServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StormReportService_ServiceDesc, srv)
+}
+
+func _StormReportService_ListStormReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStormReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormReportServiceServer).ListStormReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StormReportService_ListStormReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormReportServiceServer).ListStormReports(ctx, req.(*ListStormReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StormReportService_GetStormReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStormReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormReportServiceServer).GetStormReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StormReportService_GetStormReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormReportServiceServer).GetStormReport(ctx, req.(*GetStormReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StormReportService_CountStormReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountStormReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormReportServiceServer).CountStormReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StormReportService_CountStormReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormReportServiceServer).CountStormReports(ctx, req.(*CountStormReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StormReportService_ServiceDesc is the grpc.ServiceDesc for StormReportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StormReportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "storm.v1.StormReportService",
+	HandlerType: (*StormReportServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListStormReports",
+			Handler:    _StormReportService_ListStormReports_Handler,
+		},
+		{
+			MethodName: "GetStormReport",
+			Handler:    _StormReportService_GetStormReport_Handler,
+		},
+		{
+			MethodName: "CountStormReports",
+			Handler:    _StormReportService_CountStormReports_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "storm.proto",
+}
@@ -0,0 +1,97 @@
+// Package protoapi serves storm reports as protobuf for high-throughput
+// consumers that prefer a binary format over GraphQL JSON.
+package protoapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/pb"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentType is the media type of protobuf responses.
+const ContentType = "application/x-protobuf"
+
+// ReportLister lists filtered, paginated reports with the total count.
+type ReportLister interface {
+	ListStormReports(ctx context.Context, filter *model.StormReportFilter) ([]*model.StormReport, int, error)
+}
+
+// Option configures a handler.
+type Option func(*options)
+
+type options struct {
+	emptyStatus int
+	maxRows     int
+}
+
+// WithEmptyStatus sets the response when no reports match the filter:
+// http.StatusOK (default) sends an empty connection, http.StatusNoContent sends
+// no body, and http.StatusNotFound sends an error. Other codes are ignored.
+func WithEmptyStatus(code int) Option {
+	return func(o *options) {
+		switch code {
+		case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+			o.emptyStatus = code
+		}
+	}
+}
+
+// WithMaxRows caps the reports written per response. Rows past the cap are
+// dropped, rowcap.Header is set, and hasMore reports the remainder.
+// Non-positive values select rowcap.DefaultMax.
+func WithMaxRows(n int) Option {
+	return func(o *options) { o.maxRows = n }
+}
+
+// ReportsHandler accepts a JSON StormReportFilter (same shape as the GraphQL
+// input) and responds with a protobuf-encoded StormReportConnection.
+// Responses for time windows already closed carry an ETag, and a matching
+// If-None-Match gets 304 Not Modified without a body.
+func ReportsHandler(s ReportLister, p apifilter.Preparer, opts ...Option) http.HandlerFunc {
+	o := options{emptyStatus: http.StatusOK, maxRows: rowcap.DefaultMax}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := apifilter.FromBody(w, r, p)
+		if err != nil {
+			apifilter.BadRequest(w, err)
+			return
+		}
+
+		reports, total, err := s.ListStormReports(r.Context(), filter)
+		if err != nil {
+			http.Error(w, "query failed", http.StatusInternalServerError)
+			return
+		}
+
+		if total == 0 {
+			switch o.emptyStatus {
+			case http.StatusNoContent:
+				w.WriteHeader(http.StatusNoContent)
+				return
+			case http.StatusNotFound:
+				http.Error(w, "no reports match the filter", http.StatusNotFound)
+				return
+			}
+		}
+
+		reports = rowcap.Trim(w, reports, o.maxRows)
+
+		offset := 0
+		if filter.Offset != nil {
+			offset = *filter.Offset
+		}
+		body, err := proto.Marshal(pb.NewConnection(reports, total, offset+len(reports) < total))
+		if err != nil {
+			http.Error(w, "encode failed", http.StatusInternalServerError)
+			return
+		}
+		apifilter.WriteCacheable(w, r, filter, ContentType, body)
+	}
+}
@@ -0,0 +1,193 @@
+package protoapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/pb"
+	"github.com/couchcryptid/storm-data-api/internal/rowcap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeStore struct {
+	reports []*model.StormReport
+	total   int
+	err     error
+	got     *model.StormReportFilter
+}
+
+func (f *fakeStore) ListStormReports(_ context.Context, filter *model.StormReportFilter) ([]*model.StormReport, int, error) {
+	f.got = filter
+	return f.reports, f.total, f.err
+}
+
+const validBody = `{"timeRange":{"from":"2024-04-26T00:00:00Z","to":"2024-04-27T00:00:00Z"},"states":["NE"]}`
+
+func serve(h http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReportsHandler_Protobuf(t *testing.T) {
+	s := &fakeStore{
+		reports: []*model.StormReport{{ID: "r1", EventType: "hail", EventTime: time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC)}},
+		total:   3,
+	}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+
+	var conn pb.StormReportConnection
+	require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &conn))
+	assert.Equal(t, int32(3), conn.GetTotalCount())
+	assert.True(t, conn.GetHasMore())
+	require.Len(t, conn.GetReports(), 1)
+	assert.Equal(t, "r1", conn.GetReports()[0].GetId())
+
+	// Shared GraphQL validation applied defaults before the store call
+	require.NotNil(t, s.got.Limit)
+	assert.Equal(t, graph.MaxPageSize, *s.got.Limit)
+	assert.Equal(t, []string{"NE"}, s.got.States)
+}
+
+func TestReportsHandler_InvalidFilter(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}),
+		`{"timeRange":{"from":"2024-04-27T00:00:00Z","to":"2024-04-26T00:00:00Z"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "timeRange.to must be after timeRange.from")
+}
+
+func TestReportsHandler_MalformedBody(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}), `{`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestReportsHandler_StoreError(t *testing.T) {
+	rec := serve(ReportsHandler(&fakeStore{err: errors.New("boom")}, &graph.Resolver{}), validBody)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "boom")
+}
+
+func TestReportsHandler_EmptyResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantCode    int
+		wantType    string
+		wantBodyLen int
+	}{
+		{"default 200", nil, http.StatusOK, ContentType, 0},
+		{"explicit 200", []Option{WithEmptyStatus(http.StatusOK)}, http.StatusOK, ContentType, 0},
+		{"204", []Option{WithEmptyStatus(http.StatusNoContent)}, http.StatusNoContent, "", 0},
+		{"404", []Option{WithEmptyStatus(http.StatusNotFound)}, http.StatusNotFound, "text/plain; charset=utf-8", -1},
+		{"unsupported falls back to 200", []Option{WithEmptyStatus(http.StatusTeapot)}, http.StatusOK, ContentType, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(ReportsHandler(&fakeStore{}, &graph.Resolver{}, tt.opts...), validBody)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantType, rec.Header().Get("Content-Type"))
+			if tt.wantBodyLen >= 0 {
+				// An empty connection (total 0, no reports) encodes to zero bytes.
+				assert.Equal(t, tt.wantBodyLen, rec.Body.Len())
+			} else {
+				assert.Contains(t, rec.Body.String(), "no reports match")
+			}
+		})
+	}
+}
+
+func TestReportsHandler_EmptyStatusOnlyWhenNoMatches(t *testing.T) {
+	// A page past the end is not an empty result: total is still non-zero.
+	s := &fakeStore{total: 5}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}, WithEmptyStatus(http.StatusNotFound)), validBody)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestReportsHandler_MaxRowsTruncates(t *testing.T) {
+	s := &fakeStore{
+		reports: []*model.StormReport{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}},
+		total:   3,
+	}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}, WithMaxRows(2)), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(rowcap.Header))
+
+	var conn pb.StormReportConnection
+	require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &conn))
+	require.Len(t, conn.GetReports(), 2)
+	assert.Equal(t, "r2", conn.GetReports()[1].GetId())
+	assert.Equal(t, int32(3), conn.GetTotalCount())
+	assert.True(t, conn.GetHasMore(), "the dropped row is still pending")
+}
+
+func TestReportsHandler_MaxRowsNotReached(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{{ID: "r1"}, {ID: "r2"}}, total: 2}
+	rec := serve(ReportsHandler(s, &graph.Resolver{}, WithMaxRows(2)), validBody)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(rowcap.Header))
+
+	var conn pb.StormReportConnection
+	require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &conn))
+	assert.Len(t, conn.GetReports(), 2)
+	assert.False(t, conn.GetHasMore())
+}
+
+func TestReportsHandler_ETagNotModified(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{{ID: "r1"}}, total: 1}
+	h := ReportsHandler(s, &graph.Resolver{})
+
+	first := serve(h, validBody)
+	require.Equal(t, http.StatusOK, first.Code)
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag, "a closed window is cacheable")
+
+	req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(validBody))
+	req.Header.Set("If-None-Match", tag)
+	second := httptest.NewRecorder()
+	h.ServeHTTP(second, req)
+
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Equal(t, tag, second.Header().Get("ETag"))
+	assert.Zero(t, second.Body.Len())
+}
+
+func TestReportsHandler_ETagChangesWithResults(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{{ID: "r1"}}, total: 1}
+	h := ReportsHandler(s, &graph.Resolver{})
+	tag := serve(h, validBody).Header().Get("ETag")
+
+	s.reports = []*model.StormReport{{ID: "r2"}}
+	req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(validBody))
+	req.Header.Set("If-None-Match", tag)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, tag, rec.Header().Get("ETag"))
+	assert.NotZero(t, rec.Body.Len())
+}
+
+func TestReportsHandler_NoETagForOpenWindow(t *testing.T) {
+	now := time.Now().UTC()
+	body := `{"timeRange":{"from":"` + now.Add(-time.Hour).Format(time.RFC3339) + `","to":"` + now.Add(time.Hour).Format(time.RFC3339) + `"}}`
+	rec := serve(ReportsHandler(&fakeStore{reports: []*model.StormReport{{ID: "r1"}}, total: 1}, &graph.Resolver{}), body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"), "reports may still arrive for a window that has not closed")
+}
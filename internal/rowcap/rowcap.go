@@ -0,0 +1,73 @@
+// Package rowcap caps how many reports an HTTP response carries and tells the
+// client when rows were left out, the same way on every export surface.
+package rowcap
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Header is "true" when a response was cut short by the cap. Buffered
+// responses send it as a header; streamed ones, which commit their headers
+// before truncation is known, send it as a trailer.
+const Header = "X-Results-Truncated"
+
+// DefaultMax is the cap used when none is configured.
+const DefaultMax = 10000
+
+// ErrReached is returned by stream callbacks to stop the stream once the cap
+// is reached.
+var ErrReached = errors.New("row cap reached")
+
+// Limit returns max, or DefaultMax when max is not positive.
+func Limit(max int) int {
+	if max <= 0 {
+		return DefaultMax
+	}
+	return max
+}
+
+// Trim returns at most max rows, setting Header on w when it drops any. Call
+// it before writing the body.
+func Trim[T any](w http.ResponseWriter, rows []T, max int) []T {
+	max = Limit(max)
+	if len(rows) <= max {
+		return rows
+	}
+	w.Header().Set(Header, "true")
+	return rows[:max]
+}
+
+// Stream counts the rows of a streamed response against the cap.
+type Stream struct {
+	w         http.ResponseWriter
+	max, n    int
+	truncated bool
+}
+
+// NewStream declares the Header trailer on w. Call it before writing the body.
+func NewStream(w http.ResponseWriter, max int) *Stream {
+	w.Header().Set("Trailer", Header)
+	return &Stream{w: w, max: Limit(max)}
+}
+
+// Next reports whether one more row may be written. Once it returns false the
+// response is marked truncated; the caller should stop, e.g. with ErrReached.
+func (s *Stream) Next() bool {
+	if s.n >= s.max {
+		s.truncated = true
+		return false
+	}
+	s.n++
+	return true
+}
+
+// Truncated reports whether a row was refused.
+func (s *Stream) Truncated() bool { return s.truncated }
+
+// Finish sets the trailer. Call it after the last row is written.
+func (s *Stream) Finish() {
+	if s.truncated {
+		s.w.Header().Set(Header, "true")
+	}
+}
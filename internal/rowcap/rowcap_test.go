@@ -0,0 +1,56 @@
+package rowcap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimit(t *testing.T) {
+	assert.Equal(t, 5, Limit(5))
+	assert.Equal(t, DefaultMax, Limit(0))
+	assert.Equal(t, DefaultMax, Limit(-1))
+}
+
+func TestTrim(t *testing.T) {
+	rec := httptest.NewRecorder()
+	assert.Equal(t, []int{1, 2}, Trim(rec, []int{1, 2, 3}, 2))
+	assert.Equal(t, "true", rec.Header().Get(Header))
+
+	rec = httptest.NewRecorder()
+	assert.Equal(t, []int{1, 2}, Trim(rec, []int{1, 2}, 2))
+	assert.Empty(t, rec.Header().Get(Header), "not truncated at exactly the cap")
+}
+
+func TestStream_Truncates(t *testing.T) {
+	rec := httptest.NewRecorder()
+	s := NewStream(rec, 2)
+	written := 0
+	for range 5 {
+		if !s.Next() {
+			break
+		}
+		_, _ = rec.WriteString("row\n")
+		written++
+	}
+	s.Finish()
+
+	assert.Equal(t, 2, written)
+	assert.True(t, s.Truncated())
+	res := rec.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "true", res.Trailer.Get(Header))
+}
+
+func TestStream_UnderCap(t *testing.T) {
+	rec := httptest.NewRecorder()
+	s := NewStream(rec, 2)
+	assert.True(t, s.Next())
+	assert.True(t, s.Next())
+	s.Finish()
+
+	assert.False(t, s.Truncated())
+	assert.Empty(t, rec.Result().Trailer.Get(Header))
+}
@@ -0,0 +1,11 @@
+// Package solar holds the constants for day/night classification of storm
+// reports. The sun's position is computed in the database by the
+// solar_elevation(lat, lon, ts) function from migration
+// 006_add_solar_elevation, so filtering happens there and pagination stays
+// correct; internal/integration/solarref is the Go reference the integration
+// tests check it against.
+package solar
+
+// HorizonDegrees is the solar elevation at sunrise and sunset: the sun's upper
+// limb touching the horizon, corrected for atmospheric refraction.
+const HorizonDegrees = -0.833
@@ -34,7 +34,11 @@ func unitForEventType(et string) string {
 // round-trip. The "agg" discriminator column routes each row to the appropriate
 // result slice during scanning.
 func (s *Store) Aggregations(ctx context.Context, filter *model.StormReportFilter) (*AggResult, error) {
-	defer s.observeQuery("aggregations", time.Now())
+	ctx, done, err := s.startQuery(ctx, "aggregations")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	where, args, _ := buildWhereClause(filter)
 	whereSQL := buildWhereSQL(where)
 
@@ -55,7 +59,7 @@ func (s *Store) Aggregations(ctx context.Context, filter *model.StormReportFilte
 			   COUNT(*), NULL, NULL, time_bucket
 		FROM base GROUP BY time_bucket`
 
-	rows, err := s.pool.Query(ctx, query, args...)
+	rows, err := s.reads.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("aggregations: %w", err)
 	}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// ingestJobAuditQuery lists the reports an ingest job created (from
+// storm_reports) and modified (from storm_report_revisions), oldest first.
+// The op column carries the DB value mapped by auditOperation.
+const ingestJobAuditQuery = `SELECT id, 'insert' AS op, created_at AS at, NULL::text[] AS changed_columns
+		FROM storm_reports
+		WHERE ingest_job_id = $1
+	UNION ALL
+	SELECT report_id, 'update', changed_at, changed_columns
+		FROM storm_report_revisions
+		WHERE ingest_job_id = $1
+	ORDER BY at, id`
+
+// auditOperation maps an op value from ingestJobAuditQuery to the model enum.
+func auditOperation(op string) (model.AuditOperation, error) {
+	switch op {
+	case "insert":
+		return model.AuditOperationInsert, nil
+	case "update":
+		return model.AuditOperationUpdate, nil
+	default:
+		return "", fmt.Errorf("unknown audit operation %q", op)
+	}
+}
+
+// IngestJobAudit returns every report created or modified by the ingest job
+// with the given id, oldest first. A report updated several times by the job
+// appears once per revision.
+func (s *Store) IngestJobAudit(ctx context.Context, jobID string) ([]*model.IngestAuditEntry, error) {
+	if jobID == "" {
+		return nil, errors.New("ingest job id is required")
+	}
+	ctx, done, err := s.startQuery(ctx, "ingest_job_audit")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	rows, err := s.reads.Query(ctx, ingestJobAuditQuery, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("ingest job audit: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.IngestAuditEntry{}
+	for rows.Next() {
+		var e model.IngestAuditEntry
+		var op string
+		if err := rows.Scan(&e.ReportID, &op, &e.At, &e.ChangedColumns); err != nil {
+			return nil, fmt.Errorf("scan ingest job audit: %w", err)
+		}
+		if e.Operation, err = auditOperation(op); err != nil {
+			return nil, err
+		}
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestJobAuditQuery(t *testing.T) {
+	q := ingestJobAuditQuery
+	inserts, updates, ok := strings.Cut(q, "UNION ALL")
+	require.True(t, ok, "inserts and updates are combined")
+
+	assert.Contains(t, inserts, "'insert' AS op")
+	assert.Contains(t, inserts, "FROM storm_reports")
+	assert.Contains(t, inserts, "WHERE ingest_job_id = $1")
+
+	assert.Contains(t, updates, "'update'")
+	assert.Contains(t, updates, "FROM storm_report_revisions")
+	assert.Contains(t, updates, "WHERE ingest_job_id = $1")
+	assert.True(t, strings.HasSuffix(q, "ORDER BY at, id"), "oldest first across both sources")
+}
+
+func TestAuditOperation(t *testing.T) {
+	op, err := auditOperation("insert")
+	require.NoError(t, err)
+	assert.Equal(t, model.AuditOperationInsert, op)
+
+	op, err = auditOperation("update")
+	require.NoError(t, err)
+	assert.Equal(t, model.AuditOperationUpdate, op)
+
+	_, err = auditOperation("delete")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown audit operation "delete"`)
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQueryBudgetExceeded is returned by store reads once the request's query
+// budget is spent. Every later read in the same request fails with it too.
+var ErrQueryBudgetExceeded = errors.New("query budget exceeded")
+
+// QueryBudget caps the database work one API request may perform across all
+// of its resolvers. A zero limit disables that dimension. It is safe for
+// concurrent use, since gqlgen resolves sibling fields in parallel.
+type QueryBudget struct {
+	MaxQueries int
+	MaxDBTime  time.Duration
+
+	mu      sync.Mutex
+	queries int
+	dbTime  time.Duration
+}
+
+type budgetKey struct{}
+
+// WithQueryBudget returns a context carrying b. Store reads made with the
+// returned context are charged against it.
+func WithQueryBudget(ctx context.Context, b *QueryBudget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// QueryBudgetFromContext returns the budget attached to ctx, or nil.
+func QueryBudgetFromContext(ctx context.Context) *QueryBudget {
+	b, _ := ctx.Value(budgetKey{}).(*QueryBudget)
+	return b
+}
+
+// acquire charges one query against the budget, failing if the query count
+// or the accumulated DB time is already at its limit.
+func (b *QueryBudget) acquire() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.MaxQueries > 0 && b.queries >= b.MaxQueries {
+		return fmt.Errorf("%w: more than %d queries", ErrQueryBudgetExceeded, b.MaxQueries)
+	}
+	if b.MaxDBTime > 0 && b.dbTime >= b.MaxDBTime {
+		return fmt.Errorf("%w: %s of database time used (limit %s)", ErrQueryBudgetExceeded, b.dbTime.Round(time.Millisecond), b.MaxDBTime)
+	}
+	b.queries++
+	return nil
+}
+
+// record adds d to the accumulated DB time.
+func (b *QueryBudget) record(d time.Duration) {
+	b.mu.Lock()
+	b.dbTime += d
+	b.mu.Unlock()
+}
+
+// Used returns the queries run and DB time spent so far.
+func (b *QueryBudget) Used() (int, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.queries, b.dbTime
+}
+
+// startQuery charges a read against the request's budget (if any) and
+// returns the context to run it under, bounded by the query timeout, with a
+// func to defer that records its duration in the metrics and the budget.
+func (s *Store) startQuery(ctx context.Context, operation string) (context.Context, func(), error) {
+	b := QueryBudgetFromContext(ctx)
+	if b != nil {
+		if err := b.acquire(); err != nil {
+			return nil, nil, err
+		}
+	}
+	ctx, cancel := s.withQueryTimeout(ctx)
+	start := time.Now()
+	return ctx, func() {
+		cancel()
+		s.observeQuery(operation, start)
+		if b != nil {
+			b.record(time.Since(start))
+		}
+	}, nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBudget_QueryLimit(t *testing.T) {
+	b := &QueryBudget{MaxQueries: 2}
+	require.NoError(t, b.acquire())
+	require.NoError(t, b.acquire())
+
+	err := b.acquire()
+	require.ErrorIs(t, err, ErrQueryBudgetExceeded)
+	assert.Contains(t, err.Error(), "more than 2 queries")
+	require.ErrorIs(t, b.acquire(), ErrQueryBudgetExceeded, "stays exhausted")
+
+	n, _ := b.Used()
+	assert.Equal(t, 2, n)
+}
+
+func TestQueryBudget_DBTimeLimit(t *testing.T) {
+	b := &QueryBudget{MaxDBTime: 100 * time.Millisecond}
+	require.NoError(t, b.acquire())
+	b.record(60 * time.Millisecond)
+	require.NoError(t, b.acquire(), "under budget: next query may start")
+	b.record(60 * time.Millisecond)
+
+	err := b.acquire()
+	require.ErrorIs(t, err, ErrQueryBudgetExceeded)
+	assert.Contains(t, err.Error(), "database time")
+}
+
+func TestQueryBudget_Unlimited(t *testing.T) {
+	b := &QueryBudget{}
+	for range 100 {
+		require.NoError(t, b.acquire())
+	}
+}
+
+func TestStore_BudgetExceededAbortsQueries(t *testing.T) {
+	s := New(nil, observability.NewTestMetrics())
+	b := &QueryBudget{MaxQueries: 1}
+	ctx := WithQueryBudget(context.Background(), b)
+
+	// Spend the only query the budget allows.
+	ctx, done, err := s.startQuery(ctx, "list")
+	require.NoError(t, err)
+	done()
+
+	// Every later read fails before touching the (nil) pool.
+	_, _, err = s.ListStormReports(ctx, &model.StormReportFilter{})
+	require.ErrorIs(t, err, ErrQueryBudgetExceeded)
+	_, err = s.Aggregations(ctx, &model.StormReportFilter{})
+	require.ErrorIs(t, err, ErrQueryBudgetExceeded)
+	_, err = s.LastUpdated(ctx)
+	require.ErrorIs(t, err, ErrQueryBudgetExceeded)
+
+	n, _ := b.Used()
+	assert.Equal(t, 1, n)
+}
+
+func TestStore_StartQueryWithoutBudget(t *testing.T) {
+	s := New(nil, observability.NewTestMetrics())
+	_, done, err := s.startQuery(context.Background(), "list")
+	require.NoError(t, err)
+	done()
+}
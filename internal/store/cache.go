@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/couchcryptid/storm-data-api/internal/cache"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// EnableCache turns on in-memory caching of report pages and total counts.
+// Entries are keyed by the generated SQL and its arguments, so any filter that
+// produces identical parameters shares an entry. Must be called before the
+// store serves queries.
+func (s *Store) EnableCache(ttl time.Duration, maxEntries int) {
+	s.queryCache = cache.New[[]*model.StormReport](ttl, maxEntries)
+	s.countCache = cache.New[int](ttl, maxEntries)
+	s.pageCache = cache.New[reportPage](ttl, maxEntries)
+
+	if s.metrics != nil {
+		s.queryCache.Observe(func(k string, v []*model.StormReport) int64 {
+			return int64(len(k)) + reportsSize(v)
+		}, s.observeCache("query"))
+		s.countCache.Observe(func(k string, _ int) int64 {
+			return int64(len(k)) + int64(unsafe.Sizeof(0))
+		}, s.observeCache("count"))
+		s.pageCache.Observe(func(k string, v reportPage) int64 {
+			return int64(len(k)) + int64(unsafe.Sizeof(v)) + reportsSize(v.reports)
+		}, s.observeCache("page"))
+	}
+}
+
+// observeCache returns a cache observer that publishes the named cache's
+// entry count and memory estimate to the cache gauges.
+func (s *Store) observeCache(name string) func(cache.Stats) {
+	entries := s.metrics.CacheEntries.WithLabelValues(name)
+	bytes := s.metrics.CacheBytes.WithLabelValues(name)
+	return func(st cache.Stats) {
+		entries.Set(float64(st.Entries))
+		bytes.Set(float64(st.Bytes))
+	}
+}
+
+// reportsSize estimates the memory held by a cached report slice: the slice,
+// each report struct, and the string and pointer fields it references. Map
+// and allocator overhead is ignored, so this is a lower bound.
+func reportsSize(reports []*model.StormReport) int64 {
+	n := int64(unsafe.Sizeof(reports)) + int64(cap(reports))*int64(unsafe.Sizeof((*model.StormReport)(nil)))
+	for _, r := range reports {
+		if r == nil {
+			continue
+		}
+		n += int64(unsafe.Sizeof(*r))
+		n += int64(len(r.ID) + len(r.EventType) + len(r.Measurement.Unit) + len(r.Location.Raw) +
+			len(r.Location.Name) + len(r.Location.State) + len(r.Location.County) +
+			len(r.Comments) + len(r.SourceOffice))
+		for _, p := range []*string{r.Measurement.Severity, r.Measurement.Method, r.Location.Direction, r.SpotterLevel} {
+			if p != nil {
+				n += int64(unsafe.Sizeof(*p)) + int64(len(*p))
+			}
+		}
+		if r.Location.Distance != nil {
+			n += int64(unsafe.Sizeof(*r.Location.Distance))
+		}
+	}
+	return n
+}
+
+// FlushCache clears the query, count, page, and data range caches and returns
+// the number of entries evicted. Returns 0 when caching is disabled.
+func (s *Store) FlushCache() int {
+	n := 0
+	if s.rangeCache != nil {
+		n += s.rangeCache.Flush()
+	}
+	if s.queryCache == nil {
+		return n
+	}
+	return n + s.queryCache.Flush() + s.countCache.Flush() + s.pageCache.Flush()
+}
+
+// cacheKey derives a cache key from a query and its positional arguments.
+// Times are written as UTC, so equal instants in different zones (or with and
+// without a monotonic reading) share an entry.
+func cacheKey(query string, args []any) string {
+	var b strings.Builder
+	b.WriteString(query)
+	for _, arg := range args {
+		b.WriteByte('|')
+		switch v := arg.(type) {
+		case time.Time:
+			b.WriteString(v.UTC().Format(time.RFC3339Nano))
+		case *time.Time:
+			if v == nil {
+				b.WriteString("<nil>")
+			} else {
+				b.WriteString(v.UTC().Format(time.RFC3339Nano))
+			}
+		default:
+			fmt.Fprintf(&b, "%v", arg)
+		}
+	}
+	return b.String()
+}
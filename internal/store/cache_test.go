@@ -0,0 +1,80 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlushCache_Disabled(t *testing.T) {
+	s := &Store{}
+	assert.Equal(t, 0, s.FlushCache())
+}
+
+func TestFlushCache_CountsAllCaches(t *testing.T) {
+	s := &Store{}
+	s.EnableCache(time.Minute, 10)
+	s.queryCache.Set("q", nil)
+	s.countCache.Set("q", 5)
+	s.countCache.Set("r", 7)
+	s.pageCache.Set("p", reportPage{})
+
+	assert.Equal(t, 4, s.FlushCache())
+	assert.Equal(t, 0, s.FlushCache())
+}
+
+func TestCacheKey_DistinguishesArgs(t *testing.T) {
+	q := "SELECT COUNT(*) FROM storm_reports WHERE location_state = ANY($1)"
+	assert.Equal(t, cacheKey(q, []any{[]string{"TX"}}), cacheKey(q, []any{[]string{"TX"}}))
+	assert.NotEqual(t, cacheKey(q, []any{[]string{"TX"}}), cacheKey(q, []any{[]string{"OK"}}))
+}
+
+func TestCacheKey_NormalizesTimeZones(t *testing.T) {
+	q := "SELECT COUNT(*) FROM storm_reports WHERE event_time >= $1"
+	utc := time.Date(2024, 4, 26, 18, 30, 0, 0, time.UTC)
+	central := utc.In(time.FixedZone("CDT", -5*60*60))
+
+	assert.Equal(t, cacheKey(q, []any{utc}), cacheKey(q, []any{central}))
+	assert.Equal(t, cacheKey(q, []any{&utc}), cacheKey(q, []any{&central}))
+	assert.NotEqual(t, cacheKey(q, []any{utc}), cacheKey(q, []any{utc.Add(time.Nanosecond)}))
+}
+
+func TestEnableCache_EntryGaugeTracksInsertAndEvict(t *testing.T) {
+	m := observability.NewTestMetrics()
+	s := New(nil, m)
+	s.EnableCache(time.Minute, 2)
+	entries := m.CacheEntries.WithLabelValues("count")
+
+	assert.InDelta(t, 0, testutil.ToFloat64(entries), 0)
+	s.countCache.Set("a", 1)
+	s.countCache.Set("b", 2)
+	assert.InDelta(t, 2, testutil.ToFloat64(entries), 0)
+
+	// Full: a third insert evicts one entry first.
+	s.countCache.Set("c", 3)
+	assert.InDelta(t, 2, testutil.ToFloat64(entries), 0)
+
+	s.FlushCache()
+	assert.InDelta(t, 0, testutil.ToFloat64(entries), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(m.CacheBytes.WithLabelValues("count")), 0)
+}
+
+func TestEnableCache_MemoryGaugeGrowsWithReports(t *testing.T) {
+	m := observability.NewTestMetrics()
+	s := New(nil, m)
+	s.EnableCache(time.Minute, 10)
+	bytes := m.CacheBytes.WithLabelValues("query")
+
+	s.queryCache.Set("small", []*model.StormReport{{ID: "r1"}})
+	small := testutil.ToFloat64(bytes)
+	assert.Positive(t, small)
+
+	s.queryCache.Set("large", []*model.StormReport{{ID: "r2", Comments: strings.Repeat("x", 1000)}})
+	assert.Greater(t, testutil.ToFloat64(bytes)-small, 1000.0)
+	assert.InDelta(t, 2, testutil.ToFloat64(m.CacheEntries.WithLabelValues("query")), 0)
+}
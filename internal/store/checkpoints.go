@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxClientIDLength bounds the sync client identifier stored per checkpoint.
+const maxClientIDLength = 128
+
+// validateClientID checks a sync client identifier is non-empty and bounded.
+func validateClientID(clientID string) error {
+	if clientID == "" {
+		return errors.New("client id is required")
+	}
+	if len(clientID) > maxClientIDLength {
+		return fmt.Errorf("client id must be at most %d bytes", maxClientIDLength)
+	}
+	return nil
+}
+
+// SyncCheckpoint returns the delta-sync checkpoint last saved for clientID,
+// for use as the next filter's updatedAfter, or nil if the client has never
+// saved one.
+func (s *Store) SyncCheckpoint(ctx context.Context, clientID string) (*time.Time, error) {
+	if err := validateClientID(clientID); err != nil {
+		return nil, err
+	}
+	ctx, done, err := s.startQuery(ctx, "sync_checkpoint")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var at time.Time
+	err = s.pool.QueryRow(ctx, "SELECT checkpoint FROM sync_checkpoints WHERE client_id = $1", clientID).Scan(&at)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sync checkpoint: %w", err)
+	}
+	return &at, nil
+}
+
+// SaveSyncCheckpoint records that clientID has received every change up to
+// and including at. Checkpoints only move forward, so a late acknowledgement
+// from an earlier connection cannot rewind a client that has since advanced.
+// at is truncated to the database's microsecond precision; rounding up could
+// skip a change made in the following microsecond.
+func (s *Store) SaveSyncCheckpoint(ctx context.Context, clientID string, at time.Time) error {
+	if err := validateClientID(clientID); err != nil {
+		return err
+	}
+	defer s.observeQuery("save_sync_checkpoint", time.Now())
+	_, err := s.pool.Exec(ctx, `INSERT INTO sync_checkpoints (client_id, checkpoint) VALUES ($1, $2)
+		ON CONFLICT (client_id) DO UPDATE
+		SET checkpoint = GREATEST(sync_checkpoints.checkpoint, EXCLUDED.checkpoint), saved_at = NOW()`,
+		clientID, at.Truncate(time.Microsecond))
+	if err != nil {
+		return fmt.Errorf("save sync checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateClientID(t *testing.T) {
+	require.NoError(t, validateClientID("dashboard-1"))
+	require.NoError(t, validateClientID(strings.Repeat("a", maxClientIDLength)))
+
+	err := validateClientID("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required")
+
+	err = validateClientID(strings.Repeat("a", maxClientIDLength+1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at most 128")
+}
@@ -0,0 +1,170 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// Batching window and size for CountyLoader.
+const (
+	countyBatchWait = 2 * time.Millisecond
+	countyBatchMax  = 100
+)
+
+// CountyKey names a county the way reports do: state abbreviation and county
+// name.
+type CountyKey struct {
+	State string
+	Name  string
+}
+
+// buildCountiesQuery selects the counties matching keys in one round-trip.
+// The keys are bound as two parallel arrays, so the parameter count does not
+// grow with the batch.
+func buildCountiesQuery(keys []CountyKey) (string, []any) {
+	states := make([]string, len(keys))
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		states[i], names[i] = k.State, k.Name
+	}
+	query := `SELECT state, name, fips_code FROM counties
+		WHERE (state, name) IN (SELECT * FROM unnest($1::text[], $2::text[]))`
+	return query, []any{states, names}
+}
+
+// CountiesByKey looks up the counties named by keys. Keys without a row in
+// the counties reference table are absent from the result.
+func (s *Store) CountiesByKey(ctx context.Context, keys []CountyKey) (map[CountyKey]*model.County, error) {
+	ctx, done, err := s.startQuery(ctx, "counties")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildCountiesQuery(keys)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("counties: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[CountyKey]*model.County, len(keys))
+	for rows.Next() {
+		c := &model.County{}
+		if err := rows.Scan(&c.State, &c.Name, &c.FIPSCode); err != nil {
+			return nil, fmt.Errorf("scan county: %w", err)
+		}
+		out[CountyKey{State: c.State, Name: c.Name}] = c
+	}
+	return out, rows.Err()
+}
+
+// County returns the county named by state and name, or nil if the reference
+// table has no such county. With a CountyLoader in ctx, lookups made
+// concurrently (as gqlgen does for list items) are batched into one query.
+func (s *Store) County(ctx context.Context, state, name string) (*model.County, error) {
+	key := CountyKey{State: state, Name: name}
+	if l := CountyLoaderFromContext(ctx); l != nil {
+		return l.Load(ctx, key)
+	}
+	counties, err := s.CountiesByKey(ctx, []CountyKey{key})
+	if err != nil {
+		return nil, err
+	}
+	return counties[key], nil
+}
+
+// CountyLoader batches county lookups for one request. The first Load opens
+// a batch that is fetched once countyBatchWait has passed or it holds
+// countyBatchMax keys; every Load in the meantime joins it. Results are
+// memoized, so each key is fetched at most once per loader. It is safe for
+// concurrent use.
+type CountyLoader struct {
+	fetch    func(context.Context, []CountyKey) (map[CountyKey]*model.County, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	batches map[CountyKey]*countyBatch
+	pending *countyBatch
+}
+
+type countyBatch struct {
+	keys     []CountyKey
+	done     chan struct{}
+	counties map[CountyKey]*model.County
+	err      error
+}
+
+// NewCountyLoader returns a CountyLoader that fetches batches with fetch,
+// typically Store.CountiesByKey.
+func NewCountyLoader(fetch func(context.Context, []CountyKey) (map[CountyKey]*model.County, error)) *CountyLoader {
+	return &CountyLoader{
+		fetch:    fetch,
+		wait:     countyBatchWait,
+		maxBatch: countyBatchMax,
+		batches:  make(map[CountyKey]*countyBatch),
+	}
+}
+
+type countyLoaderKey struct{}
+
+// WithCountyLoader returns a context carrying l. Store.County calls made with
+// it are batched through l.
+func WithCountyLoader(ctx context.Context, l *CountyLoader) context.Context {
+	return context.WithValue(ctx, countyLoaderKey{}, l)
+}
+
+// CountyLoaderFromContext returns the county loader attached to ctx, or nil.
+func CountyLoaderFromContext(ctx context.Context) *CountyLoader {
+	l, _ := ctx.Value(countyLoaderKey{}).(*CountyLoader)
+	return l
+}
+
+// Load returns the county for key, or nil if there is none, waiting for the
+// batch that fetches it.
+func (l *CountyLoader) Load(ctx context.Context, key CountyKey) (*model.County, error) {
+	l.mu.Lock()
+	b, ok := l.batches[key]
+	full := false
+	if !ok {
+		b = l.pending
+		if b == nil {
+			b = &countyBatch{done: make(chan struct{})}
+			l.pending = b
+			time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+		}
+		b.keys = append(b.keys, key)
+		l.batches[key] = b
+		full = len(b.keys) >= l.maxBatch
+	}
+	l.mu.Unlock()
+	if full {
+		l.dispatch(ctx, b)
+	}
+
+	select {
+	case <-b.done:
+		return b.counties[key], b.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch fetches b unless it has already been fetched.
+func (l *CountyLoader) dispatch(ctx context.Context, b *countyBatch) {
+	l.mu.Lock()
+	if l.pending != b {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	b.counties, b.err = l.fetch(ctx, b.keys)
+	close(b.done)
+}
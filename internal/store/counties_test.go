@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCountiesQuery(t *testing.T) {
+	query, args := buildCountiesQuery([]CountyKey{{State: "TX", Name: "Dallas"}, {State: "OK", Name: "Cleveland"}})
+
+	assert.Contains(t, query, "WHERE (state, name) IN (SELECT * FROM unnest($1::text[], $2::text[]))")
+	assert.Equal(t, []any{[]string{"TX", "OK"}, []string{"Dallas", "Cleveland"}}, args, "two array params regardless of batch size")
+}
+
+// fakeCounties answers every key with a county whose FIPS code encodes it,
+// counting calls and keys fetched.
+type fakeCounties struct {
+	calls atomic.Int32
+	mu    sync.Mutex
+	keys  []CountyKey
+}
+
+func (f *fakeCounties) fetch(_ context.Context, keys []CountyKey) (map[CountyKey]*model.County, error) {
+	f.calls.Add(1)
+	f.mu.Lock()
+	f.keys = append(f.keys, keys...)
+	f.mu.Unlock()
+	out := make(map[CountyKey]*model.County, len(keys))
+	for _, k := range keys {
+		out[k] = &model.County{State: k.State, Name: k.Name, FIPSCode: k.State + "-" + k.Name}
+	}
+	return out, nil
+}
+
+func TestCountyLoader_BatchesConcurrentLoads(t *testing.T) {
+	f := &fakeCounties{}
+	l := NewCountyLoader(f.fetch)
+	l.wait = 50 * time.Millisecond // generous, so every goroutine joins the batch
+
+	// 50 reports across 5 counties, resolved concurrently as gqlgen does.
+	const reports = 50
+	got := make([]*model.County, reports)
+	var wg sync.WaitGroup
+	for i := range reports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := l.Load(context.Background(), CountyKey{State: "TX", Name: fmt.Sprintf("County %d", i%5)})
+			assert.NoError(t, err)
+			got[i] = c
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), f.calls.Load(), "one batch query, not one per report")
+	assert.Len(t, f.keys, 5, "duplicate keys fetched once")
+	for i, c := range got {
+		require.NotNil(t, c, i)
+		assert.Equal(t, fmt.Sprintf("TX-County %d", i%5), c.FIPSCode)
+	}
+}
+
+func TestCountyLoader_MemoizesAcrossBatches(t *testing.T) {
+	f := &fakeCounties{}
+	l := NewCountyLoader(f.fetch)
+	key := CountyKey{State: "OK", Name: "Cleveland"}
+
+	first, err := l.Load(context.Background(), key)
+	require.NoError(t, err)
+	second, err := l.Load(context.Background(), key)
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, int32(1), f.calls.Load())
+}
+
+func TestCountyLoader_DispatchesFullBatch(t *testing.T) {
+	f := &fakeCounties{}
+	l := NewCountyLoader(f.fetch)
+	l.wait = time.Hour // only the size limit can dispatch
+	l.maxBatch = 1
+
+	c, err := l.Load(context.Background(), CountyKey{State: "KS", Name: "Sedgwick"})
+	require.NoError(t, err)
+	assert.Equal(t, "KS-Sedgwick", c.FIPSCode)
+}
+
+func TestCountyLoader_MissingAndError(t *testing.T) {
+	l := NewCountyLoader(func(context.Context, []CountyKey) (map[CountyKey]*model.County, error) {
+		return map[CountyKey]*model.County{}, nil
+	})
+	c, err := l.Load(context.Background(), CountyKey{State: "ZZ", Name: "Nowhere"})
+	require.NoError(t, err)
+	assert.Nil(t, c, "no reference row")
+
+	boom := errors.New("boom")
+	l = NewCountyLoader(func(context.Context, []CountyKey) (map[CountyKey]*model.County, error) {
+		return nil, boom
+	})
+	_, err = l.Load(context.Background(), CountyKey{State: "TX", Name: "Dallas"})
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestCountyLoader_ContextCancelled(t *testing.T) {
+	l := NewCountyLoader((&fakeCounties{}).fetch)
+	l.wait = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := l.Load(ctx, CountyKey{State: "TX", Name: "Dallas"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCountyLoaderFromContext(t *testing.T) {
+	assert.Nil(t, CountyLoaderFromContext(context.Background()))
+	l := NewCountyLoader((&fakeCounties{}).fetch)
+	assert.Same(t, l, CountyLoaderFromContext(WithCountyLoader(context.Background(), l)))
+}
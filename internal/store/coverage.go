@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// buildCoverageGapsQuery splits the filter's time range into bucket-long
+// buckets with generate_series and LEFT JOINs the matching reports onto them,
+// keeping the buckets nothing joined to. Buckets are half-open [start, end)
+// and the last one is cut short at timeRange.to.
+func buildCoverageGapsQuery(filter *model.StormReportFilter, bucket time.Duration) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	args = append(args, filter.TimeRange.From, filter.TimeRange.To, int64(bucket/time.Second))
+	query := fmt.Sprintf(`WITH matched AS (
+			SELECT %[1]s AS t FROM storm_reports%[2]s
+		), buckets AS (
+			SELECT s AS bucket_start,
+				LEAST(s + $%[5]d * interval '1 second', $%[4]d::timestamptz) AS bucket_end
+			FROM generate_series($%[3]d::timestamptz, $%[4]d::timestamptz, $%[5]d * interval '1 second') AS s
+			WHERE s < $%[4]d::timestamptz
+		)
+		SELECT b.bucket_start, b.bucket_end
+		FROM buckets b
+		LEFT JOIN matched m ON m.t >= b.bucket_start AND m.t < b.bucket_end
+		WHERE m.t IS NULL
+		ORDER BY b.bucket_start`, timeColumn(filter), buildWhereSQL(where), idx, idx+1, idx+2)
+	return query, args
+}
+
+// CoverageGaps returns the buckets of the filter's time range, oldest first,
+// in which no report matches the filter, e.g. to spot ingest outages for a
+// region.
+func (s *Store) CoverageGaps(ctx context.Context, filter *model.StormReportFilter, bucket time.Duration) ([]*model.CoverageGap, error) {
+	ctx, done, err := s.startQuery(ctx, "coverage_gaps")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildCoverageGapsQuery(filter, bucket)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("coverage gaps: %w", err)
+	}
+	defer rows.Close()
+
+	gaps := []*model.CoverageGap{}
+	for rows.Next() {
+		var g model.CoverageGap
+		if err := rows.Scan(&g.Start, &g.End); err != nil {
+			return nil, fmt.Errorf("scan coverage gap: %w", err)
+		}
+		gaps = append(gaps, &g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return gaps, nil
+}
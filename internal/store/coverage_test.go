@@ -0,0 +1,41 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCoverageGapsQuery(t *testing.T) {
+	from := time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC)
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{From: from, To: to},
+		States:    []string{"OK"},
+	}
+
+	query, args := buildCoverageGapsQuery(filter, time.Hour)
+
+	// 2 time + states, then series start, end, bucket seconds
+	require.Len(t, args, 6)
+	assert.Equal(t, []any{from, to, int64(3600)}, args[3:])
+	assert.Contains(t, query, "SELECT event_time AS t FROM storm_reports WHERE event_time >= $1 AND event_time <= $2 AND location_state = ANY($3)", "filter predicate reused")
+	assert.Contains(t, query, "generate_series($4::timestamptz, $5::timestamptz, $6 * interval '1 second')")
+	assert.Contains(t, query, "LEFT JOIN matched m ON m.t >= b.bucket_start AND m.t < b.bucket_end")
+	assert.Contains(t, query, "WHERE m.t IS NULL", "only zero-report buckets")
+}
+
+func TestBuildCoverageGapsQuery_TimeColumn(t *testing.T) {
+	tc := model.TimeColumnProcessedAt
+	filter := &model.StormReportFilter{
+		TimeRange:  model.TimeRange{From: time.Now().Add(-time.Hour), To: time.Now()},
+		TimeColumn: &tc,
+	}
+
+	query, _ := buildCoverageGapsQuery(filter, time.Minute)
+
+	assert.Contains(t, query, "SELECT processed_at AS t FROM storm_reports WHERE processed_at >= $1")
+}
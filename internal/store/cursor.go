@@ -0,0 +1,155 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// cursorToken is the encoded form of a model.PageCursor. Sort records the
+// ordering the cursor was issued for, so a token cannot be replayed against a
+// different sortBy or sortOrder.
+type cursorToken struct {
+	AsOf time.Time       `json:"t"`
+	Sort string          `json:"s"`
+	Key  json.RawMessage `json:"k"`
+	ID   string          `json:"id"`
+}
+
+// KeysetSortable reports whether the filter's ordering supports cursor
+// pagination. MAGNITUDE_NORMALIZED ranks rows against the rest of the result
+// set, so a row's sort key is not fixed and cannot be compared in a WHERE.
+func KeysetSortable(filter *model.StormReportFilter) bool {
+	return filter.SortBy == nil || *filter.SortBy != model.SortFieldMagnitudeNormalized
+}
+
+// orderBy returns the ORDER BY expression and direction for a data query.
+func orderBy(filter *model.StormReportFilter) (col, dir string) {
+	col, dir = timeColumn(filter), "DESC"
+	if filter.SortBy != nil && filter.SortBy.IsValid() {
+		col = sortColumn(*filter.SortBy)
+		if *filter.SortBy == model.SortFieldSeverityScore {
+			col = severityScoreExpr(severityWeights(filter))
+		}
+	}
+	if filter.SortOrder != nil && filter.SortOrder.IsValid() && *filter.SortOrder == model.SortOrderAsc {
+		dir = "ASC"
+	}
+	return col, dir
+}
+
+// severityWeights returns the filter's weights or the defaults.
+func severityWeights(filter *model.StormReportFilter) model.SeverityWeights {
+	if filter.SeverityWeights != nil {
+		return *filter.SeverityWeights
+	}
+	return model.DefaultSeverityWeights
+}
+
+// sortSignature names the ordering a cursor belongs to.
+func sortSignature(filter *model.StormReportFilter) string {
+	name := timeColumn(filter)
+	if filter.SortBy != nil && filter.SortBy.IsValid() {
+		name = filter.SortBy.String()
+	}
+	_, dir := orderBy(filter)
+	return name + " " + dir
+}
+
+// sortKey returns r's value for the filter's ORDER BY expression. It must
+// agree with orderBy, including the Go type pgx scans that column into.
+func sortKey(filter *model.StormReportFilter, r *model.StormReport) any {
+	if filter.SortBy == nil || !filter.SortBy.IsValid() {
+		if timeColumn(filter) == "processed_at" {
+			return r.ProcessedAt
+		}
+		return r.EventTime
+	}
+	switch *filter.SortBy {
+	case model.SortFieldMagnitude:
+		return r.Measurement.Magnitude
+	case model.SortFieldLocationState:
+		return r.Location.State
+	case model.SortFieldEventType:
+		return r.EventType
+	case model.SortFieldSeverityScore:
+		return severityWeights(filter).Score(r)
+	case model.SortFieldSeverity:
+		return severityRank(r.Measurement.Severity)
+	default:
+		return r.EventTime
+	}
+}
+
+// EncodeCursor returns the opaque token for resuming a scan after last. asOf
+// is the snapshot time of the scan's first page.
+func EncodeCursor(filter *model.StormReportFilter, last *model.StormReport, asOf time.Time) (string, error) {
+	key, err := json.Marshal(sortKey(filter, last))
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	b, err := json.Marshal(cursorToken{AsOf: asOf, Sort: sortSignature(filter), Key: key, ID: last.ID})
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor parses a token from EncodeCursor, checking it was issued for
+// the filter's ordering.
+func DecodeCursor(filter *model.StormReportFilter, token string) (*model.PageCursor, error) {
+	invalid := errors.New("after is not a valid cursor")
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	var t cursorToken
+	if err := json.Unmarshal(b, &t); err != nil || t.ID == "" || t.AsOf.IsZero() {
+		return nil, invalid
+	}
+	if t.Sort != sortSignature(filter) {
+		return nil, errors.New("after was issued for a different sortBy or sortOrder")
+	}
+
+	// Decode the key into the type the sort column scans into.
+	var key any
+	switch sortKey(filter, &model.StormReport{}).(type) {
+	case time.Time:
+		var v time.Time
+		err = json.Unmarshal(t.Key, &v)
+		key = v
+	case float64:
+		var v float64
+		err = json.Unmarshal(t.Key, &v)
+		key = v
+	case int:
+		var v int
+		err = json.Unmarshal(t.Key, &v)
+		key = v
+	default:
+		var v string
+		err = json.Unmarshal(t.Key, &v)
+		key = v
+	}
+	if err != nil {
+		return nil, invalid
+	}
+	return &model.PageCursor{AsOf: t.AsOf, SortKey: key, ID: t.ID}, nil
+}
+
+// buildKeysetClause returns the predicate selecting rows after filter.Cursor
+// in the filter's ordering, with id breaking ties, and its args starting at
+// idx.
+func buildKeysetClause(filter *model.StormReportFilter, idx int) (string, []any) {
+	col, dir := orderBy(filter)
+	op := "<"
+	if dir == "ASC" {
+		op = ">"
+	}
+	return fmt.Sprintf("(%s, id) %s ($%d, $%d)", col, op, idx, idx+1),
+		[]any{filter.Cursor.SortKey, filter.Cursor.ID}
+}
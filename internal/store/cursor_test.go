@@ -0,0 +1,99 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	asOf := time.Date(2024, 4, 27, 12, 0, 0, 123456000, time.UTC)
+	report := &model.StormReport{
+		ID:          "r-1",
+		EventType:   "hail",
+		EventTime:   time.Date(2024, 4, 26, 18, 30, 0, 0, time.UTC),
+		ProcessedAt: time.Date(2024, 4, 26, 18, 35, 0, 0, time.UTC),
+	}
+	report.Measurement.Magnitude = 1.75
+	report.Location.State = "TX"
+	sev := "severe"
+	report.Measurement.Severity = &sev
+
+	magnitude := model.SortFieldMagnitude
+	state := model.SortFieldLocationState
+	eventType := model.SortFieldEventType
+	severity := model.SortFieldSeverityScore
+	level := model.SortFieldSeverity
+	processed := model.TimeColumnProcessedAt
+
+	tests := []struct {
+		name   string
+		filter model.StormReportFilter
+		want   any
+	}{
+		{"default time column", model.StormReportFilter{}, report.EventTime},
+		{"processed at", model.StormReportFilter{TimeColumn: &processed}, report.ProcessedAt},
+		{"magnitude", model.StormReportFilter{SortBy: &magnitude}, 1.75},
+		{"state", model.StormReportFilter{SortBy: &state}, "TX"},
+		{"event type", model.StormReportFilter{SortBy: &eventType}, "hail"},
+		{"severity score", model.StormReportFilter{SortBy: &severity}, model.DefaultSeverityWeights.Score(report)},
+		{"severity", model.StormReportFilter{SortBy: &level}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := EncodeCursor(&tt.filter, report, asOf)
+			require.NoError(t, err)
+
+			c, err := DecodeCursor(&tt.filter, token)
+			require.NoError(t, err)
+			assert.True(t, asOf.Equal(c.AsOf))
+			assert.Equal(t, "r-1", c.ID)
+			if want, ok := tt.want.(time.Time); ok {
+				assert.True(t, want.Equal(c.SortKey.(time.Time)))
+			} else {
+				assert.Equal(t, tt.want, c.SortKey)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_Rejects(t *testing.T) {
+	magnitude := model.SortFieldMagnitude
+	asc := model.SortOrderAsc
+	report := &model.StormReport{ID: "r-1"}
+	token, err := EncodeCursor(&model.StormReportFilter{SortBy: &magnitude}, report, time.Now())
+	require.NoError(t, err)
+
+	_, err = DecodeCursor(&model.StormReportFilter{SortBy: &magnitude, SortOrder: &asc}, token)
+	assert.ErrorContains(t, err, "different sortBy or sortOrder")
+
+	_, err = DecodeCursor(&model.StormReportFilter{}, token)
+	assert.ErrorContains(t, err, "different sortBy or sortOrder")
+
+	_, err = DecodeCursor(&model.StormReportFilter{}, "not a cursor!")
+	assert.ErrorContains(t, err, "not a valid cursor")
+}
+
+func TestBuildKeysetClause(t *testing.T) {
+	cursor := &model.PageCursor{SortKey: 1.5, ID: "r-1"}
+	magnitude := model.SortFieldMagnitude
+	asc := model.SortOrderAsc
+
+	clause, args := buildKeysetClause(&model.StormReportFilter{SortBy: &magnitude, Cursor: cursor}, 4)
+	assert.Equal(t, "(measurement_magnitude, id) < ($4, $5)", clause)
+	assert.Equal(t, []any{1.5, "r-1"}, args)
+
+	clause, _ = buildKeysetClause(&model.StormReportFilter{SortBy: &magnitude, SortOrder: &asc, Cursor: cursor}, 4)
+	assert.Equal(t, "(measurement_magnitude, id) > ($4, $5)", clause)
+}
+
+func TestKeysetSortable(t *testing.T) {
+	normalized := model.SortFieldMagnitudeNormalized
+	magnitude := model.SortFieldMagnitude
+	assert.True(t, KeysetSortable(&model.StormReportFilter{}))
+	assert.True(t, KeysetSortable(&model.StormReportFilter{SortBy: &magnitude}))
+	assert.False(t, KeysetSortable(&model.StormReportFilter{SortBy: &normalized}))
+}
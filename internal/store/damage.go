@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// buildDamageTotalsQuery sums the estimated damage of the reports matching
+// the filter per UTC calendar bucket of their event time. Unknown (NULL)
+// damage counts as zero. The date_trunc field is bound as the parameter after
+// the WHERE args.
+func buildDamageTotalsQuery(filter *model.StormReportFilter, bucket model.BucketUnit) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	args = append(args, strings.ToLower(bucket.String()))
+	query := fmt.Sprintf(`SELECT date_trunc($%d, event_time, 'UTC') AS bucket,
+			SUM(COALESCE(damage_property_usd, 0)) AS property,
+			SUM(COALESCE(damage_crops_usd, 0)) AS crops,
+			COUNT(*) AS count
+		FROM storm_reports%s
+		GROUP BY bucket
+		ORDER BY bucket`, idx, buildWhereSQL(where))
+	return query, args
+}
+
+// DamageTotals returns the estimated property and crop damage of the reports
+// matching the filter per calendar bucket, oldest first. Buckets without
+// reports are absent.
+func (s *Store) DamageTotals(ctx context.Context, filter *model.StormReportFilter, bucket model.BucketUnit) ([]*model.DamageTotal, error) {
+	ctx, done, err := s.startQuery(ctx, "damage_totals")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildDamageTotalsQuery(filter, bucket)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("damage totals: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []*model.DamageTotal{}
+	for rows.Next() {
+		var t model.DamageTotal
+		if err := rows.Scan(&t.Bucket, &t.PropertyDamage, &t.CropDamage, &t.ReportCount); err != nil {
+			return nil, fmt.Errorf("scan damage totals: %w", err)
+		}
+		t.TotalDamage = t.PropertyDamage + t.CropDamage
+		totals = append(totals, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
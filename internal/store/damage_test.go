@@ -0,0 +1,32 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDamageTotalsQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"OK"},
+	}
+
+	query, args := buildDamageTotalsQuery(filter, model.BucketUnitWeek)
+
+	// 2 time + states, then the date_trunc field
+	require.Len(t, args, 4)
+	assert.Equal(t, "week", args[3])
+	assert.Contains(t, query, "date_trunc($4, event_time, 'UTC') AS bucket")
+	assert.Contains(t, query, "SUM(COALESCE(damage_property_usd, 0)) AS property", "unknown damage counts as zero")
+	assert.Contains(t, query, "SUM(COALESCE(damage_crops_usd, 0)) AS crops")
+	assert.Contains(t, query, "WHERE event_time >= $1 AND event_time <= $2 AND location_state = ANY($3)", "filter predicate reused")
+	assert.Contains(t, query, "GROUP BY bucket")
+	assert.Contains(t, query, "ORDER BY bucket")
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/cache"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// dataRangeTTL is how long a data range is reused. The bounds only move as
+// new reports arrive, so slightly stale values are fine for date pickers.
+const dataRangeTTL = time.Minute
+
+// dataRangeMaxEntries bounds the number of distinct coarse filters cached.
+const dataRangeMaxEntries = 256
+
+// newDataRangeCache returns the cache used by DataRange.
+func newDataRangeCache() *cache.Cache[model.DataRange] {
+	return cache.New[model.DataRange](dataRangeTTL, dataRangeMaxEntries)
+}
+
+// buildDataRangeQuery selects the earliest and latest event time over the
+// whole table, optionally narrowed to some event types and states.
+func buildDataRangeQuery(eventTypes []model.EventType, states []string, excludeFuture bool) (string, []any) {
+	var where []string
+	var args []any
+	if len(eventTypes) > 0 {
+		args = append(args, eventTypeDBValues(eventTypes))
+		where = append(where, fmt.Sprintf("event_type = ANY($%d)", len(args)))
+	}
+	if len(states) > 0 {
+		args = append(args, states)
+		where = append(where, fmt.Sprintf("location_state = ANY($%d)", len(args)))
+	}
+	if excludeFuture {
+		where = append(where, "event_time <= now()")
+	}
+	return "SELECT MIN(event_time), MAX(event_time) FROM storm_reports" + buildWhereSQL(where), args
+}
+
+// DataRange returns the earliest and latest event times of the reports with
+// the given event types and states (all when empty). Both are nil when no
+// report matches. Results are cached for dataRangeTTL per distinct filter.
+func (s *Store) DataRange(ctx context.Context, eventTypes []model.EventType, states []string, excludeFuture bool) (*model.DataRange, error) {
+	query, args := buildDataRangeQuery(eventTypes, states, excludeFuture)
+	key := cacheKey(query, args)
+	if s.rangeCache != nil {
+		if r, ok := s.rangeCache.Get(key); ok {
+			return &r, nil
+		}
+	}
+
+	ctx, done, err := s.startQuery(ctx, "data_range")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var r model.DataRange
+	if err := s.reads.QueryRow(ctx, query, args...).Scan(&r.Earliest, &r.Latest); err != nil {
+		return nil, fmt.Errorf("data range: %w", err)
+	}
+	if s.rangeCache != nil {
+		s.rangeCache.Set(key, r)
+	}
+	return &r, nil
+}
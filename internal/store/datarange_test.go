@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDataRangeQuery(t *testing.T) {
+	query, args := buildDataRangeQuery(nil, nil, false)
+	assert.Equal(t, "SELECT MIN(event_time), MAX(event_time) FROM storm_reports", query)
+	assert.Empty(t, args)
+
+	query, args = buildDataRangeQuery([]model.EventType{model.EventTypeHail}, []string{"TX", "OK"}, true)
+	assert.Equal(t, "SELECT MIN(event_time), MAX(event_time) FROM storm_reports"+
+		" WHERE event_type = ANY($1) AND location_state = ANY($2) AND event_time <= now()", query)
+	assert.Equal(t, []any{[]string{"hail"}, []string{"TX", "OK"}}, args)
+}
+
+func TestDataRange_ServedFromCache(t *testing.T) {
+	// No pool: a cache miss would panic, so success proves the hit.
+	s := New(nil, observability.NewTestMetrics())
+	earliest := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	latest := time.Date(2024, 4, 26, 23, 0, 0, 0, time.UTC)
+	query, args := buildDataRangeQuery(nil, []string{"TX"}, false)
+	key := cacheKey(query, args)
+	s.rangeCache.Set(key, model.DataRange{Earliest: &earliest, Latest: &latest})
+
+	r, err := s.DataRange(context.Background(), nil, []string{"TX"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, earliest, *r.Earliest)
+	assert.Equal(t, latest, *r.Latest)
+
+	// Entries are dropped by FlushCache.
+	assert.Equal(t, 1, s.FlushCache())
+	_, ok := s.rangeCache.Get(key)
+	assert.False(t, ok)
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// deltaFields maps DB columns to the GraphQL field paths reported in a
+// ReportDelta, in the order they appear in the StormReport type.
+var deltaFields = []struct {
+	column string
+	name   string
+}{
+	{"event_type", "eventType"},
+	{"raw_event_type", "rawEventType"},
+	{"geo_lat", "geo.lat"},
+	{"geo_lon", "geo.lon"},
+	{"measurement_magnitude", "measurement.magnitude"},
+	{"measurement_unit", "measurement.unit"},
+	{"measurement_severity", "measurement.severity"},
+	{"event_time", "eventTime"},
+	{"source_office", "sourceOffice"},
+	{"location_raw", "location.raw"},
+	{"location_name", "location.name"},
+	{"location_distance", "location.distance"},
+	{"location_direction", "location.direction"},
+	{"location_state", "location.state"},
+	{"location_county", "location.county"},
+	{"comments", "comments"},
+	{"time_bucket", "timeBucket"},
+	{"processed_at", "processedAt"},
+	{"spotter_level", "spotterLevel"},
+	{"measurement_method", "measurement.method"},
+}
+
+// ListReportDeltas returns, for each report matching the filter, only the
+// fields that changed after filter.UpdatedAfter, plus the total count.
+// Changed columns come from storm_report_revisions; reports created after the
+// checkpoint have no revisions and are returned in full.
+func (s *Store) ListReportDeltas(ctx context.Context, filter *model.StormReportFilter) ([]*model.ReportDelta, int, error) {
+	if filter.UpdatedAfter == nil {
+		return nil, 0, errors.New("list report deltas: updatedAfter is required")
+	}
+	ctx, done, err := s.startQuery(ctx, "list_deltas")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer done()
+	where, baseArgs, idx := buildWhereClause(filter)
+	whereSQL := buildWhereSQL(where)
+
+	totalCount, err := s.countStormReports(ctx, "SELECT COUNT(*) FROM storm_reports"+whereSQL, baseArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pageSQL, pageArgs := buildOrderAndPage(filter, idx)
+	sinceIdx := idx + len(pageArgs)
+	args := make([]any, 0, len(baseArgs)+len(pageArgs)+1)
+	args = append(args, baseArgs...)
+	args = append(args, pageArgs...)
+	args = append(args, *filter.UpdatedAfter)
+
+	query := fmt.Sprintf(`SELECT id, created_at > $%[1]d, COALESCE(ch.cols, '{}'::text[]), to_jsonb(storm_reports)
+		FROM storm_reports
+		LEFT JOIN LATERAL (
+			SELECT array_agg(DISTINCT col) AS cols
+			FROM storm_report_revisions v, unnest(v.changed_columns) AS col
+			WHERE v.report_id = storm_reports.id AND v.changed_at > $%[1]d
+		) ch ON true`, sinceIdx) + whereSQL + pageSQL
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query report deltas: %w", err)
+	}
+	defer rows.Close()
+
+	deltas := []*model.ReportDelta{}
+	for rows.Next() {
+		var id string
+		var isNew bool
+		var changed []string
+		var raw []byte
+		if err := rows.Scan(&id, &isNew, &changed, &raw); err != nil {
+			return nil, 0, fmt.Errorf("scan report delta: %w", err)
+		}
+		var row map[string]any
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return nil, 0, fmt.Errorf("decode report delta: %w", err)
+		}
+		deltas = append(deltas, buildReportDelta(id, isNew, changed, row))
+	}
+	return deltas, totalCount, rows.Err()
+}
+
+// buildReportDelta selects the changed fields from a JSON-decoded report row.
+// When isNew is true every field is included.
+func buildReportDelta(id string, isNew bool, changed []string, row map[string]any) *model.ReportDelta {
+	delta := &model.ReportDelta{ID: id, Fields: []*model.FieldValue{}}
+	for _, f := range deltaFields {
+		if !isNew && !slices.Contains(changed, f.column) {
+			continue
+		}
+		delta.Fields = append(delta.Fields, &model.FieldValue{
+			Name:  f.name,
+			Value: formatDeltaValue(row[f.column]),
+		})
+	}
+	return delta
+}
+
+// formatDeltaValue renders a JSON-decoded column value as a string, or nil for SQL NULL.
+func formatDeltaValue(v any) *string {
+	var s string
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case string:
+		s = val
+	case float64:
+		s = strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		s = fmt.Sprint(val)
+	}
+	return &s
+}
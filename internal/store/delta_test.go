@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deltaRow() map[string]any {
+	return map[string]any{
+		"id":                    "abc",
+		"event_type":            "hail",
+		"geo_lat":               31.02,
+		"geo_lon":               -98.44,
+		"measurement_magnitude": 1.75,
+		"measurement_unit":      "in",
+		"measurement_severity":  "severe",
+		"event_time":            "2024-04-26T15:10:00+00:00",
+		"location_state":        "TX",
+		"location_county":       "San Saba",
+		"location_distance":     nil,
+		"comments":              "Corrected hail size.",
+	}
+}
+
+func fieldNames(d *model.ReportDelta) []string {
+	names := make([]string, len(d.Fields))
+	for i, f := range d.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+func TestBuildReportDelta_OnlyChangedFields(t *testing.T) {
+	d := buildReportDelta("abc", false, []string{"measurement_magnitude", "comments"}, deltaRow())
+
+	assert.Equal(t, "abc", d.ID)
+	assert.Equal(t, []string{"measurement.magnitude", "comments"}, fieldNames(d))
+	require.NotNil(t, d.Fields[0].Value)
+	assert.Equal(t, "1.75", *d.Fields[0].Value)
+	require.NotNil(t, d.Fields[1].Value)
+	assert.Equal(t, "Corrected hail size.", *d.Fields[1].Value)
+}
+
+func TestBuildReportDelta_NewReportIncludesAllFields(t *testing.T) {
+	d := buildReportDelta("abc", true, nil, deltaRow())
+
+	assert.Len(t, d.Fields, len(deltaFields))
+	assert.Equal(t, "eventType", d.Fields[0].Name)
+}
+
+func TestBuildReportDelta_NullValue(t *testing.T) {
+	d := buildReportDelta("abc", false, []string{"location_distance"}, deltaRow())
+
+	require.Len(t, d.Fields, 1)
+	assert.Equal(t, "location.distance", d.Fields[0].Name)
+	assert.Nil(t, d.Fields[0].Value)
+}
+
+func TestBuildReportDelta_NoChanges(t *testing.T) {
+	d := buildReportDelta("abc", false, nil, deltaRow())
+
+	assert.Empty(t, d.Fields)
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// hoursPerDay is the number of diurnal buckets, one per local clock hour.
+const hoursPerDay = 24
+
+// buildDiurnalCycleQuery counts the reports matching the filter by local hour
+// of day (0-23) of their event time in the IANA time zone tz, bound as the
+// parameter after the WHERE args. Hours without reports are absent.
+func buildDiurnalCycleQuery(filter *model.StormReportFilter, tz string) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	args = append(args, tz)
+	query := fmt.Sprintf(`SELECT EXTRACT(HOUR FROM event_time AT TIME ZONE $%d)::int AS hour, COUNT(*) AS count
+		FROM storm_reports%s
+		GROUP BY hour
+		ORDER BY hour`, idx, buildWhereSQL(where))
+	return query, args
+}
+
+// DiurnalCycle returns the number of reports matching the filter in each local
+// hour of the day in time zone tz, as 24 buckets from hour 0 with empty hours
+// zero-filled.
+func (s *Store) DiurnalCycle(ctx context.Context, filter *model.StormReportFilter, tz string) ([]*model.HourOfDayCount, error) {
+	ctx, done, err := s.startQuery(ctx, "diurnal_cycle")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildDiurnalCycleQuery(filter, tz)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("diurnal cycle: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]int, hoursPerDay)
+	for rows.Next() {
+		var hour, count int
+		if err := rows.Scan(&hour, &count); err != nil {
+			return nil, fmt.Errorf("scan diurnal cycle: %w", err)
+		}
+		if hour >= 0 && hour < hoursPerDay {
+			counts[hour] += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildDiurnalCycle(counts), nil
+}
+
+// buildDiurnalCycle turns per-hour counts indexed by hour into buckets.
+func buildDiurnalCycle(counts []int) []*model.HourOfDayCount {
+	out := make([]*model.HourOfDayCount, len(counts))
+	for hour, n := range counts {
+		out[hour] = &model.HourOfDayCount{Hour: hour, Count: n}
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDiurnalCycleQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"OK"},
+	}
+
+	query, args := buildDiurnalCycleQuery(filter, "America/Chicago")
+
+	// 2 time + states, then the time zone
+	require.Len(t, args, 4)
+	assert.Equal(t, "America/Chicago", args[3])
+	assert.Contains(t, query, "EXTRACT(HOUR FROM event_time AT TIME ZONE $4)::int AS hour")
+	assert.Contains(t, query, "WHERE event_time >= $1 AND event_time <= $2 AND location_state = ANY($3)", "filter predicate reused")
+	assert.Contains(t, query, "GROUP BY hour")
+}
+
+func TestBuildDiurnalCycle_ZeroFilled(t *testing.T) {
+	counts := make([]int, hoursPerDay)
+	counts[0] = 2
+	counts[17] = 9
+
+	cycle := buildDiurnalCycle(counts)
+
+	require.Len(t, cycle, 24)
+	for hour, b := range cycle {
+		assert.Equal(t, hour, b.Hour)
+	}
+	assert.Equal(t, 2, cycle[0].Count)
+	assert.Equal(t, 9, cycle[17].Count)
+	assert.Zero(t, cycle[1].Count)
+	assert.Zero(t, cycle[23].Count)
+}
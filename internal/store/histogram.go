@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// histogramIntervals is the allowlist of date_trunc fields a report histogram
+// can bucket by.
+var histogramIntervals = map[model.BucketUnit]string{
+	model.BucketUnitHour:  "hour",
+	model.BucketUnitDay:   "day",
+	model.BucketUnitWeek:  "week",
+	model.BucketUnitMonth: "month",
+}
+
+// buildTimeBucketQuery counts the reports matching the filter per UTC
+// interval of the filter's time column. The date_trunc field comes from
+// histogramIntervals and is bound as the parameter after the WHERE args.
+func buildTimeBucketQuery(filter *model.StormReportFilter, interval model.BucketUnit) (string, []any, error) {
+	field, ok := histogramIntervals[interval]
+	if !ok {
+		return "", nil, fmt.Errorf("invalid histogram interval %q", interval)
+	}
+	where, args, idx := buildWhereClause(filter)
+	args = append(args, field)
+	query := fmt.Sprintf(`SELECT date_trunc($%d, %s, 'UTC') AS bucket, COUNT(*) AS count
+		FROM storm_reports%s
+		GROUP BY bucket
+		ORDER BY bucket`, idx, timeColumn(filter), buildWhereSQL(where))
+	return query, args, nil
+}
+
+// CountByTimeBucket returns the number of reports matching the filter per
+// hour, day, week, or month, oldest first. Intervals without reports are
+// absent.
+func (s *Store) CountByTimeBucket(ctx context.Context, filter *model.StormReportFilter, interval model.BucketUnit) ([]*model.TimeBucketCount, error) {
+	query, args, err := buildTimeBucketQuery(filter, interval)
+	if err != nil {
+		return nil, err
+	}
+	ctx, done, err := s.startQuery(ctx, "count_by_time_bucket")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count by time bucket: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*model.TimeBucketCount{}
+	for rows.Next() {
+		var c model.TimeBucketCount
+		if err := rows.Scan(&c.Bucket, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan time bucket count: %w", err)
+		}
+		counts = append(counts, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
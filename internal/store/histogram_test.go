@@ -0,0 +1,48 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTimeBucketQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"OK"},
+	}
+
+	query, args, err := buildTimeBucketQuery(filter, model.BucketUnitHour)
+	require.NoError(t, err)
+
+	// 2 time + states, then the date_trunc field
+	require.Len(t, args, 4)
+	assert.Equal(t, "hour", args[3])
+	assert.Contains(t, query, "SELECT date_trunc($4, event_time, 'UTC') AS bucket, COUNT(*) AS count")
+	assert.Contains(t, query, "WHERE event_time >= $1 AND event_time <= $2 AND location_state = ANY($3)", "filter predicate reused")
+	assert.Contains(t, query, "GROUP BY bucket")
+	assert.Contains(t, query, "ORDER BY bucket")
+}
+
+func TestBuildTimeBucketQuery_ProcessedAt(t *testing.T) {
+	col := model.TimeColumnProcessedAt
+	filter := &model.StormReportFilter{TimeColumn: &col}
+
+	query, _, err := buildTimeBucketQuery(filter, model.BucketUnitMonth)
+	require.NoError(t, err)
+	assert.Contains(t, query, "date_trunc($3, processed_at, 'UTC')", "buckets follow the filtered time column")
+}
+
+func TestBuildTimeBucketQuery_InvalidInterval(t *testing.T) {
+	for _, interval := range []model.BucketUnit{model.BucketUnitYear, "minute", "day'); DROP TABLE storm_reports; --"} {
+		_, _, err := buildTimeBucketQuery(&model.StormReportFilter{}, interval)
+		require.Error(t, err, interval)
+		assert.Contains(t, err.Error(), "invalid histogram interval")
+	}
+}
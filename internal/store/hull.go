@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// point is a (lon, lat) pair, matching GeoJSON position order.
+type point [2]float64
+
+// buildHullPointsQuery selects the distinct coordinates of every report
+// matching the filter (ignoring pagination).
+func buildHullPointsQuery(filter *model.StormReportFilter) (string, []any) {
+	where, args, _ := buildWhereClause(filter)
+	return "SELECT DISTINCT geo_lon, geo_lat FROM storm_reports" + buildWhereSQL(where), args
+}
+
+// ConvexHull returns the convex hull of all reports matching the filter as a
+// GeoJSON polygon, or nil when the points do not enclose an area (fewer than
+// three distinct points, or all collinear). The hull is computed in Go rather
+// than with PostGIS ST_ConvexHull, which this schema does not depend on.
+func (s *Store) ConvexHull(ctx context.Context, filter *model.StormReportFilter) (*model.GeoJSONPolygon, error) {
+	ctx, done, err := s.startQuery(ctx, "convex_hull")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildHullPointsQuery(filter)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("convex hull: %w", err)
+	}
+	defer rows.Close()
+
+	var pts []point
+	for rows.Next() {
+		var p point
+		if err := rows.Scan(&p[0], &p[1]); err != nil {
+			return nil, fmt.Errorf("scan hull point: %w", err)
+		}
+		pts = append(pts, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return hullPolygon(pts), nil
+}
+
+// hullPolygon wraps the convex hull of pts as a closed GeoJSON ring, or
+// returns nil when the hull has fewer than three vertices.
+func hullPolygon(pts []point) *model.GeoJSONPolygon {
+	hull := convexHull(pts)
+	if len(hull) < 3 {
+		return nil
+	}
+	ring := make([][]float64, 0, len(hull)+1)
+	for _, p := range hull {
+		ring = append(ring, []float64{p[0], p[1]})
+	}
+	ring = append(ring, []float64{hull[0][0], hull[0][1]})
+	return &model.GeoJSONPolygon{Type: "Polygon", Coordinates: [][][]float64{ring}}
+}
+
+// convexHull returns the hull vertices in counter-clockwise order (the GeoJSON
+// exterior ring winding) using Andrew's monotone chain. Collinear points on
+// the boundary are dropped. Planar lon/lat geometry is adequate at storm scale.
+func convexHull(pts []point) []point {
+	pts = slices.Clone(pts)
+	slices.SortFunc(pts, func(a, b point) int {
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		switch {
+		case a[1] < b[1]:
+			return -1
+		case a[1] > b[1]:
+			return 1
+		}
+		return 0
+	})
+	pts = slices.Compact(pts)
+	if len(pts) < 3 {
+		return pts
+	}
+
+	cross := func(o, a, b point) float64 {
+		return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+	}
+
+	hull := make([]point, 0, 2*len(pts))
+	// Lower hull, then upper hull.
+	for _, p := range pts {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(pts) - 2; i >= 0; i-- {
+		p := pts[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// The last point repeats the first.
+	return hull[:len(hull)-1]
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildHullPointsQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+	}
+	limit, offset := 20, 40
+	filter.Limit, filter.Offset = &limit, &offset
+
+	query, args := buildHullPointsQuery(filter)
+	assert.Equal(t,
+		"SELECT DISTINCT geo_lon, geo_lat FROM storm_reports WHERE event_time >= $1 AND event_time <= $2 AND location_state = ANY($3)",
+		query)
+	assert.NotContains(t, query, "LIMIT", "hull covers the whole result set, not a page")
+	assert.Len(t, args, 3)
+}
+
+func TestConvexHull_DropsInteriorAndCollinear(t *testing.T) {
+	pts := []point{
+		{-98, 35}, {-96, 35}, {-96, 37}, {-98, 37}, // square corners
+		{-97, 36}, // interior
+		{-97, 35}, // on an edge
+		{-98, 35}, // duplicate
+	}
+	hull := convexHull(pts)
+	assert.Equal(t, []point{{-98, 35}, {-96, 35}, {-96, 37}, {-98, 37}}, hull, "counter-clockwise from lowest lon")
+}
+
+func TestHullPolygon_ClosedRing(t *testing.T) {
+	poly := hullPolygon([]point{{-98, 35}, {-96, 35}, {-97, 37}})
+	require.NotNil(t, poly)
+	assert.Equal(t, "Polygon", poly.Type)
+	require.Len(t, poly.Coordinates, 1)
+	ring := poly.Coordinates[0]
+	assert.Len(t, ring, 4)
+	assert.Equal(t, ring[0], ring[len(ring)-1], "ring is closed")
+}
+
+func TestHullPolygon_InsufficientPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		pts  []point
+	}{
+		{"none", nil},
+		{"one", []point{{-97, 35}}},
+		{"two", []point{{-97, 35}, {-96, 36}}},
+		{"duplicates", []point{{-97, 35}, {-97, 35}, {-96, 36}}},
+		{"collinear", []point{{-98, 35}, {-97, 36}, {-96, 37}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, hullPolygon(tt.pts))
+		})
+	}
+}
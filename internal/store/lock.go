@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DefaultLockTimeout is how long EditStormReport waits for a report's row
+// lock when the caller passes no timeout.
+const DefaultLockTimeout = 5 * time.Second
+
+// pgLockNotAvailable is the SQLSTATE raised when lock_timeout expires.
+const pgLockNotAvailable = "55P03"
+
+// LockTimeoutError is returned when a report's row lock is not acquired
+// within the lock timeout because another transaction holds it.
+type LockTimeoutError struct {
+	ID      string
+	Timeout time.Duration
+}
+
+func (e *LockTimeoutError) Error() string {
+	return fmt.Sprintf("storm report %s is locked by another edit (waited %s)", e.ID, e.Timeout)
+}
+
+// lockTimeoutSQL returns the SET LOCAL statement for timeout, in whole
+// milliseconds rounded up. A zero lock_timeout would wait forever, so
+// non-positive timeouts use DefaultLockTimeout.
+func lockTimeoutSQL(timeout time.Duration) string {
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	ms := (timeout + time.Millisecond - 1) / time.Millisecond
+	return fmt.Sprintf("SET LOCAL lock_timeout = %d", ms)
+}
+
+// EditStormReport locks the report with the given id (SELECT ... FOR UPDATE)
+// in a transaction and calls fn with the transaction and the locked row.
+// Concurrent edits of the same report wait for the lock; after lockTimeout
+// they get a *LockTimeoutError. Updates fn makes are bookkept by the revision
+// trigger (updated_at and storm_report_revisions) like any other write;
+// PatchStormReport layers the expectedUpdatedAt check on top. The transaction
+// commits if fn returns nil and rolls back otherwise. It returns
+// ErrReportNotFound when no report has the id.
+func (s *Store) EditStormReport(ctx context.Context, id string, lockTimeout time.Duration,
+	fn func(ctx context.Context, tx pgx.Tx, report *model.StormReport) error) error {
+	if lockTimeout <= 0 {
+		lockTimeout = DefaultLockTimeout
+	}
+	defer s.observeQuery("edit", time.Now())
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin edit: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, lockTimeoutSQL(lockTimeout)); err != nil {
+		return fmt.Errorf("set lock timeout: %w", err)
+	}
+	report, err := scanStormReport(tx.QueryRow(ctx,
+		"SELECT "+columns+" FROM storm_reports WHERE id = $1 FOR UPDATE", id))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgLockNotAvailable {
+		return &LockTimeoutError{ID: id, Timeout: lockTimeout}
+	}
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		return ErrReportNotFound
+	}
+
+	if err := fn(ctx, tx, report); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit edit: %w", err)
+	}
+	// Cached pages and counts may include the old values.
+	s.FlushCache()
+	return nil
+}
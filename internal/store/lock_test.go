@@ -0,0 +1,20 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockTimeoutSQL(t *testing.T) {
+	assert.Equal(t, "SET LOCAL lock_timeout = 250", lockTimeoutSQL(250*time.Millisecond))
+	assert.Equal(t, "SET LOCAL lock_timeout = 2", lockTimeoutSQL(1500*time.Microsecond), "rounded up")
+	assert.Equal(t, "SET LOCAL lock_timeout = 1", lockTimeoutSQL(time.Nanosecond), "never 0, which disables the timeout")
+	assert.Equal(t, "SET LOCAL lock_timeout = 5000", lockTimeoutSQL(0))
+}
+
+func TestLockTimeoutError(t *testing.T) {
+	err := &LockTimeoutError{ID: "r1", Timeout: 250 * time.Millisecond}
+	assert.Equal(t, "storm report r1 is locked by another edit (waited 250ms)", err.Error())
+}
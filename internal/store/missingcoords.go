@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// missingCoordinatesSQL matches reports stored without a position. The geo
+// columns are NOT NULL, so upstream rows lacking one arrive as (0, 0).
+const missingCoordinatesSQL = "geo_lat = 0 AND geo_lon = 0"
+
+// positionFilters lists every filter criterion that tests a report's
+// coordinates. Each entry clears its criterion on f and reports whether it
+// was set. A new position-based criterion must be added here, or
+// ExcludedMissingCoordinates undercounts.
+var positionFilters = []func(f *model.StormReportFilter) bool{
+	func(f *model.StormReportFilter) bool {
+		set := f.Near != nil
+		f.Near = nil
+		return set
+	},
+	func(f *model.StormReportFilter) bool {
+		set := f.BBox != nil
+		f.BBox = nil
+		return set
+	},
+	func(f *model.StormReportFilter) bool {
+		set := f.Geohash != nil
+		f.Geohash = nil
+		return set
+	},
+	func(f *model.StormReportFilter) bool {
+		set := f.AtLat != nil || f.AtLon != nil
+		f.AtLat, f.AtLon = nil, nil
+		return set
+	},
+	func(f *model.StormReportFilter) bool {
+		set := len(f.Polygon) > 0
+		f.Polygon = nil
+		return set
+	},
+	func(f *model.StormReportFilter) bool {
+		set := f.NearPopulatedPlace != nil
+		f.NearPopulatedPlace = nil
+		return set
+	},
+	func(f *model.StormReportFilter) bool {
+		set := f.StormTrack != nil
+		f.StormTrack = nil
+		return set
+	},
+	// Watch/warning polygons, matched by containment.
+	func(f *model.StormReportFilter) bool {
+		set := f.Warning != nil || f.TriggeredWarning != nil
+		f.Warning, f.TriggeredWarning = nil, nil
+		return set
+	},
+	// The sun's elevation is computed at the report's coordinates.
+	func(f *model.StormReportFilter) bool {
+		set := f.DayNight != nil
+		f.DayNight = nil
+		return set
+	},
+	// Per-type radius around near. The overrides are copied, since the
+	// caller's filter shares them.
+	func(f *model.StormReportFilter) bool {
+		set := false
+		overrides := make([]*model.EventTypeFilter, len(f.EventTypeFilters))
+		for i, tf := range f.EventTypeFilters {
+			c := *tf
+			set = set || c.RadiusMiles != nil
+			c.RadiusMiles = nil
+			overrides[i] = &c
+		}
+		f.EventTypeFilters = overrides
+		return set
+	},
+}
+
+// clearPositionFilters clears every position-based criterion of f and
+// reports whether any was set.
+func clearPositionFilters(f *model.StormReportFilter) bool {
+	set := false
+	for _, unset := range positionFilters {
+		if unset(f) {
+			set = true
+		}
+	}
+	return set
+}
+
+// buildMissingCoordinatesCountQuery counts the reports matching the filter
+// (ignoring pagination) that are stored without a position.
+func buildMissingCoordinatesCountQuery(filter *model.StormReportFilter) (string, []any) {
+	where, args, _ := buildWhereClause(filter)
+	where = append(where, missingCoordinatesSQL)
+	return "SELECT COUNT(*) FROM storm_reports" + buildWhereSQL(where), args
+}
+
+// ExcludedMissingCoordinates counts the reports that match every criterion
+// of the filter except its position-based ones (see positionFilters) and
+// were left out because they are stored without a position. It is 0 when
+// the filter sets no position-based criterion.
+func (s *Store) ExcludedMissingCoordinates(ctx context.Context, filter *model.StormReportFilter) (int, error) {
+	anywhere := *filter
+	if !clearPositionFilters(&anywhere) {
+		return 0, nil
+	}
+	ctx, done, err := s.startQuery(ctx, "excluded_missing_coordinates")
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+
+	query, args := buildMissingCoordinatesCountQuery(&anywhere)
+	missing, err := s.countStormReports(ctx, query, args)
+	if err != nil {
+		return 0, err
+	}
+	// A criterion that matches (0, 0), such as an area around it or
+	// unwarnedOnly, keeps those reports, so only the ones actually dropped
+	// are counted.
+	query, args = buildMissingCoordinatesCountQuery(filter)
+	kept, err := s.countStormReports(ctx, query, args)
+	if err != nil {
+		return 0, err
+	}
+	return missing - kept, nil
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countPool answers each count with filtered when the statement contains
+// marker (the position criterion under test), or anywhere otherwise.
+type countPool struct {
+	fakePool
+	marker             string
+	filtered, anywhere int
+}
+
+func (p *countPool) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	p.calls = append(p.calls, "queryrow")
+	if strings.Contains(sql, p.marker) {
+		return countRow{p.filtered}
+	}
+	return countRow{p.anywhere}
+}
+
+type countRow struct{ n int }
+
+func (r countRow) Scan(dest ...any) error {
+	*dest[0].(*int) = r.n
+	return nil
+}
+
+func missingCoordinatesFilter() *model.StormReportFilter {
+	radius := 25.0
+	return &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"OK"},
+		Near:   &model.GeoRadiusFilter{Lat: 35.2, Lon: -97.4, RadiusMiles: &radius},
+	}
+}
+
+func TestBuildMissingCoordinatesCountQuery(t *testing.T) {
+	filter := missingCoordinatesFilter()
+	where, whereArgs, _ := buildWhereClause(filter)
+
+	query, args := buildMissingCoordinatesCountQuery(filter)
+
+	assert.Equal(t, whereArgs, args)
+	assert.Equal(t, "SELECT COUNT(*) FROM storm_reports"+buildWhereSQL(append(where, "geo_lat = 0 AND geo_lon = 0")), query)
+}
+
+func TestClearPositionFilters(t *testing.T) {
+	radius, lat, lon := 10.0, 35.2, -97.4
+	night, warned := model.DayNightNight, true
+	hash := "9y6"
+	f := missingCoordinatesFilter()
+	f.BBox = &model.BoundingBoxFilter{MinLat: 30, MaxLat: 40, MinLon: -100, MaxLon: -90}
+	f.Geohash = &hash
+	f.AtLat, f.AtLon = &lat, &lon
+	f.Polygon = []model.LatLon{{Lat: 30, Lon: -100}, {Lat: 40, Lon: -100}, {Lat: 40, Lon: -90}}
+	f.NearPopulatedPlace = &model.PopulatedPlaceFilter{MinPopulation: 1000, RadiusMiles: 5}
+	f.StormTrack = &model.StormTrackFilter{RadiusMiles: 5}
+	f.Warning = &model.WarningFilter{Types: []string{"TOR"}}
+	f.TriggeredWarning = &warned
+	f.DayNight = &night
+	f.EventTypeFilters = []*model.EventTypeFilter{{EventType: model.EventTypeHail, RadiusMiles: &radius}}
+
+	anywhere := *f
+	require.True(t, clearPositionFilters(&anywhere))
+
+	assert.Equal(t, &model.StormReportFilter{
+		TimeRange:        f.TimeRange,
+		States:           f.States,
+		EventTypeFilters: []*model.EventTypeFilter{{EventType: model.EventTypeHail}},
+	}, &anywhere, "only non-position criteria remain")
+	assert.Same(t, &radius, f.EventTypeFilters[0].RadiusMiles, "caller's overrides untouched")
+
+	anywhere = model.StormReportFilter{States: []string{"OK"}}
+	assert.False(t, clearPositionFilters(&anywhere))
+}
+
+func TestExcludedMissingCoordinates(t *testing.T) {
+	s := New(nil, observability.NewTestMetrics())
+	s.reads = &countPool{marker: "geo_lat BETWEEN", anywhere: 4}
+
+	n, err := s.ExcludedMissingCoordinates(context.Background(), missingCoordinatesFilter())
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, n, "every (0, 0) report matching the other criteria falls outside the radius")
+
+	s.reads = &countPool{marker: "geo_lat BETWEEN", filtered: 4, anywhere: 4}
+	n, err = s.ExcludedMissingCoordinates(context.Background(), missingCoordinatesFilter())
+	require.NoError(t, err)
+	assert.Zero(t, n, "an area around (0, 0) keeps them")
+}
+
+func TestExcludedMissingCoordinates_Polygon(t *testing.T) {
+	s := New(nil, observability.NewTestMetrics())
+	pool := &countPool{marker: "::polygon @>", filtered: 1, anywhere: 3}
+	s.reads = pool
+	filter := missingCoordinatesFilter()
+	filter.Near = nil
+	filter.Polygon = []model.LatLon{{Lat: -1, Lon: -1}, {Lat: 1, Lon: -1}, {Lat: 1, Lon: 1}, {Lat: -1, Lon: -1}}
+
+	n, err := s.ExcludedMissingCoordinates(context.Background(), filter)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Len(t, pool.calls, 2, "counted with and without the polygon")
+}
+
+func TestExcludedMissingCoordinates_NoArea(t *testing.T) {
+	s := New(nil, observability.NewTestMetrics())
+	pool := &countPool{anywhere: 4}
+	s.reads = pool
+	filter := missingCoordinatesFilter()
+	filter.Near = nil
+
+	n, err := s.ExcludedMissingCoordinates(context.Background(), filter)
+
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, pool.calls, "no query without a position filter")
+}
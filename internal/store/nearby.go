@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// buildNearbyReportsQuery selects the limit reports geographically nearest the
+// anchor, excluding the anchor itself, whose event_time is within window of
+// the anchor's. The haversine distance orders the rows in SQL and, with
+// withDistance, is also returned after the report columns. The time window
+// bounds the rows scanned.
+func buildNearbyReportsQuery(anchor *model.StormReport, window time.Duration, limit int, excludeFuture, withDistance bool) (string, []any) {
+	where := []string{
+		"id <> $1",
+		"event_time BETWEEN $2 AND $3",
+	}
+	if excludeFuture {
+		where = append(where, "event_time <= now()")
+	}
+	args := []any{
+		anchor.ID,
+		anchor.EventTime.Add(-window),
+		anchor.EventTime.Add(window),
+		anchor.Geo.Lat,
+		anchor.Geo.Lon,
+		limit,
+	}
+	distance := unitMiles.haversine("$4", "$5", "geo_lat", "geo_lon")
+	selectList := columns
+	if withDistance {
+		// Postgres matches the identical ORDER BY expression to this output
+		// column, so the distance is still computed once per row.
+		selectList += ", " + distance + " AS distance_miles"
+	}
+	query := fmt.Sprintf(`SELECT %s
+		FROM storm_reports%s
+		ORDER BY %s, event_time, id
+		LIMIT $6`, selectList, buildWhereSQL(where), distance)
+	return query, args
+}
+
+// NearbyReports returns up to limit reports nearest the report with the given
+// ID, closest first, among those within window of its event time. DistanceMiles
+// is only filled in when withDistance is set. It returns nil, nil when no
+// report has that ID.
+func (s *Store) NearbyReports(ctx context.Context, id string, window time.Duration, limit int, excludeFuture, withDistance bool) ([]*model.NearbyReport, error) {
+	anchor, err := s.GetStormReport(ctx, id)
+	if err != nil || anchor == nil {
+		return nil, err
+	}
+
+	ctx, done, err := s.startQuery(ctx, "nearby_reports")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildNearbyReportsQuery(anchor, window, limit, excludeFuture, withDistance)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("nearby reports: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.NearbyReport, 0, limit)
+	for rows.Next() {
+		var r model.StormReport
+		var dist float64
+		dest := []any{
+			&r.ID, &r.EventType, &r.Geo.Lat, &r.Geo.Lon,
+			&r.Measurement.Magnitude, &r.Measurement.Unit,
+			&r.EventTime,
+			&r.Location.Raw, &r.Location.Name,
+			&r.Location.Distance, &r.Location.Direction,
+			&r.Location.State, &r.Location.County,
+			&r.Comments, &r.Measurement.Severity, &r.SourceOffice,
+			&r.TimeBucket, &r.ProcessedAt,
+			&r.SpotterLevel, &r.Measurement.Method, &r.RawEventType,
+		}
+		if withDistance {
+			dest = append(dest, &dist)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan nearby report: %w", err)
+		}
+		result = append(result, &model.NearbyReport{Report: &r, DistanceMiles: dist})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildNearbyReportsQuery(t *testing.T) {
+	at := time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC)
+	anchor := &model.StormReport{ID: "anchor", EventTime: at, Geo: model.Geo{Lat: 35.2, Lon: -97.4}}
+
+	query, args := buildNearbyReportsQuery(anchor, 6*time.Hour, 5, false, true)
+
+	// anchor id, window bounds, anchor coordinates, limit
+	require.Len(t, args, 6)
+	assert.Equal(t, []any{"anchor", at.Add(-6 * time.Hour), at.Add(6 * time.Hour), 35.2, -97.4, 5}, args)
+	assert.Contains(t, query, "WHERE id <> $1 AND event_time BETWEEN $2 AND $3")
+	assert.Contains(t, query, "cos(radians($4)) * cos(radians(geo_lat))")
+	assert.Contains(t, query, "radians(geo_lon) - radians($5)")
+	assert.Contains(t, query, "AS distance_miles")
+	assert.Contains(t, query, ")), event_time, id", "ordered by distance, then time and id")
+	assert.Contains(t, query, "LIMIT $6")
+	assert.NotContains(t, query, "now()")
+}
+
+func TestBuildNearbyReportsQuery_ExcludeFuture(t *testing.T) {
+	anchor := &model.StormReport{ID: "anchor", EventTime: time.Now()}
+
+	query, args := buildNearbyReportsQuery(anchor, time.Hour, 10, true, true)
+
+	assert.Len(t, args, 6)
+	assert.Contains(t, query, "event_time BETWEEN $2 AND $3 AND event_time <= now()")
+}
+
+func TestBuildNearbyReportsQuery_DistanceNotSelected(t *testing.T) {
+	anchor := &model.StormReport{ID: "anchor", EventTime: time.Now(), Geo: model.Geo{Lat: 35.2, Lon: -97.4}}
+
+	query, args := buildNearbyReportsQuery(anchor, time.Hour, 10, false, false)
+
+	selectList, rest, ok := strings.Cut(query, "FROM storm_reports")
+	require.True(t, ok)
+	assert.NotContains(t, selectList, "acos", "distance is not computed for output")
+	assert.NotContains(t, query, "distance_miles")
+	assert.Contains(t, rest, fmt.Sprintf("ORDER BY %v * acos(", earthRadiusMiles), "still ordered nearest first")
+	assert.Len(t, args, 6, "arguments are unchanged")
+}
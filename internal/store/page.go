@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// PageStats holds summary statistics computed over every row matching a
+// filter, not just the returned page.
+type PageStats struct {
+	TotalCount   int
+	MaxMagnitude *float64
+
+	// Remaining counts the matching rows from the start of the page to the
+	// end of the result set, so the page has more after it if it is shorter.
+	Remaining int
+	// QueriedAt is the database time the page was read at, the snapshot time
+	// for a keyset scan starting with this page. Zero for an empty page.
+	QueriedAt time.Time
+}
+
+// reportPage is the cached result of ListStormReportsWithStats.
+type reportPage struct {
+	reports []*model.StormReport
+	stats   PageStats
+}
+
+// statsColumns are window aggregates appended to each page row. Window
+// functions are evaluated before LIMIT/OFFSET, so every row carries the stats
+// for the full filtered set.
+const statsColumns = `COUNT(*) OVER () AS total_count,
+	MAX(measurement_magnitude) OVER () AS max_magnitude,
+	statement_timestamp() AS queried_at`
+
+// buildPageWithStatsQuery builds a single query returning the requested page
+// with total count and summary stats as extra columns on each row.
+//
+// For a keyset page the filtered rows are wrapped in a subquery and the
+// keyset predicate applied outside it, so the stats still cover the whole
+// result set rather than only the rows after the cursor. A further window
+// column counts the rows from the cursor on.
+func buildPageWithStatsQuery(filter *model.StormReportFilter) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	query := "SELECT " + columns + ",\n\t" + statsColumns +
+		" FROM storm_reports" + buildWhereSQL(where)
+	if filter.Cursor != nil {
+		keyset, keyArgs := buildKeysetClause(filter, idx)
+		query = "SELECT *, COUNT(*) OVER () AS remaining FROM (" + query + ") page WHERE " + keyset
+		args = append(args, keyArgs...)
+		idx += len(keyArgs)
+	}
+	pageSQL, pageArgs := buildOrderAndPage(filter, idx)
+	return query + pageSQL, append(args, pageArgs...)
+}
+
+// ListStormReportsWithStats returns the filtered, sorted, paginated reports
+// together with the total count and summary stats, in one round trip.
+// The stats are read from the first row. When the page is empty (e.g. offset
+// past the end) there is no row to read them from, so it falls back to a
+// COUNT(*) query. When filter.Cursor is set the page starts after it.
+func (s *Store) ListStormReportsWithStats(ctx context.Context, filter *model.StormReportFilter) ([]*model.StormReport, PageStats, error) {
+	ctx, done, err := s.startQuery(ctx, "list_with_stats")
+	if err != nil {
+		return nil, PageStats{}, err
+	}
+	defer done()
+	query, args := buildPageWithStatsQuery(filter)
+
+	key := cacheKey(query, args)
+	if s.pageCache != nil {
+		if page, ok := s.pageCache.Get(key); ok {
+			return page.reports, page.stats, nil
+		}
+	}
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, PageStats{}, fmt.Errorf("query storm reports with stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats PageStats
+	statsRead := false
+	reports, err := scanRows(rows, s.badRows, func(row scannable) (*model.StormReport, error) {
+		r, rowStats, err := scanStormReportWithStats(row, filter.Cursor != nil)
+		if err == nil && !statsRead {
+			stats, statsRead = rowStats, true
+		}
+		return r, err
+	})
+	if err != nil {
+		return nil, PageStats{}, err
+	}
+
+	if len(reports) == 0 {
+		where, baseArgs, _ := buildWhereClause(filter)
+		total, err := s.countStormReports(ctx, "SELECT COUNT(*) FROM storm_reports"+buildWhereSQL(where), baseArgs)
+		if err != nil {
+			return nil, PageStats{}, err
+		}
+		stats.TotalCount = total
+	}
+	if filter.Cursor == nil {
+		stats.Remaining = stats.TotalCount
+		if filter.Offset != nil {
+			stats.Remaining -= *filter.Offset
+		}
+	}
+
+	if s.pageCache != nil {
+		s.pageCache.Set(key, reportPage{reports: reports, stats: stats})
+	}
+	return reports, stats, nil
+}
+
+// scanStormReportWithStats scans a page row. withRemaining reads the extra
+// remaining column of a keyset page.
+func scanStormReportWithStats(row scannable, withRemaining bool) (*model.StormReport, PageStats, error) {
+	var r model.StormReport
+	var stats PageStats
+	dest := []any{
+		&r.ID, &r.EventType, &r.Geo.Lat, &r.Geo.Lon,
+		&r.Measurement.Magnitude, &r.Measurement.Unit,
+		&r.EventTime,
+		&r.Location.Raw, &r.Location.Name,
+		&r.Location.Distance, &r.Location.Direction,
+		&r.Location.State, &r.Location.County,
+		&r.Comments, &r.Measurement.Severity, &r.SourceOffice,
+		&r.TimeBucket, &r.ProcessedAt,
+		&r.SpotterLevel, &r.Measurement.Method, &r.RawEventType,
+		&stats.TotalCount, &stats.MaxMagnitude, &stats.QueriedAt,
+	}
+	if withRemaining {
+		dest = append(dest, &stats.Remaining)
+	}
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, PageStats{}, fmt.Errorf("scan storm report with stats: %w", err)
+	}
+	return &r, stats, nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPageWithStatsQuery_WindowColumns(t *testing.T) {
+	limit := 5
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+		Limit:  &limit,
+	}
+
+	query, args := buildPageWithStatsQuery(filter)
+
+	assert.Contains(t, query, "COUNT(*) OVER () AS total_count")
+	assert.Contains(t, query, "MAX(measurement_magnitude) OVER () AS max_magnitude")
+	// Window aggregates sit in the select list, before FROM
+	assert.Less(t, strings.Index(query, "OVER ()"), strings.Index(query, "FROM storm_reports"))
+	assert.Contains(t, query, "LIMIT $4")
+	// 2 time + states + limit = 4
+	assert.Len(t, args, 4)
+}
+
+func TestBuildPageWithStatsQuery_Cursor(t *testing.T) {
+	limit := 5
+	asOf := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		Limit:  &limit,
+		Cursor: &model.PageCursor{AsOf: asOf, SortKey: time.Date(2024, 4, 26, 18, 0, 0, 0, time.UTC), ID: "r-9"},
+	}
+
+	query, args := buildPageWithStatsQuery(filter)
+
+	// Snapshot bound inside the subquery, keyset predicate outside it, so the
+	// window stats cover the whole snapshot.
+	inner := strings.Index(query, "FROM storm_reports")
+	outer := strings.Index(query, ") page WHERE (event_time, id) < ($4, $5)")
+	require.Positive(t, outer)
+	assert.Less(t, inner, strings.Index(query, "created_at <= $3"))
+	assert.Less(t, strings.Index(query, "created_at <= $3"), outer)
+	assert.Contains(t, query, "COUNT(*) OVER () AS remaining")
+	assert.True(t, strings.HasSuffix(query, "ORDER BY event_time DESC, id DESC LIMIT $6"))
+	assert.Equal(t, []any{asOf, filter.Cursor.SortKey, "r-9", 5}, args[2:])
+}
+
+// statsRow is a scannable that fills the report columns with zero values and
+// the trailing window-aggregate columns with fixed stats.
+type statsRow struct {
+	total     int
+	max       *float64
+	queriedAt time.Time
+	remaining *int // keyset pages only
+}
+
+func (r statsRow) Scan(dest ...any) error {
+	n := len(dest)
+	if r.remaining != nil {
+		n--
+		*dest[n].(*int) = *r.remaining
+	}
+	*dest[n-3].(*int) = r.total
+	*dest[n-2].(**float64) = r.max
+	*dest[n-1].(*time.Time) = r.queriedAt
+	return nil
+}
+
+func TestScanStormReportWithStats(t *testing.T) {
+	maxMag := 2.75
+	at := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+	_, stats, err := scanStormReportWithStats(statsRow{total: 42, max: &maxMag, queriedAt: at}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 42, stats.TotalCount)
+	require.NotNil(t, stats.MaxMagnitude)
+	assert.InDelta(t, 2.75, *stats.MaxMagnitude, 1e-9)
+	assert.Equal(t, at, stats.QueriedAt)
+
+	remaining := 7
+	_, stats, err = scanStormReportWithStats(statsRow{total: 42, max: &maxMag, queriedAt: at, remaining: &remaining}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 42, stats.TotalCount)
+	assert.Equal(t, 7, stats.Remaining)
+}
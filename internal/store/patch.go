@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrReportNotFound is returned when a patched report does not exist.
+var ErrReportNotFound = errors.New("storm report not found")
+
+// ErrEmptyPatch is returned when a patch sets no fields.
+var ErrEmptyPatch = errors.New("patch sets no fields")
+
+// ErrVersionConflict is returned when a patch's ExpectedUpdatedAt no longer
+// matches the stored row.
+var ErrVersionConflict = errors.New("storm report was modified since it was read")
+
+// patchColumns whitelists the columns a ReportPatch can set, in SET-clause
+// order. Only these names are interpolated into the statement.
+var patchColumns = []struct {
+	column string
+	value  func(p *model.ReportPatch) (any, bool)
+}{
+	{"measurement_magnitude", func(p *model.ReportPatch) (any, bool) { return p.Magnitude, p.Magnitude != nil }},
+	{"measurement_severity", func(p *model.ReportPatch) (any, bool) { return p.Severity, p.Severity != nil }},
+	{"measurement_method", func(p *model.ReportPatch) (any, bool) { return p.MeasurementMethod, p.MeasurementMethod != nil }},
+	{"event_time", func(p *model.ReportPatch) (any, bool) { return p.EventTime, p.EventTime != nil }},
+	{"geo_lat", func(p *model.ReportPatch) (any, bool) { return p.Lat, p.Lat != nil }},
+	{"geo_lon", func(p *model.ReportPatch) (any, bool) { return p.Lon, p.Lon != nil }},
+	{"location_name", func(p *model.ReportPatch) (any, bool) { return p.LocationName, p.LocationName != nil }},
+	{"location_county", func(p *model.ReportPatch) (any, bool) { return p.LocationCounty, p.LocationCounty != nil }},
+	{"location_state", func(p *model.ReportPatch) (any, bool) { return p.LocationState, p.LocationState != nil }},
+	{"comments", func(p *model.ReportPatch) (any, bool) { return p.Comments, p.Comments != nil }},
+	{"spotter_level", func(p *model.ReportPatch) (any, bool) { return p.SpotterLevel, p.SpotterLevel != nil }},
+	{"correction_status", func(p *model.ReportPatch) (any, bool) { return p.CorrectionStatus, p.CorrectionStatus != nil }},
+}
+
+// buildPatchSQL returns an UPDATE setting only the patch's non-nil fields,
+// guarded by the expected updated_at. $1 is the id
+// and $2 the expected updated_at. The revision trigger bumps updated_at.
+func buildPatchSQL(id string, patch *model.ReportPatch) (string, []any, error) {
+	args := []any{id, patch.ExpectedUpdatedAt}
+	var set []string
+	for _, c := range patchColumns {
+		v, ok := c.value(patch)
+		if !ok {
+			continue
+		}
+		args = append(args, v)
+		set = append(set, fmt.Sprintf("%s = $%d", c.column, len(args)))
+	}
+	if len(set) == 0 {
+		return "", nil, ErrEmptyPatch
+	}
+	query := "UPDATE storm_reports SET " + strings.Join(set, ", ") +
+		" WHERE id = $1 AND updated_at = $2 RETURNING updated_at"
+	return query, args, nil
+}
+
+// PatchStormReport applies patch to the report with the given id. It runs
+// through EditStormReport, so it waits for concurrent edits of the report
+// (failing with *LockTimeoutError after DefaultLockTimeout) and then checks
+// patch.ExpectedUpdatedAt against the locked row. The revision trigger records
+// the changed columns in storm_report_revisions, so incremental sync clients
+// pick up the correction; patch.IngestJobID, if set, is recorded on that
+// revision. It returns the new updated_at, which is unchanged
+// when the patch writes the values already stored. If the row changed since
+// patch.ExpectedUpdatedAt, it returns the current updated_at with
+// ErrVersionConflict.
+func (s *Store) PatchStormReport(ctx context.Context, id string, patch *model.ReportPatch) (time.Time, error) {
+	query, args, err := buildPatchSQL(id, patch)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer s.observeQuery("patch", time.Now())
+
+	var updatedAt time.Time
+	err = s.EditStormReport(ctx, id, DefaultLockTimeout, func(ctx context.Context, tx pgx.Tx, _ *model.StormReport) error {
+		if patch.IngestJobID != nil {
+			// The revision trigger reads the job from this transaction-local setting.
+			if _, err := tx.Exec(ctx, "SELECT set_config('storm.ingest_job_id', $1, true)", *patch.IngestJobID); err != nil {
+				return fmt.Errorf("set ingest job: %w", err)
+			}
+		}
+		err := tx.QueryRow(ctx, query, args...).Scan(&updatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The row exists and is locked, so only the version guard missed.
+			if err := tx.QueryRow(ctx, "SELECT updated_at FROM storm_reports WHERE id = $1", id).Scan(&updatedAt); err != nil {
+				return fmt.Errorf("patch version: %w", err)
+			}
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return fmt.Errorf("patch storm report: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrVersionConflict) {
+		return updatedAt, err
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return updatedAt, nil
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPatchSQL_OnlySuppliedFields(t *testing.T) {
+	version := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+	mag := 1.75
+	status := model.CorrectionStatusCorrected
+	patch := &model.ReportPatch{ExpectedUpdatedAt: version, Magnitude: &mag, CorrectionStatus: &status}
+
+	query, args, err := buildPatchSQL("r-1", patch)
+	require.NoError(t, err)
+
+	assert.Equal(t, "UPDATE storm_reports SET measurement_magnitude = $3, correction_status = $4 WHERE id = $1 AND updated_at = $2 RETURNING updated_at", query)
+	assert.Equal(t, []any{"r-1", version, &mag, &status}, args)
+	assert.NotContains(t, query, "comments")
+	assert.NotContains(t, query, "geo_lat")
+}
+
+func TestBuildPatchSQL_Empty(t *testing.T) {
+	_, _, err := buildPatchSQL("r-1", &model.ReportPatch{ExpectedUpdatedAt: time.Now()})
+	assert.ErrorIs(t, err, ErrEmptyPatch)
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// buildPercentileQuery builds a per-event-type continuous percentile of
+// magnitude over all reports matching the filter (ignoring pagination). The
+// percentile is bound as the parameter after the WHERE args.
+func buildPercentileQuery(filter *model.StormReportFilter, percentile float64) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	args = append(args, percentile)
+	query := fmt.Sprintf(`SELECT event_type,
+			percentile_cont($%d) WITHIN GROUP (ORDER BY measurement_magnitude) AS magnitude,
+			COUNT(*) AS count
+		FROM storm_reports%s
+		GROUP BY event_type
+		ORDER BY event_type`, idx, buildWhereSQL(where))
+	return query, args
+}
+
+// MagnitudePercentiles returns the given magnitude percentile (in [0, 1]) for
+// each event type present in the filtered set, interpolating between values.
+func (s *Store) MagnitudePercentiles(ctx context.Context, filter *model.StormReportFilter, percentile float64) ([]*model.MagnitudePercentile, error) {
+	ctx, done, err := s.startQuery(ctx, "magnitude_percentiles")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildPercentileQuery(filter, percentile)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("magnitude percentiles: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.MagnitudePercentile{}
+	for rows.Next() {
+		p := &model.MagnitudePercentile{Percentile: percentile}
+		if err := rows.Scan(&p.EventType, &p.Magnitude, &p.Count); err != nil {
+			return nil, fmt.Errorf("scan magnitude percentile: %w", err)
+		}
+		p.Unit = unitForEventType(p.EventType)
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
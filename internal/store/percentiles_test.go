@@ -0,0 +1,31 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPercentileQuery(t *testing.T) {
+	limit := 20
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		EventTypes: []model.EventType{model.EventTypeHail},
+		Limit:      &limit,
+	}
+
+	query, args := buildPercentileQuery(filter, 0.9)
+
+	// 2 time + eventTypes, then the percentile
+	assert.Len(t, args, 4)
+	assert.InDelta(t, 0.9, args[3], 0)
+	assert.Contains(t, query, "percentile_cont($4) WITHIN GROUP (ORDER BY measurement_magnitude)")
+	assert.Contains(t, query, "WHERE event_time >= $1 AND event_time <= $2 AND event_type = ANY($3)")
+	assert.Contains(t, query, "GROUP BY event_type")
+	assert.NotContains(t, query, "LIMIT", "percentiles cover the whole filtered set")
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// placeSearchMiles bounds the reverse-geocoding search: a report farther than
+// this from every populated place has no place name.
+const placeSearchMiles = 50.0
+
+// PlaceCache memoizes reverse-geocoded place names for one request, so
+// reports sharing coordinates cost one lookup. It is safe for concurrent use,
+// since gqlgen resolves list item fields in parallel.
+type PlaceCache struct {
+	mu    sync.Mutex
+	names map[string]*string
+}
+
+// NewPlaceCache returns an empty PlaceCache.
+func NewPlaceCache() *PlaceCache {
+	return &PlaceCache{names: make(map[string]*string)}
+}
+
+type placeCacheKey struct{}
+
+// WithPlaceCache returns a context carrying c. NearestPlaceName calls made
+// with it are served from and recorded in c.
+func WithPlaceCache(ctx context.Context, c *PlaceCache) context.Context {
+	return context.WithValue(ctx, placeCacheKey{}, c)
+}
+
+// PlaceCacheFromContext returns the place cache attached to ctx, or nil.
+func PlaceCacheFromContext(ctx context.Context) *PlaceCache {
+	c, _ := ctx.Value(placeCacheKey{}).(*PlaceCache)
+	return c
+}
+
+// get returns the cached name for (lat, lon), calling load on a miss. The
+// lock is not held during load, so concurrent misses may load twice.
+func (c *PlaceCache) get(lat, lon float64, load func() (*string, error)) (*string, error) {
+	key := strconv.FormatFloat(lat, 'g', -1, 64) + "," + strconv.FormatFloat(lon, 'g', -1, 64)
+	c.mu.Lock()
+	name, ok := c.names[key]
+	c.mu.Unlock()
+	if ok {
+		return name, nil
+	}
+	name, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.names[key] = name
+	c.mu.Unlock()
+	return name, nil
+}
+
+// buildNearestPlaceQuery selects the populated place nearest (lat, lon)
+// within placeSearchMiles, formatted as "Name, ST". A bounding box narrows
+// the candidates before the haversine distance orders them.
+func buildNearestPlaceQuery(lat, lon float64) (string, []any) {
+	latDelta, lonDelta := unitMiles.degreeDeltas(lat, placeSearchMiles)
+	query := fmt.Sprintf(`SELECT label FROM (
+			SELECT name || ', ' || state AS label, population,
+				%s AS distance
+			FROM populated_places
+			WHERE lat BETWEEN $1 AND $2 AND lon BETWEEN $3 AND $4
+		) p
+		WHERE distance <= $7
+		ORDER BY distance, population DESC
+		LIMIT 1`, unitMiles.haversine("$5", "$6", "lat", "lon"))
+	args := []any{lat - latDelta, lat + latDelta, lon - lonDelta, lon + lonDelta, lat, lon, placeSearchMiles}
+	return query, args
+}
+
+// NearestPlaceName reverse-geocodes (lat, lon) to the nearest populated place,
+// e.g. "Plano, TX", or nil if none is within placeSearchMiles. With a
+// PlaceCache in ctx, repeated coordinates are answered from it.
+func (s *Store) NearestPlaceName(ctx context.Context, lat, lon float64) (*string, error) {
+	load := func() (*string, error) { return s.nearestPlaceName(ctx, lat, lon) }
+	if c := PlaceCacheFromContext(ctx); c != nil {
+		return c.get(lat, lon, load)
+	}
+	return load()
+}
+
+func (s *Store) nearestPlaceName(ctx context.Context, lat, lon float64) (*string, error) {
+	ctx, done, err := s.startQuery(ctx, "nearest_place")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildNearestPlaceQuery(lat, lon)
+
+	var name string
+	err = s.reads.QueryRow(ctx, query, args...).Scan(&name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("nearest place: %w", err)
+	}
+	return &name, nil
+}
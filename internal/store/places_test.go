@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildNearestPlaceQuery(t *testing.T) {
+	query, args := buildNearestPlaceQuery(33.02, -96.70)
+
+	latDelta, lonDelta := unitMiles.degreeDeltas(33.02, placeSearchMiles)
+	require.Len(t, args, 7)
+	assert.Equal(t, []any{33.02 - latDelta, 33.02 + latDelta, -96.70 - lonDelta, -96.70 + lonDelta, 33.02, -96.70, placeSearchMiles}, args)
+	assert.Contains(t, query, "FROM populated_places")
+	assert.Contains(t, query, "WHERE lat BETWEEN $1 AND $2 AND lon BETWEEN $3 AND $4", "bounding-box pre-filter")
+	assert.Contains(t, query, "WHERE distance <= $7")
+	assert.Contains(t, query, "ORDER BY distance, population DESC")
+	assert.Contains(t, query, "LIMIT 1")
+}
+
+func TestPlaceCache_LoadsEachCoordinateOnce(t *testing.T) {
+	c := NewPlaceCache()
+	loads := 0
+	plano := "Plano, TX"
+	load := func() (*string, error) {
+		loads++
+		return &plano, nil
+	}
+
+	for range 3 {
+		name, err := c.get(33.02, -96.70, load)
+		require.NoError(t, err)
+		assert.Equal(t, "Plano, TX", *name)
+	}
+	assert.Equal(t, 1, loads)
+
+	_, err := c.get(35.47, -97.52, load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "different coordinates miss")
+}
+
+func TestPlaceCache_CachesNoPlace(t *testing.T) {
+	c := NewPlaceCache()
+	loads := 0
+	load := func() (*string, error) {
+		loads++
+		return nil, nil
+	}
+	for range 2 {
+		name, err := c.get(0, 0, load)
+		require.NoError(t, err)
+		assert.Nil(t, name)
+	}
+	assert.Equal(t, 1, loads)
+}
+
+func TestNearestPlaceName_ServedFromRequestCache(t *testing.T) {
+	// No pool: a cache miss would panic, so this proves the hit path skips
+	// the database.
+	s := New(nil, observability.NewTestMetrics())
+	c := NewPlaceCache()
+	plano := "Plano, TX"
+	_, err := c.get(33.02, -96.70, func() (*string, error) { return &plano, nil })
+	require.NoError(t, err)
+
+	name, err := s.NearestPlaceName(WithPlaceCache(context.Background(), c), 33.02, -96.70)
+	require.NoError(t, err)
+	assert.Equal(t, "Plano, TX", *name)
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// planShapeKeys are the EXPLAIN (FORMAT JSON) node attributes describing the
+// planner's strategy. Costs, row estimates, and condition text (which embeds
+// parameter values) are left out, so the hash changes only when the plan
+// itself does.
+var planShapeKeys = []string{
+	"Node Type", "Strategy", "Partial Mode", "Join Type", "Parent Relationship",
+	"Subplan Name", "Relation Name", "Index Name", "Scan Direction",
+}
+
+// planShapeHash hashes the structure of an EXPLAIN (FORMAT JSON) result.
+func planShapeHash(explain []byte) (string, error) {
+	var out []struct {
+		Plan map[string]any `json:"Plan"`
+	}
+	if err := json.Unmarshal(explain, &out); err != nil {
+		return "", fmt.Errorf("parse plan: %w", err)
+	}
+	if len(out) == 0 || out[0].Plan == nil {
+		return "", fmt.Errorf("parse plan: no plan")
+	}
+	var b strings.Builder
+	writePlanShape(&b, out[0].Plan)
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writePlanShape writes node's shape attributes, then its children in order.
+func writePlanShape(b *strings.Builder, node map[string]any) {
+	b.WriteByte('(')
+	for _, k := range planShapeKeys {
+		if v, ok := node[k]; ok {
+			fmt.Fprintf(b, "%s=%v;", k, v)
+		}
+	}
+	children, _ := node["Plans"].([]any)
+	for _, c := range children {
+		if child, ok := c.(map[string]any); ok {
+			writePlanShape(b, child)
+		}
+	}
+	b.WriteByte(')')
+}
+
+// buildExplainQuery prefixes the page query of filter with EXPLAIN. ANALYZE
+// is off, so Postgres plans the query without running it.
+func buildExplainQuery(filter *model.StormReportFilter) (string, []any) {
+	query, args := buildPageWithStatsQuery(filter)
+	return "EXPLAIN (FORMAT JSON, ANALYZE false) " + query, args
+}
+
+// explain returns the EXPLAIN (FORMAT JSON) output for query.
+func (s *Store) explain(ctx context.Context, query string, args []any) ([]byte, error) {
+	var plan []byte
+	if err := s.reads.QueryRow(ctx, query, args...).Scan(&plan); err != nil {
+		return nil, fmt.Errorf("explain page query: %w", err)
+	}
+	return plan, nil
+}
+
+// PlanHash returns a hash of the query plan the database picks for the page
+// query of filter. It covers the plan's structure, not its costs, so filters
+// of the same shape hash alike until the planner changes strategy.
+func (s *Store) PlanHash(ctx context.Context, filter *model.StormReportFilter) (string, error) {
+	ctx, done, err := s.startQuery(ctx, "plan_hash")
+	if err != nil {
+		return "", err
+	}
+	defer done()
+	query, args := buildExplainQuery(filter)
+
+	plan, err := s.explain(ctx, query, args)
+	if err != nil {
+		return "", err
+	}
+	return planShapeHash(plan)
+}
+
+// QueryPlan is the statement ExplainStormReports ran and the plan it returned.
+type QueryPlan struct {
+	// SQL is the EXPLAIN statement, including the page query it wraps.
+	SQL  string
+	Args []any
+	// Plan is the EXPLAIN (FORMAT JSON) output.
+	Plan string
+}
+
+// ExplainStormReports returns the page query the store would run for filter
+// along with the plan Postgres picks for it, for debugging slow filters. The
+// page query itself is not executed.
+func (s *Store) ExplainStormReports(ctx context.Context, filter *model.StormReportFilter) (*QueryPlan, error) {
+	ctx, done, err := s.startQuery(ctx, "explain_reports")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildExplainQuery(filter)
+
+	plan, err := s.explain(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &QueryPlan{SQL: query, Args: args, Plan: string(plan)}, nil
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlan is EXPLAIN (FORMAT JSON) output for a page query: a limit over a
+// sort over a scan, with the given scan node, costs, and condition value.
+func fakePlan(scan, index string, cost float64, state string) []byte {
+	return []byte(`[{"Plan": {
+		"Node Type": "Limit", "Startup Cost": ` + jsonNumber(cost) + `, "Plan Rows": 20,
+		"Plans": [{
+			"Node Type": "Sort", "Parent Relationship": "Outer", "Sort Key": ["event_time DESC"],
+			"Plans": [{
+				"Node Type": "` + scan + `", "Parent Relationship": "Outer",
+				"Relation Name": "storm_reports", "Index Name": "` + index + `",
+				"Total Cost": ` + jsonNumber(cost*3) + `, "Plan Rows": 812,
+				"Filter": "(location_state = ANY ('{` + state + `}'::text[]))"
+			}]
+		}]
+	}}]`)
+}
+
+func jsonNumber(f float64) string {
+	b, _ := json.Marshal(f)
+	return string(b)
+}
+
+func TestPlanShapeHash_SameShape(t *testing.T) {
+	// Two filters of the same shape: different values, costs, and estimates.
+	a, err := planShapeHash(fakePlan("Index Scan", "idx_event_time", 12.5, "TX"))
+	require.NoError(t, err)
+	b, err := planShapeHash(fakePlan("Index Scan", "idx_event_time", 904.1, "OK"))
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+	assert.Len(t, a, 64)
+}
+
+func TestPlanShapeHash_StrategyChange(t *testing.T) {
+	indexed, err := planShapeHash(fakePlan("Index Scan", "idx_event_time", 12.5, "TX"))
+	require.NoError(t, err)
+
+	seq, err := planShapeHash(fakePlan("Seq Scan", "", 12.5, "TX"))
+	require.NoError(t, err)
+	assert.NotEqual(t, indexed, seq, "scan type changed")
+
+	other, err := planShapeHash(fakePlan("Index Scan", "idx_state", 12.5, "TX"))
+	require.NoError(t, err)
+	assert.NotEqual(t, indexed, other, "index changed")
+}
+
+func TestPlanShapeHash_Invalid(t *testing.T) {
+	_, err := planShapeHash([]byte("not json"))
+	assert.Error(t, err)
+
+	_, err = planShapeHash([]byte("[]"))
+	assert.ErrorContains(t, err, "no plan")
+}
+
+// explainPool answers every QueryRow with plan, recording the statement.
+type explainPool struct {
+	fakePool
+	plan []byte
+	sql  string
+	args []any
+}
+
+func (p *explainPool) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	p.sql, p.args = sql, args
+	return planRow{p.plan}
+}
+
+type planRow struct{ plan []byte }
+
+func (r planRow) Scan(dest ...any) error {
+	*dest[0].(*[]byte) = r.plan
+	return nil
+}
+
+func TestExplainStormReports(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+	}
+	plan := fakePlan("Index Scan", "idx_event_time", 12.5, "TX")
+	pool := &explainPool{plan: plan}
+	s := New(nil, observability.NewTestMetrics())
+	s.reads = pool
+
+	got, err := s.ExplainStormReports(context.Background(), filter)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(got.SQL, "EXPLAIN (FORMAT JSON, ANALYZE false) SELECT "), got.SQL)
+	assert.Equal(t, pool.sql, got.SQL)
+	assert.Equal(t, string(plan), got.Plan)
+
+	_, whereArgs, _ := buildWhereClause(filter)
+	assert.Equal(t, whereArgs, got.Args[:len(whereArgs)], "WHERE args come first, unchanged")
+	assert.Equal(t, pool.args, got.Args)
+}
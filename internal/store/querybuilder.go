@@ -3,9 +3,13 @@ package store
 import (
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/couchcryptid/storm-data-api/internal/geohash"
 	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/solar"
 )
 
 const (
@@ -15,8 +19,48 @@ const (
 	// milesPerDegreeLat approximates the miles-per-degree latitude (~69 mi).
 	// Used by bounding-box pre-filtering for B-tree index utilization.
 	milesPerDegreeLat = 69.0
+
+	// coordinateTolerance is how far, in degrees (about 11 m of latitude),
+	// a report may sit from atLat/atLon and still match.
+	coordinateTolerance = 0.0001
 )
 
+// distanceUnit pairs the constants that must agree for a radius unit: the
+// haversine earth radius and the length of one degree of latitude. Every
+// distance and degree conversion goes through it, so they cannot disagree.
+type distanceUnit struct {
+	earthRadius  float64
+	perDegreeLat float64
+}
+
+// unitMiles is the unit of every radius the API accepts.
+var unitMiles = distanceUnit{earthRadius: earthRadiusMiles, perDegreeLat: milesPerDegreeLat}
+
+// haversine returns a SQL expression for the great-circle distance in u
+// between (lat1, lon1) and (lat2, lon2), each a SQL expression. The acos
+// argument is clamped to 1 so rounding cannot push identical points out of
+// its domain.
+func (u distanceUnit) haversine(lat1, lon1, lat2, lon2 string) string {
+	return fmt.Sprintf(`%v * acos(least(1.0,
+			cos(radians(%s)) * cos(radians(%s)) *
+			cos(radians(%s) - radians(%s)) +
+			sin(radians(%s)) * sin(radians(%s))
+		))`, u.earthRadius, lat1, lat2, lon2, lon1, lat1, lat2)
+}
+
+// degreeDeltas converts a radius in u to the half-widths, in degrees, of a
+// box around centerLat that contains the circle. A degree of longitude shrinks
+// with cos(latitude), so lonDelta is latDelta / cos(centerLat) and widens
+// toward the poles; where that passes 180° the box spans every longitude.
+func (u distanceUnit) degreeDeltas(centerLat, radius float64) (latDelta, lonDelta float64) {
+	latDelta = radius / u.perDegreeLat
+	cos := math.Cos(centerLat * math.Pi / 180.0)
+	if cos <= latDelta/180 {
+		return latDelta, 180
+	}
+	return latDelta, latDelta / cos
+}
+
 // buildWhereSQL joins the clauses into a WHERE fragment (empty string if no clauses).
 func buildWhereSQL(clauses []string) string {
 	if len(clauses) == 0 {
@@ -34,23 +78,183 @@ func buildWhereClause(filter *model.StormReportFilter) ([]string, []any, int) {
 	idx := 1
 
 	// Time bounds (always present — required by schema)
-	where = append(where, fmt.Sprintf("event_time >= $%d", idx))
+	timeCol := timeColumn(filter)
+	where = append(where, fmt.Sprintf("%s >= $%d", timeCol, idx))
 	args = append(args, filter.TimeRange.From)
 	idx++
 
-	where = append(where, fmt.Sprintf("event_time <= $%d", idx))
+	where = append(where, fmt.Sprintf("%s <= $%d", timeCol, idx))
 	args = append(args, filter.TimeRange.To)
 	idx++
 
+	// Future-dated rows are bad upstream data
+	if filter.ExcludeFuture != nil && *filter.ExcludeFuture {
+		where = append(where, "event_time <= now()")
+	}
+
+	// Keyset pages only see reports that existed when the scan began
+	if filter.Cursor != nil {
+		where = append(where, fmt.Sprintf("created_at <= $%d", idx))
+		args = append(args, filter.Cursor.AsOf)
+		idx++
+	}
+
 	// Administrative location filters
 	if len(filter.States) > 0 {
 		where = append(where, fmt.Sprintf("location_state = ANY($%d)", idx))
 		args = append(args, filter.States)
 		idx++
 	}
-	if len(filter.Counties) > 0 {
-		where = append(where, fmt.Sprintf("location_county = ANY($%d)", idx))
-		args = append(args, filter.Counties)
+	if clause, countyArgs := buildCountyClause(filter, idx); clause != "" {
+		where = append(where, clause)
+		args = append(args, countyArgs...)
+		idx += len(countyArgs)
+	}
+	if len(filter.Offices) > 0 {
+		where = append(where, fmt.Sprintf("source_office = ANY($%d)", idx))
+		args = append(args, filter.Offices)
+		idx++
+	}
+	if filter.KeywordSearch != nil && *filter.KeywordSearch != "" {
+		where = append(where, buildKeywordClause(idx))
+		args = append(args, "%"+escapeLike(*filter.KeywordSearch)+"%", *filter.KeywordSearch)
+		idx += 2
+	}
+	if len(filter.RemarksInclude) > 0 || len(filter.RemarksExclude) > 0 {
+		where = append(where, buildRemarksClause(idx, len(filter.RemarksInclude), len(filter.RemarksExclude)))
+		args = append(args, stringArgs(filter.RemarksInclude)...)
+		args = append(args, stringArgs(filter.RemarksExclude)...)
+		idx += len(filter.RemarksInclude) + len(filter.RemarksExclude)
+	}
+	// comments is NOT NULL (missing remarks are stored as ''), so a positive
+	// minimum also excludes reports without remarks.
+	if filter.MinRemarksLength != nil {
+		where = append(where, fmt.Sprintf("LENGTH(comments) >= $%d", idx))
+		args = append(args, *filter.MinRemarksLength)
+		idx++
+	}
+	// NULL (unknown) uncertainty fails the comparison, so those reports are
+	// excluded rather than assumed precise.
+	if filter.MaxLocationUncertainty != nil {
+		where = append(where, fmt.Sprintf("location_uncertainty_m <= $%d", idx))
+		args = append(args, *filter.MaxLocationUncertainty)
+		idx++
+	}
+	if filter.CountyOutlierStdDevs != nil {
+		where = append(where, buildCountyOutlierClause(idx))
+		args = append(args, *filter.CountyOutlierStdDevs)
+		idx++
+	}
+	if len(filter.CorrectionStatus) > 0 {
+		where = append(where, fmt.Sprintf("correction_status = ANY($%d)", idx))
+		args = append(args, filter.CorrectionStatus)
+		idx++
+	}
+	if len(filter.SpotterLevels) > 0 {
+		where = append(where, fmt.Sprintf("spotter_level = ANY($%d)", idx))
+		args = append(args, filter.SpotterLevels)
+		idx++
+	}
+	if filter.Measured != nil {
+		if *filter.Measured {
+			where = append(where, fmt.Sprintf("measurement_method = '%s'", model.MeasurementMethodMeasured))
+		} else {
+			// Unknown methods count as not measured.
+			where = append(where, fmt.Sprintf("measurement_method IS DISTINCT FROM '%s'", model.MeasurementMethodMeasured))
+		}
+	}
+	if len(filter.MeasurementMethods) > 0 {
+		where = append(where, fmt.Sprintf("measurement_method = ANY($%d)", idx))
+		args = append(args, filter.MeasurementMethods)
+		idx++
+	}
+
+	// Explicit bounding box. AND-ed with any near radius clauses below, so
+	// setting both yields the intersection.
+	if b := filter.BBox; b != nil {
+		where = append(where, fmt.Sprintf(
+			"geo_lat BETWEEN $%d AND $%d AND geo_lon BETWEEN $%d AND $%d",
+			idx, idx+1, idx+2, idx+3))
+		args = append(args, b.MinLat, b.MaxLat, b.MinLon, b.MaxLon)
+		idx += 4
+	}
+
+	// Geohash cell as a bounding box (validated by ValidateFilter)
+	if filter.Geohash != nil {
+		if b, err := geohash.Decode(*filter.Geohash); err == nil {
+			where = append(where, fmt.Sprintf(
+				"geo_lat BETWEEN $%d AND $%d AND geo_lon BETWEEN $%d AND $%d",
+				idx, idx+1, idx+2, idx+3))
+			args = append(args, b.MinLat, b.MaxLat, b.MinLon, b.MaxLon)
+			idx += 4
+		}
+	}
+
+	// Exact coordinate within coordinateTolerance, as ranges so the
+	// (geo_lat, geo_lon) index applies
+	if filter.AtLat != nil && filter.AtLon != nil {
+		where = append(where, fmt.Sprintf(
+			"geo_lat > $%d AND geo_lat < $%d AND geo_lon > $%d AND geo_lon < $%d",
+			idx, idx+1, idx+2, idx+3))
+		args = append(args,
+			*filter.AtLat-coordinateTolerance, *filter.AtLat+coordinateTolerance,
+			*filter.AtLon-coordinateTolerance, *filter.AtLon+coordinateTolerance)
+		idx += 4
+	}
+
+	if len(filter.Polygon) > 0 {
+		polyWhere, polyArgs, polyIdx := buildPolygonClause(filter.Polygon, idx)
+		where = append(where, polyWhere)
+		args = append(args, polyArgs...)
+		idx = polyIdx
+	}
+
+	// Day/night by solar elevation (solar_elevation mirrors solarref.Elevation)
+	if filter.DayNight != nil && filter.DayNight.IsValid() {
+		op := ">"
+		if *filter.DayNight == model.DayNightNight {
+			op = "<="
+		}
+		where = append(where, fmt.Sprintf("solar_elevation(geo_lat, geo_lon, event_time) %s %g", op, solar.HorizonDegrees))
+	}
+
+	// Proximity to populated places
+	if filter.NearPopulatedPlace != nil {
+		placeWhere, placeArgs, placeIdx := buildPopulatedPlaceClause(filter.NearPopulatedPlace, idx)
+		where = append(where, placeWhere)
+		args = append(args, placeArgs...)
+		idx = placeIdx
+	}
+
+	// Near a storm's track at the time-nearest track point
+	if filter.StormTrack != nil {
+		trackWhere, trackArgs, trackIdx := buildStormTrackClause(filter.StormTrack, idx)
+		where = append(where, trackWhere)
+		args = append(args, trackArgs...)
+		idx = trackIdx
+	}
+
+	// Inside (or, with unwarnedOnly, outside) a watch/warning polygon while it was in effect
+	if filter.Warning != nil {
+		build := buildWarningClause
+		if filter.Warning.UnwarnedOnly != nil && *filter.Warning.UnwarnedOnly {
+			build = buildUnwarnedClause
+		}
+		warningWhere, warningArgs, warningIdx := build(filter.Warning, idx)
+		where = append(where, warningWhere)
+		args = append(args, warningArgs...)
+		idx = warningIdx
+	}
+
+	// Followed (or not) by a watch/warning issued over its location
+	if filter.TriggeredWarning != nil {
+		where = append(where, buildTriggeredWarningClause(*filter.TriggeredWarning))
+	}
+
+	// Incremental sync checkpoint
+	if filter.UpdatedAfter != nil {
+		where = append(where, fmt.Sprintf("updated_at > $%d", idx))
+		args = append(args, *filter.UpdatedAfter)
 		idx++
 	}
 
@@ -62,21 +266,27 @@ func buildWhereClause(filter *model.StormReportFilter) ([]string, []any, int) {
 		idx = newIdx
 	} else {
 		// Simple AND filtering: global filters apply uniformly to all event types
-		if len(filter.EventTypes) > 0 {
-			where = append(where, fmt.Sprintf("event_type = ANY($%d)", idx))
-			args = append(args, eventTypeDBValues(filter.EventTypes))
-			idx++
-		}
-		if len(filter.Severity) > 0 {
-			where = append(where, fmt.Sprintf("measurement_severity = ANY($%d)", idx))
-			args = append(args, severityDBValues(filter.Severity))
-			idx++
-		}
-		if filter.MinMagnitude != nil {
-			where = append(where, fmt.Sprintf("measurement_magnitude >= $%d", idx))
-			args = append(args, *filter.MinMagnitude)
-			idx++
+		if len(filter.TypeSeverityGroups) > 0 {
+			clause, groupArgs := buildTypeSeverityGroupsClause(filter.TypeSeverityGroups, idx)
+			where = append(where, clause)
+			args = append(args, groupArgs...)
+			idx += len(groupArgs)
+		} else {
+			if len(filter.EventTypes) > 0 {
+				where = append(where, fmt.Sprintf("event_type = ANY($%d)", idx))
+				args = append(args, eventTypeDBValues(filter.EventTypes))
+				idx++
+			}
+			if len(filter.Severity) > 0 {
+				where = append(where, fmt.Sprintf("measurement_severity = ANY($%d)", idx))
+				args = append(args, severityDBValues(filter.Severity))
+				idx++
+			}
 		}
+		magWhere, magArgs, magIdx := buildGlobalMagnitudeClauses(filter, idx)
+		where = append(where, magWhere...)
+		args = append(args, magArgs...)
+		idx = magIdx
 		if filter.Near != nil {
 			geoWhere, geoArgs, geoIdx := buildGeoClause(filter.Near.Lat, filter.Near.Lon, filter.Near.RadiusMiles, idx)
 			where = append(where, geoWhere...)
@@ -88,10 +298,81 @@ func buildWhereClause(filter *model.StormReportFilter) ([]string, []any, int) {
 	return where, args, idx
 }
 
+// buildTypeSeverityGroupsClause ORs the groups together, each the AND of its
+// event type and severity lists. A group with neither list matches every
+// report.
+func buildTypeSeverityGroupsClause(groups []model.TypeSeverityGroup, idx int) (string, []any) {
+	var args []any
+	parts := make([]string, 0, len(groups))
+	for _, g := range groups {
+		var conds []string
+		if len(g.EventTypes) > 0 {
+			conds = append(conds, fmt.Sprintf("event_type = ANY($%d)", idx))
+			args = append(args, eventTypeDBValues(g.EventTypes))
+			idx++
+		}
+		if len(g.Severity) > 0 {
+			conds = append(conds, fmt.Sprintf("measurement_severity = ANY($%d)", idx))
+			args = append(args, severityDBValues(g.Severity))
+			idx++
+		}
+		if len(conds) == 0 {
+			conds = append(conds, "TRUE")
+		}
+		parts = append(parts, "("+strings.Join(conds, " AND ")+")")
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", args
+}
+
+// buildGlobalMagnitudeClauses returns the global minMagnitude/maxMagnitude
+// predicates. With a magnitudeUnit they are scoped to reports measured in
+// that unit, and reports in other units pass unfiltered.
+func buildGlobalMagnitudeClauses(filter *model.StormReportFilter, idx int) ([]string, []any, int) {
+	if filter.MinMagnitude == nil && filter.MaxMagnitude == nil {
+		return nil, nil, idx
+	}
+	var where []string
+	var args []any
+	guard := "%s"
+	if unit := globalMagnitudeUnit(filter); unit != "" {
+		guard = fmt.Sprintf("(measurement_unit <> $%d OR %%s)", idx)
+		args = append(args, unit)
+		idx++
+	}
+	if filter.MinMagnitude != nil {
+		where = append(where, fmt.Sprintf(guard, fmt.Sprintf("measurement_magnitude >= $%d", idx)))
+		args = append(args, *filter.MinMagnitude)
+		idx++
+	}
+	if filter.MaxMagnitude != nil {
+		where = append(where, fmt.Sprintf(guard, fmt.Sprintf("measurement_magnitude <= $%d", idx)))
+		args = append(args, *filter.MaxMagnitude)
+		idx++
+	}
+	return where, args, idx
+}
+
+// globalMagnitudeUnit returns the measurement_unit the global magnitude
+// bounds are scoped to, or "" when they apply to every report.
+func globalMagnitudeUnit(filter *model.StormReportFilter) string {
+	if filter.MagnitudeUnit == nil {
+		return ""
+	}
+	return filter.MagnitudeUnit.DBValue()
+}
+
+// inheritsGlobalMagnitude reports whether conditions for et fall back to the
+// global magnitude bounds, which a magnitudeUnit limits to types in that unit.
+func inheritsGlobalMagnitude(filter *model.StormReportFilter, et model.EventType) bool {
+	unit := globalMagnitudeUnit(filter)
+	return unit == "" || et.MagnitudeUnit().DBValue() == unit
+}
+
 type typeCondition struct {
 	eventType   model.EventType
 	severity    []model.Severity
 	minMag      *float64
+	maxMag      *float64
 	radiusMiles *float64
 }
 
@@ -112,11 +393,17 @@ func collectTypeConditions(filter *model.StormReportFilter) []typeCondition {
 		} else {
 			tc.severity = filter.Severity
 		}
+		inherit := inheritsGlobalMagnitude(filter, typeFilter.EventType)
 		if typeFilter.MinMagnitude != nil {
 			tc.minMag = typeFilter.MinMagnitude
-		} else {
+		} else if inherit {
 			tc.minMag = filter.MinMagnitude
 		}
+		if typeFilter.MaxMagnitude != nil {
+			tc.maxMag = typeFilter.MaxMagnitude
+		} else if inherit {
+			tc.maxMag = filter.MaxMagnitude
+		}
 		if typeFilter.RadiusMiles != nil {
 			tc.radiusMiles = typeFilter.RadiusMiles
 		} else if filter.Near != nil {
@@ -127,10 +414,9 @@ func collectTypeConditions(filter *model.StormReportFilter) []typeCondition {
 
 	for _, et := range filter.EventTypes {
 		if !overrideSet[et] {
-			tc := typeCondition{
-				eventType: et,
-				severity:  filter.Severity,
-				minMag:    filter.MinMagnitude,
+			tc := typeCondition{eventType: et, severity: filter.Severity}
+			if inheritsGlobalMagnitude(filter, et) {
+				tc.minMag, tc.maxMag = filter.MinMagnitude, filter.MaxMagnitude
 			}
 			if filter.Near != nil {
 				tc.radiusMiles = filter.Near.RadiusMiles
@@ -173,6 +459,11 @@ func buildSingleTypeCondition(tc typeCondition, near *model.GeoRadiusFilter, idx
 		args = append(args, *tc.minMag)
 		idx++
 	}
+	if tc.maxMag != nil {
+		parts = append(parts, fmt.Sprintf("measurement_magnitude <= $%d", idx))
+		args = append(args, *tc.maxMag)
+		idx++
+	}
 	if near != nil && tc.radiusMiles != nil {
 		hav := buildHaversine(near.Lat, near.Lon, *tc.radiusMiles, idx)
 		parts = append(parts, hav.clause)
@@ -244,8 +535,7 @@ func buildGeoClause(lat, lon float64, radiusMiles *float64, idx int) ([]string,
 // scale (~300 reports/day, single radius query), B-tree pre-filtering is
 // sufficient and avoids the PostGIS extension dependency.
 func buildBoundingBox(lat, lon, radiusMiles float64, idx int) ([]string, []any, int) {
-	latDelta := radiusMiles / milesPerDegreeLat
-	lonDelta := radiusMiles / (milesPerDegreeLat * math.Cos(lat*math.Pi/180.0))
+	latDelta, lonDelta := unitMiles.degreeDeltas(lat, radiusMiles)
 	clause := fmt.Sprintf(
 		"geo_lat BETWEEN $%d AND $%d AND geo_lon BETWEEN $%d AND $%d",
 		idx, idx+1, idx+2, idx+3)
@@ -261,18 +551,223 @@ type haversineResult struct {
 
 // buildHaversine builds a haversine great-circle distance clause.
 func buildHaversine(lat, lon, radiusMiles float64, idx int) haversineResult {
-	clause := fmt.Sprintf(`(
-		%v * acos(
-			cos(radians($%d)) * cos(radians(geo_lat)) *
-			cos(radians(geo_lon) - radians($%d)) +
-			sin(radians($%d)) * sin(radians(geo_lat))
-		)
-	) <= $%d`, earthRadiusMiles, idx, idx+1, idx+2, idx+3)
+	distance := unitMiles.haversine(fmt.Sprintf("$%d", idx), fmt.Sprintf("$%d", idx+1), "geo_lat", "geo_lon")
 	return haversineResult{
-		clause:  clause,
-		args:    []any{lat, lon, lat, radiusMiles},
-		nextIdx: idx + 4,
+		clause:  fmt.Sprintf("(%s) <= $%d", distance, idx+2),
+		args:    []any{lat, lon, radiusMiles},
+		nextIdx: idx + 3,
+	}
+}
+
+// buildPopulatedPlaceClause builds a correlated EXISTS subquery matching reports
+// within radiusMiles of any populated place with population >= minPopulation.
+// Like the radius filter, it avoids PostGIS: a latitude/longitude window narrows
+// candidate places (the longitude window widens with the report's latitude)
+// before the haversine distance is checked.
+func buildPopulatedPlaceClause(f *model.PopulatedPlaceFilter, idx int) (string, []any, int) {
+	clause := fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM populated_places p
+		WHERE p.population >= $%[1]d
+		AND p.lat BETWEEN geo_lat - $%[2]d AND geo_lat + $%[2]d
+		AND abs(p.lon - geo_lon) <= $%[3]d / (%[4]v * cos(radians(geo_lat)))
+		AND %[5]s <= $%[3]d
+	)`, idx, idx+1, idx+2, unitMiles.perDegreeLat, unitMiles.haversine("p.lat", "p.lon", "geo_lat", "geo_lon"))
+	latDelta, _ := unitMiles.degreeDeltas(0, f.RadiusMiles)
+	args := []any{f.MinPopulation, latDelta, f.RadiusMiles}
+	return clause, args, idx + 3
+}
+
+// buildStormTrackClause matches reports within radiusMiles of the track point
+// nearest their event time. The track is passed as parallel arrays and
+// unnested; a correlated subquery picks each report's time-nearest point
+// (the earlier on a tie) and returns its haversine distance. Reports outside
+// the track's time span are excluded first, so a track endpoint does not
+// capture reports from long before or after the storm.
+func buildStormTrackClause(f *model.StormTrackFilter, idx int) (string, []any, int) {
+	times := make([]time.Time, len(f.Points))
+	lats := make([]float64, len(f.Points))
+	lons := make([]float64, len(f.Points))
+	for i, p := range f.Points {
+		times[i], lats[i], lons[i] = p.Time, p.Lat, p.Lon
+	}
+	clause := fmt.Sprintf(`(event_time BETWEEN $%[1]d AND $%[2]d AND (
+		SELECT %[7]s
+		FROM unnest($%[3]d::timestamptz[], $%[4]d::float8[], $%[5]d::float8[]) AS t(at, lat, lon)
+		ORDER BY abs(extract(epoch FROM t.at - event_time)), t.at
+		LIMIT 1
+	) <= $%[6]d)`, idx, idx+1, idx+2, idx+3, idx+4, idx+5, unitMiles.haversine("t.lat", "t.lon", "geo_lat", "geo_lon"))
+	args := []any{times[0], times[len(times)-1], times, lats, lons, f.RadiusMiles}
+	return clause, args, idx + 6
+}
+
+// buildPolygonClause keeps reports inside a polygon using PostgreSQL's native
+// polygon containment operator (@>) on (lon, lat), the same test as
+// buildWarningClause. The ring is bound as a polygon literal at idx+4. The
+// vertices' bounding box (idx..idx+3) comes first so the (geo_lat, geo_lon)
+// B-tree index narrows the rows before containment is checked.
+func buildPolygonClause(ring []model.LatLon, idx int) (string, []any, int) {
+	minLat, maxLat := ring[0].Lat, ring[0].Lat
+	minLon, maxLon := ring[0].Lon, ring[0].Lon
+	var b strings.Builder
+	b.WriteByte('(')
+	for i, p := range ring {
+		minLat, maxLat = min(minLat, p.Lat), max(maxLat, p.Lat)
+		minLon, maxLon = min(minLon, p.Lon), max(maxLon, p.Lon)
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "(%s,%s)", strconv.FormatFloat(p.Lon, 'g', -1, 64), strconv.FormatFloat(p.Lat, 'g', -1, 64))
 	}
+	b.WriteByte(')')
+	clause := fmt.Sprintf(
+		"geo_lat BETWEEN $%d AND $%d AND geo_lon BETWEEN $%d AND $%d AND $%d::polygon @> point(geo_lon, geo_lat)",
+		idx, idx+1, idx+2, idx+3, idx+4)
+	return clause, []any{minLat, maxLat, minLon, maxLon, b.String()}, idx + 5
+}
+
+// buildWarningClause builds a correlated EXISTS subquery matching reports that
+// occurred inside a watch/warning polygon while the product was in effect.
+// The temporal overlap is checked against event_time and the spatial overlap
+// uses PostgreSQL's native polygon containment operator (@>) on (lon, lat).
+func buildWarningClause(f *model.WarningFilter, idx int) (string, []any, int) {
+	conds, args, idx := warningMatchConds(f, idx)
+	return "EXISTS (SELECT 1 FROM nws_warnings w WHERE " + strings.Join(conds, " AND ") + ")", args, idx
+}
+
+// buildUnwarnedClause is the anti-join counterpart of buildWarningClause: it
+// keeps reports that no selected product covered in space and time. With no
+// id or types, every product in nws_warnings counts.
+func buildUnwarnedClause(f *model.WarningFilter, idx int) (string, []any, int) {
+	conds, args, idx := warningMatchConds(f, idx)
+	return "NOT EXISTS (SELECT 1 FROM nws_warnings w WHERE " + strings.Join(conds, " AND ") + ")", args, idx
+}
+
+// warningTriggerWindow is how soon after a report a product covering its
+// location must be issued for the report to count as having triggered it.
+const warningTriggerWindow = "60 minutes"
+
+// buildTriggeredWarningClause keeps reports that preceded a watch/warning
+// (triggered=true) or that no product followed (false). A report triggered a
+// product when the product's polygon contains it and it was issued after the
+// report, within warningTriggerWindow. This uses no parameters.
+func buildTriggeredWarningClause(triggered bool) string {
+	exists := "EXISTS"
+	if !triggered {
+		exists = "NOT EXISTS"
+	}
+	return exists + ` (SELECT 1 FROM nws_warnings w
+		WHERE w.area @> point(geo_lon, geo_lat)
+		AND w.issued_at > event_time
+		AND w.issued_at <= event_time + interval '` + warningTriggerWindow + `')`
+}
+
+// warningMatchConds returns the conditions relating a report to a product w:
+// temporal overlap, spatial containment, and the optional product selectors.
+func warningMatchConds(f *model.WarningFilter, idx int) ([]string, []any, int) {
+	conds := []string{
+		"event_time BETWEEN w.issued_at AND w.expires_at",
+		"w.area @> point(geo_lon, geo_lat)",
+	}
+	var args []any
+	if f.ID != nil {
+		conds = append(conds, fmt.Sprintf("w.id = $%d", idx))
+		args = append(args, *f.ID)
+		idx++
+	}
+	if len(f.Types) > 0 {
+		conds = append(conds, fmt.Sprintf("w.warning_type = ANY($%d)", idx))
+		args = append(args, f.Types)
+		idx++
+	}
+	return conds, args, idx
+}
+
+// buildCountyClause matches the exact county names and the county name prefix
+// (case-insensitive), OR-ed in one parenthesized group when both are set.
+// Parameters are bound from idx; it returns "" when neither is set.
+func buildCountyClause(filter *model.StormReportFilter, idx int) (string, []any) {
+	var conds []string
+	var args []any
+	if len(filter.Counties) > 0 {
+		conds = append(conds, fmt.Sprintf("location_county = ANY($%d)", idx))
+		args = append(args, filter.Counties)
+		idx++
+	}
+	if filter.CountyPrefix != nil && *filter.CountyPrefix != "" {
+		conds = append(conds, fmt.Sprintf(`location_county ILIKE $%d || '%%' ESCAPE '\'`, idx))
+		args = append(args, escapeLike(*filter.CountyPrefix))
+	}
+	switch len(conds) {
+	case 0:
+		return "", nil
+	case 1:
+		return conds[0], args
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// buildKeywordClause matches a keyword against the county (substring, with
+// the LIKE pattern bound at idx) OR the comments (full-text, with the raw term
+// bound at idx+1). plainto_tsquery treats the term as plain words, so user
+// input cannot inject tsquery operators. The tsvector expression matches
+// idx_comments_fts.
+func buildKeywordClause(idx int) string {
+	return fmt.Sprintf(`(location_county ILIKE $%d ESCAPE '\' OR to_tsvector('english', comments) @@ plainto_tsquery('english', $%d))`, idx, idx+1)
+}
+
+// countyOutlierMinReports is the smallest sample a county's norm is computed
+// from; with fewer reports the standard deviation says little.
+const countyOutlierMinReports = 3
+
+// buildCountyOutlierClause keeps reports whose magnitude exceeds their
+// county's norm by more than k standard deviations (k bound at idx). The norm
+// is the mean and sample standard deviation over every non-superseded report
+// of the same event type in the same state and county, regardless of the
+// other filters. It is computed in a correlated subquery, so the outer row's
+// columns are qualified with the table name. Counties below
+// countyOutlierMinReports yield no row, the comparison is NULL, and their
+// reports are excluded.
+func buildCountyOutlierClause(idx int) string {
+	return fmt.Sprintf(`measurement_magnitude > (
+		SELECT AVG(o.measurement_magnitude) + $%d * STDDEV_SAMP(o.measurement_magnitude)
+		FROM storm_reports o
+		WHERE o.event_type = storm_reports.event_type
+			AND o.location_state = storm_reports.location_state
+			AND o.location_county = storm_reports.location_county
+			AND o.correction_status <> '%s'
+		HAVING COUNT(*) >= %d)`, idx, model.CorrectionStatusSuperseded, countyOutlierMinReports)
+}
+
+// buildRemarksClause matches the comments against one tsquery that ANDs
+// (&&) every include phrase and the negation (!!) of every exclude phrase.
+// Phrases are bound starting at idx, includes first. Each is parsed with
+// phraseto_tsquery, so "golf ball" must appear as adjacent words and user
+// input cannot inject tsquery operators. The tsvector expression matches
+// idx_comments_fts.
+func buildRemarksClause(idx, include, exclude int) string {
+	terms := make([]string, 0, include+exclude)
+	for i := range include + exclude {
+		term := fmt.Sprintf("phraseto_tsquery('english', $%d)", idx+i)
+		if i >= include {
+			term = "!!" + term
+		}
+		terms = append(terms, term)
+	}
+	return fmt.Sprintf("to_tsvector('english', comments) @@ (%s)", strings.Join(terms, " && "))
+}
+
+// stringArgs widens a string slice to query arguments.
+func stringArgs(vals []string) []any {
+	args := make([]any, len(vals))
+	for i, v := range vals {
+		args[i] = v
+	}
+	return args
+}
+
+// escapeLike escapes LIKE wildcards so a term matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
 
 // eventTypeDBValues converts a slice of EventType enums to their lowercase DB values.
@@ -293,6 +788,51 @@ func severityDBValues(sevs []model.Severity) []string {
 	return vals
 }
 
+// timeColumn maps the filter's validated TimeColumn to the SQL column the time
+// range and default sort apply to.
+func timeColumn(filter *model.StormReportFilter) string {
+	if filter.TimeColumn != nil && *filter.TimeColumn == model.TimeColumnProcessedAt {
+		return "processed_at"
+	}
+	return "event_time"
+}
+
+// normalizedMagnitudeExpr ranks each report's magnitude against the other
+// matching reports of the same type: 0 is the type's smallest, 1 its largest.
+// It is a window function, so it is evaluated over the filtered rows before
+// LIMIT/OFFSET and is only valid in the SELECT list or ORDER BY.
+const normalizedMagnitudeExpr = "percent_rank() OVER (PARTITION BY event_type ORDER BY measurement_magnitude)"
+
+// severityOrder lists the severity levels from lowest to highest rank.
+var severityOrder = []model.Severity{
+	model.SeverityMinor, model.SeverityModerate, model.SeveritySevere, model.SeverityExtreme,
+}
+
+// severityRankExpr ranks measurement_severity by severityOrder, starting at 1.
+// Reports without a recognised severity rank 0, below MINOR.
+var severityRankExpr = func() string {
+	var b strings.Builder
+	b.WriteString("CASE measurement_severity")
+	for i, s := range severityOrder {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", s.DBValue(), i+1)
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}()
+
+// severityRank is the Go form of severityRankExpr for a stored severity.
+func severityRank(dbValue *string) int {
+	if dbValue == nil {
+		return 0
+	}
+	for i, s := range severityOrder {
+		if s.DBValue() == *dbValue {
+			return i + 1
+		}
+	}
+	return 0
+}
+
 // sortColumn maps validated SortField enum values to SQL column names.
 func sortColumn(sf model.SortField) string {
 	switch sf {
@@ -304,7 +844,42 @@ func sortColumn(sf model.SortField) string {
 		return "location_state"
 	case model.SortFieldEventType:
 		return "event_type"
+	case model.SortFieldSeverity:
+		return severityRankExpr
+	case model.SortFieldMagnitudeNormalized:
+		return normalizedMagnitudeExpr
 	default:
 		return "event_time"
 	}
 }
+
+// severityScoreExpr returns the SQL form of SeverityWeights.Score. The factors
+// come from server config, not clients, and are formatted as numeric literals.
+func severityScoreExpr(w model.SeverityWeights) string {
+	factor := func(t model.EventType) string {
+		return strconv.FormatFloat(w.Factor(t.DBValue()), 'g', -1, 64)
+	}
+	return fmt.Sprintf("(CASE event_type WHEN 'hail' THEN %s WHEN 'wind' THEN %s WHEN 'tornado' THEN %s ELSE 0 END * measurement_magnitude)",
+		factor(model.EventTypeHail), factor(model.EventTypeWind), factor(model.EventTypeTornado))
+}
+
+// buildOrderAndPage builds the ORDER BY, LIMIT, and OFFSET suffix for a data
+// query, continuing parameter numbering from idx. Returns the SQL fragment and
+// the pagination args to append after the WHERE args. id breaks ties so the
+// order is total, which keyset pagination relies on.
+func buildOrderAndPage(filter *model.StormReportFilter, idx int) (string, []any) {
+	orderCol, orderDir := orderBy(filter)
+
+	var args []any
+	sql := fmt.Sprintf(" ORDER BY %s %s, id %s", orderCol, orderDir, orderDir)
+	if filter.Limit != nil {
+		sql += fmt.Sprintf(" LIMIT $%d", idx)
+		args = append(args, *filter.Limit)
+		idx++
+	}
+	if filter.Offset != nil {
+		sql += fmt.Sprintf(" OFFSET $%d", idx)
+		args = append(args, *filter.Offset)
+	}
+	return sql, args
+}
@@ -1,11 +1,14 @@
 package store
 
 import (
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/couchcryptid/storm-data-api/internal/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildWhereClause_TimeOnly(t *testing.T) {
@@ -51,19 +54,157 @@ func TestBuildWhereClause_AllSimpleFilters(t *testing.T) {
 			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
 			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
 		},
-		EventTypes:   []model.EventType{model.EventTypeHail},
-		Severity:     []model.Severity{model.SeveritySevere},
-		States:       []string{"TX", "OK"},
-		Counties:     []string{"Dallas"},
-		MinMagnitude: &mag,
+		EventTypes:    []model.EventType{model.EventTypeHail},
+		Severity:      []model.Severity{model.SeveritySevere},
+		States:        []string{"TX", "OK"},
+		Counties:      []string{"Dallas"},
+		Offices:       []string{"FWD", "OUN"},
+		SpotterLevels: []string{"trained spotter"},
+		MinMagnitude:  &mag,
 	}
 
 	where, args, nextIdx := buildWhereClause(filter)
 
-	// 2 time + states + counties + eventTypes + severity + minMagnitude = 7
-	assert.Len(t, where, 7)
-	assert.Len(t, args, 7)
-	assert.Equal(t, 8, nextIdx)
+	// 2 time + states + counties + offices + spotterLevels + eventTypes + severity + minMagnitude = 9
+	assert.Len(t, where, 9)
+	assert.Equal(t, "source_office = ANY($5)", where[4])
+	assert.Equal(t, []string{"FWD", "OUN"}, args[4])
+	assert.Contains(t, where[5], "spotter_level = ANY($6)")
+	assert.Equal(t, []string{"trained spotter"}, args[5])
+	assert.Len(t, args, 9)
+	assert.Equal(t, 10, nextIdx)
+}
+
+func TestBuildWhereClause_EmptyOffices(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+	for _, offices := range [][]string{nil, {}} {
+		where, _, nextIdx := buildWhereClause(&model.StormReportFilter{TimeRange: tr, Offices: offices})
+		assert.Len(t, where, 2, "no office clause")
+		assert.Equal(t, 3, nextIdx)
+	}
+}
+
+func TestBuildWhereClause_MagnitudeRange(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+	minMag, maxMag := 1.0, 2.0
+
+	t.Run("min and max", func(t *testing.T) {
+		where, args, nextIdx := buildWhereClause(&model.StormReportFilter{
+			TimeRange:    tr,
+			EventTypes:   []model.EventType{model.EventTypeHail},
+			MinMagnitude: &minMag,
+			MaxMagnitude: &maxMag,
+		})
+
+		// 2 time + eventTypes + minMagnitude + maxMagnitude, as separate clauses
+		require.Len(t, where, 5)
+		assert.Equal(t, "measurement_magnitude >= $4", where[3])
+		assert.Equal(t, "measurement_magnitude <= $5", where[4])
+		assert.Equal(t, []any{1.0, 2.0}, args[3:])
+		assert.Equal(t, 6, nextIdx)
+	})
+
+	t.Run("max only", func(t *testing.T) {
+		where, args, nextIdx := buildWhereClause(&model.StormReportFilter{TimeRange: tr, MaxMagnitude: &maxMag})
+
+		require.Len(t, where, 3)
+		assert.Equal(t, "measurement_magnitude <= $3", where[2])
+		assert.Equal(t, 2.0, args[2])
+		assert.Equal(t, 4, nextIdx)
+	})
+
+	t.Run("nil max adds no clause", func(t *testing.T) {
+		where, _, _ := buildWhereClause(&model.StormReportFilter{TimeRange: tr, MinMagnitude: &minMag})
+
+		require.Len(t, where, 3)
+		assert.NotContains(t, buildWhereSQL(where), "measurement_magnitude <=")
+	})
+
+	t.Run("per-type fallback", func(t *testing.T) {
+		where, args, _ := buildWhereClause(&model.StormReportFilter{
+			TimeRange:        tr,
+			MaxMagnitude:     &maxMag,
+			EventTypeFilters: []*model.EventTypeFilter{{EventType: model.EventTypeHail}},
+		})
+
+		assert.Equal(t, "((event_type = $3 AND measurement_magnitude <= $4))", where[2])
+		assert.Equal(t, 2.0, args[3])
+	})
+
+	t.Run("unit scopes both bounds", func(t *testing.T) {
+		inches := model.MagnitudeUnitInches
+		where, args, nextIdx := buildWhereClause(&model.StormReportFilter{
+			TimeRange:     tr,
+			EventTypes:    []model.EventType{model.EventTypeHail, model.EventTypeWind},
+			MinMagnitude:  &minMag,
+			MaxMagnitude:  &maxMag,
+			MagnitudeUnit: &inches,
+		})
+
+		require.Len(t, where, 5)
+		assert.Equal(t, "(measurement_unit <> $4 OR measurement_magnitude >= $5)", where[3])
+		assert.Equal(t, "(measurement_unit <> $4 OR measurement_magnitude <= $6)", where[4])
+		assert.Equal(t, []any{"in", 1.0, 2.0}, args[3:])
+		assert.Equal(t, 7, nextIdx)
+	})
+
+	t.Run("unit without a bound adds nothing", func(t *testing.T) {
+		mph := model.MagnitudeUnitMph
+		where, args, _ := buildWhereClause(&model.StormReportFilter{TimeRange: tr, MagnitudeUnit: &mph})
+
+		assert.Len(t, where, 2)
+		assert.Len(t, args, 2)
+	})
+
+	t.Run("unspecified unit is unscoped", func(t *testing.T) {
+		unspecified := model.MagnitudeUnitUnspecified
+		where, _, _ := buildWhereClause(&model.StormReportFilter{TimeRange: tr, MinMagnitude: &minMag, MagnitudeUnit: &unspecified})
+
+		assert.Equal(t, "measurement_magnitude >= $3", where[2])
+	})
+
+	t.Run("per-type fallback limited to the unit", func(t *testing.T) {
+		mph := model.MagnitudeUnitMph
+		wind := 58.0
+		where, args, _ := buildWhereClause(&model.StormReportFilter{
+			TimeRange:     tr,
+			MinMagnitude:  &wind,
+			MagnitudeUnit: &mph,
+			EventTypes:    []model.EventType{model.EventTypeHail, model.EventTypeWind},
+			EventTypeFilters: []*model.EventTypeFilter{
+				{EventType: model.EventTypeTornado},
+			},
+		})
+
+		// Tornado (f_scale) and hail (in) do not inherit the mph bound.
+		assert.Equal(t, "((event_type = $3) OR (event_type = $4) OR (event_type = $5 AND measurement_magnitude >= $6))", where[2])
+		assert.Equal(t, []any{"tornado", "hail", "wind", 58.0}, args[2:])
+	})
+}
+
+func TestBuildWhereClause_MaxLocationUncertainty(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+	maxUncertainty := 500.0
+
+	where, args, nextIdx := buildWhereClause(&model.StormReportFilter{TimeRange: tr, MaxLocationUncertainty: &maxUncertainty})
+	require.Len(t, where, 3)
+	// A plain comparison: NULL uncertainty is unknown, so those rows drop out.
+	assert.Equal(t, "location_uncertainty_m <= $3", where[2])
+	assert.NotContains(t, where[2], "IS NULL")
+	assert.Equal(t, 500.0, args[2])
+	assert.Equal(t, 4, nextIdx)
+
+	where, _, _ = buildWhereClause(&model.StormReportFilter{TimeRange: tr})
+	assert.NotContains(t, buildWhereSQL(where), "location_uncertainty_m")
 }
 
 func TestBuildWhereClause_NearRadiusFilter(t *testing.T) {
@@ -82,11 +223,276 @@ func TestBuildWhereClause_NearRadiusFilter(t *testing.T) {
 
 	where, args, nextIdx := buildWhereClause(filter)
 
-	// 2 time + bounding box (1 clause, 4 params) + haversine (1 clause, 4 params) = 4 clauses
+	// 2 time + bounding box (1 clause, 4 params) + haversine (1 clause, 3 params) = 4 clauses
 	assert.Len(t, where, 4)
-	// 2 time args + 4 bbox args + 4 haversine args = 10
-	assert.Len(t, args, 10)
-	assert.Equal(t, 11, nextIdx)
+	// 2 time args + 4 bbox args + 3 haversine args = 9
+	assert.Len(t, args, 9)
+	assert.Equal(t, 10, nextIdx)
+	assert.Equal(t, []any{32.7767, -96.7970, radius}, args[6:])
+}
+
+func TestBuildWhereClause_BBoxFilter(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+		BBox:   &model.BoundingBoxFilter{MinLat: 32, MaxLat: 34, MinLon: -98, MaxLon: -96},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + states + bounding box (1 clause, 4 params) = 4 clauses
+	require.Len(t, where, 4)
+	assert.Equal(t, "geo_lat BETWEEN $4 AND $5 AND geo_lon BETWEEN $6 AND $7", where[3])
+	// 2 time args + 1 states arg + 4 bbox args = 7
+	assert.Len(t, args, 7)
+	assert.Equal(t, []any{32.0, 34.0, -98.0, -96.0}, args[3:])
+	assert.Equal(t, 8, nextIdx)
+}
+
+func TestBuildWhereClause_AtCoordinate(t *testing.T) {
+	lat, lon := 35.2271, -97.4395
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"OK"},
+		AtLat:  &lat,
+		AtLon:  &lon,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	require.Len(t, where, 4)
+	assert.Equal(t, "geo_lat > $4 AND geo_lat < $5 AND geo_lon > $6 AND geo_lon < $7", where[3])
+	require.Len(t, args, 7)
+	assert.InDelta(t, lat-coordinateTolerance, args[3], 1e-9)
+	assert.InDelta(t, lat+coordinateTolerance, args[4], 1e-9)
+	assert.InDelta(t, lon-coordinateTolerance, args[5], 1e-9)
+	assert.InDelta(t, lon+coordinateTolerance, args[6], 1e-9)
+	assert.Equal(t, 8, nextIdx)
+}
+
+func TestBuildWhereClause_AtCoordinateNeedsBoth(t *testing.T) {
+	lat := 35.2271
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		AtLat: &lat,
+	}
+
+	where, _, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 2, "a lone atLat (rejected by validation) adds no clause")
+	assert.Equal(t, 3, nextIdx)
+}
+
+func TestBuildWhereClause_Geohash(t *testing.T) {
+	hash := "9q8yy"
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		Geohash: &hash,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	require.Len(t, where, 3)
+	assert.Equal(t, "geo_lat BETWEEN $3 AND $4 AND geo_lon BETWEEN $5 AND $6", where[2])
+	assert.Equal(t, []any{37.7490234375, 37.79296875, -122.431640625, -122.3876953125}, args[2:])
+	assert.Equal(t, 7, nextIdx)
+}
+
+func TestDegreeDeltas_MidLatitudeWidensLongitude(t *testing.T) {
+	latDelta, lonDelta := unitMiles.degreeDeltas(45, 50)
+	assert.InDelta(t, 50/milesPerDegreeLat, latDelta, 1e-12, "radius over the unit's degree length")
+	assert.Greater(t, lonDelta, latDelta, "a degree of longitude is shorter at 45°")
+	assert.InDelta(t, latDelta/math.Cos(45*math.Pi/180), lonDelta, 1e-12)
+
+	// Equal at the equator; the whole longitude range at a pole.
+	latDelta, lonDelta = unitMiles.degreeDeltas(0, 50)
+	assert.InDelta(t, latDelta, lonDelta, 1e-12)
+	_, lonDelta = unitMiles.degreeDeltas(90, 50)
+	assert.InDelta(t, 180.0, lonDelta, 0)
+}
+
+func TestDistanceUnitHaversine(t *testing.T) {
+	expr := distanceUnit{earthRadius: 6371, perDegreeLat: 111}.haversine("$1", "$2", "geo_lat", "geo_lon")
+
+	assert.True(t, strings.HasPrefix(expr, "6371 * acos(least(1.0,"), expr)
+	assert.Contains(t, expr, "cos(radians($1)) * cos(radians(geo_lat))")
+	assert.Contains(t, expr, "cos(radians(geo_lon) - radians($2))")
+	assert.Contains(t, expr, "sin(radians($1)) * sin(radians(geo_lat))")
+}
+
+func TestBuildBoundingBox_UsesLongitudeCorrection(t *testing.T) {
+	_, args, _ := buildBoundingBox(45, -100, 69, 1)
+
+	require.Len(t, args, 4)
+	assert.InDelta(t, 44.0, args[0], 1e-9)
+	assert.InDelta(t, 46.0, args[1], 1e-9)
+	lonDelta := 1 / math.Cos(45*math.Pi/180)
+	assert.InDelta(t, -100-lonDelta, args[2], 1e-9)
+	assert.InDelta(t, -100+lonDelta, args[3], 1e-9)
+}
+
+func TestBuildWhereClause_BBoxAndNearIntersection(t *testing.T) {
+	radius := 50.0
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		BBox: &model.BoundingBoxFilter{MinLat: 32, MaxLat: 34, MinLon: -98, MaxLon: -96},
+		Near: &model.GeoRadiusFilter{Lat: 32.7767, Lon: -96.7970, RadiusMiles: &radius},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + bbox + near bounding box + haversine = 5 clauses, all AND-ed
+	assert.Len(t, where, 5)
+	assert.Equal(t, "geo_lat BETWEEN $3 AND $4 AND geo_lon BETWEEN $5 AND $6", where[2])
+	assert.Equal(t, []any{32.0, 34.0, -98.0, -96.0}, args[2:6])
+	assert.Contains(t, where[3], "geo_lat BETWEEN $7 AND $8", "near pre-filter follows the bbox")
+	assert.Contains(t, where[4], "acos(", "haversine refinement still applies")
+	assert.Contains(t, buildWhereSQL(where), where[2]+" AND "+where[3])
+	// 2 time + 4 bbox + 4 near bbox + 3 haversine = 13
+	assert.Len(t, args, 13)
+	assert.Equal(t, 14, nextIdx)
+}
+
+func TestBuildWhereClause_StormTrack(t *testing.T) {
+	t0 := time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC)
+	track := &model.StormTrackFilter{
+		Points: []*model.TrackPoint{
+			{Time: t0, Lat: 35.0, Lon: -98.0},
+			{Time: t0.Add(30 * time.Minute), Lat: 35.2, Lon: -97.6},
+			{Time: t0.Add(time.Hour), Lat: 35.4, Lon: -97.2},
+		},
+		RadiusMiles: 10,
+	}
+	filter := &model.StormReportFilter{
+		TimeRange:  model.TimeRange{From: t0.Add(-time.Hour), To: t0.Add(2 * time.Hour)},
+		StormTrack: track,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + track (1 clause, 6 params)
+	require.Len(t, where, 3)
+	clause := where[2]
+	assert.Contains(t, clause, "event_time BETWEEN $3 AND $4", "outside the track's span is excluded")
+	assert.Contains(t, clause, "unnest($5::timestamptz[], $6::float8[], $7::float8[])")
+	assert.Contains(t, clause, "ORDER BY abs(extract(epoch FROM t.at - event_time)), t.at\n\t\tLIMIT 1", "time-nearest point, earlier on a tie")
+	assert.True(t, strings.HasSuffix(clause, "<= $8)"))
+
+	require.Len(t, args, 8)
+	assert.Equal(t, t0, args[2])
+	assert.Equal(t, t0.Add(time.Hour), args[3])
+	assert.Equal(t, []time.Time{t0, t0.Add(30 * time.Minute), t0.Add(time.Hour)}, args[4])
+	assert.Equal(t, []float64{35.0, 35.2, 35.4}, args[5])
+	assert.Equal(t, []float64{-98.0, -97.6, -97.2}, args[6])
+	assert.Equal(t, 10.0, args[7])
+	assert.Equal(t, 9, nextIdx)
+}
+
+func TestBuildWhereClause_Measured(t *testing.T) {
+	tests := []struct {
+		measured bool
+		want     string
+	}{
+		{true, "measurement_method = 'measured'"},
+		{false, "measurement_method IS DISTINCT FROM 'measured'"},
+	}
+	for _, tt := range tests {
+		filter := &model.StormReportFilter{
+			TimeRange: model.TimeRange{
+				From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+			},
+			Measured: &tt.measured,
+		}
+		where, args, nextIdx := buildWhereClause(filter)
+		assert.Len(t, where, 3)
+		assert.Equal(t, tt.want, where[2])
+		assert.Len(t, args, 2)
+		assert.Equal(t, 3, nextIdx)
+	}
+}
+
+func TestBuildWhereClause_CorrectionStatus(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		CorrectionStatus: []string{"original", "corrected"},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 3)
+	assert.Equal(t, "correction_status = ANY($3)", where[2])
+	assert.Equal(t, []string{"original", "corrected"}, args[2])
+	assert.Equal(t, 4, nextIdx)
+
+	filter.CorrectionStatus = nil
+	where, _, _ = buildWhereClause(filter)
+	assert.Len(t, where, 2, "no clause without statuses")
+}
+
+func TestBuildWhereClause_MeasurementMethods(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		MeasurementMethods: []string{"measured", "estimated"},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 3)
+	assert.Equal(t, "measurement_method = ANY($3)", where[2])
+	assert.Equal(t, []string{"measured", "estimated"}, args[2])
+	assert.Equal(t, 4, nextIdx)
+}
+
+func TestBuildWhereClause_DayNight(t *testing.T) {
+	tests := []struct {
+		value model.DayNight
+		want  string
+	}{
+		{model.DayNightDay, "solar_elevation(geo_lat, geo_lon, event_time) > -0.833"},
+		{model.DayNightNight, "solar_elevation(geo_lat, geo_lon, event_time) <= -0.833"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.value), func(t *testing.T) {
+			filter := &model.StormReportFilter{
+				TimeRange: model.TimeRange{
+					From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+					To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+				},
+				DayNight: &tt.value,
+			}
+			where, args, nextIdx := buildWhereClause(filter)
+			assert.Len(t, where, 3)
+			assert.Equal(t, tt.want, where[2])
+			assert.Len(t, args, 2, "threshold is a constant, not a parameter")
+			assert.Equal(t, 3, nextIdx)
+		})
+	}
+
+	invalid := model.DayNight("DUSK")
+	where, _, _ := buildWhereClause(&model.StormReportFilter{DayNight: &invalid})
+	assert.Len(t, where, 2, "invalid values are ignored")
 }
 
 func TestBuildWhereClause_EventTypeFilters(t *testing.T) {
@@ -119,9 +525,39 @@ func TestBuildWhereClause_EventTypeFilters(t *testing.T) {
 	assert.Contains(t, orClause, "OR")
 
 	// Verify args count:
-	// 2 time + 4 bbox + (hail: type + minMag + 4 haversine) + (tornado: type + 4 haversine) = 2+4+6+5 = 17
-	assert.Len(t, args, 17)
-	assert.Equal(t, 18, nextIdx)
+	// 2 time + 4 bbox + (hail: type + minMag + 3 haversine) + (tornado: type + 3 haversine) = 2+4+5+4 = 15
+	assert.Len(t, args, 15)
+	assert.Equal(t, 16, nextIdx)
+}
+
+func TestBuildWhereClause_EventTypeFiltersMagnitudeRanges(t *testing.T) {
+	hailMin, hailMax := 1.0, 2.0
+	windMin := 58.0
+	torMin, torMax := 1.0, 3.0
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+		EventTypeFilters: []*model.EventTypeFilter{
+			{EventType: model.EventTypeHail, MinMagnitude: &hailMin, MaxMagnitude: &hailMax},
+			{EventType: model.EventTypeWind, MinMagnitude: &windMin},
+			{EventType: model.EventTypeTornado, MinMagnitude: &torMin, MaxMagnitude: &torMax},
+		},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + states + one OR group
+	require.Len(t, where, 4)
+	assert.Equal(t,
+		"((event_type = $4 AND measurement_magnitude >= $5 AND measurement_magnitude <= $6)"+
+			" OR (event_type = $7 AND measurement_magnitude >= $8)"+
+			" OR (event_type = $9 AND measurement_magnitude >= $10 AND measurement_magnitude <= $11))",
+		where[3])
+	assert.Equal(t, []any{"hail", 1.0, 2.0, "wind", 58.0, "tornado", 1.0, 3.0}, args[3:])
+	assert.Equal(t, 12, nextIdx)
 }
 
 func TestBuildWhereClause_EventTypeFiltersWithGlobalDefaults(t *testing.T) {
@@ -150,6 +586,426 @@ func TestBuildWhereClause_EventTypeFiltersWithGlobalDefaults(t *testing.T) {
 	assert.Contains(t, orClause, "OR")
 }
 
+func TestBuildWhereClause_NearPopulatedPlace(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States:             []string{"NE"},
+		NearPopulatedPlace: &model.PopulatedPlaceFilter{MinPopulation: 50000, RadiusMiles: 10},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + states + places EXISTS = 4
+	assert.Len(t, where, 4)
+	clause := where[3]
+	assert.Contains(t, clause, "EXISTS (")
+	assert.Contains(t, clause, "FROM populated_places p")
+	assert.Contains(t, clause, "p.population >= $4")
+	assert.Contains(t, clause, "p.lat BETWEEN geo_lat - $5 AND geo_lat + $5")
+	assert.Contains(t, clause, "<= $6")
+	// 2 time + states + (population, lat delta, radius) = 6
+	assert.Len(t, args, 6)
+	assert.Equal(t, 50000, args[3])
+	assert.InDelta(t, 10.0/milesPerDegreeLat, args[4], 1e-9)
+	assert.InDelta(t, 10.0, args[5], 1e-9)
+	assert.Equal(t, 7, nextIdx)
+}
+
+func TestBuildWhereClause_Warning(t *testing.T) {
+	id := "KOUN.TO.W.0042"
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		Warning: &model.WarningFilter{ID: &id, Types: []string{"TO.W", "SV.W"}},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + warning EXISTS = 3
+	assert.Len(t, where, 3)
+	clause := where[2]
+	assert.Contains(t, clause, "FROM nws_warnings w")
+	assert.Contains(t, clause, "event_time BETWEEN w.issued_at AND w.expires_at", "temporal overlap")
+	assert.Contains(t, clause, "w.area @> point(geo_lon, geo_lat)", "spatial containment")
+	assert.Contains(t, clause, "w.id = $3")
+	assert.Contains(t, clause, "w.warning_type = ANY($4)")
+	assert.Len(t, args, 4)
+	assert.Equal(t, id, args[2])
+	assert.Equal(t, []string{"TO.W", "SV.W"}, args[3])
+	assert.Equal(t, 5, nextIdx)
+}
+
+func TestBuildWhereClause_WarningTypesOnly(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		Warning: &model.WarningFilter{Types: []string{"TO.A"}},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 3)
+	assert.NotContains(t, where[2], "w.id")
+	assert.Contains(t, where[2], "w.warning_type = ANY($3)")
+	assert.Len(t, args, 3)
+	assert.Equal(t, 4, nextIdx)
+}
+
+func TestBuildWhereClause_TriggeredWarning(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		triggered bool
+		prefix    string
+	}{
+		{"triggered", true, "EXISTS ("},
+		{"not triggered", false, "NOT EXISTS ("},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &model.StormReportFilter{TimeRange: tr, TriggeredWarning: &tt.triggered}
+
+			where, args, nextIdx := buildWhereClause(filter)
+
+			require.Len(t, where, 3)
+			clause := where[2]
+			assert.True(t, strings.HasPrefix(clause, tt.prefix), clause)
+			assert.Contains(t, clause, "FROM nws_warnings w")
+			assert.Contains(t, clause, "w.area @> point(geo_lon, geo_lat)", "spatial containment")
+			assert.Contains(t, clause, "w.issued_at > event_time", "issued after the report")
+			assert.Contains(t, clause, "w.issued_at <= event_time + interval '60 minutes'", "within the trigger window")
+			assert.Len(t, args, 2, "no extra parameters")
+			assert.Equal(t, 3, nextIdx)
+		})
+	}
+}
+
+func TestBuildWhereClause_UnwarnedOnly(t *testing.T) {
+	unwarned := true
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		Warning: &model.WarningFilter{Types: []string{"TO.W"}, UnwarnedOnly: &unwarned},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 3)
+	clause := where[2]
+	assert.Contains(t, clause, "NOT EXISTS (SELECT 1 FROM nws_warnings w", "anti-join")
+	assert.Contains(t, clause, "event_time BETWEEN w.issued_at AND w.expires_at")
+	assert.Contains(t, clause, "w.area @> point(geo_lon, geo_lat)")
+	assert.Contains(t, clause, "w.warning_type = ANY($3)")
+	assert.Len(t, args, 3)
+	assert.Equal(t, 4, nextIdx)
+}
+
+func TestBuildWhereClause_UnwarnedOnlyAnyProduct(t *testing.T) {
+	unwarned := true
+	filter := &model.StormReportFilter{
+		Warning: &model.WarningFilter{UnwarnedOnly: &unwarned},
+	}
+
+	where, _, _ := buildWhereClause(filter)
+
+	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM nws_warnings w WHERE "+
+		"event_time BETWEEN w.issued_at AND w.expires_at AND w.area @> point(geo_lon, geo_lat))", where[len(where)-1])
+}
+
+func TestBuildWhereClause_KeywordSearch(t *testing.T) {
+	kw := "hail"
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States:        []string{"TX"},
+		KeywordSearch: &kw,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + states + one grouped keyword clause
+	assert.Len(t, where, 4)
+	assert.Equal(t, `(location_county ILIKE $4 ESCAPE '\' OR `+
+		`to_tsvector('english', comments) @@ plainto_tsquery('english', $5))`, where[3],
+		"OR is parenthesized so it ANDs with the other filters")
+	assert.Len(t, args, 5)
+	assert.Equal(t, "%hail%", args[3], "substring pattern for the county")
+	assert.Equal(t, "hail", args[4], "raw term for full-text")
+	assert.Equal(t, 6, nextIdx)
+	assert.Contains(t, buildWhereSQL(where), "location_state = ANY($3) AND (location_county ILIKE $4")
+}
+
+func TestBuildWhereClause_CountyPrefix(t *testing.T) {
+	prefix := "Dall_"
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		CountyPrefix: &prefix,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 3)
+	assert.Equal(t, `location_county ILIKE $3 || '%' ESCAPE '\'`, where[2])
+	assert.Len(t, args, 3)
+	assert.Equal(t, `Dall\_`, args[2], "LIKE wildcards in the prefix are escaped")
+	assert.Equal(t, 4, nextIdx)
+}
+
+func TestBuildWhereClause_CountiesAndPrefix(t *testing.T) {
+	prefix := "dall"
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States:       []string{"TX"},
+		Counties:     []string{"Tarrant", "Collin"},
+		CountyPrefix: &prefix,
+		Offices:      []string{"FWD"},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + states + one grouped county clause + offices
+	assert.Len(t, where, 5)
+	assert.Equal(t, `(location_county = ANY($4) OR location_county ILIKE $5 || '%' ESCAPE '\')`, where[3],
+		"OR is parenthesized so it ANDs with the other filters")
+	assert.Equal(t, "source_office = ANY($6)", where[4])
+	assert.Len(t, args, 6)
+	assert.Equal(t, []string{"Tarrant", "Collin"}, args[3])
+	assert.Equal(t, "dall", args[4])
+	assert.Equal(t, []string{"FWD"}, args[5])
+	assert.Equal(t, 7, nextIdx)
+}
+
+func TestBuildWhereClause_TypeSeverityGroups(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		TypeSeverityGroups: []model.TypeSeverityGroup{
+			{EventTypes: []model.EventType{model.EventTypeTornado}},
+			{EventTypes: []model.EventType{model.EventTypeHail}, Severity: []model.Severity{model.SeveritySevere, model.SeverityExtreme}},
+			{Severity: []model.Severity{model.SeverityExtreme}},
+		},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + one grouped OR clause
+	assert.Len(t, where, 3)
+	assert.Equal(t, "((event_type = ANY($3)) OR (event_type = ANY($4) AND measurement_severity = ANY($5)) OR (measurement_severity = ANY($6)))", where[2])
+	assert.Len(t, args, 6)
+	assert.Equal(t, []string{"tornado"}, args[2])
+	assert.Equal(t, []string{"hail"}, args[3])
+	assert.Equal(t, []string{"severe", "extreme"}, args[4])
+	assert.Equal(t, []string{"extreme"}, args[5])
+	assert.Equal(t, 7, nextIdx)
+}
+
+func TestBuildWhereClause_TypeSeverityGroupsThenMagnitude(t *testing.T) {
+	minMag := 1.0
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		TypeSeverityGroups: []model.TypeSeverityGroup{
+			{EventTypes: []model.EventType{model.EventTypeHail}},
+			{EventTypes: []model.EventType{model.EventTypeHail}, Severity: []model.Severity{model.SeveritySevere}},
+		},
+		MinMagnitude: &minMag,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Equal(t, "((event_type = ANY($3)) OR (event_type = ANY($4) AND measurement_severity = ANY($5)))", where[2])
+	assert.Equal(t, "measurement_magnitude >= $6", where[3], "params after the groups continue their numbering")
+	assert.Len(t, args, 6)
+	assert.InDelta(t, 1.0, args[5], 0.0001)
+	assert.Equal(t, 7, nextIdx)
+	assert.Contains(t, buildWhereSQL(where), ")) AND measurement_magnitude >= $6")
+}
+
+func TestBuildWhereClause_CountyOutlier(t *testing.T) {
+	k := 2.5
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States:               []string{"TX"},
+		CountyOutlierStdDevs: &k,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 4)
+	assert.Len(t, args, 4)
+	assert.Equal(t, 2.5, args[3], "k bound as a parameter")
+	assert.Equal(t, 5, nextIdx)
+	assert.Equal(t, buildCountyOutlierClause(4), where[3])
+}
+
+func TestBuildCountyOutlierClause(t *testing.T) {
+	clause := buildCountyOutlierClause(7)
+
+	assert.True(t, strings.HasPrefix(clause, "measurement_magnitude > ("), "outer magnitude compared to the norm")
+	assert.Contains(t, clause, "AVG(o.measurement_magnitude) + $7 * STDDEV_SAMP(o.measurement_magnitude)")
+	assert.Contains(t, clause, "FROM storm_reports o")
+	// Joined to the outer row on event type and county, qualified so the
+	// names do not resolve to the inner alias.
+	assert.Contains(t, clause, "o.event_type = storm_reports.event_type")
+	assert.Contains(t, clause, "o.location_state = storm_reports.location_state")
+	assert.Contains(t, clause, "o.location_county = storm_reports.location_county")
+	assert.Contains(t, clause, "o.correction_status <> 'deleted-supersede'", "superseded rows do not skew the norm")
+	assert.Contains(t, clause, "HAVING COUNT(*) >= 3")
+}
+
+func TestBuildWhereClause_Polygon(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"OK"},
+		Polygon: []model.LatLon{
+			{Lat: 35.0, Lon: -98.0}, {Lat: 36.5, Lon: -97.5}, {Lat: 35.25, Lon: -96.0}, {Lat: 35.0, Lon: -98.0},
+		},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + states + one polygon clause with 5 args (bbox + ring)
+	assert.Len(t, where, 4)
+	assert.Len(t, args, 8)
+	assert.Equal(t, "geo_lat BETWEEN $4 AND $5 AND geo_lon BETWEEN $6 AND $7 AND "+
+		"$8::polygon @> point(geo_lon, geo_lat)", where[3])
+	assert.Equal(t, []any{35.0, 36.5, -98.0, -96.0}, args[3:7], "vertex bounding box")
+	assert.Equal(t, "((-98,35),(-97.5,36.5),(-96,35.25),(-98,35))", args[7], "(lon,lat) ring")
+	assert.Equal(t, 9, nextIdx)
+}
+
+func TestBuildWhereClause_RemarksIncludeExclude(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States:         []string{"TX"},
+		RemarksInclude: []string{"golf ball", "roof"},
+		RemarksExclude: []string{"marble"},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	// 2 time + states + one combined remarks clause
+	assert.Len(t, where, 4)
+	assert.Equal(t, "to_tsvector('english', comments) @@ ("+
+		"phraseto_tsquery('english', $4) && phraseto_tsquery('english', $5) && "+
+		"!!phraseto_tsquery('english', $6))", where[3])
+	assert.Equal(t, []any{"golf ball", "roof", "marble"}, args[3:], "includes bound before excludes, unescaped")
+	assert.Equal(t, 7, nextIdx)
+}
+
+func TestBuildWhereClause_RemarksExcludeOnly(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		RemarksExclude: []string{"marble & !hail"},
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Equal(t, "to_tsvector('english', comments) @@ (!!phraseto_tsquery('english', $3))", where[len(where)-1])
+	assert.Equal(t, "marble & !hail", args[len(args)-1], "operators stay data, parsed as plain words")
+	assert.Equal(t, 4, nextIdx)
+}
+
+func TestBuildWhereClause_MinRemarksLength(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+	n := 80
+
+	where, args, nextIdx := buildWhereClause(&model.StormReportFilter{TimeRange: tr, MinRemarksLength: &n})
+
+	require.Len(t, where, 3)
+	assert.Equal(t, "LENGTH(comments) >= $3", where[2])
+	assert.Equal(t, 80, args[2])
+	assert.Equal(t, 4, nextIdx)
+
+	// Unset: no length predicate, so reports with empty comments still match.
+	where, _, _ = buildWhereClause(&model.StormReportFilter{TimeRange: tr})
+	assert.NotContains(t, buildWhereSQL(where), "comments")
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\% \_x\\`, escapeLike(`100% _x\`))
+	assert.Equal(t, "plain", escapeLike("plain"))
+}
+
+func TestBuildWhereClause_ExcludeFuture(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	exclude := true
+	filter.ExcludeFuture = &exclude
+	where, args, nextIdx := buildWhereClause(filter)
+	assert.Contains(t, where, "event_time <= now()")
+	assert.Len(t, args, 2, "clause adds no params")
+	assert.Equal(t, 3, nextIdx)
+
+	// Admin override
+	exclude = false
+	where, _, _ = buildWhereClause(filter)
+	assert.NotContains(t, where, "event_time <= now()")
+}
+
+func TestBuildWhereClause_UpdatedAfter(t *testing.T) {
+	since := time.Date(2024, 4, 26, 12, 0, 0, 0, time.UTC)
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		UpdatedAfter: &since,
+	}
+
+	where, args, nextIdx := buildWhereClause(filter)
+
+	assert.Len(t, where, 3)
+	assert.Equal(t, "updated_at > $3", where[2])
+	assert.Equal(t, since, args[2])
+	assert.Equal(t, 4, nextIdx)
+}
+
 func TestSortColumn(t *testing.T) {
 	tests := []struct {
 		input model.SortField
@@ -159,6 +1015,7 @@ func TestSortColumn(t *testing.T) {
 		{model.SortFieldMagnitude, "measurement_magnitude"},
 		{model.SortFieldLocationState, "location_state"},
 		{model.SortFieldEventType, "event_type"},
+		{model.SortFieldSeverity, severityRankExpr},
 		{model.SortField("UNKNOWN"), "event_time"},
 	}
 
@@ -169,6 +1026,124 @@ func TestSortColumn(t *testing.T) {
 	}
 }
 
+func TestSeverityRankExpr(t *testing.T) {
+	assert.Equal(t,
+		"CASE measurement_severity WHEN 'minor' THEN 1 WHEN 'moderate' THEN 2 WHEN 'severe' THEN 3 WHEN 'extreme' THEN 4 ELSE 0 END",
+		severityRankExpr)
+
+	rank := func(s string) int { return severityRank(&s) }
+	assert.Less(t, severityRank(nil), rank("minor"))
+	assert.Less(t, rank("minor"), rank("moderate"))
+	assert.Less(t, rank("moderate"), rank("severe"))
+	assert.Less(t, rank("severe"), rank("extreme"))
+	assert.Equal(t, 0, rank("unknown"))
+}
+
+func TestSeverityScoreExpr(t *testing.T) {
+	expr := severityScoreExpr(model.SeverityWeights{Hail: 2.5, Wind: 48, Tornado: 2})
+	// Factor = weight / reference magnitude
+	assert.Equal(t,
+		"(CASE event_type WHEN 'hail' THEN 1 WHEN 'wind' THEN 0.5 WHEN 'tornado' THEN 0.4 ELSE 0 END * measurement_magnitude)",
+		expr)
+}
+
+func TestBuildOrderAndPage_SeverityScore(t *testing.T) {
+	sortBy := model.SortFieldSeverityScore
+	asc := model.SortOrderAsc
+
+	t.Run("default weights", func(t *testing.T) {
+		sql, _ := buildOrderAndPage(&model.StormReportFilter{SortBy: &sortBy}, 1)
+		assert.Equal(t, " ORDER BY "+severityScoreExpr(model.DefaultSeverityWeights)+" DESC, id DESC", sql)
+	})
+
+	t.Run("configured weights", func(t *testing.T) {
+		w := model.SeverityWeights{Hail: 5, Wind: 1, Tornado: 1}
+		sql, _ := buildOrderAndPage(&model.StormReportFilter{SortBy: &sortBy, SortOrder: &asc, SeverityWeights: &w}, 1)
+		assert.Equal(t, " ORDER BY "+severityScoreExpr(w)+" ASC, id ASC", sql)
+		assert.Contains(t, sql, "WHEN 'hail' THEN 2 ")
+	})
+}
+
+func TestBuildOrderAndPage_SortOrder(t *testing.T) {
+	asc := model.SortOrderAsc
+	desc := model.SortOrderDesc
+	unknown := model.SortOrder("SIDEWAYS")
+	empty := model.SortOrder("")
+	magnitude := model.SortField("MAGNITUDE")
+	bogus := model.SortField("BOGUS")
+
+	tests := []struct {
+		name   string
+		sortBy *model.SortField
+		order  *model.SortOrder
+		want   string
+	}{
+		{"default newest first", nil, nil, " ORDER BY event_time DESC, id DESC"},
+		{"explicit desc", nil, &desc, " ORDER BY event_time DESC, id DESC"},
+		{"asc", nil, &asc, " ORDER BY event_time ASC, id ASC"},
+		{"unknown order falls back to desc", nil, &unknown, " ORDER BY event_time DESC, id DESC"},
+		{"empty order falls back to desc", nil, &empty, " ORDER BY event_time DESC, id DESC"},
+		{"field with asc", &magnitude, &asc, " ORDER BY measurement_magnitude ASC, id ASC"},
+		{"unknown field and order", &bogus, &unknown, " ORDER BY event_time DESC, id DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _ := buildOrderAndPage(&model.StormReportFilter{SortBy: tt.sortBy, SortOrder: tt.order}, 1)
+			assert.Equal(t, tt.want, sql)
+		})
+	}
+}
+
+func TestBuildOrderAndPage_MagnitudeNormalized(t *testing.T) {
+	sortBy := model.SortFieldMagnitudeNormalized
+	asc := model.SortOrderAsc
+	limit, offset := 10, 20
+
+	sql, args := buildOrderAndPage(&model.StormReportFilter{SortBy: &sortBy, Limit: &limit, Offset: &offset}, 4)
+	assert.Equal(t, " ORDER BY percent_rank() OVER (PARTITION BY event_type ORDER BY measurement_magnitude) DESC, id DESC LIMIT $4 OFFSET $5", sql)
+	assert.Equal(t, []any{10, 20}, args)
+
+	sql, _ = buildOrderAndPage(&model.StormReportFilter{SortBy: &sortBy, SortOrder: &asc}, 1)
+	assert.Equal(t, " ORDER BY "+normalizedMagnitudeExpr+" ASC, id ASC", sql)
+}
+
+func TestTimeColumn_WhereAndOrderBy(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+	processed := model.TimeColumnProcessedAt
+	eventTime := model.TimeColumnEventTime
+
+	tests := []struct {
+		name   string
+		column *model.TimeColumn
+		want   string
+	}{
+		{"default", nil, "event_time"},
+		{"event time", &eventTime, "event_time"},
+		{"processed at", &processed, "processed_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &model.StormReportFilter{TimeRange: tr, TimeColumn: tt.column}
+
+			where, _, _ := buildWhereClause(filter)
+			assert.Equal(t, tt.want+" >= $1", where[0])
+			assert.Equal(t, tt.want+" <= $2", where[1])
+
+			sql, _ := buildOrderAndPage(filter, 3)
+			assert.Equal(t, " ORDER BY "+tt.want+" DESC, id DESC", sql)
+		})
+	}
+
+	t.Run("explicit sortBy wins", func(t *testing.T) {
+		sortBy := model.SortFieldMagnitude
+		sql, _ := buildOrderAndPage(&model.StormReportFilter{TimeRange: tr, TimeColumn: &processed, SortBy: &sortBy}, 3)
+		assert.Equal(t, " ORDER BY measurement_magnitude DESC, id DESC", sql)
+	})
+}
+
 func TestEventTypeDBValues(t *testing.T) {
 	vals := eventTypeDBValues([]model.EventType{model.EventTypeHail, model.EventTypeWind, model.EventTypeTornado})
 	assert.Equal(t, []string{"hail", "wind", "tornado"}, vals)
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// buildReportRateQuery counts the reports matching the filter within
+// (end-window, end], grouped into intervals equal sub-intervals numbered from
+// the oldest (0). A report exactly at end falls in the last sub-interval.
+func buildReportRateQuery(filter *model.StormReportFilter, end time.Time, window time.Duration, intervals int) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	start := end.Add(-window)
+	where = append(where, fmt.Sprintf("event_time > $%d AND event_time <= $%d", idx, idx+1))
+	args = append(args, start, end, (window / time.Duration(intervals)).Seconds(), intervals-1)
+	query := fmt.Sprintf(`SELECT LEAST(
+			FLOOR(EXTRACT(EPOCH FROM (event_time - $%[1]d::timestamptz)) / $%[2]d::float8)::int,
+			$%[3]d::int
+		) AS bucket, COUNT(*) AS count
+		FROM storm_reports%[4]s
+		GROUP BY bucket
+		ORDER BY bucket`, idx, idx+2, idx+3, buildWhereSQL(where))
+	return query, args
+}
+
+// ReportRate returns the rate of reports matching the filter, in reports per
+// hour, over the window ending at end, along with the rate in each of
+// intervals equal sub-intervals (oldest first) so a rising or falling trend is
+// visible.
+func (s *Store) ReportRate(ctx context.Context, filter *model.StormReportFilter, end time.Time, window time.Duration, intervals int) (*model.ReportRate, error) {
+	ctx, done, err := s.startQuery(ctx, "report_rate")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildReportRateQuery(filter, end, window, intervals)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("report rate: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]int, intervals)
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("scan report rate: %w", err)
+		}
+		if bucket >= 0 && bucket < intervals {
+			counts[bucket] += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildReportRate(end, window, counts), nil
+}
+
+// buildReportRate turns per-sub-interval counts (oldest first) for the window
+// ending at end into hourly rates for the whole window and each sub-interval.
+func buildReportRate(end time.Time, window time.Duration, counts []int) *model.ReportRate {
+	step := window / time.Duration(len(counts))
+	rate := &model.ReportRate{
+		WindowStart: end.Add(-window),
+		WindowEnd:   end,
+		Intervals:   make([]*model.RateInterval, len(counts)),
+	}
+	for i, n := range counts {
+		start := rate.WindowStart.Add(time.Duration(i) * step)
+		rate.Intervals[i] = &model.RateInterval{
+			Start:   start,
+			End:     start.Add(step),
+			Count:   n,
+			PerHour: float64(n) / step.Hours(),
+		}
+		rate.Count += n
+	}
+	rate.PerHour = float64(rate.Count) / window.Hours()
+	return rate
+}
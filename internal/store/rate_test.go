@@ -0,0 +1,77 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReportRateQuery(t *testing.T) {
+	end := time.Date(2024, 4, 26, 23, 0, 0, 0, time.UTC)
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   end,
+		},
+		States: []string{"OK"},
+	}
+
+	query, args := buildReportRateQuery(filter, end, 2*time.Hour, 4)
+
+	// 2 time + states, then window start, end, step seconds, last bucket
+	require.Len(t, args, 7)
+	assert.Equal(t, end.Add(-2*time.Hour), args[3])
+	assert.Equal(t, end, args[4])
+	assert.InDelta(t, 1800.0, args[5], 0)
+	assert.Equal(t, 3, args[6])
+	assert.Contains(t, query, "WHERE event_time >= $1 AND event_time <= $2 AND location_state = ANY($3)")
+	assert.Contains(t, query, "event_time > $4 AND event_time <= $5")
+	assert.Contains(t, query, "(event_time - $4::timestamptz)) / $6::float8")
+	assert.Contains(t, query, "$7::int")
+	assert.Contains(t, query, "GROUP BY bucket")
+}
+
+func TestBuildReportRate_EvenlySpaced(t *testing.T) {
+	end := time.Date(2024, 4, 26, 23, 0, 0, 0, time.UTC)
+	window := time.Hour
+
+	// One report every 5 minutes across the window, offset half a spacing
+	// from the sub-interval edges: 12 reports, 12/hour. Bucket them the way
+	// the query does: (event_time - start) / step, with a report exactly at
+	// end folded into the last sub-interval.
+	counts := make([]int, 4)
+	step := window / 4
+	for i := range 12 {
+		at := end.Add(-window).Add(time.Duration(i)*5*time.Minute + 150*time.Second)
+		counts[min(int(at.Sub(end.Add(-window))/step), 3)]++
+	}
+
+	rate := buildReportRate(end, window, counts)
+
+	assert.Equal(t, end.Add(-window), rate.WindowStart)
+	assert.Equal(t, end, rate.WindowEnd)
+	assert.Equal(t, 12, rate.Count)
+	assert.InDelta(t, 12.0, rate.PerHour, 1e-9)
+	require.Len(t, rate.Intervals, 4)
+	for i, iv := range rate.Intervals {
+		assert.Equal(t, 3, iv.Count, "interval %d", i)
+		assert.InDelta(t, 12.0, iv.PerHour, 1e-9, "steady rate in interval %d", i)
+		assert.Equal(t, step, iv.End.Sub(iv.Start))
+	}
+	assert.Equal(t, rate.WindowStart, rate.Intervals[0].Start)
+	assert.Equal(t, end, rate.Intervals[3].End)
+}
+
+func TestBuildReportRate_Accelerating(t *testing.T) {
+	end := time.Date(2024, 4, 26, 23, 0, 0, 0, time.UTC)
+
+	rate := buildReportRate(end, 2*time.Hour, []int{1, 4})
+
+	assert.Equal(t, 5, rate.Count)
+	assert.InDelta(t, 2.5, rate.PerHour, 1e-9)
+	assert.InDelta(t, 1.0, rate.Intervals[0].PerHour, 1e-9)
+	assert.InDelta(t, 4.0, rate.Intervals[1].PerHour, 1e-9)
+}
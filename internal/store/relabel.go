@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// relabelSQL sets event_type to the label of raw_event_type under the code
+// map in $1 (codes) and $2 (labels), matched like EventTypeLabels.Canonical,
+// or to the raw code itself when it has no label. Only rows whose label
+// changes are written.
+const relabelSQL = `WITH labels(code, label) AS (
+	SELECT * FROM unnest($1::text[], $2::text[])
+), relabelled AS (
+	SELECT r.id, COALESCE(l.label, r.raw_event_type) AS event_type
+	FROM storm_reports r
+	LEFT JOIN labels l ON l.code = lower(btrim(r.raw_event_type, E' \t\n\r\f\x0b'))
+	WHERE r.event_type IS DISTINCT FROM COALESCE(l.label, r.raw_event_type)
+)
+UPDATE storm_reports s SET event_type = relabelled.event_type
+FROM relabelled
+WHERE s.id = relabelled.id`
+
+// RelabelEventTypes re-applies the event type labels (see
+// SetEventTypeLabels) to stored reports, so a changed code map also covers
+// reports inserted under the old one. It returns the number of reports
+// relabelled. The revision trigger records each one, so delta sync clients
+// receive the new label.
+func (s *Store) RelabelEventTypes(ctx context.Context) (int64, error) {
+	defer s.observeQuery("relabel_event_types", time.Now())
+	codes, labels := labelArrays(s.eventTypeLabels)
+	tag, err := s.pool.Exec(ctx, relabelSQL, codes, labels)
+	if err != nil {
+		return 0, fmt.Errorf("relabel event types: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// labelArrays returns the codes of labels, sorted, and their labels in the
+// same order.
+func labelArrays(labels model.EventTypeLabels) (codes, values []string) {
+	codes = make([]string, 0, len(labels))
+	for code := range labels {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	values = make([]string, len(codes))
+	for i, code := range codes {
+		values[i] = labels[code]
+	}
+	return codes, values
+}
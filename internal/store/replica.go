@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultReplicaRetryInterval is how long reads stay on the primary after the
+// replica was found unavailable, before the replica is tried again.
+const DefaultReplicaRetryInterval = 30 * time.Second
+
+// readPool is the subset of *pgxpool.Pool used by read operations.
+type readPool interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+}
+
+// writePool is the subset of *pgxpool.Pool used on the primary, which serves
+// writes as well as reads.
+type writePool interface {
+	readPool
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// SetReplica routes read operations to a read-replica pool, keeping writes,
+// ingest, and sync checkpoint reads on the primary. A read that cannot reach
+// the replica is retried on the primary, and further reads skip the replica
+// for DefaultReplicaRetryInterval. WithPrimaryReads keeps an individual
+// operation on the primary. Must be called before the store serves
+// requests.
+func (s *Store) SetReplica(replica *pgxpool.Pool) {
+	s.reads = timeoutReads{newReplicaRouter(s.pool, replica, DefaultReplicaRetryInterval)}
+}
+
+type primaryReadsKey struct{}
+
+// WithPrimaryReads returns a context whose store reads go to the primary even
+// when a replica is configured, for callers that must see their own writes.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// primaryReads reports whether ctx was marked by WithPrimaryReads.
+func primaryReads(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return v
+}
+
+// replicaRouter sends reads to the replica while it is reachable and to the
+// primary otherwise. It is safe for concurrent use.
+type replicaRouter struct {
+	primary    readPool
+	replica    readPool
+	retryAfter time.Duration
+	now        func() time.Time
+
+	// downUntil is the Unix nanosecond time before which reads skip the
+	// replica; zero while the replica is healthy.
+	downUntil atomic.Int64
+}
+
+func newReplicaRouter(primary, replica readPool, retryAfter time.Duration) *replicaRouter {
+	return &replicaRouter{primary: primary, replica: replica, retryAfter: retryAfter, now: time.Now}
+}
+
+// useReplica reports whether a read made with ctx should try the replica.
+func (r *replicaRouter) useReplica(ctx context.Context) bool {
+	return !primaryReads(ctx) && r.now().UnixNano() >= r.downUntil.Load()
+}
+
+// fallBack records err from the replica and reports whether the read should
+// be retried on the primary: only when the replica could not be reached.
+func (r *replicaRouter) fallBack(err error) bool {
+	if !replicaUnavailable(err) {
+		return false
+	}
+	r.downUntil.Store(r.now().Add(r.retryAfter).UnixNano())
+	return true
+}
+
+// Query implements readPool.
+func (r *replicaRouter) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if r.useReplica(ctx) {
+		rows, err := r.replica.Query(ctx, sql, args...)
+		if err == nil || !r.fallBack(err) {
+			return rows, err
+		}
+	}
+	return r.primary.Query(ctx, sql, args...)
+}
+
+// QueryRow implements readPool. The replica's error only surfaces on Scan,
+// so the fallback happens there.
+func (r *replicaRouter) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if !r.useReplica(ctx) {
+		return r.primary.QueryRow(ctx, sql, args...)
+	}
+	return &fallbackRow{router: r, ctx: ctx, sql: sql, args: args, row: r.replica.QueryRow(ctx, sql, args...)}
+}
+
+// BeginTx implements readPool.
+func (r *replicaRouter) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	if r.useReplica(ctx) {
+		tx, err := r.replica.BeginTx(ctx, opts)
+		if err == nil || !r.fallBack(err) {
+			return tx, err
+		}
+	}
+	return r.primary.BeginTx(ctx, opts)
+}
+
+// fallbackRow is a replica row that reruns its query on the primary when
+// Scan reports the replica unreachable.
+type fallbackRow struct {
+	router *replicaRouter
+	ctx    context.Context
+	sql    string
+	args   []any
+	row    pgx.Row
+}
+
+func (f *fallbackRow) Scan(dest ...any) error {
+	err := f.row.Scan(dest...)
+	if err != nil && f.router.fallBack(err) {
+		return f.router.primary.QueryRow(f.ctx, f.sql, f.args...).Scan(dest...)
+	}
+	return err
+}
+
+// replicaUnavailable reports whether err means the replica could not serve
+// the read at all (connection refused, pool closed, server shutting down or
+// not accepting connections), as opposed to a query error the primary would
+// return too.
+func replicaUnavailable(err error) bool {
+	var connErr *pgconn.ConnectError
+	if errors.As(err, &connErr) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08: connection exception; 57P0x: operator intervention
+		// (admin shutdown, crash shutdown, cannot connect now).
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "57P0")
+	}
+	return err != nil && strings.Contains(err.Error(), "closed pool")
+}
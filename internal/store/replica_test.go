@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePool records the operations it receives and fails each with err.
+type fakePool struct {
+	err   error
+	calls []string
+}
+
+func (p *fakePool) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	p.calls = append(p.calls, "query")
+	return nil, p.err
+}
+
+func (p *fakePool) QueryRow(context.Context, string, ...any) pgx.Row {
+	p.calls = append(p.calls, "queryrow")
+	return errRow{p.err}
+}
+
+func (p *fakePool) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	p.calls = append(p.calls, "begintx")
+	return nil, p.err
+}
+
+func (p *fakePool) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	p.calls = append(p.calls, "exec")
+	return pgconn.CommandTag{}, p.err
+}
+
+func (p *fakePool) Begin(context.Context) (pgx.Tx, error) {
+	p.calls = append(p.calls, "begin")
+	return nil, p.err
+}
+
+func (p *fakePool) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults {
+	p.calls = append(p.calls, "sendbatch")
+	return nil
+}
+
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+var (
+	errQueryFailed     = errors.New("query failed")
+	errReplicaShutdown = &pgconn.PgError{Code: "57P03", Message: "the database system is starting up"}
+)
+
+func newReplicaStore(primary, replica *fakePool) (*Store, *replicaRouter) {
+	s := New(nil, observability.NewTestMetrics())
+	r := newReplicaRouter(primary, replica, time.Minute)
+	s.pool, s.reads = primary, r
+	return s, r
+}
+
+func TestStore_ReadsUseReplicaWritesUsePrimary(t *testing.T) {
+	primary, replica := &fakePool{err: errQueryFailed}, &fakePool{err: errQueryFailed}
+	s, _ := newReplicaStore(primary, replica)
+	ctx := context.Background()
+
+	_, err := s.GetStormReport(ctx, "r1")
+	require.ErrorIs(t, err, errQueryFailed)
+	_, _, err = s.ListStormReports(ctx, &model.StormReportFilter{})
+	require.ErrorIs(t, err, errQueryFailed)
+	_, err = s.LastUpdated(ctx)
+	require.ErrorIs(t, err, errQueryFailed)
+	assert.NotEmpty(t, replica.calls)
+	assert.Empty(t, primary.calls, "query errors are not retried on the primary")
+
+	replica.calls = nil
+	require.ErrorIs(t, s.InsertStormReport(ctx, &model.StormReport{}), errQueryFailed)
+	_, err = s.SyncCheckpoint(ctx, "client-1")
+	require.ErrorIs(t, err, errQueryFailed)
+	assert.Equal(t, []string{"exec", "queryrow"}, primary.calls)
+	assert.Empty(t, replica.calls)
+}
+
+func TestStore_WithPrimaryReads(t *testing.T) {
+	primary, replica := &fakePool{err: errQueryFailed}, &fakePool{err: errQueryFailed}
+	s, _ := newReplicaStore(primary, replica)
+
+	_, err := s.GetStormReport(WithPrimaryReads(context.Background()), "r1")
+	require.ErrorIs(t, err, errQueryFailed)
+	assert.Equal(t, []string{"queryrow"}, primary.calls)
+	assert.Empty(t, replica.calls)
+}
+
+func TestReplicaRouter_FallsBackToPrimary(t *testing.T) {
+	primary, replica := &fakePool{err: errQueryFailed}, &fakePool{err: errReplicaShutdown}
+	s, r := newReplicaStore(primary, replica)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := s.GetStormReport(ctx, "r1")
+	require.ErrorIs(t, err, errQueryFailed, "row read retried on the primary")
+	assert.Equal(t, []string{"queryrow"}, replica.calls)
+	assert.Equal(t, []string{"queryrow"}, primary.calls)
+
+	_, err = r.Query(ctx, "SELECT 1")
+	require.ErrorIs(t, err, errQueryFailed)
+	_, err = r.BeginTx(ctx, pgx.TxOptions{})
+	require.ErrorIs(t, err, errQueryFailed)
+	assert.Equal(t, []string{"queryrow"}, replica.calls, "replica skipped while marked down")
+	assert.Equal(t, []string{"queryrow", "query", "begintx"}, primary.calls)
+
+	now = now.Add(time.Minute)
+	replica.err = nil
+	_, err = r.Query(ctx, "SELECT 1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"queryrow", "query"}, replica.calls, "replica retried after the interval")
+}
+
+func TestReplicaUnavailable(t *testing.T) {
+	assert.True(t, replicaUnavailable(errReplicaShutdown))
+	assert.True(t, replicaUnavailable(&pgconn.PgError{Code: "08006"}))
+	assert.True(t, replicaUnavailable(errors.New("closed pool")))
+	assert.False(t, replicaUnavailable(&pgconn.PgError{Code: "42601"}))
+	assert.False(t, replicaUnavailable(errQueryFailed))
+	assert.False(t, replicaUnavailable(nil))
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrScanFailure matches every *ScanError, for callers that only need to
+// know a row was malformed.
+var ErrScanFailure = errors.New("scan failure")
+
+// ScanError reports a result row that could not be scanned, such as an
+// unexpected NULL in a column read into a non-pointer field.
+type ScanError struct {
+	// Row is the 1-based position of the row in the result.
+	Row int
+	// Column is the 0-based index of the column that failed, or -1 when the
+	// driver did not say.
+	Column int
+	Err    error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("scan row %d column %d: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *ScanError) Unwrap() error { return e.Err }
+
+// Is makes errors.Is(err, ErrScanFailure) true for any *ScanError.
+func (e *ScanError) Is(target error) bool { return target == ErrScanFailure }
+
+func newScanError(row int, err error) *ScanError {
+	column := -1
+	var argErr pgx.ScanArgError
+	if errors.As(err, &argErr) {
+		column = argErr.ColumnIndex
+	}
+	return &ScanError{Row: row, Column: column, Err: err}
+}
+
+// SetSkipBadRows makes report listings, pages, and exports skip rows that
+// fail to scan, logging each to logger, instead of failing the whole query. A
+// nil logger discards the log lines.
+func (s *Store) SetSkipBadRows(skip bool, logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	s.badRows = badRowPolicy{skip: skip, logger: logger}
+}
+
+// badRowPolicy decides what happens to a row that fails to scan. The zero
+// value fails the query.
+type badRowPolicy struct {
+	skip   bool
+	logger *slog.Logger
+}
+
+// handle returns the *ScanError for err at row, or nil if the row is to be
+// skipped.
+func (p badRowPolicy) handle(row int, err error) error {
+	serr := newScanError(row, err)
+	if !p.skip {
+		return serr
+	}
+	p.logger.Warn("skipping malformed row", "row", serr.Row, "column", serr.Column, "error", serr.Err)
+	return nil
+}
+
+// rowIterator is the part of pgx.Rows that scanRows reads.
+type rowIterator interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// scanRows scans every row with scan. A row that fails to scan ends the read
+// with a *ScanError unless the policy skips it.
+func scanRows[T any](rows rowIterator, p badRowPolicy, scan func(scannable) (T, error)) ([]T, error) {
+	var out []T
+	for n := 1; rows.Next(); n++ {
+		v, err := scan(rows)
+		if err != nil {
+			if err := p.handle(n, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		out = append(out, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRows yields one value per row; a nil value fails to scan the way pgx
+// does for a NULL in a non-nullable column.
+type fakeRows struct {
+	values []*string
+	i      int
+	err    error
+}
+
+func (r *fakeRows) Next() bool {
+	r.i++
+	return r.i <= len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	v := r.values[r.i-1]
+	if v == nil {
+		return pgx.ScanArgError{ColumnIndex: 0, FieldName: "id", Err: errors.New("cannot scan NULL into *string")}
+	}
+	*dest[0].(*string) = *v
+	return nil
+}
+
+func (r *fakeRows) Err() error { return r.err }
+
+func scanID(row scannable) (string, error) {
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
+
+func strp(s string) *string { return &s }
+
+func TestScanRows_FailsOnBadRow(t *testing.T) {
+	rows := &fakeRows{values: []*string{strp("r1"), nil, strp("r3")}}
+
+	_, err := scanRows(rows, badRowPolicy{}, scanID)
+
+	require.ErrorIs(t, err, ErrScanFailure)
+	var serr *ScanError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, 2, serr.Row)
+	assert.Equal(t, 0, serr.Column)
+	assert.Contains(t, err.Error(), "scan row 2 column 0")
+}
+
+func TestScanRows_SkipsBadRow(t *testing.T) {
+	var logs bytes.Buffer
+	s := New(nil, observability.NewTestMetrics())
+	s.SetSkipBadRows(true, slog.New(slog.NewTextHandler(&logs, nil)))
+	rows := &fakeRows{values: []*string{strp("r1"), nil, strp("r3")}}
+
+	got, err := scanRows(rows, s.badRows, scanID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r1", "r3"}, got)
+	assert.Contains(t, logs.String(), "skipping malformed row")
+	assert.Contains(t, logs.String(), "row=2")
+}
+
+func TestScanRows_IterationErrorNotSkipped(t *testing.T) {
+	s := New(nil, observability.NewTestMetrics())
+	s.SetSkipBadRows(true, nil)
+	rows := &fakeRows{values: []*string{strp("r1")}, err: errQueryFailed}
+
+	_, err := scanRows(rows, s.badRows, scanID)
+
+	require.ErrorIs(t, err, errQueryFailed)
+	assert.NotErrorIs(t, err, ErrScanFailure)
+}
+
+func TestNewScanError_UnknownColumn(t *testing.T) {
+	err := newScanError(4, errors.New("boom"))
+	assert.Equal(t, -1, err.Column)
+	assert.Equal(t, 4, err.Row)
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// severityCategoryExpr derives the severity category from event type and
+// magnitude using thresholds. Like the stored classification it is NULL when
+// the magnitude is 0 (unknown) or the event type has no thresholds.
+func severityCategoryExpr(thresholds []model.SeverityThreshold) string {
+	var b strings.Builder
+	b.WriteString("CASE WHEN measurement_magnitude <= 0 THEN NULL")
+	for _, th := range thresholds {
+		fmt.Fprintf(&b, " WHEN event_type = '%s' THEN CASE"+
+			" WHEN measurement_magnitude >= %g THEN '%s'"+
+			" WHEN measurement_magnitude >= %g THEN '%s'"+
+			" WHEN measurement_magnitude >= %g THEN '%s'"+
+			" ELSE '%s' END",
+			th.EventType.DBValue(),
+			th.Extreme, model.SeverityExtreme,
+			th.Severe, model.SeveritySevere,
+			th.Moderate, model.SeverityModerate,
+			model.SeverityMinor)
+	}
+	b.WriteString(" END")
+	return b.String()
+}
+
+// buildSeverityDistributionQuery counts the reports matching the filter per
+// derived severity category (ignoring pagination), mildest first. Reports
+// without a category are left out.
+func buildSeverityDistributionQuery(filter *model.StormReportFilter, thresholds []model.SeverityThreshold) (string, []any) {
+	where, args, _ := buildWhereClause(filter)
+	query := fmt.Sprintf(`SELECT category, COUNT(*) AS count
+		FROM (SELECT %s AS category FROM storm_reports%s) c
+		WHERE category IS NOT NULL
+		GROUP BY category
+		ORDER BY array_position(ARRAY['%s', '%s', '%s', '%s'], category)`,
+		severityCategoryExpr(thresholds), buildWhereSQL(where),
+		model.SeverityMinor, model.SeverityModerate, model.SeveritySevere, model.SeverityExtreme)
+	return query, args
+}
+
+// SeverityDistribution returns the number of reports matching the filter in
+// each severity category, derived from magnitude and event type with the
+// store's severity thresholds. Categories
+// with no matching reports are absent, and the result is empty, not nil, when
+// nothing matches.
+func (s *Store) SeverityDistribution(ctx context.Context, filter *model.StormReportFilter) ([]*model.SeverityCount, error) {
+	ctx, done, err := s.startQuery(ctx, "severity_distribution")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildSeverityDistributionQuery(filter, s.severityThresholds)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("severity distribution: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.SeverityCount{}
+	for rows.Next() {
+		c := &model.SeverityCount{}
+		if err := rows.Scan(&c.Category, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan severity count: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
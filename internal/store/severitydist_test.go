@@ -0,0 +1,50 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityCategoryExpr(t *testing.T) {
+	expr := severityCategoryExpr(model.DefaultSeverityThresholds)
+
+	assert.Equal(t, "CASE WHEN measurement_magnitude <= 0 THEN NULL"+
+		" WHEN event_type = 'hail' THEN CASE"+
+		" WHEN measurement_magnitude >= 2.5 THEN 'EXTREME'"+
+		" WHEN measurement_magnitude >= 1.5 THEN 'SEVERE'"+
+		" WHEN measurement_magnitude >= 0.75 THEN 'MODERATE'"+
+		" ELSE 'MINOR' END"+
+		" WHEN event_type = 'wind' THEN CASE"+
+		" WHEN measurement_magnitude >= 96 THEN 'EXTREME'"+
+		" WHEN measurement_magnitude >= 74 THEN 'SEVERE'"+
+		" WHEN measurement_magnitude >= 50 THEN 'MODERATE'"+
+		" ELSE 'MINOR' END"+
+		" WHEN event_type = 'tornado' THEN CASE"+
+		" WHEN measurement_magnitude >= 5 THEN 'EXTREME'"+
+		" WHEN measurement_magnitude >= 3 THEN 'SEVERE'"+
+		" WHEN measurement_magnitude >= 2 THEN 'MODERATE'"+
+		" ELSE 'MINOR' END"+
+		" END", expr, "strictest threshold first so each branch is a lower bound")
+}
+
+func TestBuildSeverityDistributionQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+	}
+	where, whereArgs, _ := buildWhereClause(filter)
+
+	query, args := buildSeverityDistributionQuery(filter, model.DefaultSeverityThresholds)
+
+	assert.Equal(t, whereArgs, args, "same args as the list query")
+	assert.Contains(t, query, "SELECT "+severityCategoryExpr(model.DefaultSeverityThresholds)+" AS category FROM storm_reports"+buildWhereSQL(where))
+	assert.Contains(t, query, "WHERE category IS NOT NULL")
+	assert.Contains(t, query, "GROUP BY category")
+	assert.Contains(t, query, "ORDER BY array_position(ARRAY['MINOR', 'MODERATE', 'SEVERE', 'EXTREME'], category)")
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// buildSpotterRankQuery counts the reports matching the filter per spotter
+// (ignoring pagination), ranks the spotters by count with RANK(), and keeps
+// the row of the requested spotter. Reports without a spotter are not ranked.
+func buildSpotterRankQuery(filter *model.StormReportFilter, spotterID string) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	where = append(where, "spotter_id IS NOT NULL")
+	query := fmt.Sprintf(`SELECT rank, count, spotters
+		FROM (
+			SELECT spotter_id, COUNT(*) AS count,
+				RANK() OVER (ORDER BY COUNT(*) DESC) AS rank,
+				COUNT(*) OVER () AS spotters
+			FROM storm_reports%s
+			GROUP BY spotter_id
+		) ranked
+		WHERE spotter_id = $%d`, buildWhereSQL(where), idx)
+	return query, append(args, spotterID)
+}
+
+// SpotterRank returns where spotterID places among all spotters by number of
+// reports matching the filter. Spotters with equal counts share a rank, and
+// the next rank skips accordingly (1, 1, 3). It returns nil when the spotter
+// has no matching reports.
+func (s *Store) SpotterRank(ctx context.Context, filter *model.StormReportFilter, spotterID string) (*model.SpotterRank, error) {
+	ctx, done, err := s.startQuery(ctx, "spotter_rank")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildSpotterRankQuery(filter, spotterID)
+
+	r := &model.SpotterRank{SpotterID: spotterID}
+	err = s.reads.QueryRow(ctx, query, args...).Scan(&r.Rank, &r.Count, &r.Spotters)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("spotter rank: %w", err)
+	}
+	return r, nil
+}
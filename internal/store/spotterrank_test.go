@@ -0,0 +1,29 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSpotterRankQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"OK"},
+	}
+	where, whereArgs, idx := buildWhereClause(filter)
+
+	query, args := buildSpotterRankQuery(filter, "KC5ABC")
+
+	assert.Equal(t, append(whereArgs, "KC5ABC"), args, "spotter id follows the filter args")
+	assert.Contains(t, query, buildWhereSQL(append(where, "spotter_id IS NOT NULL")), "filter reused, unattributed reports excluded")
+	assert.Contains(t, query, "RANK() OVER (ORDER BY COUNT(*) DESC) AS rank")
+	assert.Contains(t, query, "GROUP BY spotter_id")
+	assert.Contains(t, query, fmt.Sprintf("WHERE spotter_id = $%d", idx), "spotter picked after ranking")
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/couchcryptid/storm-data-api/internal/cache"
 	"github.com/couchcryptid/storm-data-api/internal/model"
 	"github.com/couchcryptid/storm-data-api/internal/observability"
 	"github.com/jackc/pgx/v5"
@@ -16,17 +18,118 @@ const columns = `id, event_type, geo_lat, geo_lon, measurement_magnitude, measur
 	event_time,
 	location_raw, location_name, location_distance, location_direction,
 	location_state, location_county,
-	comments, measurement_severity, source_office, time_bucket, processed_at`
+	comments, measurement_severity, source_office, time_bucket, processed_at,
+	spotter_level, measurement_method, raw_event_type`
 
 // Store provides persistence operations for storm reports backed by PostgreSQL.
 type Store struct {
-	pool    *pgxpool.Pool
+	// pool is the primary, serving writes; reads is the primary too unless
+	// SetReplica routes reads to a replica.
+	pool    writePool
+	reads   readPool
 	metrics *observability.Metrics
+
+	// queryTimeout bounds each read; see SetQueryTimeout.
+	queryTimeout time.Duration
+
+	// badRows decides whether malformed report rows fail the read; see
+	// SetSkipBadRows.
+	badRows badRowPolicy
+
+	// eventTypeLabels normalizes event type codes on insert and in
+	// RelabelEventTypes; see SetEventTypeLabels.
+	eventTypeLabels model.EventTypeLabels
+
+	// insertSQL is the upsert statement; its ON CONFLICT target is set by
+	// SetConflictTarget.
+	insertSQL string
+
+	// severityThresholds classify magnitudes for SeverityDistribution. One
+	// entry per model.EventTypes, in that order; replaced by
+	// LoadSeverityThresholds.
+	severityThresholds []model.SeverityThreshold
+
+	// Optional result caches; nil unless EnableCache is called.
+	queryCache *cache.Cache[[]*model.StormReport]
+	countCache *cache.Cache[int]
+	pageCache  *cache.Cache[reportPage]
+
+	// rangeCache holds DataRange results briefly; always on from New.
+	rangeCache *cache.Cache[model.DataRange]
 }
 
 // New creates a Store with the given connection pool and metrics.
 func New(pool *pgxpool.Pool, m *observability.Metrics) *Store {
-	return &Store{pool: pool, metrics: m}
+	s := &Store{
+		metrics:      m,
+		insertSQL:    buildInsertSQL(DefaultConflictTarget),
+		rangeCache:   newDataRangeCache(),
+		queryTimeout: DefaultQueryTimeout,
+
+		severityThresholds: model.DefaultSeverityThresholds,
+		eventTypeLabels:    model.DefaultEventTypeLabels,
+	}
+	if pool != nil {
+		s.pool, s.reads = pool, timeoutReads{pool}
+	}
+	return s
+}
+
+// DefaultConflictTarget is the ON CONFLICT target used unless overridden:
+// the deterministic report ID.
+var DefaultConflictTarget = []string{"id"}
+
+// conflictColumns whitelists the columns accepted as an ON CONFLICT target.
+// Each is indexed by the migrations; Postgres additionally requires a unique
+// index covering exactly the chosen set.
+var conflictColumns = map[string]bool{
+	"id":                   true,
+	"event_type":           true,
+	"event_time":           true,
+	"geo_lat":              true,
+	"geo_lon":              true,
+	"location_state":       true,
+	"measurement_severity": true,
+	"spotter_level":        true,
+}
+
+// SetConflictTarget changes the column(s) that identify a duplicate report on
+// insert, so datasets with a different natural key can share the write path.
+// Columns are validated against a whitelist because they are interpolated into
+// the statement. Must be called before the store accepts inserts.
+func (s *Store) SetConflictTarget(cols []string) error {
+	if len(cols) == 0 {
+		return errors.New("conflict target requires at least one column")
+	}
+	seen := make(map[string]bool, len(cols))
+	for _, c := range cols {
+		if !conflictColumns[c] {
+			return fmt.Errorf("invalid conflict target column %q", c)
+		}
+		if seen[c] {
+			return fmt.Errorf("duplicate conflict target column %q", c)
+		}
+		seen[c] = true
+	}
+	s.insertSQL = buildInsertSQL(cols)
+	return nil
+}
+
+// SetEventTypeLabels replaces the map that normalizes upstream event type
+// codes on insert. Reports already stored keep the label they were inserted
+// with until RelabelEventTypes runs. Must be called before the store accepts
+// inserts.
+func (s *Store) SetEventTypeLabels(labels model.EventTypeLabels) {
+	s.eventTypeLabels = labels
+}
+
+// buildInsertSQL returns the insert statement with the given ON CONFLICT
+// target. Callers must pass whitelisted columns. Besides columns it writes
+// ingest_job_id, which reads never select.
+func buildInsertSQL(target []string) string {
+	return `INSERT INTO storm_reports (` + columns + `, ingest_job_id)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22)
+	ON CONFLICT (` + strings.Join(target, ", ") + `) DO NOTHING`
 }
 
 func (s *Store) observeQuery(operation string, start time.Time) {
@@ -36,28 +139,36 @@ func (s *Store) observeQuery(operation string, start time.Time) {
 // InsertStormReport upserts a storm report into the database.
 // IDs are deterministic SHA-256 hashes (event_type+state+coords+time+magnitude), so identical
 // events always produce the same ID. ON CONFLICT DO NOTHING makes inserts
-// idempotent, which is safe for Kafka's at-least-once delivery.
+// idempotent, which is safe for Kafka's at-least-once delivery. The conflict
+// target defaults to id; see SetConflictTarget.
 func (s *Store) InsertStormReport(ctx context.Context, report *model.StormReport) error {
 	defer s.observeQuery("insert", time.Now())
-	_, err := s.pool.Exec(ctx, `
-		INSERT INTO storm_reports (`+columns+`)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
-		ON CONFLICT (id) DO NOTHING`,
-		report.ID, report.EventType, report.Geo.Lat, report.Geo.Lon,
-		report.Measurement.Magnitude, report.Measurement.Unit,
-		report.EventTime,
-		report.Location.Raw, report.Location.Name,
-		report.Location.Distance, report.Location.Direction,
-		report.Location.State, report.Location.County,
-		report.Comments, report.Measurement.Severity, report.SourceOffice,
-		report.TimeBucket, report.ProcessedAt,
-	)
+	_, err := s.pool.Exec(ctx, s.insertSQL, s.insertArgs(report)...)
 	return err
 }
 
-const insertSQL = `INSERT INTO storm_reports (` + columns + `)
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
-	ON CONFLICT (id) DO NOTHING`
+// insertArgs returns the insert statement parameters for r, in columns order
+// followed by the ingest job. The event type is stored as its canonical
+// label, with the upstream code kept in raw_event_type, so every reader and
+// the eventTypes filter see the same label.
+func (s *Store) insertArgs(r *model.StormReport) []any {
+	raw := r.RawEventType
+	if raw == "" {
+		raw = r.EventType
+	}
+	return []any{
+		r.ID, s.eventTypeLabels.Canonical(raw), r.Geo.Lat, r.Geo.Lon,
+		r.Measurement.Magnitude, r.Measurement.Unit,
+		r.EventTime,
+		r.Location.Raw, r.Location.Name,
+		r.Location.Distance, r.Location.Direction,
+		r.Location.State, r.Location.County,
+		r.Comments, r.Measurement.Severity, r.SourceOffice,
+		r.TimeBucket, r.ProcessedAt,
+		r.SpotterLevel, r.Measurement.Method, raw,
+		r.IngestJobID,
+	}
+}
 
 // InsertStormReports batch-inserts multiple storm reports using pgx.Batch.
 func (s *Store) InsertStormReports(ctx context.Context, reports []*model.StormReport) error {
@@ -68,16 +179,7 @@ func (s *Store) InsertStormReports(ctx context.Context, reports []*model.StormRe
 
 	batch := &pgx.Batch{}
 	for _, r := range reports {
-		batch.Queue(insertSQL,
-			r.ID, r.EventType, r.Geo.Lat, r.Geo.Lon,
-			r.Measurement.Magnitude, r.Measurement.Unit,
-			r.EventTime,
-			r.Location.Raw, r.Location.Name,
-			r.Location.Distance, r.Location.Direction,
-			r.Location.State, r.Location.County,
-			r.Comments, r.Measurement.Severity, r.SourceOffice,
-			r.TimeBucket, r.ProcessedAt,
-		)
+		batch.Queue(s.insertSQL, s.insertArgs(r)...)
 	}
 
 	batchResults := s.pool.SendBatch(ctx, batch)
@@ -92,68 +194,154 @@ func (s *Store) InsertStormReports(ctx context.Context, reports []*model.StormRe
 	return nil
 }
 
+// InsertStormReportsPartial inserts reports one at a time inside a single
+// transaction, wrapping each row in a savepoint so a rejected row does not
+// abort the rest. It returns the rejected rows; the error is non-nil only when
+// the transaction itself fails, in which case nothing is inserted.
+func (s *Store) InsertStormReportsPartial(ctx context.Context, reports []*model.StormReport) ([]model.RowError, error) {
+	if len(reports) == 0 {
+		return nil, nil
+	}
+	defer s.observeQuery("partial_insert", time.Now())
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin partial insert: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var rejected []model.RowError
+	for i, r := range reports {
+		sp, err := tx.Begin(ctx) // SAVEPOINT
+		if err != nil {
+			return nil, fmt.Errorf("savepoint: %w", err)
+		}
+		if _, err := sp.Exec(ctx, s.insertSQL, s.insertArgs(r)...); err != nil {
+			if rbErr := sp.Rollback(ctx); rbErr != nil {
+				return nil, fmt.Errorf("rollback to savepoint: %w", rbErr)
+			}
+			rejected = append(rejected, model.RowError{Index: i, ID: r.ID, Err: err})
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit partial insert: %w", err)
+	}
+	return rejected, nil
+}
+
 // ListStormReports returns filtered, sorted, paginated reports and the total count.
 func (s *Store) ListStormReports(ctx context.Context, filter *model.StormReportFilter) ([]*model.StormReport, int, error) {
-	defer s.observeQuery("list", time.Now())
+	ctx, done, err := s.startQuery(ctx, "list")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer done()
 	where, baseArgs, idx := buildWhereClause(filter)
 
 	whereSQL := buildWhereSQL(where)
 
 	// Count total matching rows
 	countQuery := "SELECT COUNT(*) FROM storm_reports" + whereSQL
-	var totalCount int
-	if err := s.pool.QueryRow(ctx, countQuery, baseArgs...).Scan(&totalCount); err != nil {
-		return nil, 0, fmt.Errorf("count storm reports: %w", err)
+	totalCount, err := s.countStormReports(ctx, countQuery, baseArgs)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Build data query with sorting and pagination
-	orderCol := "event_time"
-	orderDir := "DESC"
-	if filter.SortBy != nil && filter.SortBy.IsValid() {
-		orderCol = sortColumn(*filter.SortBy)
-	}
-	if filter.SortOrder != nil && filter.SortOrder.IsValid() && *filter.SortOrder == model.SortOrderAsc {
-		orderDir = "ASC"
+	pageSQL, pageArgs := buildOrderAndPage(filter, idx)
+	dataArgs := make([]any, 0, len(baseArgs)+len(pageArgs))
+	dataArgs = append(dataArgs, baseArgs...)
+	dataArgs = append(dataArgs, pageArgs...)
+
+	query := "SELECT " + columns + " FROM storm_reports" + whereSQL + pageSQL
+
+	reports, err := s.queryStormReports(ctx, query, dataArgs)
+	if err != nil {
+		return nil, 0, err
 	}
+	return reports, totalCount, nil
+}
 
-	dataArgs := make([]any, len(baseArgs))
-	copy(dataArgs, baseArgs)
+// GetStormReport returns the report with the given ID, or nil if none exists.
+func (s *Store) GetStormReport(ctx context.Context, id string) (*model.StormReport, error) {
+	ctx, done, err := s.startQuery(ctx, "get")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	row := s.reads.QueryRow(ctx, "SELECT "+columns+" FROM storm_reports WHERE id = $1", id)
+	return scanStormReport(row)
+}
 
-	query := "SELECT " + columns + " FROM storm_reports" + whereSQL +
-		fmt.Sprintf(" ORDER BY %s %s", orderCol, orderDir)
+// CountStormReports returns the number of reports matching the filter,
+// ignoring sorting and pagination.
+func (s *Store) CountStormReports(ctx context.Context, filter *model.StormReportFilter) (int, error) {
+	ctx, done, err := s.startQuery(ctx, "count")
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+	where, args, _ := buildWhereClause(filter)
+	return s.countStormReports(ctx, "SELECT COUNT(*) FROM storm_reports"+buildWhereSQL(where), args)
+}
 
-	if filter.Limit != nil {
-		query += fmt.Sprintf(" LIMIT $%d", idx)
-		dataArgs = append(dataArgs, *filter.Limit)
-		idx++
+// countStormReports runs a COUNT(*) query, consulting the count cache if enabled.
+func (s *Store) countStormReports(ctx context.Context, query string, args []any) (int, error) {
+	key := cacheKey(query, args)
+	if s.countCache != nil {
+		if n, ok := s.countCache.Get(key); ok {
+			return n, nil
+		}
+	}
+	var n int
+	if err := s.reads.QueryRow(ctx, query, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count storm reports: %w", err)
 	}
-	if filter.Offset != nil {
-		query += fmt.Sprintf(" OFFSET $%d", idx)
-		dataArgs = append(dataArgs, *filter.Offset)
+	if s.countCache != nil {
+		s.countCache.Set(key, n)
 	}
+	return n, nil
+}
 
-	rows, err := s.pool.Query(ctx, query, dataArgs...)
+// queryStormReports runs a report SELECT, consulting the query cache if enabled.
+func (s *Store) queryStormReports(ctx context.Context, query string, args []any) ([]*model.StormReport, error) {
+	key := cacheKey(query, args)
+	if s.queryCache != nil {
+		if reports, ok := s.queryCache.Get(key); ok {
+			return reports, nil
+		}
+	}
+
+	rows, err := s.reads.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("query storm reports: %w", err)
+		return nil, fmt.Errorf("query storm reports: %w", err)
 	}
 	defer rows.Close()
 
-	var reports []*model.StormReport
-	for rows.Next() {
-		r, err := scanStormReport(rows)
-		if err != nil {
-			return nil, 0, err
-		}
-		reports = append(reports, r)
+	reports, err := scanRows(rows, s.badRows, scanStormReport)
+	if err != nil {
+		return nil, err
+	}
+	if s.queryCache != nil {
+		s.queryCache.Set(key, reports)
 	}
-	return reports, totalCount, rows.Err()
+	return reports, nil
 }
 
 // LastUpdated returns the most recent processed_at timestamp.
 func (s *Store) LastUpdated(ctx context.Context) (*time.Time, error) {
-	defer s.observeQuery("last_updated", time.Now())
+	ctx, done, err := s.startQuery(ctx, "last_updated")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	var t *time.Time
-	err := s.pool.QueryRow(ctx, "SELECT MAX(processed_at) FROM storm_reports").Scan(&t)
+	err = s.reads.QueryRow(ctx, "SELECT MAX(processed_at) FROM storm_reports").Scan(&t)
 	if err != nil {
 		return nil, fmt.Errorf("last updated: %w", err)
 	}
@@ -175,6 +363,7 @@ func scanStormReport(row scannable) (*model.StormReport, error) {
 		&r.Location.State, &r.Location.County,
 		&r.Comments, &r.Measurement.Severity, &r.SourceOffice,
 		&r.TimeBucket, &r.ProcessedAt,
+		&r.SpotterLevel, &r.Measurement.Method, &r.RawEventType,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_DefaultConflictTarget(t *testing.T) {
+	s := New(nil, nil)
+	assert.Contains(t, s.insertSQL, "ON CONFLICT (id) DO NOTHING")
+}
+
+func TestSetConflictTarget(t *testing.T) {
+	s := New(nil, nil)
+	require.NoError(t, s.SetConflictTarget([]string{"event_type", "event_time", "geo_lat", "geo_lon"}))
+	assert.Contains(t, s.insertSQL, "ON CONFLICT (event_type, event_time, geo_lat, geo_lon) DO NOTHING")
+}
+
+func TestSetConflictTarget_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cols []string
+		msg  string
+	}{
+		{"empty", nil, "at least one column"},
+		{"not whitelisted", []string{"comments"}, `invalid conflict target column "comments"`},
+		{"injection", []string{"id) DO UPDATE SET id = 'x' --"}, "invalid conflict target column"},
+		{"duplicate", []string{"id", "id"}, `duplicate conflict target column "id"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(nil, nil)
+			before := s.insertSQL
+			err := s.SetConflictTarget(tt.cols)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+			assert.Equal(t, before, s.insertSQL, "statement unchanged on error")
+		})
+	}
+}
+
+func TestInsertArgs_NormalizesEventType(t *testing.T) {
+	s := New(nil, nil)
+
+	for _, raw := range []string{"TOR", "tornado", "Tornado", " TORNADO "} {
+		args := s.insertArgs(&model.StormReport{EventType: raw})
+
+		require.Len(t, args, strings.Count(s.insertSQL, "$"), "one arg per placeholder")
+		assert.Equal(t, "tornado", args[1], raw)
+		assert.Equal(t, raw, args[20], "raw code kept in raw_event_type")
+	}
+
+	args := s.insertArgs(&model.StormReport{EventType: "tornado", RawEventType: "TOR"})
+	assert.Equal(t, "tornado", args[1])
+	assert.Equal(t, "TOR", args[20], "an explicit raw code wins")
+}
+
+func TestSetEventTypeLabels(t *testing.T) {
+	s := New(nil, nil)
+	s.SetEventTypeLabels(model.EventTypeLabels{"t": "tornado"})
+
+	args := s.insertArgs(&model.StormReport{EventType: "T"})
+
+	assert.Equal(t, "tornado", args[1])
+	assert.Equal(t, "T", args[20])
+}
+
+func TestLabelArrays(t *testing.T) {
+	codes, labels := labelArrays(model.EventTypeLabels{"tor": "tornado", "hail": "hail", "tstm wnd gst": "wind"})
+
+	assert.Equal(t, []string{"hail", "tor", "tstm wnd gst"}, codes)
+	assert.Equal(t, []string{"hail", "tornado", "wind"}, labels)
+}
+
+func TestRelabelEventTypes_WrapsError(t *testing.T) {
+	s := New(nil, observability.NewTestMetrics())
+	pool := &fakePool{err: errQueryFailed}
+	s.pool = pool
+
+	n, err := s.RelabelEventTypes(context.Background())
+
+	require.ErrorIs(t, err, errQueryFailed)
+	assert.Contains(t, err.Error(), "relabel event types")
+	assert.Zero(t, n)
+	assert.Equal(t, []string{"exec"}, pool.calls)
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// streamBatch is the number of rows fetched from a cursor per round trip. It
+// bounds memory regardless of how many rows the filter yields.
+const streamBatch = 500
+
+// buildCountyGroupsQuery groups the reports matching the filter by state and
+// county, ordered so the stream is deterministic.
+func buildCountyGroupsQuery(filter *model.StormReportFilter) (string, []any) {
+	where, args, _ := buildWhereClause(filter)
+	query := `SELECT location_state, location_county, COUNT(*) AS count
+		FROM storm_reports` + buildWhereSQL(where) + `
+		GROUP BY location_state, location_county
+		ORDER BY location_state, location_county`
+	return query, args
+}
+
+// buildReportStreamQuery selects every report matching the filter, oldest
+// first by the filter's time column. Sorting and pagination are ignored.
+func buildReportStreamQuery(filter *model.StormReportFilter) (string, []any) {
+	where, args, _ := buildWhereClause(filter)
+	query := "SELECT " + columns + " FROM storm_reports" + buildWhereSQL(where) +
+		" ORDER BY " + timeColumn(filter) + ", id"
+	return query, args
+}
+
+// StreamCountyGroups calls fn for every (state, county) group matching the
+// filter, in state then county order. Rows are read through a server-side
+// cursor streamBatch at a time, so neither the database driver nor the
+// caller holds the full result. Streaming stops at the first error from fn or
+// when ctx is cancelled.
+func (s *Store) StreamCountyGroups(ctx context.Context, filter *model.StormReportFilter, fn func(*model.CountyStateGroup) error) error {
+	// A stream lasts as long as the client keeps reading, so the whole stream
+	// runs under ctx; streamCursor bounds each statement by the query timeout.
+	_, done, err := s.startQuery(ctx, "stream_county_groups")
+	if err != nil {
+		return err
+	}
+	defer done()
+	query, args := buildCountyGroupsQuery(filter)
+
+	return s.streamCursor(ctx, "county_groups", query, args, func(rows pgx.Rows) error {
+		var g model.CountyStateGroup
+		if err := rows.Scan(&g.State, &g.County, &g.Count); err != nil {
+			return fmt.Errorf("scan county group: %w", err)
+		}
+		return fn(&g)
+	})
+}
+
+// StreamStormReports calls fn for every report matching the filter, oldest
+// first, ignoring the filter's sorting and pagination. Like StreamCountyGroups
+// it reads through a cursor, so memory use does not grow with the result, and
+// each cursor statement is bounded by the query timeout.
+func (s *Store) StreamStormReports(ctx context.Context, filter *model.StormReportFilter, fn func(*model.StormReport) error) error {
+	_, done, err := s.startQuery(ctx, "stream_reports")
+	if err != nil {
+		return err
+	}
+	defer done()
+	query, args := buildReportStreamQuery(filter)
+
+	n := 0
+	return s.streamCursor(ctx, "storm_reports_export", query, args, func(rows pgx.Rows) error {
+		n++
+		r, err := scanStormReport(rows)
+		if err != nil {
+			return s.badRows.handle(n, err)
+		}
+		return fn(r)
+	})
+}
+
+// streamCursor declares a server-side cursor named name over query and
+// calls each for every row, fetching streamBatch rows per round trip. The
+// DECLARE and every FETCH batch get their own query timeout, so a slow plan
+// fails with ErrQueryTimeout while a long stream of fast batches runs on. It
+// stops at the first error from each or when ctx is cancelled.
+func (s *Store) streamCursor(ctx context.Context, name, query string, args []any, each func(pgx.Rows) error) error {
+	// Cursors only live inside a transaction; rolling back closes it.
+	tx, err := s.reads.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("begin stream: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	declareCtx, cancel := s.withQueryTimeout(ctx)
+	_, err = tx.Exec(declareCtx, "DECLARE "+name+" NO SCROLL CURSOR FOR "+query, args...)
+	err = queryTimeoutErr(declareCtx, err)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("declare %s cursor: %w", name, err)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := s.fetchBatch(ctx, tx, name, each)
+		if err != nil {
+			return err
+		}
+		if n < streamBatch {
+			return nil
+		}
+	}
+}
+
+// fetchBatch fetches the next streamBatch rows of cursor name under the query
+// timeout and calls each for them, returning how many there were. Errors from
+// each are returned as is.
+func (s *Store) fetchBatch(ctx context.Context, tx pgx.Tx, name string, each func(pgx.Rows) error) (int, error) {
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM %s", streamBatch, name))
+	if err != nil {
+		return 0, fmt.Errorf("fetch %s: %w", name, queryTimeoutErr(ctx, err))
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		n++
+		if err := each(rows); err != nil {
+			return n, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return n, queryTimeoutErr(ctx, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCountyGroupsQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+	}
+
+	query, args := buildCountyGroupsQuery(filter)
+
+	require.Len(t, args, 3)
+	assert.Contains(t, query, "WHERE event_time >= $1 AND event_time <= $2 AND location_state = ANY($3)")
+	assert.Contains(t, query, "GROUP BY location_state, location_county")
+	assert.Contains(t, query, "ORDER BY location_state, location_county")
+}
+
+func TestBuildReportStreamQuery(t *testing.T) {
+	limit, offset := 5, 10
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+		Limit:  &limit,
+		Offset: &offset,
+	}
+
+	query, args := buildReportStreamQuery(filter)
+
+	require.Len(t, args, 3)
+	assert.Contains(t, query, "SELECT "+columns+" FROM storm_reports WHERE event_time >= $1")
+	assert.True(t, strings.HasSuffix(query, " ORDER BY event_time, id"), query)
+	assert.NotContains(t, query, "LIMIT", "every matching report is streamed")
+	assert.NotContains(t, query, "OFFSET")
+}
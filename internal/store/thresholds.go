@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// SeverityThresholds returns the severity thresholds in model.EventTypes
+// order.
+func (s *Store) SeverityThresholds() []model.SeverityThreshold {
+	return s.severityThresholds
+}
+
+// SetSeverityThresholds replaces the severity thresholds. thresholds must hold
+// exactly one entry per known event type. Must be called before the store
+// serves queries.
+func (s *Store) SetSeverityThresholds(thresholds []model.SeverityThreshold) error {
+	if err := model.ValidateSeverityThresholds(thresholds); err != nil {
+		return err
+	}
+	byType := make(map[model.EventType]model.SeverityThreshold, len(thresholds))
+	for _, th := range thresholds {
+		byType[th.EventType] = th
+	}
+	ordered := make([]model.SeverityThreshold, 0, len(model.EventTypes))
+	for _, e := range model.EventTypes {
+		ordered = append(ordered, byType[e])
+	}
+	s.severityThresholds = ordered
+	return nil
+}
+
+// LoadSeverityThresholds reads the severity_thresholds table and installs it
+// with SetSeverityThresholds, so the thresholds can be retuned without a
+// rebuild. It fails if any event type lacks a row.
+func (s *Store) LoadSeverityThresholds(ctx context.Context) error {
+	ctx, done, err := s.startQuery(ctx, "severity_thresholds")
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	rows, err := s.pool.Query(ctx, "SELECT event_type, moderate, severe, extreme FROM severity_thresholds")
+	if err != nil {
+		return fmt.Errorf("severity thresholds: %w", err)
+	}
+	defer rows.Close()
+
+	var thresholds []model.SeverityThreshold
+	for rows.Next() {
+		var (
+			eventType string
+			th        model.SeverityThreshold
+		)
+		if err := rows.Scan(&eventType, &th.Moderate, &th.Severe, &th.Extreme); err != nil {
+			return fmt.Errorf("scan severity threshold: %w", err)
+		}
+		th.EventType = model.EventType(strings.ToUpper(eventType))
+		thresholds = append(thresholds, th)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("severity thresholds: %w", err)
+	}
+	return s.SetSeverityThresholds(thresholds)
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSeverityThresholds(t *testing.T) {
+	s := &Store{severityThresholds: model.DefaultSeverityThresholds}
+	custom := []model.SeverityThreshold{
+		{EventType: model.EventTypeTornado, Moderate: 1, Severe: 2, Extreme: 4},
+		{EventType: model.EventTypeWind, Moderate: 58, Severe: 80, Extreme: 120},
+		{EventType: model.EventTypeHail, Moderate: 1, Severe: 2, Extreme: 4},
+	}
+
+	require.NoError(t, s.SetSeverityThresholds(custom))
+
+	assert.Equal(t, model.EventTypes[0], s.severityThresholds[0].EventType, "stored in EventTypes order")
+	w := model.DefaultSeverityWeights.WithThresholds(s.SeverityThresholds())
+	expr := severityScoreExpr(w)
+	assert.Equal(t, "(CASE event_type WHEN 'hail' THEN 0.25 WHEN 'wind' THEN 0.008333333333333333 WHEN 'tornado' THEN 0.75 ELSE 0 END * measurement_magnitude)", expr)
+	assert.NotEqual(t, severityScoreExpr(model.DefaultSeverityWeights), expr, "default references replaced")
+
+	sortBy := model.SortFieldSeverityScore
+	sql, _ := buildOrderAndPage(&model.StormReportFilter{SortBy: &sortBy, SeverityWeights: &w}, 1)
+	assert.Equal(t, " ORDER BY "+expr+" DESC, id DESC", sql)
+
+	query, _ := buildSeverityDistributionQuery(&model.StormReportFilter{
+		TimeRange: model.TimeRange{From: time.Unix(0, 0), To: time.Unix(1, 0)},
+	}, s.severityThresholds)
+	assert.Contains(t, query, " WHEN event_type = 'wind' THEN CASE"+
+		" WHEN measurement_magnitude >= 120 THEN 'EXTREME'"+
+		" WHEN measurement_magnitude >= 80 THEN 'SEVERE'"+
+		" WHEN measurement_magnitude >= 58 THEN 'MODERATE'")
+	assert.NotContains(t, query, ">= 96", "default wind threshold replaced")
+}
+
+func TestSetSeverityThresholds_RejectsIncomplete(t *testing.T) {
+	s := &Store{severityThresholds: model.DefaultSeverityThresholds}
+
+	err := s.SetSeverityThresholds(model.DefaultSeverityThresholds[:2])
+
+	require.Error(t, err)
+	assert.Equal(t, model.DefaultSeverityThresholds, s.severityThresholds, "unchanged on error")
+}
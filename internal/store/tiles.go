@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// TileCluster is a group of reports that fall in one grid cell of a map tile,
+// located at their mean position.
+type TileCluster struct {
+	Lat   float64
+	Lon   float64
+	Count int
+}
+
+// buildTileClusterQuery groups the reports matching the filter (ignoring
+// pagination and sorting) by cell of a grid x grid lattice over Web Mercator
+// tile z/x/y. The cell expressions mirror the Go tile projection; points on
+// the tile's far edges are clamped into the last cell.
+func buildTileClusterQuery(filter *model.StormReportFilter, z, x, y, grid int) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	query := fmt.Sprintf(`SELECT AVG(geo_lat), AVG(geo_lon), COUNT(*)
+		FROM (
+			SELECT geo_lat, geo_lon,
+				GREATEST(0, LEAST($%[4]d - 1, FLOOR(((geo_lon + 180) / 360 * $%[1]d - $%[2]d) * $%[4]d))) AS cx,
+				GREATEST(0, LEAST($%[4]d - 1, FLOOR(((1 - LN(TAN(RADIANS(geo_lat)) + 1 / COS(RADIANS(geo_lat))) / PI()) / 2 * $%[1]d - $%[3]d) * $%[4]d))) AS cy
+			FROM storm_reports%[5]s
+		) cells
+		GROUP BY cy, cx
+		ORDER BY cy, cx`, idx, idx+1, idx+2, idx+3, buildWhereSQL(where))
+	return query, append(args, float64(int(1)<<z), float64(x), float64(y), float64(grid))
+}
+
+// ClusterTileReports snaps the reports matching the filter to a grid x grid
+// lattice over tile z/x/y and returns one cluster per non-empty cell, in row
+// order. The filter is expected to carry the tile's bounds as its bbox.
+// Clustering runs in SQL, so low-zoom tiles are not subject to a row cap.
+func (s *Store) ClusterTileReports(ctx context.Context, filter *model.StormReportFilter, z, x, y, grid int) ([]TileCluster, error) {
+	ctx, done, err := s.startQuery(ctx, "tile_clusters")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildTileClusterQuery(filter, z, x, y, grid)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cluster tile reports: %w", err)
+	}
+	defer rows.Close()
+
+	out := []TileCluster{}
+	for rows.Next() {
+		var c TileCluster
+		if err := rows.Scan(&c.Lat, &c.Lon, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan tile cluster: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// buildTileReportsQuery selects up to limit reports matching the filter
+// (ignoring pagination and sorting), newest first.
+func buildTileReportsQuery(filter *model.StormReportFilter, limit int) (string, []any) {
+	where, args, idx := buildWhereClause(filter)
+	query := fmt.Sprintf("SELECT %s FROM storm_reports%s ORDER BY event_time DESC, id LIMIT $%d",
+		columns, buildWhereSQL(where), idx)
+	return query, append(args, limit)
+}
+
+// ListTileReports returns up to limit reports matching the filter for a map
+// tile, newest first. Unlike ListStormReports it is not bound by the page
+// size, since a tile needs every point in its bounding box.
+func (s *Store) ListTileReports(ctx context.Context, filter *model.StormReportFilter, limit int) ([]*model.StormReport, error) {
+	ctx, done, err := s.startQuery(ctx, "tile_reports")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildTileReportsQuery(filter, limit)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list tile reports: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.StormReport{}
+	for rows.Next() {
+		r, err := scanStormReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
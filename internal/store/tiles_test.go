@@ -0,0 +1,44 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTileReportsQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		BBox: &model.BoundingBoxFilter{MinLat: 31.9, MaxLat: 36.6, MinLon: -101.25, MaxLon: -95.625},
+	}
+	where, whereArgs, _ := buildWhereClause(filter)
+
+	query, args := buildTileReportsQuery(filter, 500)
+
+	assert.Equal(t, append(whereArgs, 500), args, "limit follows the filter args")
+	assert.Contains(t, query, buildWhereSQL(where), "bbox predicate reused")
+	assert.Contains(t, query, "ORDER BY event_time DESC, id LIMIT $7")
+}
+
+func TestBuildTileClusterQuery(t *testing.T) {
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		BBox: &model.BoundingBoxFilter{MinLat: 31.9, MaxLat: 36.6, MinLon: -101.25, MaxLon: -95.625},
+	}
+	where, whereArgs, _ := buildWhereClause(filter)
+
+	query, args := buildTileClusterQuery(filter, 6, 14, 25, 64)
+
+	assert.Equal(t, append(whereArgs, 64.0, 14.0, 25.0, 64.0), args, "tiles per side, x, y, grid follow the filter args")
+	assert.Contains(t, query, buildWhereSQL(where), "bbox predicate reused")
+	assert.Contains(t, query, "FLOOR(((geo_lon + 180) / 360 * $7 - $8) * $10)")
+	assert.Contains(t, query, "GROUP BY cy, cx")
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultQueryTimeout bounds each store read unless SetQueryTimeout changes it.
+const DefaultQueryTimeout = 10 * time.Second
+
+// ErrQueryTimeout is returned by store reads that run past the query timeout.
+// A deadline set by the caller (such as the request timeout) surfaces as
+// context.DeadlineExceeded instead.
+var ErrQueryTimeout = errors.New("query timed out")
+
+// SetQueryTimeout changes how long a single store read may run. Zero disables
+// the timeout, leaving reads bounded only by the caller's context.
+func (s *Store) SetQueryTimeout(d time.Duration) {
+	s.queryTimeout = d
+}
+
+// withQueryTimeout derives the context a read runs under. The deadline's
+// cause is ErrQueryTimeout, which timeoutReads uses to tell it apart from
+// the caller's own deadline.
+func (s *Store) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, s.queryTimeout, ErrQueryTimeout)
+}
+
+// queryTimeoutErr wraps err with ErrQueryTimeout when it was caused by the
+// query timeout expiring on ctx.
+func queryTimeoutErr(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || !errors.Is(context.Cause(ctx), ErrQueryTimeout) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+}
+
+// timeoutReads maps errors from reads cut short by the query timeout to
+// ErrQueryTimeout.
+type timeoutReads struct {
+	readPool
+}
+
+// Query implements readPool.
+func (p timeoutReads) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := p.readPool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, queryTimeoutErr(ctx, err)
+	}
+	return timeoutRows{Rows: rows, ctx: ctx}, nil
+}
+
+// QueryRow implements readPool.
+func (p timeoutReads) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return timeoutRow{row: p.readPool.QueryRow(ctx, sql, args...), ctx: ctx}
+}
+
+type timeoutRows struct {
+	pgx.Rows
+	ctx context.Context
+}
+
+func (r timeoutRows) Err() error { return queryTimeoutErr(r.ctx, r.Rows.Err()) }
+
+type timeoutRow struct {
+	row pgx.Row
+	ctx context.Context
+}
+
+func (r timeoutRow) Scan(dest ...any) error { return queryTimeoutErr(r.ctx, r.row.Scan(dest...)) }
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/observability"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowPool is a readPool whose reads sleep for delay, failing like pgx does
+// if ctx ends first.
+type slowPool struct {
+	delay time.Duration
+}
+
+func (p slowPool) wait(ctx context.Context) error {
+	select {
+	case <-time.After(p.delay):
+		return errQueryFailed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p slowPool) Query(ctx context.Context, _ string, _ ...any) (pgx.Rows, error) {
+	return nil, p.wait(ctx)
+}
+
+func (p slowPool) QueryRow(ctx context.Context, _ string, _ ...any) pgx.Row {
+	return errRow{p.wait(ctx)}
+}
+
+func (p slowPool) BeginTx(ctx context.Context, _ pgx.TxOptions) (pgx.Tx, error) {
+	return nil, p.wait(ctx)
+}
+
+func newSlowStore(delay, timeout time.Duration) *Store {
+	s := New(nil, observability.NewTestMetrics())
+	s.reads = timeoutReads{slowPool{delay: delay}}
+	s.SetQueryTimeout(timeout)
+	return s
+}
+
+func TestStore_QueryTimeout(t *testing.T) {
+	s := newSlowStore(time.Second, 10*time.Millisecond)
+	ctx := context.Background()
+
+	_, _, err := s.ListStormReports(ctx, &model.StormReportFilter{})
+	require.ErrorIs(t, err, ErrQueryTimeout)
+	_, err = s.GetStormReport(ctx, "r1")
+	require.ErrorIs(t, err, ErrQueryTimeout)
+	_, err = s.CountByTimeBucket(ctx, &model.StormReportFilter{}, model.BucketUnitDay)
+	require.ErrorIs(t, err, ErrQueryTimeout)
+}
+
+func TestStore_CallerDeadlineIsNotQueryTimeout(t *testing.T) {
+	s := newSlowStore(time.Second, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := s.GetStormReport(ctx, "r1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, errors.Is(err, ErrQueryTimeout))
+}
+
+func TestStore_QueryTimeoutDisabled(t *testing.T) {
+	s := newSlowStore(10*time.Millisecond, 0)
+
+	_, err := s.GetStormReport(context.Background(), "r1")
+	require.ErrorIs(t, err, errQueryFailed)
+	assert.False(t, errors.Is(err, ErrQueryTimeout))
+}
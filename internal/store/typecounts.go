@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// buildCountByTypeQuery counts the reports matching the filter per event type
+// (ignoring pagination).
+func buildCountByTypeQuery(filter *model.StormReportFilter) (string, []any) {
+	where, args, _ := buildWhereClause(filter)
+	query := fmt.Sprintf(`SELECT event_type, COUNT(*) AS count
+		FROM storm_reports%s
+		GROUP BY event_type
+		ORDER BY event_type`, buildWhereSQL(where))
+	return query, args
+}
+
+// CountByType returns the number of reports matching the filter for each
+// event type present in the filtered set. It is empty, not nil, when nothing
+// matches.
+func (s *Store) CountByType(ctx context.Context, filter *model.StormReportFilter) ([]*model.TypeCount, error) {
+	ctx, done, err := s.startQuery(ctx, "count_by_type")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	query, args := buildCountByTypeQuery(filter)
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count by type: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.TypeCount{}
+	for rows.Next() {
+		c := &model.TypeCount{}
+		if err := rows.Scan(&c.Type, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan type count: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCountByTypeQuery(t *testing.T) {
+	radius := 10.0
+	filter := &model.StormReportFilter{
+		TimeRange: model.TimeRange{
+			From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+		},
+		States: []string{"TX"},
+		Near:   &model.GeoRadiusFilter{Lat: 32.8, Lon: -97.0, RadiusMiles: &radius},
+	}
+	where, whereArgs, _ := buildWhereClause(filter)
+
+	query, args := buildCountByTypeQuery(filter)
+
+	assert.Equal(t, whereArgs, args, "same args as the list query")
+	assert.Contains(t, query, buildWhereSQL(where), "time, state, and radius predicates reused")
+	assert.Contains(t, query, "SELECT event_type, COUNT(*) AS count")
+	assert.Contains(t, query, "GROUP BY event_type")
+}
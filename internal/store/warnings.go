@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// leadTimeRow is one (warning, associated report) pair. EventTime is nil for
+// warnings with no associated reports.
+type leadTimeRow struct {
+	WarningID   string
+	WarningType string
+	IssuedAt    time.Time
+	EventTime   *time.Time
+}
+
+// WarningLeadTimes returns, for up to limit warnings issued within the time
+// range (oldest first), the earliest associated report and its lead time from
+// issuance. A report is associated with a warning when it occurred inside the
+// warning polygon while the warning was in effect, the same rule as the
+// warning filter.
+func (s *Store) WarningLeadTimes(ctx context.Context, tr model.TimeRange, types []string, limit int) ([]*model.WarningLeadTime, error) {
+	ctx, done, err := s.startQuery(ctx, "warning_lead_times")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	where := "issued_at >= $1 AND issued_at <= $2"
+	args := []any{tr.From, tr.To}
+	if len(types) > 0 {
+		args = append(args, types)
+		where += fmt.Sprintf(" AND warning_type = ANY($%d)", len(args))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`WITH w AS (
+			SELECT id, warning_type, issued_at, expires_at, area
+			FROM nws_warnings
+			WHERE %s
+			ORDER BY issued_at, id
+			LIMIT $%d
+		)
+		SELECT w.id, w.warning_type, w.issued_at, r.event_time
+		FROM w
+		LEFT JOIN storm_reports r
+			ON r.event_time BETWEEN w.issued_at AND w.expires_at
+			AND w.area @> point(r.geo_lon, r.geo_lat)
+		ORDER BY w.issued_at, w.id`, where, len(args))
+
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("warning lead times: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []leadTimeRow
+	for rows.Next() {
+		var p leadTimeRow
+		if err := rows.Scan(&p.WarningID, &p.WarningType, &p.IssuedAt, &p.EventTime); err != nil {
+			return nil, fmt.Errorf("scan warning lead time: %w", err)
+		}
+		pairs = append(pairs, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return summarizeLeadTimes(pairs), nil
+}
+
+// summarizeLeadTimes groups rows by warning (rows for the same warning must be
+// adjacent) and computes the earliest report time, the lead time from issuance
+// in whole minutes, and the associated report count.
+func summarizeLeadTimes(rows []leadTimeRow) []*model.WarningLeadTime {
+	out := []*model.WarningLeadTime{}
+	var cur *model.WarningLeadTime
+	for _, row := range rows {
+		if cur == nil || cur.WarningID != row.WarningID {
+			cur = &model.WarningLeadTime{
+				WarningID:   row.WarningID,
+				WarningType: row.WarningType,
+				IssuedAt:    row.IssuedAt,
+			}
+			out = append(out, cur)
+		}
+		if row.EventTime == nil {
+			continue
+		}
+		cur.ReportCount++
+		if cur.FirstReportTime == nil || row.EventTime.Before(*cur.FirstReportTime) {
+			t := *row.EventTime
+			cur.FirstReportTime = &t
+		}
+	}
+	for _, lt := range out {
+		if lt.FirstReportTime != nil {
+			minutes := int(math.Round(lt.FirstReportTime.Sub(lt.IssuedAt).Minutes()))
+			lt.LeadTimeMinutes = &minutes
+		}
+	}
+	return out
+}
+
+// WarningsWithoutReports returns up to limit warnings issued within the time
+// range (oldest first) that have no associated report: nothing fell inside the
+// polygon while the product was in effect. These are the false-alarm
+// candidates of warning verification.
+func (s *Store) WarningsWithoutReports(ctx context.Context, tr model.TimeRange, types []string, limit int) ([]*model.UnverifiedWarning, error) {
+	ctx, done, err := s.startQuery(ctx, "warnings_without_reports")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	query, args := buildWarningsWithoutReportsQuery(tr, types, limit)
+	rows, err := s.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("warnings without reports: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.UnverifiedWarning{}
+	for rows.Next() {
+		var w model.UnverifiedWarning
+		if err := rows.Scan(&w.WarningID, &w.WarningType, &w.IssuedAt, &w.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("scan unverified warning: %w", err)
+		}
+		out = append(out, &w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// buildWarningsWithoutReportsQuery builds the anti-join from nws_warnings to
+// storm_reports. The association rule is the one used by WarningLeadTimes and
+// the warning filter, seen from the warning's side.
+func buildWarningsWithoutReportsQuery(tr model.TimeRange, types []string, limit int) (string, []any) {
+	where := "w.issued_at >= $1 AND w.issued_at <= $2"
+	args := []any{tr.From, tr.To}
+	if len(types) > 0 {
+		args = append(args, types)
+		where += fmt.Sprintf(" AND w.warning_type = ANY($%d)", len(args))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`SELECT w.id, w.warning_type, w.issued_at, w.expires_at
+		FROM nws_warnings w
+		WHERE %s
+			AND NOT EXISTS (
+				SELECT 1 FROM storm_reports r
+				WHERE r.event_time BETWEEN w.issued_at AND w.expires_at
+					AND w.area @> point(r.geo_lon, r.geo_lat)
+			)
+		ORDER BY w.issued_at, w.id
+		LIMIT $%d`, where, len(args))
+	return query, args
+}
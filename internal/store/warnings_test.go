@@ -0,0 +1,74 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeLeadTimes(t *testing.T) {
+	issued := time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		tm := issued.Add(time.Duration(minutes) * time.Minute)
+		return &tm
+	}
+
+	rows := []leadTimeRow{
+		// Reports arrive out of order; the earliest (12m) wins.
+		{WarningID: "TO.W.1", WarningType: "TO.W", IssuedAt: issued, EventTime: at(25)},
+		{WarningID: "TO.W.1", WarningType: "TO.W", IssuedAt: issued, EventTime: at(12)},
+		{WarningID: "TO.W.1", WarningType: "TO.W", IssuedAt: issued, EventTime: at(40)},
+		// No associated reports (LEFT JOIN miss).
+		{WarningID: "SV.W.2", WarningType: "SV.W", IssuedAt: issued.Add(time.Hour), EventTime: nil},
+	}
+
+	got := summarizeLeadTimes(rows)
+	require.Len(t, got, 2)
+
+	tor := got[0]
+	assert.Equal(t, "TO.W.1", tor.WarningID)
+	assert.Equal(t, "TO.W", tor.WarningType)
+	assert.Equal(t, 3, tor.ReportCount)
+	require.NotNil(t, tor.FirstReportTime)
+	assert.Equal(t, *at(12), *tor.FirstReportTime)
+	require.NotNil(t, tor.LeadTimeMinutes)
+	assert.Equal(t, 12, *tor.LeadTimeMinutes)
+
+	svr := got[1]
+	assert.Equal(t, "SV.W.2", svr.WarningID)
+	assert.Equal(t, 0, svr.ReportCount)
+	assert.Nil(t, svr.FirstReportTime)
+	assert.Nil(t, svr.LeadTimeMinutes)
+}
+
+func TestSummarizeLeadTimes_Empty(t *testing.T) {
+	assert.Empty(t, summarizeLeadTimes(nil))
+}
+
+func TestBuildWarningsWithoutReportsQuery(t *testing.T) {
+	tr := model.TimeRange{
+		From: time.Date(2024, 4, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+	}
+
+	query, args := buildWarningsWithoutReportsQuery(tr, []string{"TO.W"}, 50)
+
+	assert.Contains(t, query, "FROM nws_warnings w")
+	assert.Contains(t, query, "NOT EXISTS (", "anti-join")
+	assert.Contains(t, query, "r.event_time BETWEEN w.issued_at AND w.expires_at", "temporal overlap")
+	assert.Contains(t, query, "w.area @> point(r.geo_lon, r.geo_lat)", "spatial containment")
+	assert.Contains(t, query, "w.warning_type = ANY($3)")
+	assert.Contains(t, query, "LIMIT $4")
+	assert.Equal(t, []any{tr.From, tr.To, []string{"TO.W"}, 50}, args)
+}
+
+func TestBuildWarningsWithoutReportsQuery_AllTypes(t *testing.T) {
+	query, args := buildWarningsWithoutReportsQuery(model.TimeRange{}, nil, 10)
+
+	assert.NotContains(t, query, "warning_type = ANY")
+	assert.Contains(t, query, "LIMIT $3")
+	assert.Len(t, args, 3)
+}
@@ -0,0 +1,80 @@
+// Package streamapi serves high-cardinality aggregations as newline-delimited
+// JSON, written while the database is still producing rows instead of being
+// buffered into one response.
+package streamapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/httpserver"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+)
+
+// ContentType is the media type of streamed responses.
+const ContentType = "application/x-ndjson"
+
+// flushEvery is how many lines are written between flushes to the client.
+const flushEvery = 100
+
+// writeWindow is how long the stream may take to reach its next flush. The
+// route bypasses the server's TimeoutHandler but not its WriteTimeout, so the
+// write deadline is pushed out by this much at the start and after every
+// flush, up to the request context's deadline.
+const writeWindow = 30 * time.Second
+
+// CountyGroupStreamer yields state/county groups one at a time.
+type CountyGroupStreamer interface {
+	StreamCountyGroups(ctx context.Context, filter *model.StormReportFilter, fn func(*model.CountyStateGroup) error) error
+}
+
+// CountyGroupsHandler accepts a JSON StormReportFilter (same shape as the
+// GraphQL input) and streams one {"state","county","count"} object per line.
+// Once the first line is written the status can no longer change, so a later
+// failure, including the request context's deadline passing, is reported as a
+// final {"error": ...} line. A client disconnect cancels the request context,
+// which stops the database cursor.
+func CountyGroupsHandler(s CountyGroupStreamer, p apifilter.Preparer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := apifilter.FromBody(w, r, p)
+		if err != nil {
+			apifilter.BadRequest(w, err)
+			return
+		}
+
+		rc := http.NewResponseController(w)
+		_ = httpserver.ExtendWriteDeadline(r.Context(), rc, writeWindow)
+		enc := json.NewEncoder(w)
+		written := 0
+		err = s.StreamCountyGroups(r.Context(), filter, func(g *model.CountyStateGroup) error {
+			if written == 0 {
+				w.Header().Set("Content-Type", ContentType)
+			}
+			if err := enc.Encode(g); err != nil {
+				return err
+			}
+			written++
+			if written%flushEvery == 0 {
+				_ = rc.Flush()
+				_ = httpserver.ExtendWriteDeadline(r.Context(), rc, writeWindow)
+			}
+			return nil
+		})
+		switch {
+		case err == nil:
+			w.Header().Set("Content-Type", ContentType)
+			_ = rc.Flush()
+		case errors.Is(r.Context().Err(), context.Canceled):
+			// Client went away; nobody is left to tell.
+		case written == 0:
+			http.Error(w, "query failed", http.StatusInternalServerError)
+		default:
+			_ = enc.Encode(map[string]string{"error": "stream failed"})
+			_ = rc.Flush()
+		}
+	}
+}
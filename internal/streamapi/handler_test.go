@@ -0,0 +1,197 @@
+package streamapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStreamer yields groups one at a time, stopping on cancellation like the
+// cursor-backed store, and fails with err after failAfter groups if set.
+type fakeStreamer struct {
+	groups    []*model.CountyStateGroup
+	err       error
+	failAfter int
+	yielded   int
+}
+
+func (f *fakeStreamer) StreamCountyGroups(ctx context.Context, _ *model.StormReportFilter, fn func(*model.CountyStateGroup) error) error {
+	for i, g := range f.groups {
+		if f.err != nil && i == f.failAfter {
+			return f.err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(g); err != nil {
+			return err
+		}
+		f.yielded++
+	}
+	return nil
+}
+
+const validBody = `{"timeRange":{"from":"2024-04-26T00:00:00Z","to":"2024-04-27T00:00:00Z"}}`
+
+func groups(n int) []*model.CountyStateGroup {
+	out := make([]*model.CountyStateGroup, n)
+	for i := range out {
+		out[i] = &model.CountyStateGroup{State: "TX", County: fmt.Sprintf("County %04d", i), Count: i + 1}
+	}
+	return out
+}
+
+func readLines(t *testing.T, rec *httptest.ResponseRecorder) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+		out = append(out, m)
+	}
+	require.NoError(t, sc.Err())
+	return out
+}
+
+func TestCountyGroupsHandler_StreamsAllGroups(t *testing.T) {
+	s := &fakeStreamer{groups: groups(250)}
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(validBody))
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(s, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.True(t, rec.Flushed)
+	lines := readLines(t, rec)
+	require.Len(t, lines, 250)
+	assert.Equal(t, map[string]any{"state": "TX", "county": "County 0000", "count": 1.0}, lines[0])
+	assert.Equal(t, "County 0249", lines[249]["county"])
+}
+
+func TestCountyGroupsHandler_Empty(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(validBody))
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(&fakeStreamer{}, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestCountyGroupsHandler_CancelledStopsStream(t *testing.T) {
+	s := &fakeStreamer{groups: groups(10)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(validBody)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(s, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	assert.Zero(t, s.yielded)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestCountyGroupsHandler_ErrorBeforeFirstRow(t *testing.T) {
+	s := &fakeStreamer{groups: groups(3), err: errors.New("db down")}
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(validBody))
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(s, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "db down")
+}
+
+func TestCountyGroupsHandler_ErrorMidStream(t *testing.T) {
+	s := &fakeStreamer{groups: groups(5), err: errors.New("db down"), failAfter: 2}
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(validBody))
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(s, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	lines := readLines(t, rec)
+	require.Len(t, lines, 3)
+	assert.Equal(t, map[string]any{"error": "stream failed"}, lines[2])
+}
+
+func TestCountyGroupsHandler_ErrorAfterFlush(t *testing.T) {
+	s := &fakeStreamer{groups: groups(flushEvery + 50), err: errors.New("db down"), failAfter: flushEvery + 20}
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(validBody))
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(s, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, rec.Flushed)
+	lines := readLines(t, rec)
+	require.Len(t, lines, flushEvery+21)
+	assert.Equal(t, "County 0119", lines[flushEvery+19]["county"])
+	assert.Equal(t, map[string]any{"error": "stream failed"}, lines[flushEvery+20])
+}
+
+// stallStreamer yields n groups, then blocks until the request context ends.
+type stallStreamer struct{ n int }
+
+func (s stallStreamer) StreamCountyGroups(ctx context.Context, _ *model.StormReportFilter, fn func(*model.CountyStateGroup) error) error {
+	for _, g := range groups(s.n) {
+		if err := fn(g); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCountyGroupsHandler_DeadlineEndsWithErrorLine(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/stream/county-groups", strings.NewReader(validBody))
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(stallStreamer{n: 3}, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	lines := readLines(t, rec)
+	require.Len(t, lines, 4)
+	assert.Equal(t, map[string]any{"error": "stream failed"}, lines[3], "a capped stream is not passed off as complete")
+}
+
+// deadlineRecorder records the write deadlines set through
+// http.ResponseController.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+}
+
+func (d *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	d.deadlines = append(d.deadlines, t)
+	return nil
+}
+
+func TestCountyGroupsHandler_ExtendsWriteDeadlinePerFlush(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(validBody))
+	rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+	CountyGroupsHandler(&fakeStreamer{groups: groups(2 * flushEvery)}, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, rec.deadlines, 3, "once up front, then after each flush")
+	assert.WithinDuration(t, time.Now().Add(writeWindow), rec.deadlines[2], 5*time.Second)
+}
+
+func TestCountyGroupsHandler_InvalidFilter(t *testing.T) {
+	s := &fakeStreamer{groups: groups(1)}
+	req := httptest.NewRequest(http.MethodPost, "/stream/county-groups", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	CountyGroupsHandler(s, &graph.Resolver{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Zero(t, s.yielded)
+}
@@ -0,0 +1,210 @@
+// Package tileapi serves storm reports as Mapbox Vector Tiles for web maps
+// (Mapbox GL, MapLibre). Tiles are encoded in Go; the schema has no PostGIS
+// for ST_AsMVT.
+package tileapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/apifilter"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+// ContentType is the media type of vector tile responses.
+const ContentType = "application/vnd.mapbox-vector-tile"
+
+// LayerName is the name of the tile's single layer, for map style sources.
+const LayerName = "reports"
+
+// MaxZoom is the deepest zoom level served.
+const MaxZoom = 22
+
+// MaxFeatures caps the points in one tile. Past it, the newest reports are kept.
+const MaxFeatures = 5000
+
+// Clustering defaults: tiles at or below DefaultClusterMaxZoom are
+// clustered on a DefaultClusterGrid x DefaultClusterGrid lattice (64 tile
+// units, or 16 px on a 256 px tile, per cell).
+const (
+	DefaultClusterMaxZoom = 7
+	DefaultClusterGrid    = 64
+)
+
+// ReportLister lists reports for a tile without the page-size limit, or
+// their grid clusters.
+type ReportLister interface {
+	ListTileReports(ctx context.Context, filter *model.StormReportFilter, limit int) ([]*model.StormReport, error)
+	ClusterTileReports(ctx context.Context, filter *model.StormReportFilter, z, x, y, grid int) ([]store.TileCluster, error)
+}
+
+// Option configures a handler.
+type Option func(*options)
+
+type options struct {
+	clusterMaxZoom int
+	clusterGrid    int
+}
+
+// WithClusterMaxZoom sets the deepest zoom whose tiles are clustered; deeper
+// tiles carry individual reports. A negative zoom disables clustering.
+func WithClusterMaxZoom(z int) Option {
+	return func(o *options) { o.clusterMaxZoom = z }
+}
+
+// WithClusterGrid sets the cells per tile side used for clustering, between
+// 1 and Extent. Other values are ignored.
+func WithClusterGrid(n int) Option {
+	return func(o *options) {
+		if n >= 1 && n <= Extent {
+			o.clusterGrid = n
+		}
+	}
+}
+
+// TileBounds returns the latitude/longitude bounds of Web Mercator tile
+// z/x/y (XYZ scheme, y growing southward).
+func TileBounds(z, x, y int) model.BoundingBoxFilter {
+	n := float64(int(1) << z)
+	return model.BoundingBoxFilter{
+		MinLat: tileLat(float64(y+1), n),
+		MaxLat: tileLat(float64(y), n),
+		MinLon: float64(x)/n*360 - 180,
+		MaxLon: float64(x+1)/n*360 - 180,
+	}
+}
+
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
+
+// project returns the position of (lat, lon) in tile z/x/y, in units of
+// Extent from the tile's top-left corner.
+func project(lat, lon float64, z, x, y int) (int, int) {
+	n := float64(int(1) << z)
+	fx := (lon + 180) / 360 * n
+	fy := (1 - math.Asinh(math.Tan(lat*math.Pi/180))/math.Pi) / 2 * n
+	return int(math.Floor((fx - float64(x)) * Extent)), int(math.Floor((fy - float64(y)) * Extent))
+}
+
+// parseTile parses the z, x, and y path parameters ("{y}.mvt" has its
+// suffix stripped by the route) and checks they name a tile.
+func parseTile(zs, xs, ys string) (int, int, int, error) {
+	z, err := strconv.Atoi(zs)
+	if err != nil || z < 0 || z > MaxZoom {
+		return 0, 0, 0, fmt.Errorf("zoom must be an integer between 0 and %d", MaxZoom)
+	}
+	n := 1 << z
+	x, err := strconv.Atoi(xs)
+	if err != nil || x < 0 || x >= n {
+		return 0, 0, 0, fmt.Errorf("x must be an integer between 0 and %d at zoom %d", n-1, z)
+	}
+	y, err := strconv.Atoi(strings.TrimSuffix(ys, ".mvt"))
+	if err != nil || y < 0 || y >= n {
+		return 0, 0, 0, fmt.Errorf("y must be an integer between 0 and %d at zoom %d", n-1, z)
+	}
+	return z, x, y, nil
+}
+
+// Encode returns an MVT tile z/x/y with one point feature per report in the
+// LayerName layer. Each feature carries id, event_type, magnitude, unit,
+// event_time, and severity (when set) properties.
+func Encode(reports []*model.StormReport, z, x, y int) []byte {
+	l := newLayer(LayerName)
+	for _, r := range reports {
+		px, py := project(r.Geo.Lat, r.Geo.Lon, z, x, y)
+		props := []property{
+			{"id", r.ID},
+			{"event_type", r.EventType},
+			{"magnitude", r.Measurement.Magnitude},
+			{"unit", r.Measurement.Unit},
+			{"event_time", r.EventTime.UTC().Format(time.RFC3339)},
+		}
+		if r.Measurement.Severity != nil {
+			props = append(props, property{"severity", *r.Measurement.Severity})
+		}
+		l.addPoint(px, py, props)
+	}
+	return l.encode()
+}
+
+// EncodeClusters returns an MVT tile z/x/y with one point feature per
+// cluster in the LayerName layer, carrying cluster (true) and point_count
+// properties.
+func EncodeClusters(clusters []store.TileCluster, z, x, y int) []byte {
+	l := newLayer(LayerName)
+	for _, c := range clusters {
+		px, py := project(c.Lat, c.Lon, z, x, y)
+		l.addPoint(px, py, []property{
+			{"cluster", true},
+			{"point_count", uint64(c.Count)},
+		})
+	}
+	return l.encode()
+}
+
+// TileHandler serves GET /tiles/{z}/{x}/{y}.mvt. The optional filter query
+// parameter takes a JSON StormReportFilter (same shape as the GraphQL
+// input); its bbox is replaced by the tile's bounds. Tiles up to the
+// cluster zoom carry grid clusters, deeper tiles individual reports. Tiles
+// without reports are 204 No Content.
+func TileHandler(s ReportLister, p apifilter.Preparer, opts ...Option) http.HandlerFunc {
+	o := options{clusterMaxZoom: DefaultClusterMaxZoom, clusterGrid: DefaultClusterGrid}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		z, x, y, err := parseTile(chi.URLParam(r, "z"), chi.URLParam(r, "x"), chi.URLParam(r, "y"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := apifilter.DecodeQuery(r)
+		if err != nil {
+			apifilter.BadRequest(w, err)
+			return
+		}
+		bounds := TileBounds(z, x, y)
+		filter.BBox = &bounds
+		if err := p.PrepareFilter(filter); err != nil {
+			apifilter.BadRequest(w, err)
+			return
+		}
+
+		var tile []byte
+		if z <= o.clusterMaxZoom {
+			clusters, err := s.ClusterTileReports(r.Context(), filter, z, x, y, o.clusterGrid)
+			if err != nil {
+				http.Error(w, "query failed", http.StatusInternalServerError)
+				return
+			}
+			if len(clusters) > 0 {
+				tile = EncodeClusters(clusters, z, x, y)
+			}
+		} else {
+			reports, err := s.ListTileReports(r.Context(), filter, MaxFeatures)
+			if err != nil {
+				http.Error(w, "query failed", http.StatusInternalServerError)
+				return
+			}
+			if len(reports) > 0 {
+				tile = Encode(reports, z, x, y)
+			}
+		}
+		if tile == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = w.Write(tile)
+	}
+}
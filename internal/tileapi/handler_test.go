@@ -0,0 +1,307 @@
+package tileapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/couchcryptid/storm-data-api/internal/graph"
+	"github.com/couchcryptid/storm-data-api/internal/model"
+	"github.com/couchcryptid/storm-data-api/internal/store"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type fakeStore struct {
+	reports  []*model.StormReport
+	clusters []store.TileCluster
+	err      error
+	filter   *model.StormReportFilter
+	limit    int
+	grid     int
+}
+
+func (f *fakeStore) ListTileReports(_ context.Context, filter *model.StormReportFilter, limit int) ([]*model.StormReport, error) {
+	f.filter, f.limit = filter, limit
+	return f.reports, f.err
+}
+
+func (f *fakeStore) ClusterTileReports(_ context.Context, filter *model.StormReportFilter, _, _, _, grid int) ([]store.TileCluster, error) {
+	f.filter, f.grid = filter, grid
+	return f.clusters, f.err
+}
+
+const validFilter = `{"timeRange":{"from":"2024-04-26T00:00:00Z","to":"2024-04-27T00:00:00Z"}}`
+
+func serve(s ReportLister, path, filter string, opts ...Option) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Get("/tiles/{z}/{x}/{y}.mvt", TileHandler(s, &graph.Resolver{}, opts...))
+	if filter != "" {
+		path += "?filter=" + url.QueryEscape(filter)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+// decodedFeature is a point feature read back from an encoded tile.
+type decodedFeature struct {
+	x, y  int64
+	props map[string]any
+}
+
+// decodeTile parses a single-layer tile, failing the test on any field that
+// does not follow the MVT specification.
+func decodeTile(t *testing.T, b []byte) (name string, extent uint64, features []decodedFeature) {
+	t.Helper()
+	num, typ, n := protowire.ConsumeTag(b)
+	require.Positive(t, n)
+	require.Equal(t, protowire.Number(tileLayers), num)
+	require.Equal(t, protowire.BytesType, typ)
+	lb, m := protowire.ConsumeBytes(b[n:])
+	require.Positive(t, m)
+	require.Len(t, b, n+m, "one layer")
+
+	var version uint64
+	var keys []string
+	var values []any
+	var raw [][]byte
+	for len(lb) > 0 {
+		num, typ, n := protowire.ConsumeTag(lb)
+		require.Positive(t, n)
+		lb = lb[n:]
+		switch {
+		case num == layerVersion && typ == protowire.VarintType:
+			version, n = protowire.ConsumeVarint(lb)
+		case num == layerExtent && typ == protowire.VarintType:
+			extent, n = protowire.ConsumeVarint(lb)
+		case num == layerName && typ == protowire.BytesType:
+			name, n = protowire.ConsumeString(lb)
+		case num == layerKeys && typ == protowire.BytesType:
+			var k string
+			k, n = protowire.ConsumeString(lb)
+			keys = append(keys, k)
+		case num == layerFeatures && typ == protowire.BytesType:
+			var f []byte
+			f, n = protowire.ConsumeBytes(lb)
+			raw = append(raw, f)
+		case num == layerValues && typ == protowire.BytesType:
+			var vb []byte
+			vb, n = protowire.ConsumeBytes(lb)
+			vnum, vtyp, vn := protowire.ConsumeTag(vb)
+			require.Positive(t, vn)
+			switch {
+			case vnum == valueString && vtyp == protowire.BytesType:
+				s, _ := protowire.ConsumeString(vb[vn:])
+				values = append(values, s)
+			case vnum == valueDouble && vtyp == protowire.Fixed64Type:
+				d, _ := protowire.ConsumeFixed64(vb[vn:])
+				values = append(values, math.Float64frombits(d))
+			case vnum == valueUint && vtyp == protowire.VarintType:
+				u, _ := protowire.ConsumeVarint(vb[vn:])
+				values = append(values, u)
+			case vnum == valueBool && vtyp == protowire.VarintType:
+				u, _ := protowire.ConsumeVarint(vb[vn:])
+				values = append(values, protowire.DecodeBool(u))
+			default:
+				t.Fatalf("unexpected value field %d", vnum)
+			}
+		default:
+			t.Fatalf("unexpected layer field %d", num)
+		}
+		require.Positive(t, n)
+		lb = lb[n:]
+	}
+	require.Equal(t, uint64(mvtVersion), version)
+
+	for _, fb := range raw {
+		f := decodedFeature{props: map[string]any{}}
+		var geomType uint64
+		var tags, geom []uint64
+		for len(fb) > 0 {
+			num, _, n := protowire.ConsumeTag(fb)
+			fb = fb[n:]
+			switch num {
+			case featureType:
+				geomType, n = protowire.ConsumeVarint(fb)
+			case featureTags, featureGeometry:
+				var packed []byte
+				packed, n = protowire.ConsumeBytes(fb)
+				var out []uint64
+				for len(packed) > 0 {
+					v, vn := protowire.ConsumeVarint(packed)
+					out = append(out, v)
+					packed = packed[vn:]
+				}
+				if num == featureTags {
+					tags = out
+				} else {
+					geom = out
+				}
+			}
+			fb = fb[n:]
+		}
+		require.Equal(t, uint64(geomTypePoint), geomType)
+		require.Len(t, geom, 3, "MoveTo with one point")
+		require.Equal(t, uint64(cmdMoveTo|1<<3), geom[0])
+		f.x, f.y = protowire.DecodeZigZag(geom[1]), protowire.DecodeZigZag(geom[2])
+		require.Zero(t, len(tags)%2)
+		for i := 0; i < len(tags); i += 2 {
+			f.props[keys[tags[i]]] = values[tags[i+1]]
+		}
+		features = append(features, f)
+	}
+	return name, extent, features
+}
+
+func TestTileHandler_PopulatedTile(t *testing.T) {
+	severe := "severe"
+	s := &fakeStore{reports: []*model.StormReport{
+		{
+			ID: "norman", EventType: "hail", EventTime: time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC),
+			Geo:         model.Geo{Lat: 35.2, Lon: -97.4},
+			Measurement: model.Measurement{Magnitude: 1.75, Unit: "in", Severity: &severe},
+		},
+		{
+			ID: "tulsa", EventType: "wind", EventTime: time.Date(2024, 4, 26, 21, 0, 0, 0, time.UTC),
+			Geo:         model.Geo{Lat: 36.1, Lon: -96.0},
+			Measurement: model.Measurement{Magnitude: 65, Unit: "mph"},
+		},
+	}}
+	// Tile 6/14/25 covers Oklahoma.
+	rec := serve(s, "/tiles/6/14/25.mvt", validFilter, WithClusterMaxZoom(-1))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, TileBounds(6, 14, 25), *s.filter.BBox, "filtered to the tile")
+	assert.Equal(t, MaxFeatures, s.limit)
+
+	name, extent, features := decodeTile(t, rec.Body.Bytes())
+	assert.Equal(t, LayerName, name)
+	assert.Equal(t, uint64(Extent), extent)
+	require.Len(t, features, 2)
+	for _, f := range features {
+		assert.True(t, f.x >= 0 && f.x < Extent && f.y >= 0 && f.y < Extent, "point inside the tile")
+	}
+	assert.Equal(t, map[string]any{
+		"id": "norman", "event_type": "hail", "magnitude": 1.75, "unit": "in",
+		"event_time": "2024-04-26T20:00:00Z", "severity": "severe",
+	}, features[0].props)
+	assert.Equal(t, "tulsa", features[1].props["id"])
+	assert.NotContains(t, features[1].props, "severity")
+	assert.Less(t, features[1].y, features[0].y, "Tulsa is north of Norman")
+}
+
+func TestTileHandler_ClustersLowZoom(t *testing.T) {
+	s := &fakeStore{clusters: []store.TileCluster{
+		{Lat: 35.3, Lon: -97.5, Count: 12},
+		{Lat: 36.1, Lon: -96.0, Count: 1},
+	}}
+	// Tile 4/3/6 covers the southern Plains.
+	rec := serve(s, "/tiles/4/3/6.mvt", validFilter)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, DefaultClusterGrid, s.grid)
+	assert.Equal(t, TileBounds(4, 3, 6), *s.filter.BBox, "clusters filtered to the tile")
+	assert.Zero(t, s.limit, "individual reports not listed")
+
+	_, _, features := decodeTile(t, rec.Body.Bytes())
+	require.Len(t, features, 2)
+	assert.Equal(t, map[string]any{"cluster": true, "point_count": uint64(12)}, features[0].props)
+	assert.Equal(t, map[string]any{"cluster": true, "point_count": uint64(1)}, features[1].props)
+	for _, f := range features {
+		assert.True(t, f.x >= 0 && f.x < Extent && f.y >= 0 && f.y < Extent, "cluster inside the tile")
+	}
+}
+
+func TestTileHandler_IndividualHighZoom(t *testing.T) {
+	s := &fakeStore{reports: []*model.StormReport{{
+		ID: "norman", EventType: "hail", EventTime: time.Date(2024, 4, 26, 20, 0, 0, 0, time.UTC),
+		Geo:         model.Geo{Lat: 35.2, Lon: -97.4},
+		Measurement: model.Measurement{Magnitude: 1.75, Unit: "in"},
+	}}}
+	// Tile 10/234/404 covers Norman, OK.
+	rec := serve(s, "/tiles/10/234/404.mvt", validFilter)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, s.grid, "not clustered")
+
+	_, _, features := decodeTile(t, rec.Body.Bytes())
+	require.Len(t, features, 1)
+	assert.Equal(t, "norman", features[0].props["id"])
+	assert.NotContains(t, features[0].props, "cluster")
+}
+
+func TestTileHandler_ClusterOptions(t *testing.T) {
+	s := &fakeStore{clusters: []store.TileCluster{{Lat: 35.2, Lon: -97.4, Count: 3}}}
+
+	rec := serve(s, "/tiles/10/234/404.mvt", validFilter, WithClusterMaxZoom(12), WithClusterGrid(128))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 128, s.grid, "zoom 10 clustered under a raised threshold")
+
+	s = &fakeStore{}
+	serve(s, "/tiles/4/3/6.mvt", validFilter, WithClusterGrid(0), WithClusterGrid(Extent+1))
+	assert.Equal(t, DefaultClusterGrid, s.grid, "out-of-range grids ignored")
+}
+
+func TestTileHandler_EmptyTile(t *testing.T) {
+	rec := serve(&fakeStore{}, "/tiles/6/14/25.mvt", validFilter)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.Bytes())
+
+	rec = serve(&fakeStore{}, "/tiles/10/234/404.mvt", validFilter)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestTileHandler_BadRequests(t *testing.T) {
+	tests := []struct {
+		name, path, filter, msg string
+	}{
+		{"zoom too deep", "/tiles/23/0/0.mvt", validFilter, "zoom must be"},
+		{"x out of range", "/tiles/2/4/0.mvt", validFilter, "x must be"},
+		{"y not a number", "/tiles/2/0/a.mvt", validFilter, "y must be"},
+		{"malformed filter", "/tiles/2/0/0.mvt", "{", "invalid filter"},
+		{"missing time range", "/tiles/2/0/0.mvt", "", "timeRange"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(&fakeStore{}, tt.path, tt.filter)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.msg)
+		})
+	}
+}
+
+func TestTileHandler_StoreError(t *testing.T) {
+	rec := serve(&fakeStore{err: errors.New("boom")}, "/tiles/6/14/25.mvt", validFilter)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "boom")
+}
+
+func TestTileBounds(t *testing.T) {
+	world := TileBounds(0, 0, 0)
+	assert.InDelta(t, -180, world.MinLon, 1e-9)
+	assert.InDelta(t, 180, world.MaxLon, 1e-9)
+	assert.InDelta(t, 85.0511287798, world.MaxLat, 1e-9)
+	assert.InDelta(t, -85.0511287798, world.MinLat, 1e-9)
+
+	b := TileBounds(6, 14, 25)
+	assert.InDelta(t, -101.25, b.MinLon, 1e-9)
+	assert.InDelta(t, -95.625, b.MaxLon, 1e-9)
+	assert.InDelta(t, 31.952162238, b.MinLat, 1e-9)
+	assert.InDelta(t, 36.597889133, b.MaxLat, 1e-9)
+
+	// The tile's corners project to its own corners.
+	x, y := project(b.MaxLat, b.MinLon, 6, 14, 25)
+	assert.Equal(t, [2]int{0, 0}, [2]int{x, y})
+	x, y = project(b.MinLat, b.MaxLon, 6, 14, 25)
+	assert.InDelta(t, Extent, x, 1)
+	assert.InDelta(t, Extent, y, 1)
+}
@@ -0,0 +1,142 @@
+package tileapi
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Extent is the tile's coordinate range, the MVT default.
+const Extent = 4096
+
+// Field numbers and constants from the Mapbox Vector Tile specification
+// (vector_tile.proto, version 2.1).
+const (
+	tileLayers = 3
+
+	layerName     = 1
+	layerFeatures = 2
+	layerKeys     = 3
+	layerValues   = 4
+	layerExtent   = 5
+	layerVersion  = 15
+
+	featureTags     = 2
+	featureType     = 3
+	featureGeometry = 4
+
+	valueString = 1
+	valueDouble = 3
+	valueUint   = 5
+	valueBool   = 7
+
+	geomTypePoint = 1
+	cmdMoveTo     = 1
+	mvtVersion    = 2
+)
+
+// property is a feature attribute. value is a string, float64, uint64, or
+// bool.
+type property struct {
+	key   string
+	value any
+}
+
+// layer builds one MVT layer of point features, interning keys and values
+// as the specification requires.
+type layer struct {
+	name     string
+	keys     []string
+	keyIdx   map[string]uint64
+	values   [][]byte
+	valueIdx map[any]uint64
+	features [][]byte
+}
+
+func newLayer(name string) *layer {
+	return &layer{name: name, keyIdx: make(map[string]uint64), valueIdx: make(map[any]uint64)}
+}
+
+// addPoint appends a point feature at tile coordinates (x, y).
+func (l *layer) addPoint(x, y int, props []property) {
+	var tags []byte
+	for _, p := range props {
+		tags = protowire.AppendVarint(tags, l.key(p.key))
+		tags = protowire.AppendVarint(tags, l.value(p.value))
+	}
+	var geom []byte
+	geom = protowire.AppendVarint(geom, cmdMoveTo|1<<3)
+	geom = protowire.AppendVarint(geom, protowire.EncodeZigZag(int64(x)))
+	geom = protowire.AppendVarint(geom, protowire.EncodeZigZag(int64(y)))
+
+	var f []byte
+	f = protowire.AppendTag(f, featureTags, protowire.BytesType)
+	f = protowire.AppendBytes(f, tags)
+	f = protowire.AppendTag(f, featureType, protowire.VarintType)
+	f = protowire.AppendVarint(f, geomTypePoint)
+	f = protowire.AppendTag(f, featureGeometry, protowire.BytesType)
+	f = protowire.AppendBytes(f, geom)
+	l.features = append(l.features, f)
+}
+
+func (l *layer) key(k string) uint64 {
+	if i, ok := l.keyIdx[k]; ok {
+		return i
+	}
+	i := uint64(len(l.keys))
+	l.keyIdx[k] = i
+	l.keys = append(l.keys, k)
+	return i
+}
+
+func (l *layer) value(v any) uint64 {
+	if i, ok := l.valueIdx[v]; ok {
+		return i
+	}
+	var b []byte
+	switch v := v.(type) {
+	case string:
+		b = protowire.AppendTag(b, valueString, protowire.BytesType)
+		b = protowire.AppendString(b, v)
+	case float64:
+		b = protowire.AppendTag(b, valueDouble, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(v))
+	case uint64:
+		b = protowire.AppendTag(b, valueUint, protowire.VarintType)
+		b = protowire.AppendVarint(b, v)
+	case bool:
+		b = protowire.AppendTag(b, valueBool, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(v))
+	}
+	i := uint64(len(l.values))
+	l.valueIdx[v] = i
+	l.values = append(l.values, b)
+	return i
+}
+
+// encode returns the layer as a complete single-layer tile.
+func (l *layer) encode() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, layerVersion, protowire.VarintType)
+	b = protowire.AppendVarint(b, mvtVersion)
+	b = protowire.AppendTag(b, layerName, protowire.BytesType)
+	b = protowire.AppendString(b, l.name)
+	for _, f := range l.features {
+		b = protowire.AppendTag(b, layerFeatures, protowire.BytesType)
+		b = protowire.AppendBytes(b, f)
+	}
+	for _, k := range l.keys {
+		b = protowire.AppendTag(b, layerKeys, protowire.BytesType)
+		b = protowire.AppendString(b, k)
+	}
+	for _, v := range l.values {
+		b = protowire.AppendTag(b, layerValues, protowire.BytesType)
+		b = protowire.AppendBytes(b, v)
+	}
+	b = protowire.AppendTag(b, layerExtent, protowire.VarintType)
+	b = protowire.AppendVarint(b, Extent)
+
+	var tile []byte
+	tile = protowire.AppendTag(tile, tileLayers, protowire.BytesType)
+	return protowire.AppendBytes(tile, b)
+}